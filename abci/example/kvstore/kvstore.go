@@ -30,7 +30,10 @@ const (
 	defaultLane     string = "default"
 )
 
-var _ types.Application = (*Application)(nil)
+var (
+	_ types.Application   = (*Application)(nil)
+	_ types.StateExporter = (*Application)(nil)
+)
 
 // Application is the kvstore state machine. It complies with the abci.Application interface.
 // It takes transactions in the form of key=value and saves them in a database. This is
@@ -598,6 +601,82 @@ func (app *Application) getValidators() (validators []types.ValidatorUpdate) {
 	return validators
 }
 
+// kvPair is an entry of the application database, as exported by
+// ExportState.
+type kvPair struct {
+	Key   []byte `json:"key"`
+	Value []byte `json:"value"`
+}
+
+// ExportState implements types.StateExporter by dumping the whole database,
+// including the validators and the state.
+func (app *Application) ExportState(context.Context) ([]byte, error) {
+	itr, err := app.state.db.Iterator(nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer itr.Close()
+
+	pairs := []kvPair{}
+	for ; itr.Valid(); itr.Next() {
+		pairs = append(pairs, kvPair{
+			Key:   append([]byte(nil), itr.Key()...),
+			Value: append([]byte(nil), itr.Value()...),
+		})
+	}
+	if err := itr.Error(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(pairs)
+}
+
+// ImportState implements types.StateExporter by replacing the content of the
+// database with an exported one.
+func (app *Application) ImportState(_ context.Context, height int64, state []byte) error {
+	var pairs []kvPair
+	if err := json.Unmarshal(state, &pairs); err != nil {
+		return err
+	}
+
+	itr, err := app.state.db.Iterator(nil, nil)
+	if err != nil {
+		return err
+	}
+	var keys [][]byte
+	for ; itr.Valid(); itr.Next() {
+		keys = append(keys, append([]byte(nil), itr.Key()...))
+	}
+	err = itr.Error()
+	itr.Close()
+	if err != nil {
+		return err
+	}
+
+	batch := app.state.db.NewBatch()
+	defer batch.Close()
+	for _, key := range keys {
+		if err := batch.Delete(key); err != nil {
+			return err
+		}
+	}
+	for _, pair := range pairs {
+		if err := batch.Set(pair.Key, pair.Value); err != nil {
+			return err
+		}
+	}
+	if err := batch.WriteSync(); err != nil {
+		return err
+	}
+
+	app.state = loadState(app.state.db)
+	// reloaded from the database by Info
+	app.valAddrToPubKeyMap = make(map[string]crypto.PubKey)
+	if app.state.Height != height {
+		return fmt.Errorf("imported state has height %d, expected %d", app.state.Height, height)
+	}
+	return nil
+}
+
 // -----------------------------
 
 type State struct {
@@ -139,6 +139,30 @@ func TestPersistentKVStoreInfo(t *testing.T) {
 }
 
 // add a validator, remove a validator, update a validator.
+func TestExportImportState(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	kvstore := NewInMemoryApplication()
+	testKVStore(ctx, t, kvstore, NewTx(testKey, testValue))
+	state, err := kvstore.ExportState(ctx)
+	require.NoError(t, err)
+
+	restored := NewInMemoryApplication()
+	require.Error(t, restored.ImportState(ctx, 2, state))
+	require.NoError(t, restored.ImportState(ctx, 1, state))
+
+	info, err := kvstore.Info(ctx, &types.InfoRequest{})
+	require.NoError(t, err)
+	restoredInfo, err := restored.Info(ctx, &types.InfoRequest{})
+	require.NoError(t, err)
+	require.Equal(t, info, restoredInfo)
+
+	resQuery, err := restored.Query(ctx, &types.QueryRequest{Path: "/store", Data: []byte(testKey)})
+	require.NoError(t, err)
+	require.Equal(t, testValue, string(resQuery.Value))
+}
+
 func TestValUpdates(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
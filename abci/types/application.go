@@ -40,6 +40,20 @@ type Application interface {
 	ApplySnapshotChunk(ctx context.Context, req *ApplySnapshotChunkRequest) (*ApplySnapshotChunkResponse, error) // Apply a snapshot chunk
 }
 
+// StateExporter is an optional interface applications can implement to let
+// CometBFT take and restore snapshots of their state for state sync, instead
+// of implementing the snapshot methods of Application. It is only available
+// to applications running in the same process as CometBFT.
+type StateExporter interface {
+	// ExportState returns the state of the application as of the last
+	// committed block. It is called right after Commit, before the next
+	// block is finalized.
+	ExportState(ctx context.Context) ([]byte, error)
+	// ImportState replaces the state of the application with one returned by
+	// ExportState after the block at the given height was committed.
+	ImportState(ctx context.Context, height int64, state []byte) error
+}
+
 // -------------------------------------------------------
 // BaseApplication is a base form of Application
 
@@ -251,6 +251,11 @@ func (m *DefaultNodeInfo) GetOther() DefaultNodeInfoOther {
 type DefaultNodeInfoOther struct {
 	TxIndex    string `protobuf:"bytes,1,opt,name=tx_index,json=txIndex,proto3" json:"tx_index,omitempty"`
 	RPCAddress string `protobuf:"bytes,2,opt,name=rpc_address,json=rpcAddress,proto3" json:"rpc_address,omitempty"`
+	// Compression algorithms supported by the node, in order of preference.
+	Compression []string `protobuf:"bytes,3,rep,name=compression,proto3" json:"compression,omitempty"`
+	// Channels on which the node compresses messages, if the peer supports one
+	// of its compression algorithms.
+	CompressedChannels []byte `protobuf:"bytes,4,opt,name=compressed_channels,json=compressedChannels,proto3" json:"compressed_channels,omitempty"`
 }
 
 func (m *DefaultNodeInfoOther) Reset()         { *m = DefaultNodeInfoOther{} }
@@ -300,6 +305,20 @@ func (m *DefaultNodeInfoOther) GetRPCAddress() string {
 	return ""
 }
 
+func (m *DefaultNodeInfoOther) GetCompression() []string {
+	if m != nil {
+		return m.Compression
+	}
+	return nil
+}
+
+func (m *DefaultNodeInfoOther) GetCompressedChannels() []byte {
+	if m != nil {
+		return m.CompressedChannels
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*NetAddress)(nil), "cometbft.p2p.v1.NetAddress")
 	proto.RegisterType((*ProtocolVersion)(nil), "cometbft.p2p.v1.ProtocolVersion")
@@ -310,38 +329,40 @@ func init() {
 func init() { proto.RegisterFile("cometbft/p2p/v1/types.proto", fileDescriptor_b87302e2cbe06eca) }
 
 var fileDescriptor_b87302e2cbe06eca = []byte{
-	// 485 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x52, 0x4d, 0x8f, 0xda, 0x30,
-	0x10, 0x25, 0x21, 0x7c, 0xec, 0x50, 0xca, 0xd6, 0x42, 0x55, 0x76, 0x2b, 0x25, 0x08, 0xa9, 0x12,
-	0x27, 0xd2, 0xa5, 0xa7, 0x1e, 0x97, 0x72, 0xa1, 0x87, 0x6d, 0x6a, 0x55, 0x3d, 0xf4, 0x82, 0x20,
-	0x36, 0x60, 0xc1, 0xc6, 0x96, 0xe3, 0xa5, 0xf4, 0x5f, 0xf4, 0x67, 0xed, 0x71, 0x8f, 0x3d, 0x45,
-	0x55, 0x38, 0xf7, 0x3f, 0x54, 0x76, 0x02, 0x42, 0xe9, 0xde, 0xe6, 0xcd, 0xf3, 0xcc, 0xbc, 0x79,
-	0x1e, 0x78, 0x13, 0xf1, 0x7b, 0xaa, 0x16, 0x4b, 0x15, 0x88, 0x91, 0x08, 0x76, 0x37, 0x81, 0xfa,
-	0x29, 0x68, 0x32, 0x14, 0x92, 0x2b, 0x8e, 0x3a, 0x47, 0x72, 0x28, 0x46, 0x62, 0xb8, 0xbb, 0xb9,
-	0xee, 0xae, 0xf8, 0x8a, 0x1b, 0x2e, 0xd0, 0x51, 0xfe, 0xac, 0x1f, 0x02, 0xdc, 0x51, 0x75, 0x4b,
-	0x88, 0xa4, 0x49, 0x82, 0x5e, 0x83, 0xcd, 0x88, 0x6b, 0xf5, 0xac, 0xc1, 0xc5, 0xb8, 0x9e, 0xa5,
-	0xbe, 0x3d, 0x9d, 0x60, 0x9b, 0x11, 0x93, 0x17, 0xae, 0x7d, 0x96, 0x0f, 0xb1, 0xcd, 0x04, 0x42,
-	0xe0, 0x08, 0x2e, 0x95, 0x5b, 0xed, 0x59, 0x83, 0x36, 0x36, 0x71, 0xff, 0x2b, 0x74, 0x42, 0xdd,
-	0x3a, 0xe2, 0xdb, 0x6f, 0x54, 0x26, 0x8c, 0xc7, 0xe8, 0x0a, 0xaa, 0x62, 0x24, 0x4c, 0x5f, 0x67,
-	0xdc, 0xc8, 0x52, 0xbf, 0x1a, 0x8e, 0x42, 0xac, 0x73, 0xa8, 0x0b, 0xb5, 0xc5, 0x96, 0x47, 0x1b,
-	0xd3, 0xdc, 0xc1, 0x39, 0x40, 0x97, 0x50, 0x9d, 0x0b, 0x61, 0xda, 0x3a, 0x58, 0x87, 0xfd, 0xbf,
-	0x36, 0x74, 0x26, 0x74, 0x39, 0x7f, 0xd8, 0xaa, 0x3b, 0x4e, 0xe8, 0x34, 0x5e, 0x72, 0xf4, 0x05,
-	0x2e, 0x45, 0x31, 0x69, 0xb6, 0xcb, 0x47, 0x99, 0x19, 0xad, 0x51, 0x6f, 0x58, 0xda, 0x7e, 0x58,
-	0x92, 0x34, 0x76, 0x1e, 0x53, 0xbf, 0x82, 0x3b, 0xa2, 0xa4, 0xf4, 0x03, 0x74, 0x48, 0x3e, 0x65,
-	0x16, 0x73, 0x42, 0x67, 0x8c, 0x14, 0x5b, 0xbf, 0xca, 0x52, 0xbf, 0x7d, 0x2e, 0x60, 0x82, 0xdb,
-	0xe4, 0x0c, 0x12, 0xe4, 0x43, 0x6b, 0xcb, 0x12, 0x45, 0xe3, 0xd9, 0x9c, 0x10, 0x69, 0xb4, 0x5f,
-	0x60, 0xc8, 0x53, 0xda, 0x5f, 0xe4, 0x42, 0x23, 0xa6, 0xea, 0x07, 0x97, 0x1b, 0xd7, 0x31, 0xe4,
-	0x11, 0x6a, 0xe6, 0xa8, 0xbf, 0x96, 0x33, 0x05, 0x44, 0xd7, 0xd0, 0x8c, 0xd6, 0xf3, 0x38, 0xa6,
-	0xdb, 0xc4, 0xad, 0xf7, 0xac, 0xc1, 0x0b, 0x7c, 0xc2, 0xba, 0xea, 0x9e, 0xc7, 0x6c, 0x43, 0xa5,
-	0xdb, 0xc8, 0xab, 0x0a, 0x88, 0x6e, 0xa1, 0xc6, 0xd5, 0x9a, 0x4a, 0xb7, 0x69, 0xdc, 0x78, 0xfb,
-	0x9f, 0x1b, 0x25, 0x27, 0x3f, 0xeb, 0xc7, 0x85, 0x25, 0x79, 0x65, 0x7f, 0x01, 0xdd, 0xe7, 0x1e,
-	0xa1, 0x2b, 0x68, 0xaa, 0xfd, 0x8c, 0xc5, 0x84, 0xee, 0xf3, 0x3b, 0xc1, 0x0d, 0xb5, 0x9f, 0x6a,
-	0x88, 0x02, 0x68, 0x49, 0x11, 0x99, 0xed, 0x69, 0x92, 0x14, 0xbe, 0xbd, 0xcc, 0x52, 0x1f, 0x70,
-	0xf8, 0xb1, 0xb8, 0x30, 0x0c, 0x52, 0x44, 0x45, 0x3c, 0xfe, 0xf4, 0x98, 0x79, 0xd6, 0x53, 0xe6,
-	0x59, 0x7f, 0x32, 0xcf, 0xfa, 0x75, 0xf0, 0x2a, 0x4f, 0x07, 0xaf, 0xf2, 0xfb, 0xe0, 0x55, 0xbe,
-	0xbf, 0x5b, 0x31, 0xb5, 0x7e, 0x58, 0x68, 0xdd, 0xc1, 0xe9, 0xc8, 0x4f, 0xc1, 0x5c, 0xb0, 0xa0,
-	0x74, 0xfa, 0x8b, 0xba, 0xf9, 0xc9, 0xf7, 0xff, 0x02, 0x00, 0x00, 0xff, 0xff, 0x50, 0x06, 0xfb,
-	0xcd, 0x14, 0x03, 0x00, 0x00,
+	// 516 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x53, 0xbb, 0x8e, 0xda, 0x40,
+	0x14, 0xc5, 0x0f, 0x5e, 0x97, 0x10, 0x36, 0x13, 0x14, 0x79, 0x37, 0x92, 0x6d, 0x21, 0x45, 0xa2,
+	0xc2, 0x59, 0x52, 0xa5, 0x5c, 0x96, 0x86, 0x14, 0x1b, 0x67, 0x14, 0xa5, 0x48, 0x63, 0x81, 0x67,
+	0x00, 0x0b, 0xf0, 0x8c, 0xec, 0x59, 0x42, 0xfe, 0x22, 0x3f, 0x93, 0x7f, 0xd8, 0x72, 0xcb, 0x54,
+	0x28, 0x32, 0x75, 0xfe, 0x21, 0x9a, 0xb1, 0x21, 0xc8, 0xd9, 0xee, 0x9c, 0x7b, 0xe6, 0xbe, 0x8e,
+	0xee, 0xc0, 0xeb, 0x90, 0x6d, 0xa8, 0x98, 0xcd, 0x85, 0xc7, 0x87, 0xdc, 0xdb, 0x5e, 0x7b, 0xe2,
+	0x3b, 0xa7, 0xe9, 0x80, 0x27, 0x4c, 0x30, 0xd4, 0x39, 0x8a, 0x03, 0x3e, 0xe4, 0x83, 0xed, 0xf5,
+	0x55, 0x77, 0xc1, 0x16, 0x4c, 0x69, 0x9e, 0x44, 0xf9, 0xb3, 0x9e, 0x0f, 0x70, 0x47, 0xc5, 0x0d,
+	0x21, 0x09, 0x4d, 0x53, 0xf4, 0x0a, 0xf4, 0x88, 0x58, 0x9a, 0xab, 0xf5, 0x9b, 0xa3, 0x5a, 0xb6,
+	0x77, 0xf4, 0xc9, 0x18, 0xeb, 0x11, 0x51, 0x71, 0x6e, 0xe9, 0x67, 0x71, 0x1f, 0xeb, 0x11, 0x47,
+	0x08, 0x4c, 0xce, 0x12, 0x61, 0x19, 0xae, 0xd6, 0x6f, 0x63, 0x85, 0x7b, 0x9f, 0xa1, 0xe3, 0xcb,
+	0xd2, 0x21, 0x5b, 0x7f, 0xa1, 0x49, 0x1a, 0xb1, 0x18, 0x5d, 0x82, 0xc1, 0x87, 0x5c, 0xd5, 0x35,
+	0x47, 0xf5, 0x6c, 0xef, 0x18, 0xfe, 0xd0, 0xc7, 0x32, 0x86, 0xba, 0x50, 0x9d, 0xad, 0x59, 0xb8,
+	0x52, 0xc5, 0x4d, 0x9c, 0x13, 0x74, 0x01, 0xc6, 0x94, 0x73, 0x55, 0xd6, 0xc4, 0x12, 0xf6, 0xfe,
+	0xe8, 0xd0, 0x19, 0xd3, 0xf9, 0xf4, 0x7e, 0x2d, 0xee, 0x18, 0xa1, 0x93, 0x78, 0xce, 0xd0, 0x27,
+	0xb8, 0xe0, 0x45, 0xa7, 0x60, 0x9b, 0xb7, 0x52, 0x3d, 0x5a, 0x43, 0x77, 0x50, 0xda, 0x7e, 0x50,
+	0x1a, 0x69, 0x64, 0x3e, 0xec, 0x9d, 0x0a, 0xee, 0xf0, 0xd2, 0xa4, 0xef, 0xa1, 0x43, 0xf2, 0x2e,
+	0x41, 0xcc, 0x08, 0x0d, 0x22, 0x52, 0x6c, 0xfd, 0x22, 0xdb, 0x3b, 0xed, 0xf3, 0x01, 0xc6, 0xb8,
+	0x4d, 0xce, 0x28, 0x41, 0x0e, 0xb4, 0xd6, 0x51, 0x2a, 0x68, 0x1c, 0x4c, 0x09, 0x49, 0xd4, 0xec,
+	0x4d, 0x0c, 0x79, 0x48, 0xfa, 0x8b, 0x2c, 0xa8, 0xc7, 0x54, 0x7c, 0x63, 0xc9, 0xca, 0x32, 0x95,
+	0x78, 0xa4, 0x52, 0x39, 0xce, 0x5f, 0xcd, 0x95, 0x82, 0xa2, 0x2b, 0x68, 0x84, 0xcb, 0x69, 0x1c,
+	0xd3, 0x75, 0x6a, 0xd5, 0x5c, 0xad, 0xff, 0x0c, 0x9f, 0xb8, 0xcc, 0xda, 0xb0, 0x38, 0x5a, 0xd1,
+	0xc4, 0xaa, 0xe7, 0x59, 0x05, 0x45, 0x37, 0x50, 0x65, 0x62, 0x49, 0x13, 0xab, 0xa1, 0xdc, 0x78,
+	0xf3, 0x9f, 0x1b, 0x25, 0x27, 0x3f, 0xca, 0xc7, 0x85, 0x25, 0x79, 0x66, 0xef, 0xa7, 0x06, 0xdd,
+	0xa7, 0x5e, 0xa1, 0x4b, 0x68, 0x88, 0x5d, 0x10, 0xc5, 0x84, 0xee, 0xf2, 0x43, 0xc1, 0x75, 0xb1,
+	0x9b, 0x48, 0x8a, 0x3c, 0x68, 0x25, 0x3c, 0x54, 0xeb, 0xd3, 0x34, 0x2d, 0x8c, 0x7b, 0x9e, 0xed,
+	0x1d, 0xc0, 0xfe, 0x6d, 0x71, 0x62, 0x18, 0x12, 0x1e, 0x16, 0x18, 0xb9, 0xd0, 0x0a, 0xd9, 0x86,
+	0x4b, 0x2c, 0x77, 0x37, 0x5c, 0xa3, 0xdf, 0xc4, 0xe7, 0x21, 0xe4, 0xc1, 0xcb, 0x23, 0xa5, 0x24,
+	0x38, 0x59, 0x61, 0x2a, 0x2b, 0xd0, 0x3f, 0xe9, 0xb6, 0x50, 0x46, 0x1f, 0x1e, 0x32, 0x5b, 0x7b,
+	0xcc, 0x6c, 0xed, 0x77, 0x66, 0x6b, 0x3f, 0x0e, 0x76, 0xe5, 0xf1, 0x60, 0x57, 0x7e, 0x1d, 0xec,
+	0xca, 0xd7, 0xb7, 0x8b, 0x48, 0x2c, 0xef, 0x67, 0xd2, 0x0b, 0xef, 0xf4, 0x71, 0x4e, 0x60, 0xca,
+	0x23, 0xaf, 0xf4, 0x9d, 0x66, 0x35, 0x75, 0x1d, 0xef, 0xfe, 0x0e, 0x00, 0x29, 0x53, 0x9b, 0x21,
+	0x68, 0x03, 0x00, 0x00,
 }
 
 func (m *NetAddress) Marshal() (dAtA []byte, err error) {
@@ -529,6 +550,22 @@ func (m *DefaultNodeInfoOther) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.CompressedChannels) > 0 {
+		i -= len(m.CompressedChannels)
+		copy(dAtA[i:], m.CompressedChannels)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.CompressedChannels)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Compression) > 0 {
+		for iNdEx := len(m.Compression) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Compression[iNdEx])
+			copy(dAtA[i:], m.Compression[iNdEx])
+			i = encodeVarintTypes(dAtA, i, uint64(len(m.Compression[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
 	if len(m.RPCAddress) > 0 {
 		i -= len(m.RPCAddress)
 		copy(dAtA[i:], m.RPCAddress)
@@ -646,6 +683,16 @@ func (m *DefaultNodeInfoOther) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovTypes(uint64(l))
 	}
+	if len(m.Compression) > 0 {
+		for _, s := range m.Compression {
+			l = len(s)
+			n += 1 + l + sovTypes(uint64(l))
+		}
+	}
+	l = len(m.CompressedChannels)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
 	return n
 }
 
@@ -1298,6 +1345,72 @@ func (m *DefaultNodeInfoOther) Unmarshal(dAtA []byte) error {
 			}
 			m.RPCAddress = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Compression", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Compression = append(m.Compression, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CompressedChannels", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CompressedChannels = append(m.CompressedChannels[:0], dAtA[iNdEx:postIndex]...)
+			if m.CompressedChannels == nil {
+				m.CompressedChannels = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
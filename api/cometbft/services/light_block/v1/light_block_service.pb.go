@@ -0,0 +1,247 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: cometbft/services/light_block/v1/light_block_service.proto
+
+package v1
+
+import (
+	context "context"
+	fmt "fmt"
+	grpc1 "github.com/cosmos/gogoproto/grpc"
+	proto "github.com/cosmos/gogoproto/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+func init() {
+	proto.RegisterFile("cometbft/services/light_block/v1/light_block_service.proto", fileDescriptor_281da193bdadda52)
+}
+
+var fileDescriptor_281da193bdadda52 = []byte{
+	// 255 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xb2, 0x4a, 0xce, 0xcf, 0x4d,
+	0x2d, 0x49, 0x4a, 0x2b, 0xd1, 0x2f, 0x4e, 0x2d, 0x2a, 0xcb, 0x4c, 0x4e, 0x2d, 0xd6, 0xcf, 0xc9,
+	0x4c, 0xcf, 0x28, 0x89, 0x4f, 0xca, 0xc9, 0x4f, 0xce, 0xd6, 0x2f, 0x33, 0x44, 0xe6, 0xc6, 0x43,
+	0xd5, 0xe8, 0x15, 0x14, 0xe5, 0x97, 0xe4, 0x0b, 0x29, 0xc0, 0xf4, 0xea, 0xc1, 0xf4, 0xea, 0x21,
+	0x29, 0xd6, 0x2b, 0x33, 0x94, 0x32, 0x22, 0xc5, 0x74, 0x88, 0xa9, 0x46, 0xbd, 0xcc, 0x5c, 0x82,
+	0x3e, 0x20, 0x51, 0x27, 0x90, 0x60, 0x30, 0x44, 0x9f, 0x50, 0x15, 0x17, 0xb7, 0x7b, 0x6a, 0x89,
+	0x53, 0xa5, 0x47, 0x2a, 0x48, 0x4a, 0xc8, 0x44, 0x8f, 0x90, 0xdd, 0x7a, 0x48, 0xca, 0x83, 0x52,
+	0x0b, 0x4b, 0x53, 0x8b, 0x4b, 0xa4, 0x4c, 0x49, 0xd4, 0x55, 0x5c, 0x90, 0x9f, 0x57, 0x9c, 0x2a,
+	0x54, 0xc6, 0xc5, 0xe9, 0x9e, 0x5a, 0xe2, 0x93, 0x58, 0x92, 0x5a, 0x5c, 0x22, 0x64, 0x44, 0x94,
+	0x19, 0x10, 0xc5, 0x30, 0x7b, 0x8d, 0x49, 0xd2, 0x03, 0xb1, 0xd5, 0x80, 0x51, 0xa8, 0x99, 0x91,
+	0x8b, 0x2f, 0x28, 0xb5, 0x20, 0xbf, 0xa8, 0xc4, 0xb5, 0x2c, 0x33, 0x25, 0x35, 0x2f, 0x39, 0x55,
+	0xc8, 0x9c, 0xb0, 0x49, 0xa8, 0x3a, 0x60, 0x4e, 0xb0, 0x20, 0x5d, 0x23, 0xc4, 0x1d, 0x4e, 0xd1,
+	0x27, 0x1e, 0xc9, 0x31, 0x5e, 0x78, 0x24, 0xc7, 0xf8, 0xe0, 0x91, 0x1c, 0xe3, 0x84, 0xc7, 0x72,
+	0x0c, 0x17, 0x1e, 0xcb, 0x31, 0xdc, 0x78, 0x2c, 0xc7, 0x10, 0xe5, 0x98, 0x9e, 0x59, 0x92, 0x51,
+	0x9a, 0x04, 0x32, 0x59, 0x1f, 0x1e, 0xd1, 0x70, 0x46, 0x62, 0x41, 0xa6, 0x3e, 0xa1, 0xe8, 0x4f,
+	0x62, 0x03, 0xc7, 0xb9, 0x31, 0x60, 0x00, 0x31, 0x3c, 0x0b, 0x21, 0x87, 0x02, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// LightBlockServiceClient is the client API for LightBlockService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type LightBlockServiceClient interface {
+	// GetByHeight retrieves the light block at a particular height, or the
+	// latest one.
+	GetByHeight(ctx context.Context, in *GetByHeightRequest, opts ...grpc.CallOption) (*GetByHeightResponse, error)
+	// GetLatest returns a stream of the light blocks of the latest blocks
+	// committed by the network. This is a long-lived stream that is only
+	// terminated by the server if an error occurs. The caller is expected to
+	// handle such disconnections and automatically reconnect.
+	GetLatest(ctx context.Context, in *GetLatestRequest, opts ...grpc.CallOption) (LightBlockService_GetLatestClient, error)
+	// ReportEvidence adds evidence of misbehavior to the evidence pool of the
+	// node.
+	ReportEvidence(ctx context.Context, in *ReportEvidenceRequest, opts ...grpc.CallOption) (*ReportEvidenceResponse, error)
+}
+
+type lightBlockServiceClient struct {
+	cc grpc1.ClientConn
+}
+
+func NewLightBlockServiceClient(cc grpc1.ClientConn) LightBlockServiceClient {
+	return &lightBlockServiceClient{cc}
+}
+
+func (c *lightBlockServiceClient) GetByHeight(ctx context.Context, in *GetByHeightRequest, opts ...grpc.CallOption) (*GetByHeightResponse, error) {
+	out := new(GetByHeightResponse)
+	err := c.cc.Invoke(ctx, "/cometbft.services.light_block.v1.LightBlockService/GetByHeight", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightBlockServiceClient) GetLatest(ctx context.Context, in *GetLatestRequest, opts ...grpc.CallOption) (LightBlockService_GetLatestClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_LightBlockService_serviceDesc.Streams[0], "/cometbft.services.light_block.v1.LightBlockService/GetLatest", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightBlockServiceGetLatestClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type LightBlockService_GetLatestClient interface {
+	Recv() (*GetLatestResponse, error)
+	grpc.ClientStream
+}
+
+type lightBlockServiceGetLatestClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightBlockServiceGetLatestClient) Recv() (*GetLatestResponse, error) {
+	m := new(GetLatestResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightBlockServiceClient) ReportEvidence(ctx context.Context, in *ReportEvidenceRequest, opts ...grpc.CallOption) (*ReportEvidenceResponse, error) {
+	out := new(ReportEvidenceResponse)
+	err := c.cc.Invoke(ctx, "/cometbft.services.light_block.v1.LightBlockService/ReportEvidence", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LightBlockServiceServer is the server API for LightBlockService service.
+type LightBlockServiceServer interface {
+	// GetByHeight retrieves the light block at a particular height, or the
+	// latest one.
+	GetByHeight(context.Context, *GetByHeightRequest) (*GetByHeightResponse, error)
+	// GetLatest returns a stream of the light blocks of the latest blocks
+	// committed by the network. This is a long-lived stream that is only
+	// terminated by the server if an error occurs. The caller is expected to
+	// handle such disconnections and automatically reconnect.
+	GetLatest(*GetLatestRequest, LightBlockService_GetLatestServer) error
+	// ReportEvidence adds evidence of misbehavior to the evidence pool of the
+	// node.
+	ReportEvidence(context.Context, *ReportEvidenceRequest) (*ReportEvidenceResponse, error)
+}
+
+// UnimplementedLightBlockServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedLightBlockServiceServer struct {
+}
+
+func (*UnimplementedLightBlockServiceServer) GetByHeight(ctx context.Context, req *GetByHeightRequest) (*GetByHeightResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetByHeight not implemented")
+}
+func (*UnimplementedLightBlockServiceServer) GetLatest(req *GetLatestRequest, srv LightBlockService_GetLatestServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetLatest not implemented")
+}
+func (*UnimplementedLightBlockServiceServer) ReportEvidence(ctx context.Context, req *ReportEvidenceRequest) (*ReportEvidenceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportEvidence not implemented")
+}
+
+func RegisterLightBlockServiceServer(s grpc1.Server, srv LightBlockServiceServer) {
+	s.RegisterService(&_LightBlockService_serviceDesc, srv)
+}
+
+func _LightBlockService_GetByHeight_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetByHeightRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightBlockServiceServer).GetByHeight(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cometbft.services.light_block.v1.LightBlockService/GetByHeight",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightBlockServiceServer).GetByHeight(ctx, req.(*GetByHeightRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LightBlockService_GetLatest_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetLatestRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightBlockServiceServer).GetLatest(m, &lightBlockServiceGetLatestServer{stream})
+}
+
+type LightBlockService_GetLatestServer interface {
+	Send(*GetLatestResponse) error
+	grpc.ServerStream
+}
+
+type lightBlockServiceGetLatestServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightBlockServiceGetLatestServer) Send(m *GetLatestResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _LightBlockService_ReportEvidence_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportEvidenceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightBlockServiceServer).ReportEvidence(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cometbft.services.light_block.v1.LightBlockService/ReportEvidence",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightBlockServiceServer).ReportEvidence(ctx, req.(*ReportEvidenceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var LightBlockService_serviceDesc = _LightBlockService_serviceDesc
+var _LightBlockService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "cometbft.services.light_block.v1.LightBlockService",
+	HandlerType: (*LightBlockServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetByHeight",
+			Handler:    _LightBlockService_GetByHeight_Handler,
+		},
+		{
+			MethodName: "ReportEvidence",
+			Handler:    _LightBlockService_ReportEvidence_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetLatest",
+			Handler:       _LightBlockService_GetLatest_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "cometbft/services/light_block/v1/light_block_service.proto",
+}
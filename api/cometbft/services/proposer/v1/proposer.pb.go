@@ -0,0 +1,971 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: cometbft/services/proposer/v1/proposer.proto
+
+package v1
+
+import (
+	fmt "fmt"
+	proto "github.com/cosmos/gogoproto/proto"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+// GetProposerScheduleRequest is a request for the expected proposers of the
+// heights following the latest block.
+type GetProposerScheduleRequest struct {
+	// Number of heights to return. Defaults to 10 if 0, capped at 100.
+	Count int64 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+}
+
+func (m *GetProposerScheduleRequest) Reset()         { *m = GetProposerScheduleRequest{} }
+func (m *GetProposerScheduleRequest) String() string { return proto.CompactTextString(m) }
+func (*GetProposerScheduleRequest) ProtoMessage()    {}
+func (*GetProposerScheduleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_49d352406582509d, []int{0}
+}
+func (m *GetProposerScheduleRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *GetProposerScheduleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_GetProposerScheduleRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *GetProposerScheduleRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetProposerScheduleRequest.Merge(m, src)
+}
+func (m *GetProposerScheduleRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *GetProposerScheduleRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetProposerScheduleRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetProposerScheduleRequest proto.InternalMessageInfo
+
+func (m *GetProposerScheduleRequest) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+// ScheduledProposer is the expected proposer of round 0 of a height.
+type ScheduledProposer struct {
+	Height      int64  `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Address     []byte `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	PubKeyBytes []byte `protobuf:"bytes,3,opt,name=pub_key_bytes,json=pubKeyBytes,proto3" json:"pub_key_bytes,omitempty"`
+	PubKeyType  string `protobuf:"bytes,4,opt,name=pub_key_type,json=pubKeyType,proto3" json:"pub_key_type,omitempty"`
+	VotingPower int64  `protobuf:"varint,5,opt,name=voting_power,json=votingPower,proto3" json:"voting_power,omitempty"`
+}
+
+func (m *ScheduledProposer) Reset()         { *m = ScheduledProposer{} }
+func (m *ScheduledProposer) String() string { return proto.CompactTextString(m) }
+func (*ScheduledProposer) ProtoMessage()    {}
+func (*ScheduledProposer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_49d352406582509d, []int{1}
+}
+func (m *ScheduledProposer) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ScheduledProposer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ScheduledProposer.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ScheduledProposer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ScheduledProposer.Merge(m, src)
+}
+func (m *ScheduledProposer) XXX_Size() int {
+	return m.Size()
+}
+func (m *ScheduledProposer) XXX_DiscardUnknown() {
+	xxx_messageInfo_ScheduledProposer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ScheduledProposer proto.InternalMessageInfo
+
+func (m *ScheduledProposer) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *ScheduledProposer) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *ScheduledProposer) GetPubKeyBytes() []byte {
+	if m != nil {
+		return m.PubKeyBytes
+	}
+	return nil
+}
+
+func (m *ScheduledProposer) GetPubKeyType() string {
+	if m != nil {
+		return m.PubKeyType
+	}
+	return ""
+}
+
+func (m *ScheduledProposer) GetVotingPower() int64 {
+	if m != nil {
+		return m.VotingPower
+	}
+	return 0
+}
+
+// GetProposerScheduleResponse contains the expected proposers of round 0 of
+// the heights following the latest block. Only the proposers up to
+// final_until are final: the later ones assume that the application does not
+// update the validator set, and change if it does.
+type GetProposerScheduleResponse struct {
+	LastHeight int64                `protobuf:"varint,1,opt,name=last_height,json=lastHeight,proto3" json:"last_height,omitempty"`
+	FinalUntil int64                `protobuf:"varint,2,opt,name=final_until,json=finalUntil,proto3" json:"final_until,omitempty"`
+	Proposers  []*ScheduledProposer `protobuf:"bytes,3,rep,name=proposers,proto3" json:"proposers,omitempty"`
+	// Human-readable explanation of the limits of the schedule.
+	Note string `protobuf:"bytes,4,opt,name=note,proto3" json:"note,omitempty"`
+}
+
+func (m *GetProposerScheduleResponse) Reset()         { *m = GetProposerScheduleResponse{} }
+func (m *GetProposerScheduleResponse) String() string { return proto.CompactTextString(m) }
+func (*GetProposerScheduleResponse) ProtoMessage()    {}
+func (*GetProposerScheduleResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_49d352406582509d, []int{2}
+}
+func (m *GetProposerScheduleResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *GetProposerScheduleResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_GetProposerScheduleResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *GetProposerScheduleResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetProposerScheduleResponse.Merge(m, src)
+}
+func (m *GetProposerScheduleResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *GetProposerScheduleResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetProposerScheduleResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetProposerScheduleResponse proto.InternalMessageInfo
+
+func (m *GetProposerScheduleResponse) GetLastHeight() int64 {
+	if m != nil {
+		return m.LastHeight
+	}
+	return 0
+}
+
+func (m *GetProposerScheduleResponse) GetFinalUntil() int64 {
+	if m != nil {
+		return m.FinalUntil
+	}
+	return 0
+}
+
+func (m *GetProposerScheduleResponse) GetProposers() []*ScheduledProposer {
+	if m != nil {
+		return m.Proposers
+	}
+	return nil
+}
+
+func (m *GetProposerScheduleResponse) GetNote() string {
+	if m != nil {
+		return m.Note
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*GetProposerScheduleRequest)(nil), "cometbft.services.proposer.v1.GetProposerScheduleRequest")
+	proto.RegisterType((*ScheduledProposer)(nil), "cometbft.services.proposer.v1.ScheduledProposer")
+	proto.RegisterType((*GetProposerScheduleResponse)(nil), "cometbft.services.proposer.v1.GetProposerScheduleResponse")
+}
+
+func init() {
+	proto.RegisterFile("cometbft/services/proposer/v1/proposer.proto", fileDescriptor_49d352406582509d)
+}
+
+var fileDescriptor_49d352406582509d = []byte{
+	// 370 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x92, 0xcf, 0x8e, 0x93, 0x50,
+	0x14, 0x87, 0x7b, 0xa5, 0xad, 0xe9, 0xa1, 0x2e, 0xbc, 0x31, 0x86, 0x68, 0xa4, 0xc8, 0x8a, 0x85,
+	0x01, 0x5b, 0xf7, 0x2e, 0xba, 0xd1, 0xc4, 0xc4, 0x34, 0xa8, 0x89, 0x71, 0x43, 0xf8, 0x73, 0x5a,
+	0x88, 0xc8, 0xbd, 0x72, 0x2f, 0x18, 0xde, 0xc2, 0x27, 0x99, 0x97, 0x98, 0xcd, 0x2c, 0xbb, 0x9c,
+	0xe5, 0xa4, 0x7d, 0x91, 0x09, 0xd0, 0xcb, 0x64, 0x32, 0x93, 0xee, 0xce, 0xf9, 0xf8, 0xe0, 0xf0,
+	0x3b, 0x39, 0xf0, 0x2e, 0x66, 0x7f, 0x50, 0x46, 0x5b, 0xe9, 0x09, 0x2c, 0xeb, 0x2c, 0x46, 0xe1,
+	0xf1, 0x92, 0x71, 0x26, 0xb0, 0xf4, 0xea, 0xe5, 0x50, 0xbb, 0xbc, 0x64, 0x92, 0xd1, 0x37, 0xca,
+	0x76, 0x95, 0xed, 0x0e, 0x46, 0xbd, 0xb4, 0x57, 0xf0, 0xea, 0x13, 0xca, 0xcd, 0x89, 0x7c, 0x8b,
+	0x53, 0x4c, 0xaa, 0x1c, 0x7d, 0xfc, 0x5b, 0xa1, 0x90, 0xf4, 0x05, 0x4c, 0x62, 0x56, 0x15, 0xd2,
+	0x20, 0x16, 0x71, 0x34, 0xbf, 0x6f, 0xec, 0x0b, 0x02, 0xcf, 0x95, 0x99, 0xa8, 0x57, 0xe9, 0x4b,
+	0x98, 0xa6, 0x98, 0xed, 0x52, 0x25, 0x9f, 0x3a, 0x6a, 0xc0, 0xd3, 0x30, 0x49, 0x4a, 0x14, 0xc2,
+	0x78, 0x62, 0x11, 0x67, 0xee, 0xab, 0x96, 0xda, 0xf0, 0x8c, 0x57, 0x51, 0xf0, 0x1b, 0x9b, 0x20,
+	0x6a, 0x24, 0x0a, 0x43, 0xeb, 0x9e, 0xeb, 0xbc, 0x8a, 0xbe, 0x60, 0xb3, 0x6e, 0x11, 0xb5, 0x60,
+	0xae, 0x1c, 0xd9, 0x70, 0x34, 0xc6, 0x16, 0x71, 0x66, 0x3e, 0xf4, 0xca, 0xf7, 0x86, 0x23, 0x7d,
+	0x0b, 0xf3, 0x9a, 0xc9, 0xac, 0xd8, 0x05, 0x9c, 0xfd, 0xc3, 0xd2, 0x98, 0x74, 0xd3, 0xf5, 0x9e,
+	0x6d, 0x5a, 0x64, 0x5f, 0x12, 0x78, 0xfd, 0x68, 0x4a, 0xc1, 0x59, 0x21, 0x90, 0x2e, 0x40, 0xcf,
+	0x43, 0x21, 0x83, 0x7b, 0xff, 0x0f, 0x2d, 0xfa, 0xdc, 0x67, 0x58, 0x80, 0xbe, 0xcd, 0x8a, 0x30,
+	0x0f, 0xaa, 0x42, 0x66, 0x79, 0x97, 0x43, 0xf3, 0xa1, 0x43, 0x3f, 0x5a, 0x42, 0xbf, 0xc2, 0x4c,
+	0x6d, 0xb5, 0x8d, 0xa1, 0x39, 0xfa, 0xea, 0xbd, 0x7b, 0x76, 0xf3, 0xee, 0x83, 0x0d, 0xfa, 0x77,
+	0x9f, 0xa0, 0x14, 0xc6, 0x05, 0x93, 0x2a, 0x6e, 0x57, 0xaf, 0x7f, 0x5e, 0x1d, 0x4c, 0xb2, 0x3f,
+	0x98, 0xe4, 0xe6, 0x60, 0x92, 0xff, 0x47, 0x73, 0xb4, 0x3f, 0x9a, 0xa3, 0xeb, 0xa3, 0x39, 0xfa,
+	0xf5, 0x71, 0x97, 0xc9, 0xb4, 0x8a, 0xda, 0x81, 0xde, 0x70, 0x1c, 0x43, 0x11, 0xf2, 0xcc, 0x3b,
+	0x7b, 0x32, 0xd1, 0xb4, 0x3b, 0x95, 0x0f, 0xb7, 0x03, 0x00, 0xd7, 0x85, 0x72, 0x3f, 0x5a, 0x02,
+	0x00, 0x00,
+}
+
+func (m *GetProposerScheduleRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetProposerScheduleRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *GetProposerScheduleRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Count != 0 {
+		i = encodeVarintProposer(dAtA, i, uint64(m.Count))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ScheduledProposer) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ScheduledProposer) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ScheduledProposer) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.VotingPower != 0 {
+		i = encodeVarintProposer(dAtA, i, uint64(m.VotingPower))
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.PubKeyType) > 0 {
+		i -= len(m.PubKeyType)
+		copy(dAtA[i:], m.PubKeyType)
+		i = encodeVarintProposer(dAtA, i, uint64(len(m.PubKeyType)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.PubKeyBytes) > 0 {
+		i -= len(m.PubKeyBytes)
+		copy(dAtA[i:], m.PubKeyBytes)
+		i = encodeVarintProposer(dAtA, i, uint64(len(m.PubKeyBytes)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Address) > 0 {
+		i -= len(m.Address)
+		copy(dAtA[i:], m.Address)
+		i = encodeVarintProposer(dAtA, i, uint64(len(m.Address)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Height != 0 {
+		i = encodeVarintProposer(dAtA, i, uint64(m.Height))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *GetProposerScheduleResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetProposerScheduleResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *GetProposerScheduleResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Note) > 0 {
+		i -= len(m.Note)
+		copy(dAtA[i:], m.Note)
+		i = encodeVarintProposer(dAtA, i, uint64(len(m.Note)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Proposers) > 0 {
+		for iNdEx := len(m.Proposers) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Proposers[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintProposer(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if m.FinalUntil != 0 {
+		i = encodeVarintProposer(dAtA, i, uint64(m.FinalUntil))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.LastHeight != 0 {
+		i = encodeVarintProposer(dAtA, i, uint64(m.LastHeight))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintProposer(dAtA []byte, offset int, v uint64) int {
+	offset -= sovProposer(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *GetProposerScheduleRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Count != 0 {
+		n += 1 + sovProposer(uint64(m.Count))
+	}
+	return n
+}
+
+func (m *ScheduledProposer) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Height != 0 {
+		n += 1 + sovProposer(uint64(m.Height))
+	}
+	l = len(m.Address)
+	if l > 0 {
+		n += 1 + l + sovProposer(uint64(l))
+	}
+	l = len(m.PubKeyBytes)
+	if l > 0 {
+		n += 1 + l + sovProposer(uint64(l))
+	}
+	l = len(m.PubKeyType)
+	if l > 0 {
+		n += 1 + l + sovProposer(uint64(l))
+	}
+	if m.VotingPower != 0 {
+		n += 1 + sovProposer(uint64(m.VotingPower))
+	}
+	return n
+}
+
+func (m *GetProposerScheduleResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.LastHeight != 0 {
+		n += 1 + sovProposer(uint64(m.LastHeight))
+	}
+	if m.FinalUntil != 0 {
+		n += 1 + sovProposer(uint64(m.FinalUntil))
+	}
+	if len(m.Proposers) > 0 {
+		for _, e := range m.Proposers {
+			l = e.Size()
+			n += 1 + l + sovProposer(uint64(l))
+		}
+	}
+	l = len(m.Note)
+	if l > 0 {
+		n += 1 + l + sovProposer(uint64(l))
+	}
+	return n
+}
+
+func sovProposer(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozProposer(x uint64) (n int) {
+	return sovProposer(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *GetProposerScheduleRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowProposer
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetProposerScheduleRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetProposerScheduleRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+			}
+			m.Count = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProposer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Count |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipProposer(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthProposer
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ScheduledProposer) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowProposer
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ScheduledProposer: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ScheduledProposer: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			}
+			m.Height = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProposer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Height |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Address", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProposer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthProposer
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthProposer
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Address = append(m.Address[:0], dAtA[iNdEx:postIndex]...)
+			if m.Address == nil {
+				m.Address = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PubKeyBytes", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProposer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthProposer
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthProposer
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PubKeyBytes = append(m.PubKeyBytes[:0], dAtA[iNdEx:postIndex]...)
+			if m.PubKeyBytes == nil {
+				m.PubKeyBytes = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PubKeyType", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProposer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthProposer
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthProposer
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PubKeyType = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field VotingPower", wireType)
+			}
+			m.VotingPower = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProposer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.VotingPower |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipProposer(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthProposer
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetProposerScheduleResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowProposer
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetProposerScheduleResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetProposerScheduleResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastHeight", wireType)
+			}
+			m.LastHeight = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProposer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LastHeight |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FinalUntil", wireType)
+			}
+			m.FinalUntil = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProposer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FinalUntil |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Proposers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProposer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthProposer
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthProposer
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Proposers = append(m.Proposers, &ScheduledProposer{})
+			if err := m.Proposers[len(m.Proposers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Note", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProposer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthProposer
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthProposer
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Note = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipProposer(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthProposer
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipProposer(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	depth := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowProposer
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowProposer
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+		case 1:
+			iNdEx += 8
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowProposer
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthProposer
+			}
+			iNdEx += length
+		case 3:
+			depth++
+		case 4:
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupProposer
+			}
+			depth--
+		case 5:
+			iNdEx += 4
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthProposer
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
+	}
+	return 0, io.ErrUnexpectedEOF
+}
+
+var (
+	ErrInvalidLengthProposer        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowProposer          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupProposer = fmt.Errorf("proto: unexpected end of group")
+)
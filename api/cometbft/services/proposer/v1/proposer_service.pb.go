@@ -0,0 +1,131 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: cometbft/services/proposer/v1/proposer_service.proto
+
+package v1
+
+import (
+	context "context"
+	fmt "fmt"
+	grpc1 "github.com/cosmos/gogoproto/grpc"
+	proto "github.com/cosmos/gogoproto/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+func init() {
+	proto.RegisterFile("cometbft/services/proposer/v1/proposer_service.proto", fileDescriptor_a956515dce9bcad4)
+}
+
+var fileDescriptor_a956515dce9bcad4 = []byte{
+	// 191 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x32, 0x49, 0xce, 0xcf, 0x4d,
+	0x2d, 0x49, 0x4a, 0x2b, 0xd1, 0x2f, 0x4e, 0x2d, 0x2a, 0xcb, 0x4c, 0x4e, 0x2d, 0xd6, 0x2f, 0x28,
+	0xca, 0x2f, 0xc8, 0x2f, 0x4e, 0x2d, 0xd2, 0x2f, 0x33, 0x84, 0xb3, 0xe3, 0xa1, 0xb2, 0x7a, 0x05,
+	0x45, 0xf9, 0x25, 0xf9, 0x42, 0xb2, 0x30, 0x5d, 0x7a, 0x30, 0x5d, 0x7a, 0x30, 0x95, 0x7a, 0x65,
+	0x86, 0x52, 0x3a, 0xc4, 0x19, 0x0a, 0x31, 0xcc, 0x68, 0x01, 0x23, 0x17, 0x7f, 0x00, 0x54, 0x28,
+	0x18, 0xa2, 0x5e, 0xa8, 0x87, 0x91, 0x4b, 0xd8, 0x3d, 0xb5, 0x04, 0x2e, 0x9c, 0x9c, 0x91, 0x9a,
+	0x52, 0x9a, 0x93, 0x2a, 0x64, 0xa9, 0x87, 0xd7, 0x66, 0x3d, 0x2c, 0x7a, 0x82, 0x52, 0x0b, 0x4b,
+	0x53, 0x8b, 0x4b, 0xa4, 0xac, 0xc8, 0xd1, 0x5a, 0x5c, 0x90, 0x9f, 0x57, 0x9c, 0xea, 0x14, 0x71,
+	0xe2, 0x91, 0x1c, 0xe3, 0x85, 0x47, 0x72, 0x8c, 0x0f, 0x1e, 0xc9, 0x31, 0x4e, 0x78, 0x2c, 0xc7,
+	0x70, 0xe1, 0xb1, 0x1c, 0xc3, 0x8d, 0xc7, 0x72, 0x0c, 0x51, 0x76, 0xe9, 0x99, 0x25, 0x19, 0xa5,
+	0x49, 0x20, 0xb3, 0xf5, 0xe1, 0xbe, 0x86, 0x33, 0x12, 0x0b, 0x32, 0xf5, 0xf1, 0x86, 0x45, 0x12,
+	0x1b, 0x38, 0x0c, 0x8c, 0x01, 0x03, 0x00, 0xd5, 0xcb, 0x60, 0xbc, 0x88, 0x01, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// ProposerServiceClient is the client API for ProposerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ProposerServiceClient interface {
+	// GetProposerSchedule returns the expected proposers of round 0 of the
+	// heights following the latest block.
+	GetProposerSchedule(ctx context.Context, in *GetProposerScheduleRequest, opts ...grpc.CallOption) (*GetProposerScheduleResponse, error)
+}
+
+type proposerServiceClient struct {
+	cc grpc1.ClientConn
+}
+
+func NewProposerServiceClient(cc grpc1.ClientConn) ProposerServiceClient {
+	return &proposerServiceClient{cc}
+}
+
+func (c *proposerServiceClient) GetProposerSchedule(ctx context.Context, in *GetProposerScheduleRequest, opts ...grpc.CallOption) (*GetProposerScheduleResponse, error) {
+	out := new(GetProposerScheduleResponse)
+	err := c.cc.Invoke(ctx, "/cometbft.services.proposer.v1.ProposerService/GetProposerSchedule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ProposerServiceServer is the server API for ProposerService service.
+type ProposerServiceServer interface {
+	// GetProposerSchedule returns the expected proposers of round 0 of the
+	// heights following the latest block.
+	GetProposerSchedule(context.Context, *GetProposerScheduleRequest) (*GetProposerScheduleResponse, error)
+}
+
+// UnimplementedProposerServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedProposerServiceServer struct {
+}
+
+func (*UnimplementedProposerServiceServer) GetProposerSchedule(ctx context.Context, req *GetProposerScheduleRequest) (*GetProposerScheduleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProposerSchedule not implemented")
+}
+
+func RegisterProposerServiceServer(s grpc1.Server, srv ProposerServiceServer) {
+	s.RegisterService(&_ProposerService_serviceDesc, srv)
+}
+
+func _ProposerService_GetProposerSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProposerScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProposerServiceServer).GetProposerSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cometbft.services.proposer.v1.ProposerService/GetProposerSchedule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProposerServiceServer).GetProposerSchedule(ctx, req.(*GetProposerScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var ProposerService_serviceDesc = _ProposerService_serviceDesc
+var _ProposerService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "cometbft.services.proposer.v1.ProposerService",
+	HandlerType: (*ProposerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetProposerSchedule",
+			Handler:    _ProposerService_GetProposerSchedule_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "cometbft/services/proposer/v1/proposer_service.proto",
+}
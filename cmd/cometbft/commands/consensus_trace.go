@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cometbft/cometbft/internal/consensus"
+)
+
+var (
+	traceFromHeight int64
+	traceToHeight   int64
+	traceOutputFile string
+)
+
+func init() {
+	ConsensusTraceCmd.Flags().Int64Var(&traceFromHeight, "from", 0, "lowest height to export")
+	ConsensusTraceCmd.Flags().Int64Var(&traceToHeight, "to", 0, "highest height to export (0 means the latest)")
+	ConsensusTraceCmd.Flags().StringVar(&traceOutputFile, "output", "", "file to write the JSON to (default: stdout)")
+}
+
+// ConsensusTraceCmd exports the consensus timeline recorded when the
+// consensus.trace config option is enabled.
+var ConsensusTraceCmd = &cobra.Command{
+	Use:     "consensus-trace",
+	Aliases: []string{"consensus_trace"},
+	Short:   "Export the recorded consensus timeline as JSON",
+	Long: `
+Export the consensus timeline recorded by the node as a JSON array of events:
+proposals, block parts, prevotes and precommits (with their arrival time and
+sender), timeouts fired, locks and commits.
+
+Recording must be enabled with "trace = true" in the [consensus] section of
+the config. The trace is bounded by "trace_max_size", so only the most recent
+heights are available. The command can be used while the node is running.
+`,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		if traceToHeight > 0 && traceToHeight < traceFromHeight {
+			return errors.New("--to must not be lower than --from")
+		}
+
+		traceFile := config.Consensus.TraceFile()
+		if _, err := os.Stat(traceFile); err != nil {
+			return fmt.Errorf("no consensus trace found at %s: %w", traceFile, err)
+		}
+
+		events, err := consensus.ReadTrace(traceFile, traceFromHeight, traceToHeight)
+		if err != nil {
+			return fmt.Errorf("failed to read consensus trace: %w", err)
+		}
+
+		bz, err := json.MarshalIndent(events, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		if traceOutputFile == "" {
+			fmt.Println(string(bz))
+			return nil
+		}
+		return os.WriteFile(traceOutputFile, bz, 0o644)
+	},
+}
@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cometbft/cometbft/privval"
+)
+
+var (
+	thresholdT         int
+	thresholdN         int
+	thresholdOutputDir string
+)
+
+// GenThresholdKeysCmd allows the generation of a validator key split into
+// several shares, any t of which are able to sign on behalf of the validator.
+var GenThresholdKeysCmd = &cobra.Command{
+	Use:     "gen-threshold-keys",
+	Aliases: []string{"gen_threshold_keys"},
+	Short:   "Generate a BLS12-381 validator key split into t-of-n key shares",
+	Long: `Generate a BLS12-381 validator key split into n key shares, any t of which
+are required to sign.
+
+For every share, a directory "share<i>" containing a priv_validator_key.json
+and priv_validator_state.json is created; each is meant to be handed to a
+different operator who runs it behind a remote signer. The file
+threshold_key.json contains the public parts only and must be set as
+priv_validator_threshold_key_file on the validator node.
+
+The combined private key is never written to disk. Requires a binary built
+with the bls12381 build tag.`,
+	RunE: genThresholdKeys,
+}
+
+func init() {
+	GenThresholdKeysCmd.Flags().IntVar(&thresholdT, "threshold", 2,
+		"number of key shares required to sign")
+	GenThresholdKeysCmd.Flags().IntVar(&thresholdN, "shares", 3,
+		"total number of key shares")
+	GenThresholdKeysCmd.Flags().StringVar(&thresholdOutputDir, "o", "./threshold-keys",
+		"directory to store the key shares in")
+}
+
+func genThresholdKeys(*cobra.Command, []string) error {
+	key, shares, err := privval.GenThresholdKey(thresholdT, thresholdN)
+	if err != nil {
+		return fmt.Errorf("cannot generate threshold key: %w", err)
+	}
+
+	for i, share := range shares {
+		shareDir := filepath.Join(thresholdOutputDir, fmt.Sprintf("share%d", key.Shares[i].Index))
+		if err := os.MkdirAll(shareDir, nodeDirPerm); err != nil {
+			return err
+		}
+		pv := privval.NewFilePV(share,
+			filepath.Join(shareDir, "priv_validator_key.json"),
+			filepath.Join(shareDir, "priv_validator_state.json"))
+		pv.Save()
+	}
+
+	keyFile := filepath.Join(thresholdOutputDir, "threshold_key.json")
+	if err := key.Save(keyFile); err != nil {
+		return err
+	}
+
+	fmt.Printf("Generated %d-of-%d threshold key %v in %s\n",
+		key.Threshold, len(key.Shares), key.PubKey.Address(), thresholdOutputDir)
+	return nil
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/cometbft/cometbft/libs/log"
 	cmtmath "github.com/cometbft/cometbft/libs/math"
 	"github.com/cometbft/cometbft/light"
+	"github.com/cometbft/cometbft/light/journal"
 	lproxy "github.com/cometbft/cometbft/light/proxy"
 	lrpc "github.com/cometbft/cometbft/light/rpc"
 	dbs "github.com/cometbft/cometbft/light/store/db"
@@ -44,7 +45,10 @@ When /abci_query is called, the Merkle key path format is:
 	/{store name}/{key}
 
 Please verify with your application that this Merkle key format is used (true
-for applications built w/ Cosmos SDK).
+for applications built w/ Cosmos SDK). The proofs of the responses are verified
+with the proof operators given by --proof-ops, all the known ones by default:
+simple value proofs and ICS-23 proofs of IAVL trees, sparse merkle trees and
+simple merkle maps.
 `,
 	RunE: runProxy,
 	Args: cobra.ExactArgs(1),
@@ -68,6 +72,8 @@ var (
 
 	verbose bool
 
+	proofOpTypes []string
+
 	primaryKey   = []byte("primary")
 	witnessesKey = []byte("witnesses")
 )
@@ -94,6 +100,10 @@ func init() {
 	LightCmd.Flags().StringVar(&trustLevelStr, "trust-level", "1/3",
 		"trust level. Must be between 1/3 and 3/3",
 	)
+	LightCmd.Flags().StringSliceVar(&proofOpTypes, "proof-ops", nil,
+		"types of the proof operators used to verify /abci_query responses, comma-separated. "+
+			"Defaults to all of "+strings.Join(lrpc.ProofOpTypes(), ","),
+	)
 	LightCmd.Flags().BoolVar(&sequential, "sequential", false,
 		"sequential verification. Verify all headers sequentially as opposed to using skipping verification",
 	)
@@ -145,8 +155,19 @@ func runProxy(_ *cobra.Command, args []string) error {
 		return fmt.Errorf("can't parse trust level: %w", err)
 	}
 
+	prt, err := lrpc.NewProofRuntime(proofOpTypes...)
+	if err != nil {
+		return err
+	}
+
+	evidenceJournal, err := journal.New(evidenceJournalDir())
+	if err != nil {
+		return fmt.Errorf("can't open the evidence journal: %w", err)
+	}
+
 	options := []light.Option{
 		light.Logger(logger),
+		light.EvidenceJournal(evidenceJournal),
 		light.ConfirmationFunction(func(action string) bool {
 			fmt.Println(action)
 			scanner := bufio.NewScanner(os.Stdin)
@@ -214,10 +235,12 @@ func runProxy(_ *cobra.Command, args []string) error {
 		cfg.WriteTimeout = config.RPC.TimeoutBroadcastTxCommit + 1*time.Second
 	}
 
-	p, err := lproxy.NewProxy(c, listenAddr, primaryAddr, cfg, logger, lrpc.KeyPathFn(lrpc.DefaultMerkleKeyPathFn()))
+	p, err := lproxy.NewProxy(c, listenAddr, primaryAddr, cfg, logger,
+		lrpc.KeyPathFn(lrpc.DefaultMerkleKeyPathFn()), lrpc.ProofRuntime(prt))
 	if err != nil {
 		return err
 	}
+	p.Journal = evidenceJournal
 
 	// Stop upon receiving SIGTERM or CTRL-C.
 	cmtos.TrapSignal(logger, func() {
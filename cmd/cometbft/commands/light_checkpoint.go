@@ -0,0 +1,189 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	dbm "github.com/cometbft/cometbft-db"
+	cmtmath "github.com/cometbft/cometbft/libs/math"
+	"github.com/cometbft/cometbft/light/store"
+	"github.com/cometbft/cometbft/light/store/checkpoint"
+	dbs "github.com/cometbft/cometbft/light/store/db"
+	"github.com/cometbft/cometbft/types"
+	cmttime "github.com/cometbft/cometbft/types/time"
+)
+
+var (
+	checkpointFile    string
+	checkpointGenesis string
+	checkpointHash    []byte
+)
+
+func init() {
+	LightCheckpointCmd.PersistentFlags().StringVar(&home, "home-dir",
+		os.ExpandEnv(filepath.Join("$HOME", ".cometbft-light")), "specify the home directory")
+	LightCheckpointCmd.PersistentFlags().DurationVar(&trustingPeriod, "trusting-period", 168*time.Hour,
+		"trusting period that headers can be verified within. Should be significantly less than the unbonding period")
+	LightCheckpointCmd.PersistentFlags().StringVar(&trustLevelStr, "trust-level", "1/3",
+		"trust level. Must be between 1/3 and 3/3",
+	)
+
+	LightCheckpointExportCmd.Flags().StringVarP(&checkpointFile, "output", "o", "",
+		"file to write the checkpoint bundle to")
+	_ = LightCheckpointExportCmd.MarkFlagRequired("output")
+
+	LightCheckpointImportCmd.Flags().StringVarP(&checkpointFile, "file", "f", "",
+		"checkpoint bundle to import")
+	LightCheckpointImportCmd.Flags().BytesHexVar(&checkpointHash, "hash", []byte{},
+		"trusted hash of the first header of the bundle")
+	LightCheckpointImportCmd.Flags().StringVar(&checkpointGenesis, "genesis", "",
+		"genesis file, to trust a bundle starting at the initial height instead of a hash")
+	_ = LightCheckpointImportCmd.MarkFlagRequired("file")
+	LightCheckpointImportCmd.MarkFlagsMutuallyExclusive("hash", "genesis")
+	LightCheckpointImportCmd.MarkFlagsOneRequired("hash", "genesis")
+
+	LightCheckpointCmd.AddCommand(LightCheckpointExportCmd, LightCheckpointImportCmd)
+	LightCmd.AddCommand(LightCheckpointCmd)
+}
+
+// LightCheckpointCmd groups the commands handling checkpoint bundles.
+var LightCheckpointCmd = &cobra.Command{
+	Use:   "checkpoint",
+	Short: "Export or import the light client store as a checkpoint bundle",
+	Long: `
+A checkpoint bundle is a compact file of verified headers, keeping only the
+anchors needed to verify its last header from its first one with skipping
+verification. A fresh light client can import a bundle, verifying it from the
+hash of its first header or from the genesis validators, then start from its
+last header instead of syncing from a trusted hash.
+`,
+}
+
+// LightCheckpointExportCmd writes the light client store as a checkpoint
+// bundle.
+var LightCheckpointExportCmd = &cobra.Command{
+	Use:   "export [chainID]",
+	Short: "Write the verified headers of the light client store to a checkpoint bundle",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		trustLevel, err := cmtmath.ParseFraction(trustLevelStr)
+		if err != nil {
+			return fmt.Errorf("can't parse trust level: %w", err)
+		}
+		db, err := dbm.NewPebbleDB("light-client-db", home)
+		if err != nil {
+			return fmt.Errorf("can't open the db: %w", err)
+		}
+		defer db.Close()
+
+		blocks, err := loadLightBlocks(dbs.New(db, args[0]))
+		if err != nil {
+			return err
+		}
+		b, err := checkpoint.Compact(blocks, trustingPeriod, trustLevel)
+		if err != nil {
+			return fmt.Errorf("failed to compact headers: %w", err)
+		}
+		if err := checkpoint.WriteFile(checkpointFile, b); err != nil {
+			return err
+		}
+		fmt.Printf("Exported %d of %d headers, from height %d to %d\n",
+			len(b.LightBlocks), len(blocks), b.First().Height, b.Last().Height)
+		return nil
+	},
+}
+
+// LightCheckpointImportCmd verifies a checkpoint bundle and saves it in the
+// light client store.
+var LightCheckpointImportCmd = &cobra.Command{
+	Use:   "import [chainID]",
+	Short: "Verify a checkpoint bundle and save its headers in the light client store",
+	Long: `
+Verify a checkpoint bundle and save its headers in the light client store, so
+that the light client proxy can be started from them with:
+
+	cometbft light [chainID] -p [primary] -w [witnesses]
+
+The last header of the bundle must be within the trusting period.
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		chainID := args[0]
+		trustLevel, err := cmtmath.ParseFraction(trustLevelStr)
+		if err != nil {
+			return fmt.Errorf("can't parse trust level: %w", err)
+		}
+		b, err := checkpoint.ReadFile(checkpointFile)
+		if err != nil {
+			return err
+		}
+
+		if checkpointGenesis != "" {
+			genDoc, err := types.GenesisDocFromFile(checkpointGenesis)
+			if err != nil {
+				return err
+			}
+			if genDoc.ChainID != chainID {
+				return fmt.Errorf("expected chain ID %q, genesis has %q", chainID, genDoc.ChainID)
+			}
+			err = b.VerifyFromGenesis(genDoc, trustingPeriod, trustLevel)
+			if err != nil {
+				return fmt.Errorf("failed to verify bundle: %w", err)
+			}
+		} else if err := b.VerifyFromHash(chainID, checkpointHash, trustingPeriod, trustLevel); err != nil {
+			return fmt.Errorf("failed to verify bundle: %w", err)
+		}
+		if expiry := b.Last().Time.Add(trustingPeriod); !expiry.After(cmttime.Now()) {
+			return fmt.Errorf("last header %d expired at %v", b.Last().Height, expiry)
+		}
+
+		db, err := dbm.NewPebbleDB("light-client-db", home)
+		if err != nil {
+			return fmt.Errorf("can't open the db: %w", err)
+		}
+		defer db.Close()
+		s := dbs.New(db, chainID)
+		for _, lb := range b.LightBlocks {
+			if err := s.SaveLightBlock(lb); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("Imported %d headers, up to height %d\n", len(b.LightBlocks), b.Last().Height)
+		return nil
+	},
+}
+
+// loadLightBlocks returns the light blocks of the store, sorted by height.
+func loadLightBlocks(s store.Store) ([]*types.LightBlock, error) {
+	height, err := s.LastLightBlockHeight()
+	if err != nil {
+		return nil, err
+	}
+	if height <= 0 {
+		return nil, errors.New("empty light client store")
+	}
+	last, err := s.LightBlock(height)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks := []*types.LightBlock{last}
+	for {
+		lb, err := s.LightBlockBefore(blocks[len(blocks)-1].Height)
+		if errors.Is(err, store.ErrLightBlockNotFound) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, lb)
+	}
+	slices.Reverse(blocks)
+	return blocks, nil
+}
@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	cmtjson "github.com/cometbft/cometbft/libs/json"
+	"github.com/cometbft/cometbft/light/journal"
+)
+
+var (
+	evidenceOutput string
+	evidenceProto  bool
+)
+
+func init() {
+	LightEvidenceCmd.PersistentFlags().StringVar(&home, "home-dir",
+		os.ExpandEnv(filepath.Join("$HOME", ".cometbft-light")), "specify the home directory")
+
+	LightEvidenceExportCmd.Flags().StringVarP(&evidenceOutput, "output", "o", "",
+		"file to write the report to, instead of the standard output")
+	LightEvidenceExportCmd.Flags().BoolVar(&evidenceProto, "proto", false,
+		"write the protobuf encoding of the evidence instead of the JSON report")
+
+	LightEvidenceCmd.AddCommand(LightEvidenceListCmd, LightEvidenceExportCmd)
+	LightCmd.AddCommand(LightEvidenceCmd)
+}
+
+// evidenceJournalDir returns the directory of the evidence journal of the
+// light client.
+func evidenceJournalDir() string {
+	return filepath.Join(home, "evidence")
+}
+
+// LightEvidenceCmd groups the commands reading the evidence journal.
+var LightEvidenceCmd = &cobra.Command{
+	Use:   "evidence",
+	Short: "List or export the light client attacks detected by the light client",
+	Long: `
+When it detects a light client attack, the light client records a report of
+the attack in its evidence journal: the conflicting and trusted traces, the
+common height, the byzantine validators and the evidence submitted to the
+providers. A report can be exported as JSON, or the evidence as protobuf, to
+back a governance or slashing proposal.
+`,
+}
+
+// LightEvidenceListCmd lists the reports of the evidence journal.
+var LightEvidenceListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the attacks recorded in the evidence journal",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		j, err := journal.New(evidenceJournalDir())
+		if err != nil {
+			return err
+		}
+		reports, err := j.Reports()
+		if err != nil {
+			return err
+		}
+		for _, r := range reports {
+			cmd.Printf("%X %s %s common=%d conflicting=%d accused=%s\n",
+				r.Hash, r.DetectedAt.UTC().Format("2006-01-02T15:04:05Z"), r.AttackType,
+				r.CommonHeight, r.ConflictingHeight, r.Accused)
+		}
+		return nil
+	},
+}
+
+// LightEvidenceExportCmd writes a report of the evidence journal.
+var LightEvidenceExportCmd = &cobra.Command{
+	Use:   "export [hash]",
+	Short: "Export the report of an attack recorded in the evidence journal",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		hash, err := hex.DecodeString(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid hash: %w", err)
+		}
+		j, err := journal.New(evidenceJournalDir())
+		if err != nil {
+			return err
+		}
+		report, err := j.Report(hash)
+		if err != nil {
+			return err
+		}
+
+		bz := report.EvidenceProto
+		if !evidenceProto {
+			bz, err = cmtjson.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return err
+			}
+		}
+		if evidenceOutput == "" {
+			_, err = cmd.OutOrStdout().Write(bz)
+			return err
+		}
+		return os.WriteFile(evidenceOutput, bz, 0o600)
+	},
+}
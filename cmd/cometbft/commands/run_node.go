@@ -2,8 +2,12 @@ package commands
 
 import (
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	"github.com/cometbft/cometbft/crypto"
 	"github.com/cometbft/cometbft/crypto/ed25519"
@@ -99,7 +103,7 @@ func NewRunNodeCmd(nodeProvider nm.Provider) *cobra.Command {
 		Use:     "start",
 		Aliases: []string{"node", "run"},
 		Short:   "Run the CometBFT node",
-		RunE: func(_ *cobra.Command, _ []string) error {
+		RunE: func(cmd *cobra.Command, _ []string) error {
 			n, err := nodeProvider(config, logger, cliParams, genPrivKeyFromFlag)
 			if err != nil {
 				return fmt.Errorf("failed to create node: %w", err)
@@ -111,6 +115,8 @@ func NewRunNodeCmd(nodeProvider nm.Provider) *cobra.Command {
 
 			logger.Info("Started node", "nodeInfo", n.Switch().NodeInfo())
 
+			go reloadAdmissionPolicyOnSIGHUP(cmd, n)
+
 			// Stop upon receiving SIGTERM or CTRL-C.
 			cmtos.TrapSignal(logger, func() {
 				if n.IsRunning() {
@@ -128,3 +134,26 @@ func NewRunNodeCmd(nodeProvider nm.Provider) *cobra.Command {
 	AddNodeFlags(cmd)
 	return cmd
 }
+
+// reloadAdmissionPolicyOnSIGHUP reads the configuration file again whenever the
+// process receives SIGHUP, and replaces the p2p admission policy of the node.
+func reloadAdmissionPolicyOnSIGHUP(cmd *cobra.Command, n *nm.Node) {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, syscall.SIGHUP)
+	for range c {
+		if err := viper.ReadInConfig(); err != nil {
+			logger.Error("Failed to read the config file to reload the admission policy", "err", err)
+			continue
+		}
+		conf, err := ParseConfig(cmd)
+		if err != nil {
+			logger.Error("Failed to parse the config file to reload the admission policy", "err", err)
+			continue
+		}
+		if err := n.ReloadAdmissionPolicy(conf.P2P); err != nil {
+			logger.Error("Failed to reload the admission policy", "err", err)
+			continue
+		}
+		logger.Info("Reloaded the admission policy")
+	}
+}
@@ -0,0 +1,132 @@
+package commands
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	cfg "github.com/cometbft/cometbft/config"
+	nm "github.com/cometbft/cometbft/node"
+	"github.com/cometbft/cometbft/proxy"
+)
+
+var (
+	stateSyncArchiveFile string
+	stateSyncRPCServers  []string
+	stateSyncTrustHeight int64
+	stateSyncTrustHash   string
+	stateSyncLightBlocks string
+)
+
+func init() {
+	StateSyncRestoreCmd.Flags().StringVar(&stateSyncArchiveFile, "file", "",
+		"snapshot archive to restore: a directory, a tar or a tar.gz file")
+	StateSyncRestoreCmd.Flags().StringSliceVar(&stateSyncRPCServers, "rpc-servers", nil,
+		"RPC servers used by the light client to verify the snapshot (default: statesync.rpc_servers)")
+	StateSyncRestoreCmd.Flags().Int64Var(&stateSyncTrustHeight, "trust-height", 0,
+		"trusted height of the light client (default: statesync.trust_height)")
+	StateSyncRestoreCmd.Flags().StringVar(&stateSyncTrustHash, "trust-hash", "",
+		"hash of the block at the trusted height (default: statesync.trust_hash)")
+	StateSyncRestoreCmd.Flags().StringVar(&stateSyncLightBlocks, "light-blocks", "",
+		"light blocks file verifying the snapshot offline, instead of a light client using RPC servers")
+	_ = StateSyncRestoreCmd.MarkFlagRequired("file")
+
+	StateSyncCmd.AddCommand(StateSyncRestoreCmd)
+}
+
+// StateSyncCmd groups the commands used to state sync offline.
+var StateSyncCmd = &cobra.Command{
+	Use:   "statesync",
+	Short: "Offline state sync commands",
+}
+
+// StateSyncRestoreCmd restores a snapshot from a local archive.
+var StateSyncRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore a state sync snapshot from a local archive",
+	Long: `
+Restore a state sync snapshot downloaded out of band, instead of fetching it from
+peers, then bootstrap the state and block stores at the snapshot height. The node
+must be stopped and its stores must be empty. The application configured by
+proxy_app must be running and empty as well.
+
+The archive is a directory, or a tar file (optionally gzipped) of a directory,
+holding a metadata.json file and one file per chunk named after the chunk index
+(0, 1, ...). metadata.json describes the snapshot:
+
+  {"height": 1000, "format": 1, "chunks": 2, "hash": "<base64>", "metadata": "<base64>"}
+
+Snapshots taken by CometBFT itself (see statesync.snapshot_interval) are stored
+in this layout under the data/snapshots directory.
+
+The snapshot is verified by a light client, using the RPC servers and trusted
+height and hash of the [statesync] section of the config, unless set by flags.
+
+To restore the snapshot without any network access, pass a light blocks file
+with --light-blocks. The file holds the light blocks at the trusted height, at
+the snapshot height and at the two next heights, and the consensus parameters
+at the height after the snapshot:
+
+  {"light_blocks": [{"signed_header": ..., "validator_set": ...}, ...],
+   "consensus_params": ...}
+
+The light blocks are verified against the trusted hash, so the file can be
+assembled from the commit, validators and consensus_params RPC endpoints of any
+node. The trusting period must not be expired.
+
+The restoration stops on SIGINT or SIGTERM.
+`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		if len(stateSyncRPCServers) > 0 {
+			config.StateSync.RPCServers = stateSyncRPCServers
+		}
+		if stateSyncTrustHeight > 0 {
+			config.StateSync.TrustHeight = stateSyncTrustHeight
+		}
+		if stateSyncTrustHash != "" {
+			config.StateSync.TrustHash = stateSyncTrustHash
+		}
+		if err := validateStateSyncRestoreConfig(*config.StateSync); err != nil {
+			return fmt.Errorf("invalid state sync configuration: %w", err)
+		}
+
+		ctx, cancel := signal.NotifyContext(cmd.Context(), syscall.SIGTERM, syscall.SIGINT)
+		defer cancel()
+		err := nm.BootstrapStateFromArchive(ctx, config, cfg.DefaultDBProvider,
+			nm.DefaultGenesisDocProviderFunc(config),
+			proxy.DefaultClientCreator(config.ProxyApp, config.ABCI, config.DBDir()),
+			stateSyncArchiveFile, stateSyncLightBlocks)
+		if err != nil {
+			return fmt.Errorf("failed to restore snapshot: %w", err)
+		}
+		fmt.Println("Restored snapshot, the node can be started")
+		return nil
+	},
+}
+
+// validateStateSyncRestoreConfig validates the state sync configuration used
+// to verify the snapshot. The RPC servers are not needed with a light blocks
+// file.
+func validateStateSyncRestoreConfig(ssConfig cfg.StateSyncConfig) error {
+	ssConfig.Enable = true
+	if stateSyncLightBlocks == "" {
+		return ssConfig.ValidateBasic()
+	}
+	if ssConfig.TrustPeriod <= 0 {
+		return errors.New("trust_period is required")
+	}
+	if ssConfig.TrustHeight <= 0 {
+		return errors.New("trust_height is required")
+	}
+	if ssConfig.TrustHash == "" {
+		return errors.New("trust_hash is required")
+	}
+	if _, err := hex.DecodeString(ssConfig.TrustHash); err != nil {
+		return fmt.Errorf("invalid trust_hash: %w", err)
+	}
+	return nil
+}
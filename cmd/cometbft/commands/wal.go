@@ -0,0 +1,182 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cometbft/cometbft/internal/consensus"
+	cmtjson "github.com/cometbft/cometbft/libs/json"
+)
+
+var (
+	walTruncateHeight int64
+	walDumpFromHeight int64
+	walDumpToHeight   int64
+	walDumpOutputFile string
+)
+
+func init() {
+	WALTruncateCmd.Flags().Int64Var(&walTruncateHeight, "height", 0,
+		"truncate right after the end of this height instead of after the last valid record")
+	WALDumpCmd.Flags().Int64Var(&walDumpFromHeight, "from", 0, "lowest height to dump")
+	WALDumpCmd.Flags().Int64Var(&walDumpToHeight, "to", 0, "highest height to dump (0 means the latest)")
+	WALDumpCmd.Flags().StringVar(&walDumpOutputFile, "output", "", "file to write the JSON to (default: stdout)")
+
+	WALCmd.AddCommand(WALListCmd, WALVerifyCmd, WALTruncateCmd, WALDumpCmd)
+}
+
+// WALCmd groups the commands used to inspect and repair the consensus WAL.
+var WALCmd = &cobra.Command{
+	Use:   "wal",
+	Short: "Inspect, verify and repair the consensus write-ahead log",
+	Long: `
+Inspect, verify and repair the consensus write-ahead log (WAL).
+
+The node must be stopped before running "wal truncate". The other commands only
+read the WAL.
+`,
+}
+
+// WALListCmd lists the files of the WAL and the heights they end.
+var WALListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the WAL files and the heights ended in each of them",
+	RunE: func(_ *cobra.Command, _ []string) error {
+		info, err := consensus.InspectWAL(config.Consensus.WalFile())
+		if err != nil {
+			return fmt.Errorf("failed to read WAL: %w", err)
+		}
+		for _, f := range info.Files {
+			fmt.Printf("%s: %d bytes, %d records%s\n", f.Path, f.Size, f.Records, corruptionSuffix(f))
+			for _, eh := range info.EndHeights {
+				if eh.File == f.Index {
+					fmt.Printf("  ENDHEIGHT %d at offset %d\n", eh.Height, eh.Offset)
+				}
+			}
+		}
+		return nil
+	},
+}
+
+// WALVerifyCmd checks the CRC of every record of the WAL.
+var WALVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check every record of the WAL and report corrupted files",
+	RunE: func(_ *cobra.Command, _ []string) error {
+		info, err := consensus.InspectWAL(config.Consensus.WalFile())
+		if err != nil {
+			return fmt.Errorf("failed to read WAL: %w", err)
+		}
+		for _, f := range info.Files {
+			fmt.Printf("%s: %d records%s\n", f.Path, f.Records, corruptionSuffix(f))
+		}
+		if f := info.FirstCorrupted(); f != nil {
+			return fmt.Errorf("WAL is corrupted in %s at offset %d; "+
+				"run \"wal truncate\" to drop everything after the last valid record", f.Path, f.ValidSize)
+		}
+		fmt.Println("WAL is valid")
+		return nil
+	},
+}
+
+// WALTruncateCmd drops the end of the WAL.
+var WALTruncateCmd = &cobra.Command{
+	Use:   "truncate",
+	Short: "Drop the end of the WAL, after the last valid record or a given height",
+	Long: `
+Drop the end of the consensus WAL. By default, everything after the last valid
+record is removed, allowing a node that refuses to start because of a corrupted
+WAL to start again. With --height, everything written after the end of that
+height is removed; this is needed after rolling back the state, since the node
+refuses to replay a WAL that ends a height it has not committed.
+
+The node must be stopped. Removed records are lost, consider backing up the
+WAL directory first.
+`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		walFile := config.Consensus.WalFile()
+		info, err := consensus.InspectWAL(walFile)
+		if err != nil {
+			return fmt.Errorf("failed to read WAL: %w", err)
+		}
+
+		file, offset := info.LastValid()
+		if cmd.Flags().Changed("height") {
+			eh, ok := info.EndHeight(walTruncateHeight)
+			if !ok {
+				return fmt.Errorf("WAL has no end of height %d", walTruncateHeight)
+			}
+			if eh.File > file || (eh.File == file && eh.Offset > offset) {
+				return fmt.Errorf("end of height %d comes after a corrupted record", walTruncateHeight)
+			}
+			file, offset = eh.File, eh.Offset
+		} else if info.FirstCorrupted() == nil {
+			fmt.Println("WAL is valid, nothing to truncate")
+			return nil
+		}
+
+		if err := consensus.TruncateWAL(walFile, file, offset); err != nil {
+			return fmt.Errorf("failed to truncate WAL: %w", err)
+		}
+		fmt.Printf("Truncated WAL at offset %d of file %d\n", offset, file)
+		return nil
+	},
+}
+
+// WALDumpCmd writes the records of the selected heights as JSON.
+var WALDumpCmd = &cobra.Command{
+	Use:   "dump",
+	Short: "Dump the WAL records of the selected heights as JSON",
+	Long: `
+Dump the WAL records of the selected heights as JSON, one record per line, in
+the format of scripts/wal2json. The output can be turned back into a WAL with
+scripts/json2wal.
+`,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		if walDumpToHeight > 0 && walDumpToHeight < walDumpFromHeight {
+			return errors.New("--to must not be lower than --from")
+		}
+
+		var out io.Writer = os.Stdout
+		if walDumpOutputFile != "" {
+			if err := os.MkdirAll(filepath.Dir(walDumpOutputFile), 0o755); err != nil {
+				return err
+			}
+			f, err := os.Create(walDumpOutputFile)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			out = f
+		}
+
+		return consensus.WalkWAL(config.Consensus.WalFile(), func(height int64, msg *consensus.TimedWALMessage) error {
+			if height < walDumpFromHeight || (walDumpToHeight > 0 && height > walDumpToHeight) {
+				return nil
+			}
+			bz, err := cmtjson.Marshal(msg)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(out, "%s\n", bz); err != nil {
+				return err
+			}
+			if m, ok := msg.Msg.(consensus.EndHeightMessage); ok {
+				_, err = fmt.Fprintf(out, "ENDHEIGHT %d\n", m.Height)
+			}
+			return err
+		})
+	},
+}
+
+func corruptionSuffix(f consensus.WALFileInfo) string {
+	if f.Err == nil {
+		return ""
+	}
+	return fmt.Sprintf(", corrupted at offset %d: %v", f.ValidSize, f.Err)
+}
@@ -16,6 +16,7 @@ func main() {
 	rootCmd := cmd.RootCmd
 	rootCmd.AddCommand(
 		cmd.GenValidatorCmd,
+		cmd.GenThresholdKeysCmd,
 		cmd.InitFilesCmd,
 		cmd.LightCmd,
 		cmd.ResetAllCmd,
@@ -27,6 +28,9 @@ func main() {
 		cmd.GenNodeKeyCmd,
 		cmd.VersionCmd,
 		cmd.RollbackStateCmd,
+		cmd.ConsensusTraceCmd,
+		cmd.WALCmd,
+		cmd.StateSyncCmd,
 		cmd.CompactGoLevelDBCmd,
 		cmd.InspectCmd,
 		debug.DebugCmd,
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -51,6 +52,18 @@ const (
 
 	MempoolTypeFlood = "flood"
 	MempoolTypeNop   = "nop"
+
+	// AddrBookBackendFile saves the address book to a JSON file.
+	AddrBookBackendFile = "file"
+	// AddrBookBackendDB saves the address book to a database.
+	AddrBookBackendDB = "db"
+
+	// TopologyValidator is the role of a validator only connecting to its
+	// sentries.
+	TopologyValidator = "validator"
+	// TopologySentry is the role of a node shielding validators from the rest
+	// of the network.
+	TopologySentry = "sentry"
 )
 
 // NOTE: Most of the structs & relevant comments + the
@@ -206,6 +219,9 @@ func (cfg *Config) PossibleMisconfigurations() []string {
 	for _, elem := range cfg.StateSync.PossibleMisconfigurations() {
 		res = append(res, "[statesync] section: "+elem)
 	}
+	for _, elem := range cfg.P2P.PossibleMisconfigurations() {
+		res = append(res, "[p2p] section: "+elem)
+	}
 	return res
 }
 
@@ -268,6 +284,11 @@ type BaseConfig struct {
 	// connections from an external PrivValidator process
 	PrivValidatorListenAddr string `mapstructure:"priv_validator_laddr"`
 
+	// Path to the JSON file describing a threshold (t-of-n) validator key.
+	// If set, PrivValidatorListenAddr is a comma-separated list of addresses,
+	// one for each key share holder in the order of the shares in the file.
+	PrivValidatorThresholdKey string `mapstructure:"priv_validator_threshold_key_file"`
+
 	// A JSON file containing the private key to use for p2p authenticated encryption
 	NodeKey string `mapstructure:"node_key_file"`
 
@@ -321,6 +342,11 @@ func (cfg BaseConfig) PrivValidatorStateFile() string {
 	return rootify(cfg.PrivValidatorState, cfg.RootDir)
 }
 
+// PrivValidatorThresholdKeyFile returns the full path to the threshold key file.
+func (cfg BaseConfig) PrivValidatorThresholdKeyFile() string {
+	return rootify(cfg.PrivValidatorThresholdKey, cfg.RootDir)
+}
+
 // NodeKeyFile returns the full path to the node_key.json file.
 func (cfg BaseConfig) NodeKeyFile() string {
 	return rootify(cfg.NodeKey, cfg.RootDir)
@@ -346,6 +372,10 @@ func (cfg BaseConfig) ValidateBasic() error {
 		return errors.New("unknown log_format (must be 'plain' or 'json')")
 	}
 
+	if cfg.PrivValidatorThresholdKey != "" && cfg.PrivValidatorListenAddr == "" {
+		return errors.New("priv_validator_threshold_key_file requires priv_validator_laddr to be set")
+	}
+
 	return cfg.validateProxyApp()
 }
 
@@ -624,6 +654,14 @@ type GRPCConfig struct {
 	// If no height is provided, the block results of the latest height are returned
 	BlockResultsService *GRPCBlockResultsServiceConfig `mapstructure:"block_results_service"`
 
+	// The gRPC proposer service provides the expected proposers of the
+	// upcoming heights
+	ProposerService *GRPCProposerServiceConfig `mapstructure:"proposer_service"`
+
+	// The gRPC light block service provides the light blocks used by light
+	// clients, and accepts the evidence they report
+	LightBlockService *GRPCLightBlockServiceConfig `mapstructure:"light_block_service"`
+
 	// The "privileged" section provides configuration for the gRPC server
 	// dedicated to privileged clients.
 	Privileged *GRPCPrivilegedConfig `mapstructure:"privileged"`
@@ -635,6 +673,8 @@ func DefaultGRPCConfig() *GRPCConfig {
 		VersionService:      DefaultGRPCVersionServiceConfig(),
 		BlockService:        DefaultGRPCBlockServiceConfig(),
 		BlockResultsService: DefaultGRPCBlockResultsServiceConfig(),
+		ProposerService:     DefaultGRPCProposerServiceConfig(),
+		LightBlockService:   DefaultGRPCLightBlockServiceConfig(),
 		Privileged:          DefaultGRPCPrivilegedConfig(),
 	}
 }
@@ -645,6 +685,8 @@ func TestGRPCConfig() *GRPCConfig {
 		VersionService:      TestGRPCVersionServiceConfig(),
 		BlockService:        TestGRPCBlockServiceConfig(),
 		BlockResultsService: DefaultGRPCBlockResultsServiceConfig(),
+		ProposerService:     DefaultGRPCProposerServiceConfig(),
+		LightBlockService:   DefaultGRPCLightBlockServiceConfig(),
 		Privileged:          TestGRPCPrivilegedConfig(),
 	}
 }
@@ -704,6 +746,26 @@ func TestGRPCBlockServiceConfig() *GRPCBlockServiceConfig {
 	}
 }
 
+type GRPCProposerServiceConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+func DefaultGRPCProposerServiceConfig() *GRPCProposerServiceConfig {
+	return &GRPCProposerServiceConfig{
+		Enabled: true,
+	}
+}
+
+type GRPCLightBlockServiceConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+func DefaultGRPCLightBlockServiceConfig() *GRPCLightBlockServiceConfig {
+	return &GRPCLightBlockServiceConfig{
+		Enabled: true,
+	}
+}
+
 // -----------------------------------------------------------------------------
 // GRPCPrivilegedConfig
 
@@ -759,11 +821,16 @@ type P2PConfig struct { //nolint: maligned
 	// Address to listen for incoming connections
 	ListenAddress string `mapstructure:"laddr"`
 
+	// Address to listen for incoming connections over QUIC, alongside
+	// ListenAddress. If empty, the node does not accept QUIC connections.
+	QUICListenAddress string `mapstructure:"quic_laddr"`
+
 	// Address to advertise to peers for them to dial
 	ExternalAddress string `mapstructure:"external_address"`
 
 	// Comma separated list of seed nodes to connect to
 	// We only use these if we can’t connect to peers in the addrbook
+	// A seed of the form dns://<name> lists seeds in the TXT records of <name>
 	Seeds string `mapstructure:"seeds"`
 
 	// Comma separated list of nodes to keep persistent connections to
@@ -772,6 +839,11 @@ type P2PConfig struct { //nolint: maligned
 	// Path to address book
 	AddrBook string `mapstructure:"addr_book_file"`
 
+	// Backend saving the address book: "file" for the JSON file at AddrBook,
+	// or "db" for a database using the node's db_backend, which only saves
+	// the addresses that changed and suits large books, e.g. of seed nodes.
+	AddrBookBackend string `mapstructure:"addr_book_backend"`
+
 	// Set true for strict address routability rules
 	// Set false for private or local networks
 	AddrBookStrict bool `mapstructure:"addr_book_strict"`
@@ -800,6 +872,17 @@ type P2PConfig struct { //nolint: maligned
 	// Rate at which packets can be received, in bytes/second
 	RecvRate int64 `mapstructure:"recv_rate"`
 
+	// Compression algorithms ("zstd" or "snappy"), in order of preference,
+	// compressing block parts, blocksync responses and mempool transactions
+	// sent to peers supporting one of them. If empty, messages are sent
+	// uncompressed.
+	Compression []string `mapstructure:"compression"`
+
+	// Rates of the messages of given channels, in bytes/second, as
+	// "<channel ID>:<send rate>:<recv rate>" entries, e.g. "0x30:102400:102400".
+	// A rate of 0 leaves the channel limited only by send_rate and recv_rate.
+	ChannelRates []string `mapstructure:"channel_rates"`
+
 	// Set true to enable the peer-exchange reactor
 	PexReactor bool `mapstructure:"pex"`
 
@@ -816,10 +899,62 @@ type P2PConfig struct { //nolint: maligned
 	// Toggle to disable guard against peers connecting from the same ip.
 	AllowDuplicateIP bool `mapstructure:"allow_duplicate_ip"`
 
+	// Role of the node in a sentry topology: "validator" for a validator
+	// only connecting to its sentries, "sentry" for a node shielding
+	// validators from the rest of the network, or empty for no topology.
+	Topology string `mapstructure:"topology"`
+
+	// Comma separated list of nodes (id@host:port) on the other side of the
+	// topology: the sentries of a validator, or the validators shielded by a
+	// sentry.
+	TopologyPeers string `mapstructure:"topology_peers"`
+
+	// Admission policy, enforced on the connections the node accepts and
+	// dials. It is reloaded when the node receives SIGHUP.
+	//
+	// CIDRs of the IPs of the peers. If not empty, only the IPs in
+	// AdmissionAllowCIDRs are accepted. AdmissionDenyCIDRs take precedence.
+	AdmissionAllowCIDRs []string `mapstructure:"admission_allow_cidrs"`
+	AdmissionDenyCIDRs  []string `mapstructure:"admission_deny_cidrs"`
+
+	// Minimum block and app protocol versions of the peers.
+	AdmissionMinBlockVersion uint64 `mapstructure:"admission_min_block_version"`
+	AdmissionMinAppVersion   uint64 `mapstructure:"admission_min_app_version"`
+
+	// Channels the peers must support, e.g. "0x20".
+	AdmissionRequiredChannels []string `mapstructure:"admission_required_channels"`
+
+	// Monikers of refused peers.
+	AdmissionDenyMonikers []string `mapstructure:"admission_deny_monikers"`
+
+	// Maximum number of connections with the IPs of a subnet, or 0 for no
+	// limit. The subnets are the IPv4 and IPv6 prefixes of the given lengths.
+	AdmissionMaxConnsPerSubnet   int `mapstructure:"admission_max_conns_per_subnet"`
+	AdmissionSubnetPrefixLenIPv4 int `mapstructure:"admission_subnet_prefix_len_ipv4"`
+	AdmissionSubnetPrefixLenIPv6 int `mapstructure:"admission_subnet_prefix_len_ipv6"`
+
+	// Time after which the score of a peer is halved, e.g. a peer with a
+	// score of -100 has a score of -50 one half-life later. If zero, scores
+	// don't decay.
+	PeerScoreHalfLife time.Duration `mapstructure:"peer_score_half_life"`
+
+	// Score below which a peer is disconnected.
+	PeerScoreDisconnectThreshold float64 `mapstructure:"peer_score_disconnect_threshold"`
+
+	// Score below which a peer is disconnected and banned for PeerScoreBanTime.
+	PeerScoreBanThreshold float64 `mapstructure:"peer_score_ban_threshold"`
+
+	// Time for which a peer whose score fell below PeerScoreBanThreshold is banned.
+	PeerScoreBanTime time.Duration `mapstructure:"peer_score_ban_time"`
+
 	// Peer connection configuration.
 	HandshakeTimeout time.Duration `mapstructure:"handshake_timeout"`
 	DialTimeout      time.Duration `mapstructure:"dial_timeout"`
 
+	// Offer a Noise handshake instead of the SecretConnection one to the
+	// peers of TCP connections.
+	NoiseHandshake bool `mapstructure:"noise_handshake"`
+
 	// Testing params.
 	// Force dial to fail
 	TestDialFail bool `mapstructure:"test_dial_fail"`
@@ -834,6 +969,7 @@ func DefaultP2PConfig() *P2PConfig {
 		ListenAddress:                "tcp://0.0.0.0:26656",
 		ExternalAddress:              "",
 		AddrBook:                     defaultAddrBookPath,
+		AddrBookBackend:              AddrBookBackendFile,
 		AddrBookStrict:               true,
 		MaxNumInboundPeers:           40,
 		MaxNumOutboundPeers:          10,
@@ -845,8 +981,17 @@ func DefaultP2PConfig() *P2PConfig {
 		PexReactor:                   true,
 		SeedMode:                     false,
 		AllowDuplicateIP:             false,
+		Topology:                     "",
+		TopologyPeers:                "",
+		AdmissionSubnetPrefixLenIPv4: 24,
+		AdmissionSubnetPrefixLenIPv6: 48,
+		PeerScoreHalfLife:            time.Hour,
+		PeerScoreDisconnectThreshold: -100,
+		PeerScoreBanThreshold:        -200,
+		PeerScoreBanTime:             24 * time.Hour,
 		HandshakeTimeout:             20 * time.Second,
 		DialTimeout:                  3 * time.Second,
+		NoiseHandshake:               false,
 		TestDialFail:                 false,
 		TestFuzz:                     false,
 		TestFuzzConfig:               DefaultFuzzConnConfig(),
@@ -869,6 +1014,12 @@ func (cfg *P2PConfig) AddrBookFile() string {
 // ValidateBasic performs basic validation (checking param bounds, etc.) and
 // returns an error if any check fails.
 func (cfg *P2PConfig) ValidateBasic() error {
+	switch cfg.AddrBookBackend {
+	case AddrBookBackendFile, AddrBookBackendDB:
+	default:
+		return fmt.Errorf("unknown addr_book_backend %q, must be %q or %q",
+			cfg.AddrBookBackend, AddrBookBackendFile, AddrBookBackendDB)
+	}
 	if cfg.MaxNumInboundPeers < 0 {
 		return cmterrors.ErrNegativeField{Field: "max_num_inbound_peers"}
 	}
@@ -890,9 +1041,202 @@ func (cfg *P2PConfig) ValidateBasic() error {
 	if cfg.RecvRate < 0 {
 		return cmterrors.ErrNegativeField{Field: "recv_rate"}
 	}
+	for i, compression := range cfg.Compression {
+		switch compression {
+		case "zstd", "snappy":
+		default:
+			return fmt.Errorf("compression: unsupported algorithm %q", compression)
+		}
+		for _, other := range cfg.Compression[:i] {
+			if compression == other {
+				return fmt.Errorf("compression: duplicate algorithm %q", compression)
+			}
+		}
+	}
+	if _, err := cfg.ChannelRateLimits(); err != nil {
+		return err
+	}
+	if cfg.PeerScoreHalfLife < 0 {
+		return cmterrors.ErrNegativeField{Field: "peer_score_half_life"}
+	}
+	if cfg.PeerScoreDisconnectThreshold >= 0 {
+		return errors.New("peer_score_disconnect_threshold must be negative")
+	}
+	if cfg.PeerScoreBanThreshold > cfg.PeerScoreDisconnectThreshold {
+		return errors.New("peer_score_ban_threshold can't be greater than peer_score_disconnect_threshold")
+	}
+	if cfg.PeerScoreBanTime < 0 {
+		return cmterrors.ErrNegativeField{Field: "peer_score_ban_time"}
+	}
+	if cfg.QUICListenAddress != "" && !strings.HasPrefix(cfg.QUICListenAddress, "quic://") {
+		return fmt.Errorf("quic_laddr must start with quic://, got %q", cfg.QUICListenAddress)
+	}
+	switch cfg.Topology {
+	case "":
+	case TopologyValidator, TopologySentry:
+		if len(cfg.TopologyPeerIDs()) == 0 {
+			return fmt.Errorf("topology %q requires topology_peers", cfg.Topology)
+		}
+		if cfg.Topology == TopologyValidator && cfg.SeedMode {
+			return errors.New("seed_mode can't be enabled with topology \"validator\"")
+		}
+	default:
+		return fmt.Errorf("unknown topology %q, must be %q or %q", cfg.Topology, TopologyValidator, TopologySentry)
+	}
+	if _, _, err := cfg.AdmissionCIDRs(); err != nil {
+		return err
+	}
+	if _, err := cfg.AdmissionChannels(); err != nil {
+		return err
+	}
+	if cfg.AdmissionMaxConnsPerSubnet < 0 {
+		return cmterrors.ErrNegativeField{Field: "admission_max_conns_per_subnet"}
+	}
+	if cfg.AdmissionSubnetPrefixLenIPv4 < 0 || cfg.AdmissionSubnetPrefixLenIPv4 > 32 {
+		return fmt.Errorf("admission_subnet_prefix_len_ipv4 must be between 0 and 32, got %d", cfg.AdmissionSubnetPrefixLenIPv4)
+	}
+	if cfg.AdmissionSubnetPrefixLenIPv6 < 0 || cfg.AdmissionSubnetPrefixLenIPv6 > 128 {
+		return fmt.Errorf("admission_subnet_prefix_len_ipv6 must be between 0 and 128, got %d", cfg.AdmissionSubnetPrefixLenIPv6)
+	}
 	return nil
 }
 
+// AdmissionCIDRs returns the parsed AdmissionAllowCIDRs and AdmissionDenyCIDRs.
+func (cfg *P2PConfig) AdmissionCIDRs() (allow, deny []*net.IPNet, err error) {
+	allow, err = parseCIDRs(cfg.AdmissionAllowCIDRs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("admission_allow_cidrs: %w", err)
+	}
+	deny, err = parseCIDRs(cfg.AdmissionDenyCIDRs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("admission_deny_cidrs: %w", err)
+	}
+	return allow, deny, nil
+}
+
+// AdmissionChannels returns the parsed AdmissionRequiredChannels.
+func (cfg *P2PConfig) AdmissionChannels() ([]byte, error) {
+	chIDs := make([]byte, 0, len(cfg.AdmissionRequiredChannels))
+	for _, ch := range cfg.AdmissionRequiredChannels {
+		chID, err := strconv.ParseUint(ch, 0, 8)
+		if err != nil {
+			return nil, fmt.Errorf("admission_required_channels: invalid channel %q", ch)
+		}
+		chIDs = append(chIDs, byte(chID))
+	}
+	return chIDs, nil
+}
+
+// ChannelRate is the rate of the messages of a channel, in bytes/second.
+// A rate of 0 means unlimited.
+type ChannelRate struct {
+	SendRate int64
+	RecvRate int64
+}
+
+// ChannelRateLimits returns the parsed ChannelRates, by channel ID.
+func (cfg *P2PConfig) ChannelRateLimits() (map[byte]ChannelRate, error) {
+	rates := make(map[byte]ChannelRate, len(cfg.ChannelRates))
+	for _, entry := range cfg.ChannelRates {
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("channel_rates: invalid entry %q, must be \"<channel ID>:<send rate>:<recv rate>\"", entry)
+		}
+		chID, err := strconv.ParseUint(parts[0], 0, 8)
+		if err != nil {
+			return nil, fmt.Errorf("channel_rates: invalid channel %q", parts[0])
+		}
+		if _, ok := rates[byte(chID)]; ok {
+			return nil, fmt.Errorf("channel_rates: duplicate channel %q", parts[0])
+		}
+		sendRate, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil || sendRate < 0 {
+			return nil, fmt.Errorf("channel_rates: invalid send rate %q", parts[1])
+		}
+		recvRate, err := strconv.ParseInt(parts[2], 10, 64)
+		if err != nil || recvRate < 0 {
+			return nil, fmt.Errorf("channel_rates: invalid recv rate %q", parts[2])
+		}
+		rates[byte(chID)] = ChannelRate{SendRate: sendRate, RecvRate: recvRate}
+	}
+	return rates, nil
+}
+
+func parseCIDRs(cidrs []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, nil
+}
+
+// TopologyPeerIDs returns the IDs of the nodes in TopologyPeers.
+func (cfg *P2PConfig) TopologyPeerIDs() []string {
+	return peerIDs(cfg.TopologyPeers)
+}
+
+// PossibleMisconfigurations returns a list of possible conflicting entries that
+// may lead to unexpected behavior, such as a validator in a sentry topology
+// exposed to the rest of the network.
+func (cfg *P2PConfig) PossibleMisconfigurations() []string {
+	res := []string{}
+	switch cfg.Topology {
+	case TopologyValidator:
+		sentries := make(map[string]struct{})
+		for _, id := range cfg.TopologyPeerIDs() {
+			sentries[id] = struct{}{}
+		}
+		if cfg.PexReactor {
+			res = append(res, "pex is enabled but is disabled by topology = \"validator\"")
+		}
+		if cfg.Seeds != "" {
+			res = append(res, "seeds are ignored with topology = \"validator\"")
+		}
+		for _, id := range peerIDs(cfg.PersistentPeers) {
+			if _, ok := sentries[id]; !ok {
+				res = append(res, fmt.Sprintf("persistent peer %s is not a sentry and is ignored", id))
+			}
+		}
+		for _, id := range peerIDs(cfg.UnconditionalPeerIDs) {
+			if _, ok := sentries[id]; !ok {
+				res = append(res, fmt.Sprintf("unconditional peer %s is not a sentry and is refused", id))
+			}
+		}
+		if cfg.ExternalAddress != "" {
+			res = append(res, "external_address is set, advertising the validator address to its sentries")
+		}
+		if strings.Contains(cfg.ListenAddress, "0.0.0.0") || strings.Contains(cfg.QUICListenAddress, "0.0.0.0") {
+			res = append(res, "the validator listens on all interfaces; consider listening on the private network of its sentries only")
+		}
+	case TopologySentry:
+		for _, id := range cfg.TopologyPeerIDs() {
+			for _, seed := range peerIDs(cfg.Seeds) {
+				if id == seed {
+					res = append(res, fmt.Sprintf("validator %s is listed in seeds", id))
+				}
+			}
+		}
+	}
+	return res
+}
+
+// peerIDs returns the IDs in a comma separated list of IDs or id@host:port
+// addresses.
+func peerIDs(peers string) []string {
+	var ids []string
+	for _, peer := range strings.Split(peers, ",") {
+		id, _, _ := strings.Cut(strings.TrimSpace(peer), "@")
+		if id != "" {
+			ids = append(ids, strings.ToLower(id))
+		}
+	}
+	return ids
+}
+
 // FuzzConnConfig is a FuzzedConnection configuration.
 type FuzzConnConfig struct {
 	Mode         int
@@ -1072,6 +1416,14 @@ type StateSyncConfig struct {
 	DiscoveryTime       time.Duration `mapstructure:"discovery_time"`
 	ChunkRequestTimeout time.Duration `mapstructure:"chunk_request_timeout"`
 	ChunkFetchers       int32         `mapstructure:"chunk_fetchers"`
+
+	// If not 0, the node snapshots its own state and the state exported by
+	// the application every SnapshotInterval blocks, and serves the snapshots
+	// to peers. Requires an in-process application implementing
+	// abci.StateExporter.
+	SnapshotInterval uint64 `mapstructure:"snapshot_interval"`
+	// Number of snapshots to keep, older ones are pruned.
+	SnapshotKeepRecent uint32 `mapstructure:"snapshot_keep_recent"`
 }
 
 func (cfg *StateSyncConfig) TrustHashBytes() []byte {
@@ -1090,6 +1442,7 @@ func DefaultStateSyncConfig() *StateSyncConfig {
 		DiscoveryTime:       15 * time.Second,
 		ChunkRequestTimeout: 10 * time.Second,
 		ChunkFetchers:       4,
+		SnapshotKeepRecent:  2,
 	}
 }
 
@@ -1145,6 +1498,10 @@ func (cfg *StateSyncConfig) ValidateBasic() error {
 		}
 	}
 
+	if cfg.SnapshotInterval > 0 && cfg.SnapshotKeepRecent == 0 {
+		return cmterrors.ErrNegativeOrZeroField{Field: "snapshot_keep_recent"}
+	}
+
 	return nil
 }
 
@@ -1163,12 +1520,22 @@ func (cfg *StateSyncConfig) PossibleMisconfigurations() []string {
 // BlockSyncConfig (formerly known as FastSync) defines the configuration for the CometBFT block sync service.
 type BlockSyncConfig struct {
 	Version string `mapstructure:"version"`
+
+	// If true, the blocks received from peers are checked against the headers
+	// verified by a light client anchored at TrustHeight and TrustHash.
+	VerifyWithLightClient bool `mapstructure:"verify_with_light_client"`
+	// RPC servers used as primary and witnesses by the light client.
+	RPCServers  []string      `mapstructure:"rpc_servers"`
+	TrustPeriod time.Duration `mapstructure:"trust_period"`
+	TrustHeight int64         `mapstructure:"trust_height"`
+	TrustHash   string        `mapstructure:"trust_hash"`
 }
 
 // DefaultBlockSyncConfig returns a default configuration for the block sync service.
 func DefaultBlockSyncConfig() *BlockSyncConfig {
 	return &BlockSyncConfig{
-		Version: "v0",
+		Version:     "v0",
+		TrustPeriod: 168 * time.Hour,
 	}
 }
 
@@ -1181,12 +1548,55 @@ func TestBlockSyncConfig() *BlockSyncConfig {
 func (cfg *BlockSyncConfig) ValidateBasic() error {
 	switch cfg.Version {
 	case v0:
-		return nil
 	case v1, v2:
 		return ErrDeprecatedBlocksyncVersion{Version: cfg.Version, Allowed: []string{v0}}
 	default:
 		return ErrUnknownBlocksyncVersion{cfg.Version}
 	}
+
+	if cfg.VerifyWithLightClient {
+		if len(cfg.RPCServers) == 0 {
+			return cmterrors.ErrRequiredField{Field: "rpc_servers"}
+		}
+
+		if len(cfg.RPCServers) < 2 {
+			return ErrNotEnoughRPCServers
+		}
+
+		for _, server := range cfg.RPCServers {
+			if len(server) == 0 {
+				return ErrEmptyRPCServerEntry
+			}
+		}
+
+		if cfg.TrustPeriod <= 0 {
+			return cmterrors.ErrRequiredField{Field: "trust_period"}
+		}
+
+		if cfg.TrustHeight <= 0 {
+			return cmterrors.ErrRequiredField{Field: "trust_height"}
+		}
+
+		if len(cfg.TrustHash) == 0 {
+			return cmterrors.ErrRequiredField{Field: "trust_hash"}
+		}
+
+		if _, err := hex.DecodeString(cfg.TrustHash); err != nil {
+			return fmt.Errorf("invalid trust_hash: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// TrustHashBytes returns the hash of the trusted block as a byte slice.
+func (cfg *BlockSyncConfig) TrustHashBytes() []byte {
+	// validated in ValidateBasic, so we can safely panic here
+	bytes, err := hex.DecodeString(cfg.TrustHash)
+	if err != nil {
+		panic(err)
+	}
+	return bytes
 }
 
 // -----------------------------------------------------------------------------
@@ -1210,6 +1620,17 @@ type ConsensusConfig struct {
 	// Deprecated: use `next_block_delay` in the ABCI application's `FinalizeBlockResponse`.
 	TimeoutCommit time.Duration `mapstructure:"timeout_commit"`
 
+	// If true, derive timeout_propose and timeout_vote from the recently
+	// observed proposal and vote delays instead of using the static values.
+	// The deltas remain static.
+	AdaptiveTimeouts bool `mapstructure:"adaptive_timeouts"`
+	// Bounds of timeout_propose when adaptive timeouts are enabled
+	TimeoutProposeMin time.Duration `mapstructure:"timeout_propose_min"`
+	TimeoutProposeMax time.Duration `mapstructure:"timeout_propose_max"`
+	// Bounds of timeout_vote when adaptive timeouts are enabled
+	TimeoutVoteMin time.Duration `mapstructure:"timeout_vote_min"`
+	TimeoutVoteMax time.Duration `mapstructure:"timeout_vote_max"`
+
 	// EmptyBlocks mode and possible interval between empty blocks
 	CreateEmptyBlocks         bool          `mapstructure:"create_empty_blocks"`
 	CreateEmptyBlocksInterval time.Duration `mapstructure:"create_empty_blocks_interval"`
@@ -1220,6 +1641,14 @@ type ConsensusConfig struct {
 	PeerGossipIntraloopSleepDuration time.Duration `mapstructure:"peer_gossip_intraloop_sleep_duration"` // upper bound on randomly selected values
 
 	DoubleSignCheckHeight int64 `mapstructure:"double_sign_check_height"`
+
+	// If true, record a timeline of consensus events (proposals, block parts,
+	// votes, timeouts, locks) to TracePath for post-mortem debugging.
+	Trace     bool   `mapstructure:"trace"`
+	TracePath string `mapstructure:"trace_file"`
+	// Maximum total size of the trace files in bytes. Once exceeded, the
+	// oldest entries are removed.
+	TraceMaxSize int64 `mapstructure:"trace_max_size"`
 }
 
 // DefaultConsensusConfig returns a default configuration for the consensus service.
@@ -1231,12 +1660,20 @@ func DefaultConsensusConfig() *ConsensusConfig {
 		TimeoutVote:                      1000 * time.Millisecond,
 		TimeoutVoteDelta:                 500 * time.Millisecond,
 		TimeoutCommit:                    1000 * time.Millisecond,
+		AdaptiveTimeouts:                 false,
+		TimeoutProposeMin:                1000 * time.Millisecond,
+		TimeoutProposeMax:                10000 * time.Millisecond,
+		TimeoutVoteMin:                   500 * time.Millisecond,
+		TimeoutVoteMax:                   5000 * time.Millisecond,
 		CreateEmptyBlocks:                true,
 		CreateEmptyBlocksInterval:        0 * time.Second,
 		PeerGossipSleepDuration:          100 * time.Millisecond,
 		PeerQueryMaj23SleepDuration:      2000 * time.Millisecond,
 		PeerGossipIntraloopSleepDuration: 0 * time.Second,
 		DoubleSignCheckHeight:            int64(0),
+		Trace:                            false,
+		TracePath:                        filepath.Join(DefaultDataDir, "cs.trace", "trace"),
+		TraceMaxSize:                     100 * 1024 * 1024, // 100MB
 	}
 }
 
@@ -1299,6 +1736,11 @@ func (cfg *ConsensusConfig) SetWalFile(walFile string) {
 	cfg.walFile = walFile
 }
 
+// TraceFile returns the full path to the consensus trace file.
+func (cfg *ConsensusConfig) TraceFile() string {
+	return rootify(cfg.TracePath, cfg.RootDir)
+}
+
 // ValidateBasic performs basic validation (checking param bounds, etc.) and
 // returns an error if any check fails.
 func (cfg *ConsensusConfig) ValidateBasic() error {
@@ -1317,6 +1759,20 @@ func (cfg *ConsensusConfig) ValidateBasic() error {
 	if cfg.TimeoutCommit < 0 {
 		return cmterrors.ErrNegativeField{Field: "timeout_commit"}
 	}
+	if cfg.AdaptiveTimeouts {
+		if cfg.TimeoutProposeMin <= 0 {
+			return cmterrors.ErrNegativeOrZeroField{Field: "timeout_propose_min"}
+		}
+		if cfg.TimeoutProposeMax < cfg.TimeoutProposeMin {
+			return errors.New("timeout_propose_max can't be less than timeout_propose_min")
+		}
+		if cfg.TimeoutVoteMin <= 0 {
+			return cmterrors.ErrNegativeOrZeroField{Field: "timeout_vote_min"}
+		}
+		if cfg.TimeoutVoteMax < cfg.TimeoutVoteMin {
+			return errors.New("timeout_vote_max can't be less than timeout_vote_min")
+		}
+	}
 	if cfg.CreateEmptyBlocksInterval < 0 {
 		return cmterrors.ErrNegativeField{Field: "create_empty_blocks_interval"}
 	}
@@ -1329,6 +1785,12 @@ func (cfg *ConsensusConfig) ValidateBasic() error {
 	if cfg.DoubleSignCheckHeight < 0 {
 		return cmterrors.ErrNegativeField{Field: "double_sign_check_height"}
 	}
+	if cfg.TraceMaxSize < 0 {
+		return cmterrors.ErrNegativeField{Field: "trace_max_size"}
+	}
+	if cfg.Trace && cfg.TraceMaxSize == 0 {
+		return cmterrors.ErrNegativeOrZeroField{Field: "trace_max_size"}
+	}
 	return nil
 }
 
@@ -62,6 +62,13 @@ priv_validator_state_file = "{{ js .BaseConfig.PrivValidatorState }}"
 # connections from an external PrivValidator process
 priv_validator_laddr = "{{ .BaseConfig.PrivValidatorListenAddr }}"
 
+# Path to the JSON file describing a threshold (t-of-n) validator key, as
+# generated by "cometbft gen-threshold-keys". If set, priv_validator_laddr must
+# be a comma-separated list of addresses, one for each key share holder in the
+# order of the shares in the key file, and CometBFT combines the partial
+# signatures of the share holders.
+priv_validator_threshold_key_file = "{{ js .BaseConfig.PrivValidatorThresholdKey }}"
+
 # Path to the JSON file containing the private key to use for node authentication in the p2p protocol
 node_key_file = "{{ js .BaseConfig.NodeKey }}"
 
@@ -218,6 +225,18 @@ enabled = {{ .GRPC.BlockService.Enabled }}
 [grpc.block_results_service]
 enabled = {{ .GRPC.BlockResultsService.Enabled }}
 
+# The gRPC proposer service returns the expected proposers of round 0 of the
+# heights following the latest block. Proposers beyond the next two heights
+# assume that the application does not update the validator set.
+[grpc.proposer_service]
+enabled = {{ .GRPC.ProposerService.Enabled }}
+
+# The gRPC light block service returns the light block (signed header and
+# validator set) at a given height in one call, streams the light blocks of new
+# blocks, and accepts evidence reported by light clients.
+[grpc.light_block_service]
+enabled = {{ .GRPC.LightBlockService.Enabled }}
+
 #
 # Configuration for privileged gRPC endpoints, which should **never** be exposed
 # to the public internet.
@@ -247,12 +266,19 @@ enabled = {{ .GRPC.Privileged.PruningService.Enabled }}
 # Address to listen for incoming connections
 laddr = "{{ .P2P.ListenAddress }}"
 
+# Address to listen for incoming connections over QUIC, alongside laddr.
+# Peers dial it with a quic:// address, e.g. quic://<id>@159.89.10.97:26656.
+# If empty, the node does not accept QUIC connections.
+quic_laddr = "{{ .P2P.QUICListenAddress }}"
+
 # Address to advertise to peers for them to dial. If empty, will use the same
 # port as the laddr, and will introspect on the listener to figure out the
 # address. IP and port are required. Example: 159.89.10.97:26656
 external_address = "{{ .P2P.ExternalAddress }}"
 
-# Comma separated list of seed nodes to connect to
+# Comma separated list of seed nodes to connect to.
+# A seed of the form dns://<name> is resolved to the id@host:port entries
+# listed in the TXT records of <name>, and resolved again periodically
 seeds = "{{ .P2P.Seeds }}"
 
 # Comma separated list of nodes to keep persistent connections to
@@ -261,6 +287,13 @@ persistent_peers = "{{ .P2P.PersistentPeers }}"
 # Path to address book
 addr_book_file = "{{ js .P2P.AddrBook }}"
 
+# Backend saving the address book:
+#   1) "file" - the JSON file at addr_book_file, rewritten as a whole
+#   2) "db" - a database using db_backend in db_dir, only saving the
+#      addresses that changed. Suits large books, e.g. of seed nodes.
+#      The file at addr_book_file, if any, is imported on first start.
+addr_book_backend = "{{ .P2P.AddrBookBackend }}"
+
 # Set true for strict address routability rules
 # Set false for private or local networks
 addr_book_strict = {{ .P2P.AddrBookStrict }}
@@ -289,6 +322,16 @@ send_rate = {{ .P2P.SendRate }}
 # Rate at which packets can be received, in bytes/second
 recv_rate = {{ .P2P.RecvRate }}
 
+# Compression algorithms ("zstd" or "snappy"), in order of preference,
+# compressing block parts, blocksync responses and mempool transactions sent to
+# peers supporting one of them. If empty, messages are sent uncompressed.
+compression = [{{ range .P2P.Compression }}{{ printf "%q, " . }}{{end}}]
+
+# Rates of the messages of given channels, in bytes/second, as
+# "<channel ID>:<send rate>:<recv rate>" entries, e.g. "0x30:102400:102400".
+# A rate of 0 leaves the channel limited only by send_rate and recv_rate.
+channel_rates = [{{ range .P2P.ChannelRates }}{{ printf "%q, " . }}{{end}}]
+
 # Set true to enable the peer-exchange reactor
 pex = {{ .P2P.PexReactor }}
 
@@ -304,10 +347,71 @@ private_peer_ids = "{{ .P2P.PrivatePeerIDs }}"
 # Toggle to disable guard against peers connecting from the same ip.
 allow_duplicate_ip = {{ .P2P.AllowDuplicateIP }}
 
+# Role of the node in a sentry topology:
+#   1) "validator" - the node only connects to its sentries, listed in
+#      topology_peers. Other connections are refused and PEX is disabled.
+#   2) "sentry" - the node keeps persistent connections to the validators
+#      listed in topology_peers and never gossips their addresses.
+#   3) "" - no topology.
+topology = "{{ .P2P.Topology }}"
+
+# Comma separated list of nodes (id@host:port) on the other side of the
+# topology: the sentries of a validator, or the validators shielded by a sentry.
+topology_peers = "{{ .P2P.TopologyPeers }}"
+
+# Admission policy, enforced on the connections the node accepts and dials.
+# Refused connections and peers are logged. The policy is reloaded from this
+# file when the node receives SIGHUP, disconnecting the peers it refuses.
+#
+# If not empty, only peers with an IP in one of these CIDRs are accepted,
+# e.g. ["10.0.0.0/8", "2001:db8::/32"].
+admission_allow_cidrs = [{{ range .P2P.AdmissionAllowCIDRs }}{{ printf "%q, " . }}{{end}}]
+
+# Peers with an IP in one of these CIDRs are refused, even if allowed by
+# admission_allow_cidrs.
+admission_deny_cidrs = [{{ range .P2P.AdmissionDenyCIDRs }}{{ printf "%q, " . }}{{end}}]
+
+# Minimum block and app protocol versions of the peers (0 for any version).
+admission_min_block_version = {{ .P2P.AdmissionMinBlockVersion }}
+admission_min_app_version = {{ .P2P.AdmissionMinAppVersion }}
+
+# Channels the peers must support, e.g. ["0x20", "0x21"].
+admission_required_channels = [{{ range .P2P.AdmissionRequiredChannels }}{{ printf "%q, " . }}{{end}}]
+
+# Monikers of refused peers. Peers choose their moniker, so this only keeps out
+# the peers identifying themselves.
+admission_deny_monikers = [{{ range .P2P.AdmissionDenyMonikers }}{{ printf "%q, " . }}{{end}}]
+
+# Maximum number of connections with the IPs of a subnet (0 for no limit),
+# e.g. to keep a single hosting provider from taking all the peer slots. The
+# subnets are the IPv4 and IPv6 prefixes of the given lengths.
+admission_max_conns_per_subnet = {{ .P2P.AdmissionMaxConnsPerSubnet }}
+admission_subnet_prefix_len_ipv4 = {{ .P2P.AdmissionSubnetPrefixLenIPv4 }}
+admission_subnet_prefix_len_ipv6 = {{ .P2P.AdmissionSubnetPrefixLenIPv6 }}
+
+# Reactors report the good and bad behaviors of peers, e.g. invalid blocks or
+# votes, which add to their score. The score decays toward zero, halving every
+# peer_score_half_life (0 disables the decay).
+peer_score_half_life = "{{ .P2P.PeerScoreHalfLife }}"
+
+# Peers whose score falls below this threshold are disconnected.
+peer_score_disconnect_threshold = {{ .P2P.PeerScoreDisconnectThreshold }}
+
+# Peers whose score falls below this threshold are disconnected and banned for
+# peer_score_ban_time.
+peer_score_ban_threshold = {{ .P2P.PeerScoreBanThreshold }}
+peer_score_ban_time = "{{ .P2P.PeerScoreBanTime }}"
+
 # Peer connection configuration.
 handshake_timeout = "{{ .P2P.HandshakeTimeout }}"
 dial_timeout = "{{ .P2P.DialTimeout }}"
 
+# Offer the peers of TCP connections to authenticate and encrypt the
+# connection with a Noise_XX_25519_ChaChaPoly_SHA256 handshake, instead of the
+# SecretConnection one. The SecretConnection handshake is performed with the
+# peers which don't support it, or don't enable it.
+noise_handshake = {{ .P2P.NoiseHandshake }}
+
 #######################################################
 ###          Mempool Configuration Options          ###
 #######################################################
@@ -408,8 +512,9 @@ trust_period = "{{ .StateSync.TrustPeriod }}"
 # Time to spend discovering snapshots before initiating a restore.
 discovery_time = "{{ .StateSync.DiscoveryTime }}"
 
-# Temporary directory for state sync snapshot chunks, defaults to the OS tempdir (typically /tmp).
-# Will create a new, randomly named directory within, and remove it when done.
+# Directory for state sync snapshot chunks, defaults to the data directory. The chunks and the
+# progress of the restoration are kept in a directory within, so that a restoration interrupted by a
+# restart can resume, and it is removed when done.
 temp_dir = "{{ .StateSync.TempDir }}"
 
 # The timeout duration before re-requesting a chunk, possibly from a different
@@ -419,6 +524,15 @@ chunk_request_timeout = "{{ .StateSync.ChunkRequestTimeout }}"
 # The number of concurrent chunk fetchers to run (default: 1).
 chunk_fetchers = "{{ .StateSync.ChunkFetchers }}"
 
+# If not 0, the node takes a snapshot of its state and of the state exported by the application
+# every snapshot_interval blocks, and serves it to peers doing state sync. This does not require
+# the application to implement the snapshot ABCI methods, but it must run in the same process and
+# implement the optional state export interface.
+snapshot_interval = {{ .StateSync.SnapshotInterval }}
+
+# Number of snapshots to keep, older ones are deleted.
+snapshot_keep_recent = {{ .StateSync.SnapshotKeepRecent }}
+
 #######################################################
 ###       Block Sync Configuration Options          ###
 #######################################################
@@ -432,6 +546,17 @@ chunk_fetchers = "{{ .StateSync.ChunkFetchers }}"
 #   1) "v0" - the default block sync implementation
 version = "{{ .BlockSync.Version }}"
 
+# If true, the blocks received from peers are checked against headers verified by a light client,
+# and peers serving blocks that disagree with them are banned. This allows syncing from untrusted
+# peers and rejecting long-range forks. The light client is anchored at a trusted height and hash
+# obtained from a trusted source and uses the RPC servers (comma-separated, at least two) as
+# primary and witnesses.
+verify_with_light_client = {{ .BlockSync.VerifyWithLightClient }}
+rpc_servers = "{{ StringsJoin .BlockSync.RPCServers "," }}"
+trust_height = {{ .BlockSync.TrustHeight }}
+trust_hash = "{{ .BlockSync.TrustHash }}"
+trust_period = "{{ .BlockSync.TrustPeriod }}"
+
 #######################################################
 ###         Consensus Configuration Options         ###
 #######################################################
@@ -450,6 +575,19 @@ timeout_vote_delta = "{{ .Consensus.TimeoutVoteDelta }}"
 # Deprecated: use `next_block_delay` in the ABCI application's `FinalizeBlockResponse`.
 timeout_commit = "{{ .Consensus.TimeoutCommit }}"
 
+# If true, timeout_propose and timeout_vote are derived from the recently
+# observed proposal and vote delays (95th percentile, with some headroom)
+# instead of being static. The values only change between heights, are kept
+# within the bounds below (the minimums must be positive), and the deltas
+# above still apply to later rounds.
+# The values in use are exposed by the consensus_propose_timeout_seconds and
+# consensus_vote_timeout_seconds metrics.
+adaptive_timeouts = {{ .Consensus.AdaptiveTimeouts }}
+timeout_propose_min = "{{ .Consensus.TimeoutProposeMin }}"
+timeout_propose_max = "{{ .Consensus.TimeoutProposeMax }}"
+timeout_vote_min = "{{ .Consensus.TimeoutVoteMin }}"
+timeout_vote_max = "{{ .Consensus.TimeoutVoteMax }}"
+
 # How many blocks to look back to check existence of the node's consensus votes before joining consensus
 # When non-zero, the node will panic upon restart
 # if the same consensus key was used to sign {double_sign_check_height} last blocks.
@@ -465,6 +603,15 @@ peer_gossip_sleep_duration = "{{ .Consensus.PeerGossipSleepDuration }}"
 peer_gossip_intraloop_sleep_duration = "{{ .Consensus.PeerGossipIntraloopSleepDuration }}"
 peer_query_maj23_sleep_duration = "{{ .Consensus.PeerQueryMaj23SleepDuration }}"
 
+# If true, record a per-height timeline of consensus events (proposals, block
+# parts, votes with their arrival times and senders, timeouts, locks) to
+# trace_file. Export it as JSON with "cometbft consensus-trace".
+trace = {{ .Consensus.Trace }}
+trace_file = "{{ js .Consensus.TracePath }}"
+# Maximum total size of the trace files in bytes; the oldest entries are
+# removed once exceeded. Must be positive if the trace is enabled.
+trace_max_size = {{ .Consensus.TraceMaxSize }}
+
 #######################################################
 ###         Storage Configuration Options           ###
 #######################################################
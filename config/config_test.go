@@ -136,6 +136,69 @@ func TestP2PConfigValidateBasic(t *testing.T) {
 		require.Error(t, cfg.ValidateBasic())
 		reflect.ValueOf(cfg).Elem().FieldByName(fieldName).SetInt(0)
 	}
+
+	cfg.Compression = []string{"zstd", "snappy"}
+	require.NoError(t, cfg.ValidateBasic())
+	cfg.Compression = []string{"zstd", "zstd"}
+	require.Error(t, cfg.ValidateBasic())
+	cfg.Compression = []string{"brotli"}
+	require.Error(t, cfg.ValidateBasic())
+	cfg.Compression = nil
+
+	cfg.ChannelRates = []string{"0x30:102400:0", "32:0:2048"}
+	require.NoError(t, cfg.ValidateBasic())
+	rates, err := cfg.ChannelRateLimits()
+	require.NoError(t, err)
+	require.Equal(t, map[byte]config.ChannelRate{
+		0x30: {SendRate: 102400},
+		0x20: {RecvRate: 2048},
+	}, rates)
+	for _, invalid := range []string{"0x30:1", "0x100:1:1", "0x30:-1:0", "0x30:0:x"} {
+		cfg.ChannelRates = []string{invalid}
+		require.Error(t, cfg.ValidateBasic(), invalid)
+	}
+	cfg.ChannelRates = []string{"0x30:1:1", "48:2:2"}
+	require.Error(t, cfg.ValidateBasic())
+	cfg.ChannelRates = nil
+
+	cfg.AddrBookBackend = config.AddrBookBackendDB
+	require.NoError(t, cfg.ValidateBasic())
+	cfg.AddrBookBackend = "sqlite"
+	require.Error(t, cfg.ValidateBasic())
+	cfg.AddrBookBackend = config.AddrBookBackendFile
+
+	cfg.Topology = "bastion"
+	require.Error(t, cfg.ValidateBasic())
+	cfg.Topology = config.TopologySentry
+	require.Error(t, cfg.ValidateBasic())
+	cfg.TopologyPeers = "abcd@10.0.0.1:26656"
+	require.NoError(t, cfg.ValidateBasic())
+	cfg.Topology = config.TopologyValidator
+	require.NoError(t, cfg.ValidateBasic())
+	cfg.SeedMode = true
+	require.Error(t, cfg.ValidateBasic())
+	cfg.Topology, cfg.TopologyPeers, cfg.SeedMode = "", "", false
+
+	cfg.AdmissionAllowCIDRs = []string{"10.0.0.0/8", "2001:db8::/32"}
+	cfg.AdmissionRequiredChannels = []string{"0x20", "33"}
+	require.NoError(t, cfg.ValidateBasic())
+	chIDs, err := cfg.AdmissionChannels()
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x20, 0x21}, chIDs)
+	cfg.AdmissionDenyCIDRs = []string{"10.0.0.1"}
+	require.Error(t, cfg.ValidateBasic())
+	cfg.AdmissionDenyCIDRs = nil
+	cfg.AdmissionRequiredChannels = []string{"0x100"}
+	require.Error(t, cfg.ValidateBasic())
+	cfg.AdmissionRequiredChannels = nil
+	cfg.AdmissionMaxConnsPerSubnet = -1
+	require.Error(t, cfg.ValidateBasic())
+	cfg.AdmissionMaxConnsPerSubnet = 0
+	cfg.AdmissionSubnetPrefixLenIPv4 = 33
+	require.Error(t, cfg.ValidateBasic())
+	cfg.AdmissionSubnetPrefixLenIPv4 = 24
+	cfg.AdmissionSubnetPrefixLenIPv6 = 129
+	require.Error(t, cfg.ValidateBasic())
 }
 
 func TestMempoolConfigValidateBasic(t *testing.T) {
@@ -195,6 +258,12 @@ func TestMempoolConfigValidateBasic(t *testing.T) {
 func TestStateSyncConfigValidateBasic(t *testing.T) {
 	cfg := config.TestStateSyncConfig()
 	require.NoError(t, cfg.ValidateBasic())
+
+	// snapshots need to keep at least one snapshot
+	cfg.SnapshotInterval = 100
+	require.NoError(t, cfg.ValidateBasic())
+	cfg.SnapshotKeepRecent = 0
+	require.Error(t, cfg.ValidateBasic())
 }
 
 func TestBlockSyncConfigValidateBasic(t *testing.T) {
@@ -207,6 +276,20 @@ func TestBlockSyncConfigValidateBasic(t *testing.T) {
 
 	cfg.Version = "invalid"
 	require.Error(t, cfg.ValidateBasic())
+
+	// light client verification needs a trusted anchor and two RPC servers
+	cfg = config.TestBlockSyncConfig()
+	cfg.VerifyWithLightClient = true
+	require.Error(t, cfg.ValidateBasic())
+
+	cfg.RPCServers = []string{"first_rpc", "second_rpc"}
+	cfg.TrustHeight = 1
+	cfg.TrustHash = "not hex"
+	require.Error(t, cfg.ValidateBasic())
+
+	cfg.TrustHash = "AB"
+	require.NoError(t, cfg.ValidateBasic())
+	assert.Equal(t, []byte{0xab}, cfg.TrustHashBytes())
 }
 
 func TestConsensusConfig_ValidateBasic(t *testing.T) {
@@ -230,6 +313,16 @@ func TestConsensusConfig_ValidateBasic(t *testing.T) {
 		"PeerQueryMaj23SleepDuration":          {func(c *config.ConsensusConfig) { c.PeerQueryMaj23SleepDuration = time.Second }, false},
 		"PeerQueryMaj23SleepDuration negative": {func(c *config.ConsensusConfig) { c.PeerQueryMaj23SleepDuration = -1 }, true},
 		"DoubleSignCheckHeight negative":       {func(c *config.ConsensusConfig) { c.DoubleSignCheckHeight = -1 }, true},
+		"AdaptiveTimeouts":                     {func(c *config.ConsensusConfig) { c.AdaptiveTimeouts = true }, false},
+		"TimeoutProposeMin zero":               {func(c *config.ConsensusConfig) { c.AdaptiveTimeouts = true; c.TimeoutProposeMin = 0 }, true},
+		"TimeoutProposeMax zero":               {func(c *config.ConsensusConfig) { c.AdaptiveTimeouts = true; c.TimeoutProposeMax = 0 }, true},
+		"TimeoutProposeMax less than min":      {func(c *config.ConsensusConfig) { c.AdaptiveTimeouts = true; c.TimeoutProposeMax = 1 }, true},
+		"TimeoutVoteMin zero":                  {func(c *config.ConsensusConfig) { c.AdaptiveTimeouts = true; c.TimeoutVoteMin = 0 }, true},
+		"TimeoutVoteMax zero":                  {func(c *config.ConsensusConfig) { c.AdaptiveTimeouts = true; c.TimeoutVoteMax = 0 }, true},
+		"TimeoutVoteMin zero, not adaptive":    {func(c *config.ConsensusConfig) { c.TimeoutVoteMin = 0 }, false},
+		"TraceMaxSize negative":                {func(c *config.ConsensusConfig) { c.TraceMaxSize = -1 }, true},
+		"TraceMaxSize zero":                    {func(c *config.ConsensusConfig) { c.Trace = true; c.TraceMaxSize = 0 }, true},
+		"TraceMaxSize zero, trace disabled":    {func(c *config.ConsensusConfig) { c.TraceMaxSize = 0 }, false},
 	}
 	for desc, tc := range testcases {
 		t.Run(desc, func(t *testing.T) {
@@ -266,6 +359,36 @@ func TestConfigPossibleMisconfigurations(t *testing.T) {
 	require.Len(t, cfg.PossibleMisconfigurations(), 0)
 }
 
+func TestP2PPossibleMisconfigurations(t *testing.T) {
+	cfg := config.TestP2PConfig()
+	require.Len(t, cfg.PossibleMisconfigurations(), 0)
+
+	cfg.Topology = config.TopologyValidator
+	cfg.TopologyPeers = "abcd@10.0.0.1:26656"
+	cfg.PexReactor = false
+	cfg.PersistentPeers = "abcd@10.0.0.1:26656"
+	require.Len(t, cfg.PossibleMisconfigurations(), 0)
+
+	cfg.PexReactor = true
+	cfg.PersistentPeers = "abcd@10.0.0.1:26656,ef01@1.2.3.4:26656"
+	cfg.UnconditionalPeerIDs = "ef01"
+	cfg.ExternalAddress = "1.2.3.4:26656"
+	cfg.ListenAddress = "tcp://0.0.0.0:26656"
+	require.Equal(t, []string{
+		"pex is enabled but is disabled by topology = \"validator\"",
+		"persistent peer ef01 is not a sentry and is ignored",
+		"unconditional peer ef01 is not a sentry and is refused",
+		"external_address is set, advertising the validator address to its sentries",
+		"the validator listens on all interfaces; consider listening on the private network of its sentries only",
+	}, cfg.PossibleMisconfigurations())
+
+	cfg = config.TestP2PConfig()
+	cfg.Topology = config.TopologySentry
+	cfg.TopologyPeers = "abcd@10.0.0.2:26656"
+	cfg.Seeds = "abcd@10.0.0.2:26656"
+	require.Equal(t, []string{"validator abcd is listed in seeds"}, cfg.PossibleMisconfigurations())
+}
+
 func TestStateSyncPossibleMisconfigurations(t *testing.T) {
 	cfg := config.DefaultStateSyncConfig()
 	require.Len(t, cfg.PossibleMisconfigurations(), 0)
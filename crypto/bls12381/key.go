@@ -108,3 +108,17 @@ func (PubKey) Bytes() []byte {
 func (PubKey) Type() string {
 	return KeyType
 }
+
+// ===============================================================================================
+// Threshold (t-of-n) signatures
+// ===============================================================================================
+
+// GenThresholdKeyShares returns ErrDisabled.
+func GenThresholdKeyShares(int, int) (crypto.PubKey, []crypto.PrivKey, error) {
+	return nil, nil, ErrDisabled
+}
+
+// CombineThresholdSignatures returns ErrDisabled.
+func CombineThresholdSignatures([]uint32, [][]byte) ([]byte, error) {
+	return nil, ErrDisabled
+}
@@ -0,0 +1,139 @@
+//go:build bls12381
+
+package bls12381
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	blst "github.com/supranational/blst/bindings/go"
+
+	"github.com/cometbft/cometbft/crypto"
+)
+
+// ===============================================================================================
+// Threshold (t-of-n) signatures
+// ===============================================================================================
+
+// GenThresholdKeyShares generates a fresh BLS12-381 key and splits it into
+// `total` shares using Shamir's secret sharing, so that any `threshold` of
+// them can produce a valid signature for the returned group public key.
+//
+// Share i (starting at 1) is returned at position i-1. Each share is an
+// ordinary private key: signing with it yields a partial signature, which can
+// be combined with others using CombineThresholdSignatures.
+func GenThresholdKeyShares(threshold, total int) (crypto.PubKey, []crypto.PrivKey, error) {
+	if err := validateThresholdParams(threshold, total); err != nil {
+		return nil, nil, err
+	}
+
+	// f(x) = a_0 + a_1*x + ... + a_{t-1}*x^{t-1}, with a_0 being the group secret.
+	coeffs := make([]*blst.Scalar, threshold)
+	for i := range coeffs {
+		sk, err := GenPrivKey()
+		if err != nil {
+			return nil, nil, err
+		}
+		coeffs[i] = sk.sk
+	}
+	groupKey := &PrivKey{sk: coeffs[0]}
+
+	shares := make([]crypto.PrivKey, total)
+	for i := 0; i < total; i++ {
+		x := scalarFromIndex(uint32(i + 1))
+		// Horner's method.
+		acc := *coeffs[threshold-1]
+		for k := threshold - 2; k >= 0; k-- {
+			acc.MulAssign(x)
+			acc.AddAssign(coeffs[k])
+		}
+		if !acc.Valid() {
+			return nil, nil, fmt.Errorf("bls12381: invalid key share #%d, try again", i+1)
+		}
+		share := acc
+		shares[i] = &PrivKey{sk: &share}
+	}
+
+	pubKey := groupKey.PubKey()
+	groupKey.Zeroize()
+	for _, c := range coeffs[1:] {
+		c.Zeroize()
+	}
+
+	return pubKey, shares, nil
+}
+
+// CombineThresholdSignatures interpolates the partial signatures produced by
+// the key shares with the given (1-based) indices into a signature for the
+// group public key. The caller is responsible for passing at least
+// `threshold` valid partial signatures; indices must be unique.
+func CombineThresholdSignatures(indices []uint32, sigs [][]byte) ([]byte, error) {
+	if len(indices) == 0 {
+		return nil, errors.New("bls12381: no partial signatures")
+	}
+	if len(indices) != len(sigs) {
+		return nil, fmt.Errorf("bls12381: got %d indices for %d partial signatures", len(indices), len(sigs))
+	}
+
+	xs := make([]*blst.Scalar, len(indices))
+	seen := make(map[uint32]struct{}, len(indices))
+	for i, idx := range indices {
+		if idx == 0 {
+			return nil, errors.New("bls12381: share index must be positive")
+		}
+		if _, ok := seen[idx]; ok {
+			return nil, fmt.Errorf("bls12381: duplicate share index %d", idx)
+		}
+		seen[idx] = struct{}{}
+		xs[i] = scalarFromIndex(idx)
+	}
+
+	var combined *blst.P2
+	for i, sig := range sigs {
+		partial := new(blstSignature).Uncompress(sig)
+		if partial == nil || !partial.SigValidate(false) {
+			return nil, fmt.Errorf("bls12381: invalid partial signature from share %d", indices[i])
+		}
+
+		// Lagrange coefficient at x = 0: prod_{j != i} x_j / (x_j - x_i).
+		num := scalarFromIndex(1)
+		den := scalarFromIndex(1)
+		for j, xj := range xs {
+			if j == i {
+				continue
+			}
+			num.MulAssign(xj)
+			diff, _ := xj.Sub(xs[i])
+			den.MulAssign(diff)
+		}
+		lambda, _ := num.Mul(den.Inverse())
+
+		var point blst.P2
+		point.FromAffine(partial)
+		point.MultAssign(lambda)
+		if combined == nil {
+			combined = &point
+		} else {
+			combined.AddAssign(&point)
+		}
+	}
+
+	return combined.ToAffine().Compress(), nil
+}
+
+func validateThresholdParams(threshold, total int) error {
+	if threshold < 1 {
+		return fmt.Errorf("bls12381: threshold must be positive, got %d", threshold)
+	}
+	if total < threshold {
+		return fmt.Errorf("bls12381: number of shares (%d) must not be less than the threshold (%d)", total, threshold)
+	}
+	return nil
+}
+
+func scalarFromIndex(idx uint32) *blst.Scalar {
+	var bz [32]byte
+	binary.BigEndian.PutUint32(bz[28:], idx)
+	return new(blst.Scalar).FromBEndian(bz[:])
+}
@@ -0,0 +1,75 @@
+//go:build bls12381
+
+package bls12381_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cometbft/cometbft/crypto/bls12381"
+)
+
+func TestThresholdSignatures(t *testing.T) {
+	pubKey, shares, err := bls12381.GenThresholdKeyShares(3, 5)
+	require.NoError(t, err)
+	require.Len(t, shares, 5)
+
+	msg := []byte("hello threshold world")
+
+	partials := make([][]byte, len(shares))
+	for i, share := range shares {
+		partials[i], err = share.Sign(msg)
+		require.NoError(t, err)
+		// A partial signature is not valid for the group key.
+		assert.False(t, pubKey.VerifySignature(msg, partials[i]))
+	}
+
+	testCases := []struct {
+		name    string
+		indices []uint32
+		valid   bool
+	}{
+		{"first three", []uint32{1, 2, 3}, true},
+		{"last three", []uint32{3, 4, 5}, true},
+		{"unordered", []uint32{5, 1, 3}, true},
+		{"all", []uint32{1, 2, 3, 4, 5}, true},
+		{"below threshold", []uint32{2, 4}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sigs := make([][]byte, len(tc.indices))
+			for i, idx := range tc.indices {
+				sigs[i] = partials[idx-1]
+			}
+			sig, err := bls12381.CombineThresholdSignatures(tc.indices, sigs)
+			require.NoError(t, err)
+			assert.Equal(t, tc.valid, pubKey.VerifySignature(msg, sig))
+		})
+	}
+}
+
+func TestCombineThresholdSignatures_InvalidInput(t *testing.T) {
+	_, shares, err := bls12381.GenThresholdKeyShares(2, 3)
+	require.NoError(t, err)
+	sig, err := shares[0].Sign([]byte("msg"))
+	require.NoError(t, err)
+
+	_, err = bls12381.CombineThresholdSignatures(nil, nil)
+	require.Error(t, err)
+	_, err = bls12381.CombineThresholdSignatures([]uint32{1, 1}, [][]byte{sig, sig})
+	require.Error(t, err)
+	_, err = bls12381.CombineThresholdSignatures([]uint32{0}, [][]byte{sig})
+	require.Error(t, err)
+	_, err = bls12381.CombineThresholdSignatures([]uint32{1}, [][]byte{[]byte("garbage")})
+	require.Error(t, err)
+}
+
+func TestGenThresholdKeyShares_InvalidParams(t *testing.T) {
+	_, _, err := bls12381.GenThresholdKeyShares(0, 3)
+	require.Error(t, err)
+	_, _, err = bls12381.GenThresholdKeyShares(4, 3)
+	require.Error(t, err)
+}
@@ -0,0 +1,145 @@
+// Package commitment implements merkle.ProofOperator(s) verifying ICS-23
+// commitment proofs, as returned in ABCI query responses by applications
+// storing their state in IAVL trees, sparse merkle trees or simple merkle
+// maps (e.g. a multistore of IAVL stores).
+package commitment
+
+import (
+	"errors"
+	"fmt"
+
+	ics23 "github.com/cosmos/ics23/go"
+
+	cmtcrypto "github.com/cometbft/cometbft/api/cometbft/crypto/v1"
+	"github.com/cometbft/cometbft/crypto/merkle"
+)
+
+// Types of the ICS-23 commitment proof operators. They match the ones used by
+// the Cosmos SDK.
+const (
+	ProofOpIAVLCommitment         = "ics23:iavl"
+	ProofOpSMTCommitment          = "ics23:smt"
+	ProofOpSimpleMerkleCommitment = "ics23:simple"
+)
+
+// ErrTooManyArgs is returned when the input to [Op.Run] has length exceeding
+// 1.
+var ErrTooManyArgs = errors.New("commitment: len(args) > 1")
+
+// Op verifies an ICS-23 commitment proof of the existence of a value under a
+// key, or of the absence of the key, against the proof spec of the tree
+// storing it. Run returns the root of the tree.
+type Op struct {
+	typ  string
+	spec *ics23.ProofSpec
+	// Encoded in ProofOp.Key.
+	key []byte
+
+	// To encode in ProofOp.Data.
+	Proof *ics23.CommitmentProof
+}
+
+var _ merkle.ProofOperator = Op{}
+
+// NewIAVLOp returns an operator verifying a proof of an IAVL tree.
+func NewIAVLOp(key []byte, proof *ics23.CommitmentProof) Op {
+	return Op{typ: ProofOpIAVLCommitment, spec: ics23.IavlSpec, key: key, Proof: proof}
+}
+
+// NewSMTOp returns an operator verifying a proof of a sparse merkle tree.
+func NewSMTOp(key []byte, proof *ics23.CommitmentProof) Op {
+	return Op{typ: ProofOpSMTCommitment, spec: ics23.SmtSpec, key: key, Proof: proof}
+}
+
+// NewSimpleMerkleOp returns an operator verifying a proof of a simple merkle
+// map, as built by CometBFT's merkle package.
+func NewSimpleMerkleOp(key []byte, proof *ics23.CommitmentProof) Op {
+	return Op{typ: ProofOpSimpleMerkleCommitment, spec: ics23.TendermintSpec, key: key, Proof: proof}
+}
+
+// IAVLOpDecoder decodes a cmtcrypto.ProofOp of type ProofOpIAVLCommitment.
+func IAVLOpDecoder(pop cmtcrypto.ProofOp) (merkle.ProofOperator, error) {
+	return decode(pop, ProofOpIAVLCommitment, NewIAVLOp)
+}
+
+// SMTOpDecoder decodes a cmtcrypto.ProofOp of type ProofOpSMTCommitment.
+func SMTOpDecoder(pop cmtcrypto.ProofOp) (merkle.ProofOperator, error) {
+	return decode(pop, ProofOpSMTCommitment, NewSMTOp)
+}
+
+// SimpleMerkleOpDecoder decodes a cmtcrypto.ProofOp of type
+// ProofOpSimpleMerkleCommitment.
+func SimpleMerkleOpDecoder(pop cmtcrypto.ProofOp) (merkle.ProofOperator, error) {
+	return decode(pop, ProofOpSimpleMerkleCommitment, NewSimpleMerkleOp)
+}
+
+func decode(
+	pop cmtcrypto.ProofOp,
+	typ string,
+	newOp func([]byte, *ics23.CommitmentProof) Op,
+) (merkle.ProofOperator, error) {
+	if pop.Type != typ {
+		return nil, merkle.ErrInvalidProof{
+			Err: fmt.Errorf("unexpected ProofOp.Type; got %v, want %v", pop.Type, typ),
+		}
+	}
+	proof := &ics23.CommitmentProof{}
+	if err := proof.Unmarshal(pop.Data); err != nil {
+		return nil, merkle.ErrInvalidProof{
+			Err: fmt.Errorf("decoding ProofOp.Data into CommitmentProof: %w", err),
+		}
+	}
+	return newOp(pop.Key, proof), nil
+}
+
+// ProofOp encodes the Op as a cmtcrypto.ProofOp, which can later be decoded.
+func (op Op) ProofOp() cmtcrypto.ProofOp {
+	bz, err := op.Proof.Marshal()
+	if err != nil {
+		panic(err)
+	}
+	return cmtcrypto.ProofOp{
+		Type: op.typ,
+		Key:  op.key,
+		Data: bz,
+	}
+}
+
+func (op Op) String() string {
+	return fmt.Sprintf("CommitmentOp{%v %v}", op.typ, op.GetKey())
+}
+
+// Run verifies the proof of the existence of args[0] under the key of the
+// operator or, if args is empty, of the absence of the key, and returns the
+// root of the tree.
+func (op Op) Run(args [][]byte) ([][]byte, error) {
+	root, err := op.Proof.Calculate()
+	if err != nil {
+		return nil, merkle.ErrInvalidProof{
+			Err: fmt.Errorf("computing root: %w", err),
+		}
+	}
+
+	switch len(args) {
+	case 0:
+		if !ics23.VerifyNonMembership(op.spec, root, op.Proof, op.key) {
+			return nil, merkle.ErrInvalidProof{
+				Err: fmt.Errorf("key %X is not proven absent", op.key),
+			}
+		}
+	case 1:
+		if !ics23.VerifyMembership(op.spec, root, op.Proof, op.key, args[0]) {
+			return nil, merkle.ErrInvalidProof{
+				Err: fmt.Errorf("value under key %X is not proven", op.key),
+			}
+		}
+	default:
+		return nil, ErrTooManyArgs
+	}
+
+	return [][]byte{root}, nil
+}
+
+func (op Op) GetKey() []byte {
+	return op.key
+}
@@ -0,0 +1,167 @@
+package commitment
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"testing"
+
+	ics23 "github.com/cosmos/ics23/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cmtcrypto "github.com/cometbft/cometbft/api/cometbft/crypto/v1"
+	"github.com/cometbft/cometbft/crypto/merkle"
+)
+
+// kvItem encodes a key and a value as a leaf of a simple merkle map.
+func kvItem(key, value string) []byte {
+	vhash := sha256.Sum256([]byte(value))
+	bz := binary.AppendUvarint(nil, uint64(len(key)))
+	bz = append(bz, key...)
+	bz = binary.AppendUvarint(bz, uint64(len(vhash)))
+	return append(bz, vhash[:]...)
+}
+
+func leafHash(item []byte) []byte {
+	h := sha256.Sum256(append([]byte{0}, item...))
+	return h[:]
+}
+
+// simpleMap is the simple merkle map {a: 1, b: 2}.
+type simpleMap struct {
+	root       []byte
+	leafA      []byte
+	leafB      []byte
+	existenceA *ics23.ExistenceProof
+	existenceB *ics23.ExistenceProof
+}
+
+func newSimpleMap() *simpleMap {
+	itemA, itemB := kvItem("a", "1"), kvItem("b", "2")
+	m := &simpleMap{
+		root:  merkle.HashFromByteSlices([][]byte{itemA, itemB}),
+		leafA: leafHash(itemA),
+		leafB: leafHash(itemB),
+	}
+	leaf := ics23.TendermintSpec.LeafSpec
+	m.existenceA = &ics23.ExistenceProof{
+		Key:   []byte("a"),
+		Value: []byte("1"),
+		Leaf:  leaf,
+		Path:  []*ics23.InnerOp{{Hash: ics23.HashOp_SHA256, Prefix: []byte{1}, Suffix: m.leafB}},
+	}
+	m.existenceB = &ics23.ExistenceProof{
+		Key:   []byte("b"),
+		Value: []byte("2"),
+		Leaf:  leaf,
+		Path:  []*ics23.InnerOp{{Hash: ics23.HashOp_SHA256, Prefix: append([]byte{1}, m.leafA...)}},
+	}
+	return m
+}
+
+func exist(p *ics23.ExistenceProof) *ics23.CommitmentProof {
+	return &ics23.CommitmentProof{Proof: &ics23.CommitmentProof_Exist{Exist: p}}
+}
+
+func nonexist(p *ics23.NonExistenceProof) *ics23.CommitmentProof {
+	return &ics23.CommitmentProof{Proof: &ics23.CommitmentProof_Nonexist{Nonexist: p}}
+}
+
+func TestOp_Run(t *testing.T) {
+	m := newSimpleMap()
+
+	testCases := map[string]struct {
+		op      Op
+		args    [][]byte
+		wantErr bool
+	}{
+		"existence": {
+			op:   NewSimpleMerkleOp([]byte("a"), exist(m.existenceA)),
+			args: [][]byte{[]byte("1")},
+		},
+		"existence of the right leaf": {
+			op:   NewSimpleMerkleOp([]byte("b"), exist(m.existenceB)),
+			args: [][]byte{[]byte("2")},
+		},
+		"other value": {
+			op:      NewSimpleMerkleOp([]byte("a"), exist(m.existenceA)),
+			args:    [][]byte{[]byte("2")},
+			wantErr: true,
+		},
+		"other key": {
+			op:      NewSimpleMerkleOp([]byte("b"), exist(m.existenceA)),
+			args:    [][]byte{[]byte("1")},
+			wantErr: true,
+		},
+		"other spec": {
+			op:      NewIAVLOp([]byte("a"), exist(m.existenceA)),
+			args:    [][]byte{[]byte("1")},
+			wantErr: true,
+		},
+		"absence after the last key": {
+			op: NewSimpleMerkleOp([]byte("c"), nonexist(&ics23.NonExistenceProof{
+				Key:  []byte("c"),
+				Left: m.existenceB,
+			})),
+		},
+		"absence before the first key": {
+			op: NewSimpleMerkleOp([]byte("0"), nonexist(&ics23.NonExistenceProof{
+				Key:   []byte("0"),
+				Right: m.existenceA,
+			})),
+		},
+		"absence of an existing key": {
+			op: NewSimpleMerkleOp([]byte("a"), nonexist(&ics23.NonExistenceProof{
+				Key:  []byte("a"),
+				Left: m.existenceB,
+			})),
+			wantErr: true,
+		},
+		"absence with an existence proof": {
+			op:      NewSimpleMerkleOp([]byte("a"), exist(m.existenceA)),
+			wantErr: true,
+		},
+		"too many args": {
+			op:      NewSimpleMerkleOp([]byte("a"), exist(m.existenceA)),
+			args:    [][]byte{[]byte("1"), []byte("2")},
+			wantErr: true,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			root, err := tc.op.Run(tc.args)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, [][]byte{m.root}, root)
+		})
+	}
+}
+
+func TestOpDecoder(t *testing.T) {
+	m := newSimpleMap()
+	op := NewSimpleMerkleOp([]byte("a"), exist(m.existenceA))
+
+	pop := op.ProofOp()
+	assert.Equal(t, ProofOpSimpleMerkleCommitment, pop.Type)
+	decoded, err := SimpleMerkleOpDecoder(pop)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("a"), decoded.GetKey())
+
+	_, err = IAVLOpDecoder(pop)
+	require.ErrorAs(t, err, &merkle.ErrInvalidProof{})
+
+	pop.Data = []byte{0xFF}
+	_, err = SimpleMerkleOpDecoder(pop)
+	require.ErrorAs(t, err, &merkle.ErrInvalidProof{})
+
+	// The decoded operator verifies values through a proof runtime.
+	prt := merkle.NewProofRuntime()
+	prt.RegisterOpDecoder(ProofOpSimpleMerkleCommitment, SimpleMerkleOpDecoder)
+	kp := merkle.KeyPath{}.AppendKey([]byte("a"), merkle.KeyEncodingURL)
+	proofOps := &cmtcrypto.ProofOps{Ops: []cmtcrypto.ProofOp{op.ProofOp()}}
+	require.NoError(t, prt.VerifyValue(proofOps, m.root, kp.String(), []byte("1")))
+	require.Error(t, prt.VerifyValue(proofOps, m.root, kp.String(), []byte("2")))
+}
@@ -49,3 +49,19 @@ func (e ErrReactorValidation) Error() string {
 func (e ErrReactorValidation) Unwrap() error {
 	return e.Err
 }
+
+// errLightBlockNotVerified is returned when the light client has not verified
+// the header at the height of a block yet.
+var errLightBlockNotVerified = errors.New("header not verified by the light client yet")
+
+// ErrLightBlockMismatch is returned when a block differs from the header
+// verified by the light client at the same height.
+type ErrLightBlockMismatch struct {
+	Height   int64
+	Expected []byte
+	Got      []byte
+}
+
+func (e ErrLightBlockMismatch) Error() string {
+	return fmt.Sprintf("block at height %d has hash %X, but the light client verified %X", e.Height, e.Got, e.Expected)
+}
@@ -44,6 +44,18 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Name:      "latest_block_height",
 			Help:      "The height of the latest block.",
 		}, labels).With(labelsAndValues...),
+		PeerThroughput: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "peer_throughput",
+			Help:      "Rate at which a peer sends us blocks, in bytes per second.",
+		}, append(labels, "peer_id")).With(labelsAndValues...),
+		PeerStallSeconds: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "peer_stall_seconds",
+			Help:      "Time spent waiting for a peer to send the block at the pool's height.",
+		}, append(labels, "peer_id")).With(labelsAndValues...),
 	}
 }
 
@@ -54,5 +66,7 @@ func NopMetrics() *Metrics {
 		TotalTxs:          discard.NewGauge(),
 		BlockSizeBytes:    discard.NewGauge(),
 		LatestBlockHeight: discard.NewGauge(),
+		PeerThroughput:    discard.NewGauge(),
+		PeerStallSeconds:  discard.NewCounter(),
 	}
 }
@@ -25,6 +25,10 @@ type Metrics struct {
 	BlockSizeBytes metrics.Gauge
 	// The height of the latest block.
 	LatestBlockHeight metrics.Gauge
+	// Rate at which a peer sends us blocks, in bytes per second.
+	PeerThroughput metrics.Gauge `metrics_labels:"peer_id"`
+	// Time spent waiting for a peer to send the block at the pool's height.
+	PeerStallSeconds metrics.Counter `metrics_labels:"peer_id"`
 }
 
 func (m *Metrics) recordBlockMetrics(block *types.Block) {
@@ -0,0 +1,165 @@
+package blocksync
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/cometbft/cometbft/internal/tempfile"
+	cmtsync "github.com/cometbft/cometbft/libs/sync"
+	"github.com/cometbft/cometbft/p2p"
+	cmttime "github.com/cometbft/cometbft/types/time"
+)
+
+const (
+	// weight of the latest sample in the moving averages of a peer score.
+	peerScoreAlpha = 0.2
+
+	// maximum number of scores kept in the scores file, most recently seen
+	// peers first.
+	maxSavedPeerScores = 1000
+)
+
+// peerScore summarizes how well a peer served blocks.
+type peerScore struct {
+	// Moving average of the delay between a request and the block.
+	Latency time.Duration `json:"latency"`
+	// Moving average of the rate at which the peer sent us data, in bytes/s.
+	Throughput float64 `json:"throughput"`
+	// Number of errors caused by the peer. Halved at the start of every
+	// block sync session, so that old errors are eventually forgotten.
+	Errors   float64   `json:"errors"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// Peers we have no history for are given the rate a peer starts with in
+// the receive monitor, so that they are tried early.
+var defaultPeerScore = peerScore{
+	Throughput: float64(minRecvRate) * math.E,
+}
+
+// value is the throughput of the peer, discounted by its latency and errors.
+// Higher is better.
+func (s peerScore) value() float64 {
+	return s.Throughput / (1 + s.Latency.Seconds()) / (1 + s.Errors)
+}
+
+// peerScores keeps the scores of the peers that served blocks, including the
+// ones of previous block sync sessions if loaded from a file.
+type peerScores struct {
+	mtx    cmtsync.Mutex
+	scores map[p2p.ID]*peerScore
+}
+
+func newPeerScores() *peerScores {
+	return &peerScores{scores: make(map[p2p.ID]*peerScore)}
+}
+
+func (ps *peerScores) get(peerID p2p.ID) peerScore {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	if s, ok := ps.scores[peerID]; ok {
+		return *s
+	}
+	return defaultPeerScore
+}
+
+func (ps *peerScores) value(peerID p2p.ID) float64 {
+	return ps.get(peerID).value()
+}
+
+// getOrCreate returns the score of the peer, creating it if needed.
+//
+// CONTRACT: ps.mtx must be locked.
+func (ps *peerScores) getOrCreate(peerID p2p.ID) *peerScore {
+	s, ok := ps.scores[peerID]
+	if !ok {
+		s = &peerScore{}
+		*s = defaultPeerScore
+		ps.scores[peerID] = s
+	}
+	s.LastSeen = cmttime.Now()
+	return s
+}
+
+// recordBlock updates the score of a peer that sent us a block latency after
+// we requested it, while sending us data at the given rate (0 if unknown).
+func (ps *peerScores) recordBlock(peerID p2p.ID, latency time.Duration, rate int64) {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	s := ps.getOrCreate(peerID)
+	if s.Latency == 0 {
+		s.Latency = latency
+	} else {
+		s.Latency = time.Duration(peerScoreAlpha*float64(latency) + (1-peerScoreAlpha)*float64(s.Latency))
+	}
+	if rate > 0 {
+		s.Throughput = peerScoreAlpha*float64(rate) + (1-peerScoreAlpha)*s.Throughput
+	}
+}
+
+// recordError counts an error (timeout, invalid or unexpected block) against
+// the peer.
+func (ps *peerScores) recordError(peerID p2p.ID) {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	ps.getOrCreate(peerID).Errors++
+}
+
+// loadFromFile loads the scores saved by saveToFile. A missing file is not an
+// error.
+func (ps *peerScores) loadFromFile(filePath string) error {
+	bz, err := os.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	scores := make(map[p2p.ID]*peerScore)
+	if err := json.Unmarshal(bz, &scores); err != nil {
+		return err
+	}
+
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+	for peerID, s := range scores {
+		s.Errors /= 2
+		ps.scores[peerID] = s
+	}
+	return nil
+}
+
+// saveToFile writes the scores of the most recently seen peers to filePath.
+func (ps *peerScores) saveToFile(filePath string) error {
+	ps.mtx.Lock()
+	ids := make([]p2p.ID, 0, len(ps.scores))
+	for peerID := range ps.scores {
+		ids = append(ids, peerID)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ps.scores[ids[i]].LastSeen.After(ps.scores[ids[j]].LastSeen)
+	})
+	if len(ids) > maxSavedPeerScores {
+		ids = ids[:maxSavedPeerScores]
+	}
+	scores := make(map[p2p.ID]*peerScore, len(ids))
+	for _, peerID := range ids {
+		s := *ps.scores[peerID]
+		scores[peerID] = &s
+	}
+	ps.mtx.Unlock()
+
+	bz, err := json.MarshalIndent(scores, "", "\t")
+	if err != nil {
+		return err
+	}
+	return tempfile.WriteFileAtomic(filePath, bz, 0o644)
+}
@@ -46,10 +46,15 @@ const (
 	// routine time to connect to peers.
 	peerConnWait = 3 * time.Second
 
-	// If we're within minBlocksForSingleRequest blocks of the pool's height, we
-	// send 2 parallel requests to 2 peers for the same block. If we're further
-	// away, we send a single request.
-	minBlocksForSingleRequest = 50
+	// Number of consecutive heights requested from the same peer when a peer
+	// can serve them all, so that blocks are downloaded in ranges.
+	requestRangeSize = 8
+
+	// The block at the pool's height is requested from a second peer if the
+	// first one did not send it within twice its average latency, bounded by
+	// minHedgeDelay and maxHedgeDelay.
+	minHedgeDelay = 100 * time.Millisecond
+	maxHedgeDelay = 2 * time.Second
 )
 
 var (
@@ -64,7 +69,9 @@ var (
 	Every so often we ask peers what height they're on so we can keep going.
 
 	Requests are continuously made for blocks of higher heights until
-	the limit is reached. If most of the requests have no available peers, and we
+	the limit is reached. Consecutive heights are requested from the same
+	peer, picked by score (see peerScore), and the block at the pool's height
+	is requested from a second peer if the first one is late. If most of the requests have no available peers, and we
 	are not at peer limits, we can probably switch to consensus reactor.
 */
 
@@ -81,11 +88,18 @@ type BlockPool struct {
 	// peers
 	peers         map[p2p.ID]*bpPeer
 	bannedPeers   map[p2p.ID]time.Time
-	sortedPeers   []*bpPeer // sorted by curRate, highest first
+	sortedPeers   []*bpPeer // sorted by score, highest first
 	maxPeerHeight int64     // the biggest reported height
 
+	// scores of the peers, loaded from and saved to scoresFile if set, so
+	// that they are kept across block sync sessions.
+	scores     *peerScores
+	scoresFile string
+
 	requestsCh chan<- BlockRequest
 	errorsCh   chan<- peerError
+
+	metrics *Metrics
 }
 
 // NewBlockPool returns a new BlockPool with the height equal to start. Block
@@ -97,22 +111,39 @@ func NewBlockPool(start int64, requestsCh chan<- BlockRequest, errorsCh chan<- p
 		requesters:  make(map[int64]*bpRequester),
 		height:      start,
 		startHeight: start,
+		scores:      newPeerScores(),
 
 		requestsCh: requestsCh,
 		errorsCh:   errorsCh,
+		metrics:    NopMetrics(),
 	}
 	bp.BaseService = *service.NewBaseService(nil, "BlockPool", bp)
 	return bp
 }
 
-// OnStart implements service.Service by spawning requesters routine and recording
-// pool's start time.
+// OnStart implements service.Service by loading the peer scores, spawning
+// requesters routine and recording pool's start time.
 func (pool *BlockPool) OnStart() error {
+	if pool.scoresFile != "" {
+		if err := pool.scores.loadFromFile(pool.scoresFile); err != nil {
+			pool.Logger.Error("Failed to load peer scores", "file", pool.scoresFile, "err", err)
+		}
+	}
 	pool.startTime = time.Now()
 	go pool.makeRequestersRoutine()
 	return nil
 }
 
+// OnStop implements service.Service by saving the peer scores.
+func (pool *BlockPool) OnStop() {
+	if pool.scoresFile == "" {
+		return
+	}
+	if err := pool.scores.saveToFile(pool.scoresFile); err != nil {
+		pool.Logger.Error("Failed to save peer scores", "file", pool.scoresFile, "err", err)
+	}
+}
+
 func (pool *BlockPool) makeRequestersRoutine() {
 	for {
 		if !pool.IsRunning() {
@@ -143,7 +174,7 @@ func (pool *BlockPool) makeRequestersRoutine() {
 		case maxPeerHeightReached: // If we're caught up, wait for a bit so reactor could finish or a higher height is reported.
 			time.Sleep(requestInterval)
 		default:
-			pool.makeNextRequester(nextHeight)
+			pool.makeNextRequesters(nextHeight)
 			// Sleep for a bit to make the requests more ordered.
 			time.Sleep(requestInterval)
 		}
@@ -169,6 +200,7 @@ func (pool *BlockPool) removeTimedoutPeers() {
 			}
 
 			peer.curRate = curRate
+			pool.metrics.PeerThroughput.With("peer_id", string(peer.id)).Set(float64(curRate))
 		}
 
 		if peer.didTimeout {
@@ -245,10 +277,10 @@ func (pool *BlockPool) PopRequest() {
 	delete(pool.requesters, pool.height)
 	pool.height++
 
-	// Notify the next minBlocksForSingleRequest requesters about new height, so
-	// they can potentially request a block from the second peer.
-	for i := int64(0); i < minBlocksForSingleRequest && i < int64(len(pool.requesters)); i++ {
-		pool.requesters[pool.height+i].newHeight(pool.height)
+	// Notify the requester at the new height, so it can request the block
+	// from a second peer if the first one is late.
+	if r := pool.requesters[pool.height]; r != nil {
+		r.newHeight(pool.height)
 	}
 }
 
@@ -326,10 +358,13 @@ func (pool *BlockPool) AddBlock(peerID p2p.ID, block *types.Block, extCommit *ty
 		return err
 	}
 
+	var rate int64
 	peer := pool.peers[peerID]
 	if peer != nil {
 		peer.decrPending(blockSize)
+		rate = peer.recvMonitor.Status().CurRate
 	}
+	pool.scores.recordBlock(peerID, requester.requestLatency(peerID), rate)
 
 	return nil
 }
@@ -365,9 +400,10 @@ func (pool *BlockPool) SetPeerRange(peerID p2p.ID, base int64, height int64) {
 		peer = newBPPeer(pool, peerID, base, height)
 		peer.setLogger(pool.Logger.With("peer", peerID))
 		pool.peers[peerID] = peer
-		// no need to sort because curRate is 0 at start.
-		// just add to the beginning so it's picked first by pickIncrAvailablePeer.
-		pool.sortedPeers = append([]*bpPeer{peer}, pool.sortedPeers...)
+		// peers we know nothing about have a default score, high enough for
+		// them to be tried early.
+		pool.sortedPeers = append(pool.sortedPeers, peer)
+		pool.sortPeers()
 	}
 
 	if height > pool.maxPeerHeight {
@@ -398,6 +434,7 @@ func (pool *BlockPool) removePeer(peerID p2p.ID) {
 		}
 
 		delete(pool.peers, peerID)
+		pool.metrics.PeerThroughput.With("peer_id", string(peerID)).Set(0)
 		for i, p := range pool.sortedPeers {
 			if p.id == peerID {
 				pool.sortedPeers = append(pool.sortedPeers[:i], pool.sortedPeers[i+1:]...)
@@ -431,14 +468,23 @@ func (pool *BlockPool) isPeerBanned(peerID p2p.ID) bool {
 func (pool *BlockPool) banPeer(peerID p2p.ID) {
 	pool.Logger.Debug("Banning peer", peerID)
 	pool.bannedPeers[peerID] = cmttime.Now()
+	pool.scores.recordError(peerID)
 }
 
-// Pick an available peer with the given height available.
+// Pick an available peer with the given height available, preferring
+// preferredPeerID if set and available.
 // If no peers are available, returns nil.
-func (pool *BlockPool) pickIncrAvailablePeer(height int64, excludePeerID p2p.ID) *bpPeer {
+func (pool *BlockPool) pickIncrAvailablePeer(height int64, preferredPeerID, excludePeerID p2p.ID) *bpPeer {
 	pool.mtx.Lock()
 	defer pool.mtx.Unlock()
 
+	if peer := pool.peers[preferredPeerID]; peer != nil && peer.id != excludePeerID &&
+		!peer.didTimeout && peer.numPending < maxPendingRequestsPerPeer &&
+		height >= peer.base && height <= peer.height {
+		peer.incrPending()
+		return peer
+	}
+
 	for _, peer := range pool.sortedPeers {
 		if peer.id == excludePeerID {
 			continue
@@ -460,23 +506,90 @@ func (pool *BlockPool) pickIncrAvailablePeer(height int64, excludePeerID p2p.ID)
 	return nil
 }
 
-// Sort peers by curRate, highest first.
+// Pick a peer able to serve all the heights from minHeight to maxHeight
+// without exceeding maxPendingRequestsPerPeer. Ranges are spread across
+// peers in proportion to their score: the peer with the highest score per
+// pending request is picked. If no peer can serve the range, returns an
+// empty ID.
+//
+// CONTRACT: pool.mtx must be locked.
+func (pool *BlockPool) pickRangePeer(minHeight, maxHeight int64) p2p.ID {
+	var (
+		bestID    p2p.ID
+		bestValue float64
+	)
+	for _, peer := range pool.sortedPeers {
+		if peer.didTimeout {
+			continue
+		}
+		if minHeight < peer.base || maxHeight > peer.height {
+			continue
+		}
+		if int64(peer.numPending)+maxHeight-minHeight+1 > maxPendingRequestsPerPeer {
+			continue
+		}
+		if value := pool.scores.value(peer.id) / float64(1+peer.numPending); bestID == "" || value > bestValue {
+			bestID, bestValue = peer.id, value
+		}
+	}
+	return bestID
+}
+
+// Sort peers by score, highest first.
 //
 // CONTRACT: pool.mtx must be locked.
 func (pool *BlockPool) sortPeers() {
-	sort.Slice(pool.sortedPeers, func(i, j int) bool {
-		return pool.sortedPeers[i].curRate > pool.sortedPeers[j].curRate
+	values := make(map[p2p.ID]float64, len(pool.sortedPeers))
+	for _, peer := range pool.sortedPeers {
+		values[peer.id] = pool.scores.value(peer.id)
+	}
+	sort.SliceStable(pool.sortedPeers, func(i, j int) bool {
+		return values[pool.sortedPeers[i].id] > values[pool.sortedPeers[j].id]
 	})
 }
 
-func (pool *BlockPool) makeNextRequester(nextHeight int64) {
+// hedgeDelay returns how long to wait for the block at the pool's height
+// before requesting it from a peer other than peerID.
+func (pool *BlockPool) hedgeDelay(peerID p2p.ID) time.Duration {
+	delay := 2 * pool.scores.get(peerID).Latency
+	if delay < minHedgeDelay {
+		return minHedgeDelay
+	}
+	if delay > maxHedgeDelay {
+		return maxHedgeDelay
+	}
+	return delay
+}
+
+// makeNextRequesters creates the requesters for up to requestRangeSize
+// heights starting at nextHeight, all of them preferring the best peer able
+// to serve the whole range. If there is no such peer, a single requester is
+// created.
+func (pool *BlockPool) makeNextRequesters(nextHeight int64) {
 	pool.mtx.Lock()
-	request := newBPRequester(pool, nextHeight)
-	pool.requesters[nextHeight] = request
+	lastHeight := nextHeight + requestRangeSize - 1
+	if lastHeight > pool.maxPeerHeight {
+		lastHeight = pool.maxPeerHeight
+	}
+	if free := int64(len(pool.peers)*maxPendingRequestsPerPeer - len(pool.requesters)); lastHeight-nextHeight+1 > free {
+		lastHeight = nextHeight + free - 1
+	}
+	peerID := pool.pickRangePeer(nextHeight, lastHeight)
+	if peerID == "" {
+		lastHeight = nextHeight
+	}
+	requesters := make([]*bpRequester, 0, lastHeight-nextHeight+1)
+	for height := nextHeight; height <= lastHeight; height++ {
+		request := newBPRequester(pool, height, peerID)
+		pool.requesters[height] = request
+		requesters = append(requesters, request)
+	}
 	pool.mtx.Unlock()
 
-	if err := request.Start(); err != nil {
-		request.Logger.Error("Error starting request", "err", err)
+	for _, request := range requesters {
+		if err := request.Start(); err != nil {
+			request.Logger.Error("Error starting request", "err", err)
+		}
 	}
 }
 
@@ -490,6 +603,7 @@ func (pool *BlockPool) sendRequest(height int64, peerID p2p.ID) {
 
 // thread-safe.
 func (pool *BlockPool) sendError(err error, peerID p2p.ID) {
+	pool.scores.recordError(peerID)
 	if !pool.IsRunning() {
 		return
 	}
@@ -596,12 +710,12 @@ func (peer *bpPeer) onTimeout() {
 
 // bpRequester requests a block from a peer.
 //
-// If the height is within minBlocksForSingleRequest blocks of the pool's
-// height, it will send an additional request to another peer. This is to avoid
-// a situation where blocksync is stuck because of a single slow peer. Note
-// that it's okay to send a single request when the requested height is far
-// from the pool's height. If the peer is slow, it will timeout and be replaced
-// with another peer.
+// If the height is the pool's height and the peer did not send the block
+// within the hedge delay (see BlockPool.hedgeDelay), it will send an
+// additional request to another peer. This is to avoid a situation where
+// blocksync is stuck because of a single slow peer. Note that it's okay to
+// send a single request for the other heights. If the peer is slow, it will
+// timeout and be replaced with another peer.
 type bpRequester struct {
 	service.BaseService
 
@@ -611,15 +725,19 @@ type bpRequester struct {
 	redoCh      chan p2p.ID // redo may got multiple messages, add peerId to identify repeat
 	newHeightCh chan int64
 
-	mtx          cmtsync.Mutex
-	peerID       p2p.ID
-	secondPeerID p2p.ID // alternative peer to request from (if close to pool's height)
-	gotBlockFrom p2p.ID
-	block        *types.Block
-	extCommit    *types.ExtendedCommit
+	mtx                   cmtsync.Mutex
+	preferredPeerID       p2p.ID // peer serving the range this height belongs to
+	peerID                p2p.ID
+	secondPeerID          p2p.ID // alternative peer to request from (if at pool's height)
+	peerRequestedAt       time.Time
+	secondPeerRequestedAt time.Time
+	headSince             time.Time // when the height became the pool's height
+	gotBlockFrom          p2p.ID
+	block                 *types.Block
+	extCommit             *types.ExtendedCommit
 }
 
-func newBPRequester(pool *BlockPool, height int64) *bpRequester {
+func newBPRequester(pool *BlockPool, height int64, preferredPeerID p2p.ID) *bpRequester {
 	bpr := &bpRequester{
 		pool:        pool,
 		height:      height,
@@ -627,9 +745,10 @@ func newBPRequester(pool *BlockPool, height int64) *bpRequester {
 		redoCh:      make(chan p2p.ID, 1),
 		newHeightCh: make(chan int64, 1),
 
-		peerID:       "",
-		secondPeerID: "",
-		block:        nil,
+		preferredPeerID: preferredPeerID,
+		peerID:          "",
+		secondPeerID:    "",
+		block:           nil,
 	}
 	bpr.BaseService = *service.NewBaseService(nil, "bpRequester", bpr)
 	return bpr
@@ -697,6 +816,20 @@ func (bpr *bpRequester) didRequestFrom(peerID p2p.ID) bool {
 	return bpr.peerID == peerID || bpr.secondPeerID == peerID
 }
 
+// Returns the time elapsed since we requested the block from the given peer,
+// or 0 if we did not.
+func (bpr *bpRequester) requestLatency(peerID p2p.ID) time.Duration {
+	bpr.mtx.Lock()
+	defer bpr.mtx.Unlock()
+	switch peerID {
+	case bpr.peerID:
+		return time.Since(bpr.peerRequestedAt)
+	case bpr.secondPeerID:
+		return time.Since(bpr.secondPeerRequestedAt)
+	}
+	return 0
+}
+
 // Returns the ID of the peer who sent us the block.
 func (bpr *bpRequester) gotBlockFromPeerID() p2p.ID {
 	bpr.mtx.Lock()
@@ -739,6 +872,10 @@ func (bpr *bpRequester) redo(peerID p2p.ID) {
 func (bpr *bpRequester) pickPeerAndSendRequest() {
 	bpr.mtx.Lock()
 	secondPeerID := bpr.secondPeerID
+	// The range peer is only preferred for the first request; if it failed,
+	// any other peer will do.
+	preferredPeerID := bpr.preferredPeerID
+	bpr.preferredPeerID = ""
 	bpr.mtx.Unlock()
 
 	var peer *bpPeer
@@ -747,7 +884,7 @@ PICK_PEER_LOOP:
 		if !bpr.IsRunning() || !bpr.pool.IsRunning() {
 			return
 		}
-		peer = bpr.pool.pickIncrAvailablePeer(bpr.height, secondPeerID)
+		peer = bpr.pool.pickIncrAvailablePeer(bpr.height, preferredPeerID, secondPeerID)
 		if peer == nil {
 			bpr.Logger.Debug("No peers currently available; will retry shortly", "height", bpr.height)
 			time.Sleep(requestInterval)
@@ -757,6 +894,7 @@ PICK_PEER_LOOP:
 	}
 	bpr.mtx.Lock()
 	bpr.peerID = peer.id
+	bpr.peerRequestedAt = time.Now()
 	bpr.mtx.Unlock()
 
 	bpr.pool.sendRequest(bpr.height, peer.id)
@@ -773,10 +911,11 @@ func (bpr *bpRequester) pickSecondPeerAndSendRequest() (picked bool) {
 	peerID := bpr.peerID
 	bpr.mtx.Unlock()
 
-	secondPeer := bpr.pool.pickIncrAvailablePeer(bpr.height, peerID)
+	secondPeer := bpr.pool.pickIncrAvailablePeer(bpr.height, "", peerID)
 	if secondPeer != nil {
 		bpr.mtx.Lock()
 		bpr.secondPeerID = secondPeer.id
+		bpr.secondPeerRequestedAt = time.Now()
 		bpr.mtx.Unlock()
 
 		bpr.pool.sendRequest(bpr.height, secondPeer.id)
@@ -794,6 +933,43 @@ func (bpr *bpRequester) newHeight(height int64) {
 	}
 }
 
+// Records that the block is at the pool's height, if it is. Returns true if
+// so.
+func (bpr *bpRequester) markHead(poolHeight int64) bool {
+	if poolHeight != bpr.height {
+		return false
+	}
+	bpr.mtx.Lock()
+	defer bpr.mtx.Unlock()
+	if bpr.headSince.IsZero() {
+		bpr.headSince = time.Now()
+	}
+	return true
+}
+
+// Returns the first peer we requested the block from.
+func (bpr *bpRequester) firstPeerID() p2p.ID {
+	bpr.mtx.Lock()
+	defer bpr.mtx.Unlock()
+	return bpr.peerID
+}
+
+// Reports the time the pool waited for this block at its height, if it did,
+// as stall time of the first peer we requested the block from.
+func (bpr *bpRequester) recordStall() {
+	bpr.mtx.Lock()
+	headSince, peerID := bpr.headSince, bpr.peerID
+	if peerID == "" {
+		peerID = bpr.gotBlockFrom
+	}
+	bpr.mtx.Unlock()
+
+	if headSince.IsZero() {
+		return
+	}
+	bpr.pool.metrics.PeerStallSeconds.With("peer_id", string(peerID)).Add(time.Since(headSince).Seconds())
+}
+
 // Responsible for making more requests as necessary
 // Returns only when a block is found (e.g. AddBlock() is called).
 func (bpr *bpRequester) requestRoutine() {
@@ -803,14 +979,16 @@ OUTER_LOOP:
 	for {
 		bpr.pickPeerAndSendRequest()
 
-		poolHeight := bpr.pool.Height()
-		if bpr.height-poolHeight < minBlocksForSingleRequest {
-			bpr.pickSecondPeerAndSendRequest()
-		}
-
 		retryTimer := time.NewTimer(requestRetrySeconds * time.Second)
 		defer retryTimer.Stop()
 
+		// Fires when it is time to request the block at the pool's height
+		// from a second peer.
+		var hedgeCh <-chan time.Time
+		if !gotBlock && bpr.markHead(bpr.pool.Height()) {
+			hedgeCh = time.After(bpr.pool.hedgeDelay(bpr.firstPeerID()))
+		}
+
 		for {
 			select {
 			case <-bpr.pool.Quit():
@@ -841,7 +1019,12 @@ OUTER_LOOP:
 					continue OUTER_LOOP
 				}
 			case newHeight := <-bpr.newHeightCh:
-				if !gotBlock && bpr.height-newHeight < minBlocksForSingleRequest {
+				if !gotBlock && hedgeCh == nil && bpr.markHead(newHeight) {
+					hedgeCh = time.After(bpr.pool.hedgeDelay(bpr.firstPeerID()))
+				}
+			case <-hedgeCh:
+				hedgeCh = nil
+				if !gotBlock {
 					// The operation is a noop if the second peer is already set. The cost is checking a mutex.
 					//
 					// If the second peer was just set, reset the retryTimer to give the
@@ -853,6 +1036,7 @@ OUTER_LOOP:
 				}
 			case <-bpr.gotBlockCh:
 				gotBlock = true
+				bpr.recordStall()
 				// We got a block!
 				// Continue the for-loop and wait til Quit.
 			}
@@ -1,6 +1,7 @@
 package blocksync
 
 import (
+	"path/filepath"
 	"strconv"
 	"testing"
 	"time"
@@ -383,3 +384,56 @@ func TestBlockPoolMaliciousNode(t *testing.T) {
 		}
 	}
 }
+
+func TestBlockPoolRequestsRanges(t *testing.T) {
+	requestsCh := make(chan BlockRequest)
+	errorsCh := make(chan peerError, 10)
+
+	pool := NewBlockPool(1, requestsCh, errorsCh)
+	pool.SetLogger(log.TestingLogger())
+	pool.scoresFile = filepath.Join(t.TempDir(), "peer_scores.json")
+	// "slow" has a worse history than "fast", so it is picked last.
+	pool.scores.recordBlock("slow", 5*time.Second, minRecvRate)
+	pool.scores.recordError("slow")
+
+	require.NoError(t, pool.Start())
+	pool.SetPeerRange("slow", 1, 100)
+	pool.SetPeerRange("fast", 1, 100)
+
+	// Each range of requestRangeSize heights is requested from a single peer.
+	requestedFrom := make(map[int64]p2p.ID)
+	for len(requestedFrom) < 3*requestRangeSize {
+		select {
+		case request := <-requestsCh:
+			if _, ok := requestedFrom[request.Height]; !ok {
+				requestedFrom[request.Height] = request.PeerID
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatal("Timed out waiting for block requests")
+		}
+	}
+	assert.EqualValues(t, "fast", requestedFrom[1])
+	for height := int64(1); height <= 3*requestRangeSize; height++ {
+		first := height - (height-1)%requestRangeSize
+		assert.Equal(t, requestedFrom[first], requestedFrom[height], "height %d", height)
+	}
+
+	// The scores are saved when the pool stops.
+	require.NoError(t, pool.Stop())
+	scores := newPeerScores()
+	require.NoError(t, scores.loadFromFile(pool.scoresFile))
+	assert.InDelta(t, 0.5, scores.get("slow").Errors, 1e-9)
+	assert.Greater(t, scores.value("fast"), scores.value("slow"))
+}
+
+func TestBlockPoolHedgeDelay(t *testing.T) {
+	pool := NewBlockPool(1, make(chan BlockRequest), make(chan peerError, 10))
+
+	assert.Equal(t, minHedgeDelay, pool.hedgeDelay("unknown"))
+
+	pool.scores.recordBlock("peer", 300*time.Millisecond, 0)
+	assert.Equal(t, 600*time.Millisecond, pool.hedgeDelay("peer"))
+
+	pool.scores.recordBlock("slow", time.Minute, 0)
+	assert.Equal(t, maxHedgeDelay, pool.hedgeDelay("slow"))
+}
@@ -1,6 +1,9 @@
 package blocksync
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"sync"
@@ -13,6 +16,7 @@ import (
 	sm "github.com/cometbft/cometbft/state"
 	"github.com/cometbft/cometbft/store"
 	"github.com/cometbft/cometbft/types"
+	cmttime "github.com/cometbft/cometbft/types/time"
 )
 
 const (
@@ -29,8 +33,17 @@ const (
 	statusUpdateIntervalSeconds = 10
 	// check if we should switch to consensus reactor.
 	switchToConsensusIntervalSeconds = 1
+
+	// number of heights above the pool height verified by the light client
+	lightVerifyAheadBlocks = 100
+	// wait before verifying heights again when the light client is idle or failed.
+	lightVerifyIntervalMS = 100
 )
 
+// lightClientRetryInterval is the time waited before retrying to create the
+// light client.
+var lightClientRetryInterval = 5 * time.Second
+
 type consensusReactor interface {
 	// for when we switch from blocksync reactor and block sync to
 	// the consensus machine
@@ -42,6 +55,15 @@ type mempoolReactor interface {
 	EnableInOutTxs()
 }
 
+// LightClient is the subset of light.Client used to verify synced blocks.
+type LightClient interface {
+	// VerifyLightBlockAtHeight fetches and verifies the light block at the
+	// given height, unless it was already verified.
+	VerifyLightBlockAtHeight(ctx context.Context, height int64, now time.Time) (*types.LightBlock, error)
+	// TrustedLightBlock returns the verified light block at the given height.
+	TrustedLightBlock(height int64) (*types.LightBlock, error)
+}
+
 type peerError struct {
 	err    error
 	peerID p2p.ID
@@ -70,12 +92,44 @@ type Reactor struct {
 
 	switchToConsensusMs int
 
+	// if set, synced blocks must match the headers verified by the light
+	// client it creates
+	newLightClient func(ctx context.Context) (LightClient, error)
+	lightMtx       sync.Mutex
+	lightClient    LightClient
+
 	metrics *Metrics
 }
 
+type ReactorOption func(*Reactor)
+
+// WithLightClient makes the reactor check every synced block against the
+// header verified by lc at its height before applying it. The blocks are held
+// in the pool until lc verifies their header. A block that disagrees with a
+// verified header is rejected and the peer that served it is banned.
+func WithLightClient(lc LightClient) ReactorOption {
+	return WithLightClientFunc(func(context.Context) (LightClient, error) { return lc, nil })
+}
+
+// WithLightClientFunc is like WithLightClient, with the light client created
+// by newLightClient once block sync starts. The creation is retried until it
+// succeeds, so that the node starts while the light client's primary is
+// unreachable.
+func WithLightClientFunc(newLightClient func(ctx context.Context) (LightClient, error)) ReactorOption {
+	return func(bcR *Reactor) { bcR.newLightClient = newLightClient }
+}
+
+// WithPeerScoresFile makes the block pool load the scores of the peers from
+// filePath when it starts and save them back when it stops, so that peers that
+// served blocks well in previous sessions are picked first.
+func WithPeerScoresFile(filePath string) ReactorOption {
+	return func(bcR *Reactor) { bcR.pool.scoresFile = filePath }
+}
+
 // NewReactor returns new reactor instance.
 func NewReactor(state sm.State, blockExec *sm.BlockExecutor, store *store.BlockStore,
 	blockSync bool, localAddr crypto.Address, metrics *Metrics, offlineStateSyncHeight int64,
+	options ...ReactorOption,
 ) *Reactor {
 	storeHeight := store.Height()
 	if storeHeight == 0 {
@@ -104,6 +158,7 @@ func NewReactor(state sm.State, blockExec *sm.BlockExecutor, store *store.BlockS
 		startHeight = state.InitialHeight
 	}
 	pool := NewBlockPool(startHeight, requestsCh, errorsCh)
+	pool.metrics = metrics
 
 	bcR := &Reactor{
 		initialState: state,
@@ -116,6 +171,9 @@ func NewReactor(state sm.State, blockExec *sm.BlockExecutor, store *store.BlockS
 		errorsCh:     errorsCh,
 		metrics:      metrics,
 	}
+	for _, option := range options {
+		option(bcR)
+	}
 	bcR.BaseReactor = *p2p.NewBaseReactor("Reactor", bcR)
 	return bcR
 }
@@ -133,6 +191,7 @@ func (bcR *Reactor) OnStart() error {
 		if err != nil {
 			return err
 		}
+		bcR.startLightRoutine()
 		bcR.poolRoutineWg.Add(1)
 		go func() {
 			defer bcR.poolRoutineWg.Done()
@@ -152,6 +211,7 @@ func (bcR *Reactor) SwitchToBlockSync(state sm.State) error {
 	if err != nil {
 		return err
 	}
+	bcR.startLightRoutine()
 	bcR.poolRoutineWg.Add(1)
 	go func() {
 		defer bcR.poolRoutineWg.Done()
@@ -401,6 +461,14 @@ FOR_LOOP:
 			}
 
 			if state, err = bcR.processBlock(first, second, firstParts, state, extCommit); err != nil {
+				if errors.Is(err, errLightBlockNotVerified) {
+					// Wait for the light client until the next tick.
+					select {
+					case <-didProcessCh:
+					default:
+					}
+					continue FOR_LOOP
+				}
 				bcR.Logger.Error("Invalid block", "height", first.Height, "err", err)
 				continue FOR_LOOP
 			}
@@ -424,6 +492,111 @@ FOR_LOOP:
 	}
 }
 
+func (bcR *Reactor) startLightRoutine() {
+	if bcR.newLightClient == nil {
+		return
+	}
+	bcR.poolRoutineWg.Add(1)
+	go func() {
+		defer bcR.poolRoutineWg.Done()
+		bcR.lightRoutine()
+	}()
+}
+
+// lightRoutine creates the light client, and verifies the headers of the
+// heights the pool is about to apply while the pool is running.
+func (bcR *Reactor) lightRoutine() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-bcR.Quit():
+		case <-bcR.pool.Quit():
+		case <-ctx.Done():
+		}
+		cancel()
+	}()
+
+	lc := bcR.getLightClient()
+	for lc == nil {
+		var err error
+		lc, err = bcR.newLightClient(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			bcR.Logger.Error("Failed to create light client, retrying", "err", err, "retry_in", lightClientRetryInterval)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(lightClientRetryInterval):
+			}
+		}
+	}
+	bcR.lightMtx.Lock()
+	bcR.lightClient = lc
+	bcR.lightMtx.Unlock()
+
+	ticker := time.NewTicker(lightVerifyIntervalMS * time.Millisecond)
+	defer ticker.Stop()
+	lastErrorLog := time.Time{}
+
+	for {
+		// Verify the heights from the next block to apply, so that the pool
+		// doesn't wait for them.
+		height := bcR.pool.Height()
+		maxHeight := min(height+lightVerifyAheadBlocks, bcR.pool.MaxPeerHeight())
+		for h := height; h <= maxHeight && ctx.Err() == nil; h++ {
+			if _, err := lc.TrustedLightBlock(h); err == nil {
+				continue
+			}
+			lb, err := lc.VerifyLightBlockAtHeight(ctx, h, cmttime.Now())
+			if err != nil {
+				// The blocks at h and above are held until it succeeds.
+				if ctx.Err() == nil && time.Since(lastErrorLog) > statusUpdateIntervalSeconds*time.Second {
+					bcR.Logger.Error("Failed to verify header with light client", "height", h, "err", err)
+					lastErrorLog = time.Now()
+				}
+				break
+			}
+			bcR.Logger.Debug("Light client verified header", "height", lb.Height, "hash", lb.Hash())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (bcR *Reactor) getLightClient() LightClient {
+	bcR.lightMtx.Lock()
+	defer bcR.lightMtx.Unlock()
+	return bcR.lightClient
+}
+
+// verifyWithLightClient checks that block matches the header verified by the
+// light client at its height. It returns errLightBlockNotVerified if the light
+// client has not verified the height yet.
+func (bcR *Reactor) verifyWithLightClient(block *types.Block) error {
+	if bcR.newLightClient == nil {
+		return nil
+	}
+	lc := bcR.getLightClient()
+	if lc == nil {
+		return errLightBlockNotVerified
+	}
+	lb, err := lc.TrustedLightBlock(block.Height)
+	if err != nil {
+		return errLightBlockNotVerified
+	}
+	if hash := block.Hash(); !bytes.Equal(hash, lb.Hash()) {
+		return ErrLightBlockMismatch{Height: block.Height, Expected: lb.Hash(), Got: hash}
+	}
+	return nil
+}
+
 // BroadcastStatusRequest broadcasts `BlockStore` base and height.
 func (bcR *Reactor) BroadcastStatusRequest() {
 	bcR.Switch.Broadcast(p2p.Envelope{
@@ -538,8 +711,15 @@ func (bcR *Reactor) processBlock(first, second *types.Block, firstParts *types.P
 	// first.Hash() doesn't verify the tx contents, so MakePartSet() is
 	// currently necessary.
 	// TODO(sergio): Should we also validate against the extended commit?
-	err := state.Validators.VerifyCommitLight(
-		chainID, firstID, first.Height, second.LastCommit)
+	err := bcR.verifyWithLightClient(first)
+	if errors.Is(err, errLightBlockNotVerified) {
+		// Hold the block in the pool until its header is verified.
+		return state, err
+	}
+	if err == nil {
+		err = state.Validators.VerifyCommitLight(
+			chainID, firstID, first.Height, second.LastCommit)
+	}
 
 	if err == nil {
 		// validate the block before we persist it
@@ -565,6 +745,7 @@ func (bcR *Reactor) processBlock(first, second *types.Block, firstParts *types.P
 		if peer != nil {
 			// NOTE: we've already removed the peer's request, but we
 			// still need to clean up the rest.
+			bcR.Switch.ReportBehavior(peer, p2p.PeerBehaviorBadBlock)
 			bcR.Switch.StopPeerForError(peer, ErrReactorValidation{Err: err})
 		}
 		peerID2 := bcR.pool.RemovePeerAndRedoAllPeerRequests(second.Height)
@@ -572,6 +753,7 @@ func (bcR *Reactor) processBlock(first, second *types.Block, firstParts *types.P
 		if peer2 != nil && peer2 != peer {
 			// NOTE: we've already removed the peer's request, but we
 			// still need to clean up the rest.
+			bcR.Switch.ReportBehavior(peer2, p2p.PeerBehaviorBadBlock)
 			bcR.Switch.StopPeerForError(peer2, ErrReactorValidation{Err: err})
 		}
 		return state, err
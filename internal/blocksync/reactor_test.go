@@ -1,10 +1,13 @@
 package blocksync
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"reflect"
 	"sort"
+	"sync"
 	"testing"
 	"time"
 
@@ -242,6 +245,148 @@ func TestNoBlockResponse(t *testing.T) {
 	}
 }
 
+// mockLightClient verifies the headers of store up to maxHeight, except the
+// header at forkHeight, which does not belong to the synced chain.
+type mockLightClient struct {
+	store      sm.BlockStore
+	forkHeight int64
+	maxHeight  int64
+
+	mtx      sync.Mutex
+	verified map[int64]*types.LightBlock
+}
+
+func newMockLightClient(store sm.BlockStore, forkHeight, maxHeight int64) *mockLightClient {
+	return &mockLightClient{
+		store:      store,
+		forkHeight: forkHeight,
+		maxHeight:  maxHeight,
+		verified:   make(map[int64]*types.LightBlock),
+	}
+}
+
+func (lc *mockLightClient) VerifyLightBlockAtHeight(_ context.Context, height int64, _ time.Time) (*types.LightBlock, error) {
+	lc.mtx.Lock()
+	defer lc.mtx.Unlock()
+	if height > lc.maxHeight {
+		return nil, errors.New("no header at height")
+	}
+	header := &types.Header{
+		ChainID:        test.DefaultTestChainID,
+		Height:         height,
+		ValidatorsHash: []byte("validators"),
+	}
+	if height != lc.forkHeight {
+		meta := lc.store.LoadBlockMeta(height)
+		if meta == nil {
+			return nil, errors.New("no header at height")
+		}
+		header = &meta.Header
+	}
+	lb := &types.LightBlock{SignedHeader: &types.SignedHeader{Header: header}}
+	lc.verified[height] = lb
+	return lb, nil
+}
+
+func (lc *mockLightClient) TrustedLightBlock(height int64) (*types.LightBlock, error) {
+	lc.mtx.Lock()
+	defer lc.mtx.Unlock()
+	lb, ok := lc.verified[height]
+	if !ok {
+		return nil, errors.New("not verified")
+	}
+	return lb, nil
+}
+
+func newLightClientReactorPairs(t *testing.T, maxBlockHeight int64) []ReactorPair {
+	t.Helper()
+	config = test.ResetTestRoot("blocksync_reactor_test")
+	t.Cleanup(func() { os.RemoveAll(config.RootDir) })
+	genDoc, privVals := randGenesisDoc()
+
+	reactorPairs := make([]ReactorPair, 2)
+	reactorPairs[0] = newReactor(t, log.TestingLogger(), genDoc, privVals, maxBlockHeight)
+	reactorPairs[1] = newReactor(t, log.TestingLogger(), genDoc, privVals, 0)
+	t.Cleanup(func() {
+		for _, r := range reactorPairs {
+			err := r.reactor.Stop()
+			require.NoError(t, err)
+			err = r.app.Stop()
+			require.NoError(t, err)
+		}
+	})
+	return reactorPairs
+}
+
+func TestLightClientMismatchStopsPeer(t *testing.T) {
+	const forkHeight = int64(10)
+
+	reactorPairs := newLightClientReactorPairs(t, 30)
+	lc := newMockLightClient(reactorPairs[0].reactor.store, forkHeight, 30)
+	WithLightClient(lc)(reactorPairs[1].reactor.Reactor)
+
+	p2p.MakeConnectedSwitches(config.P2P, 2, func(i int, s *p2p.Switch) *p2p.Switch {
+		s.AddReactor("BLOCKSYNC", reactorPairs[i].reactor)
+		return s
+	}, p2p.Connect2Switches)
+
+	// The peer serving the chain disagreeing with the light client is stopped.
+	require.Eventually(t, func() bool {
+		return reactorPairs[1].reactor.Switch.Peers().Size() == 0
+	}, 30*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, forkHeight-1, reactorPairs[1].reactor.store.Height())
+	pool := reactorPairs[1].reactor.pool
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+	assert.True(t, pool.isPeerBanned(reactorPairs[0].reactor.Switch.NodeInfo().ID()))
+}
+
+func TestLightClientHoldsUnverifiedBlocks(t *testing.T) {
+	const maxVerifiedHeight = int64(15)
+
+	defer func(interval time.Duration) { lightClientRetryInterval = interval }(lightClientRetryInterval)
+	lightClientRetryInterval = 10 * time.Millisecond
+
+	reactorPairs := newLightClientReactorPairs(t, 30)
+	lc := newMockLightClient(reactorPairs[0].reactor.store, 0, maxVerifiedHeight)
+	// The light client is created once its primary is reachable.
+	attempts := 0
+	WithLightClientFunc(func(context.Context) (LightClient, error) {
+		attempts++
+		if attempts < 3 {
+			return nil, errors.New("primary unreachable")
+		}
+		return lc, nil
+	})(reactorPairs[1].reactor.Reactor)
+
+	p2p.MakeConnectedSwitches(config.P2P, 2, func(i int, s *p2p.Switch) *p2p.Switch {
+		s.AddReactor("BLOCKSYNC", reactorPairs[i].reactor)
+		return s
+	}, p2p.Connect2Switches)
+
+	// Every applied block was verified, and the blocks above the verified
+	// heights are held without punishing the peer.
+	require.Eventually(t, func() bool {
+		return reactorPairs[1].reactor.store.Height() == maxVerifiedHeight
+	}, 30*time.Second, 10*time.Millisecond)
+	time.Sleep(500 * time.Millisecond)
+	assert.Equal(t, maxVerifiedHeight, reactorPairs[1].reactor.store.Height())
+	assert.Equal(t, 1, reactorPairs[1].reactor.Switch.Peers().Size())
+	for h := int64(1); h <= maxVerifiedHeight; h++ {
+		_, err := lc.TrustedLightBlock(h)
+		require.NoError(t, err, "height %d", h)
+	}
+
+	// The held blocks are applied once verified.
+	lc.mtx.Lock()
+	lc.maxHeight = 30
+	lc.mtx.Unlock()
+	require.Eventually(t, func() bool {
+		return reactorPairs[1].reactor.store.Height() >= 25
+	}, 30*time.Second, 10*time.Millisecond)
+}
+
 // NOTE: This is too hard to test without
 // an easy way to add test peer to switch
 // or without significant refactoring of the module.
@@ -168,6 +168,18 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Name:      "full_prevote_delay",
 			Help:      "Interval in seconds between the proposal timestamp and the timestamp of the latest prevote in a round where all validators voted.",
 		}, append(labels, "proposer_address")).With(labelsAndValues...),
+		ProposeTimeoutSeconds: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "propose_timeout_seconds",
+			Help:      "Timeout in seconds to wait for a proposal in round 0 of the current height.",
+		}, labels).With(labelsAndValues...),
+		VoteTimeoutSeconds: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "vote_timeout_seconds",
+			Help:      "Timeout in seconds to wait for straggler votes in round 0 of the current height.",
+		}, labels).With(labelsAndValues...),
 		VoteExtensionReceiveCount: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
 			Namespace: namespace,
 			Subsystem: MetricsSubsystem,
@@ -236,6 +248,8 @@ func NopMetrics() *Metrics {
 		BlockGossipPartsReceived:    discard.NewCounter(),
 		QuorumPrevoteDelay:          discard.NewGauge(),
 		FullPrevoteDelay:            discard.NewGauge(),
+		ProposeTimeoutSeconds:       discard.NewGauge(),
+		VoteTimeoutSeconds:          discard.NewGauge(),
 		VoteExtensionReceiveCount:   discard.NewCounter(),
 		ProposalReceiveCount:        discard.NewCounter(),
 		ProposalCreateCount:         discard.NewCounter(),
@@ -98,6 +98,16 @@ type Metrics struct {
 	// metrics:Interval in seconds between the proposal timestamp and the timestamp of the latest prevote in a round where all validators voted.
 	FullPrevoteDelay metrics.Gauge `metrics_labels:"proposer_address"`
 
+	// ProposeTimeoutSeconds is the timeout_propose in use for round 0 of the
+	// current height. It only changes if adaptive timeouts are enabled.
+	// metrics:Timeout in seconds to wait for a proposal in round 0 of the current height.
+	ProposeTimeoutSeconds metrics.Gauge
+
+	// VoteTimeoutSeconds is the timeout_vote in use for round 0 of the
+	// current height. It only changes if adaptive timeouts are enabled.
+	// metrics:Timeout in seconds to wait for straggler votes in round 0 of the current height.
+	VoteTimeoutSeconds metrics.Gauge
+
 	// VoteExtensionReceiveCount is the number of vote extensions received by this
 	// node. The metric is annotated by the status of the vote extension from the
 	// application, either 'accepted' or 'rejected'.
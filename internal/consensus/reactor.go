@@ -33,6 +33,10 @@ const (
 
 	blocksToContributeToBecomeGoodPeer = 10000
 	votesToContributeToBecomeGoodPeer  = 10000
+
+	// the good behaviors of peers are reported to the switch in batches
+	blockPartsToReportGoodBehavior = 100
+	votesToReportGoodBehavior      = 100
 )
 
 // -----------------------------------------------------------------------------
@@ -154,12 +158,15 @@ conR:
 // GetChannels implements Reactor.
 func (*Reactor) GetChannels() []*p2p.ChannelDescriptor {
 	// TODO optimize
+	// The state, data and vote channels are guaranteed a minimum bandwidth,
+	// so that consensus can't be starved by the other reactors.
 	return []*p2p.ChannelDescriptor{
 		{
 			ID:                  StateChannel,
 			Priority:            6,
 			SendQueueCapacity:   100,
 			RecvMessageCapacity: maxMsgSize,
+			MinSendRate:         20480, // 20KB/s
 			MessageType:         &cmtcons.Message{},
 		},
 		{
@@ -169,6 +176,7 @@ func (*Reactor) GetChannels() []*p2p.ChannelDescriptor {
 			SendQueueCapacity:   100,
 			RecvBufferCapacity:  50 * 4096,
 			RecvMessageCapacity: maxMsgSize,
+			MinSendRate:         204800, // 200KB/s
 			MessageType:         &cmtcons.Message{},
 		},
 		{
@@ -177,6 +185,7 @@ func (*Reactor) GetChannels() []*p2p.ChannelDescriptor {
 			SendQueueCapacity:   100,
 			RecvBufferCapacity:  100 * 100,
 			RecvMessageCapacity: maxMsgSize,
+			MinSendRate:         102400, // 100KB/s
 			MessageType:         &cmtcons.Message{},
 		},
 		{
@@ -1031,14 +1040,26 @@ func (conR *Reactor) peerStatsRoutine() {
 			}
 			switch msg.Msg.(type) {
 			case *VoteMessage:
-				if numVotes := ps.RecordVote(); numVotes%votesToContributeToBecomeGoodPeer == 0 {
+				numVotes := ps.RecordVote()
+				if numVotes%votesToReportGoodBehavior == 0 {
+					conR.Switch.ReportBehavior(peer, p2p.PeerBehaviorGoodVotes)
+				}
+				if numVotes%votesToContributeToBecomeGoodPeer == 0 {
 					conR.Switch.MarkPeerAsGood(peer)
 				}
 			case *BlockPartMessage:
-				if numParts := ps.RecordBlockPart(); numParts%blocksToContributeToBecomeGoodPeer == 0 {
+				numParts := ps.RecordBlockPart()
+				if numParts%blockPartsToReportGoodBehavior == 0 {
+					conR.Switch.ReportBehavior(peer, p2p.PeerBehaviorGoodBlockParts)
+				}
+				if numParts%blocksToContributeToBecomeGoodPeer == 0 {
 					conR.Switch.MarkPeerAsGood(peer)
 				}
 			}
+		case pb := <-conR.conS.behaviorQueue:
+			if peer := conR.Switch.Peers().Get(pb.PeerID); peer != nil {
+				conR.Switch.ReportBehavior(peer, pb.Behavior)
+			}
 		case <-conR.conS.Quit():
 			return
 
@@ -47,6 +47,17 @@ func startConsensusNet(t *testing.T, css []*State, n int) (
 	[]*Reactor,
 	[]types.Subscription,
 	[]*types.EventBus,
+) {
+	t.Helper()
+	return startConsensusNetOver(t, css, n, nil)
+}
+
+// startConsensusNetOver starts a testnet whose switches are connected over
+// network, or over net.Pipe if network is nil.
+func startConsensusNetOver(t *testing.T, css []*State, n int, network *p2p.MemoryNetwork) (
+	[]*Reactor,
+	[]types.Subscription,
+	[]*types.EventBus,
 ) {
 	t.Helper()
 	reactors := make([]*Reactor, n)
@@ -73,11 +84,16 @@ func startConsensusNet(t *testing.T, css []*State, n int) (
 		}
 	}
 	// make connected switches and start all reactors
-	p2p.MakeConnectedSwitches(config.P2P, n, func(i int, s *p2p.Switch) *p2p.Switch {
+	initSwitch := func(i int, s *p2p.Switch) *p2p.Switch {
 		s.AddReactor("CONSENSUS", reactors[i])
 		s.SetLogger(reactors[i].conS.Logger.With("module", "p2p"))
 		return s
-	}, p2p.Connect2Switches)
+	}
+	if network != nil {
+		p2p.StartAndConnectSwitches(p2p.MakeMemorySwitches(config.P2P, n, network, initSwitch), p2p.Dial2Switches)
+	} else {
+		p2p.MakeConnectedSwitches(config.P2P, n, initSwitch, p2p.Connect2Switches)
+	}
 
 	// now that everyone is connected,  start the state machines
 	// If we started the state machines before everyone was connected,
@@ -120,6 +136,41 @@ func TestReactorBasic(t *testing.T) {
 	})
 }
 
+// Ensure a testnet keeps making blocks over links with latency while a
+// validator is partitioned off, and that the validator catches up once the
+// partition heals.
+func TestReactorMemoryNetworkPartition(t *testing.T) {
+	n := 4
+	css, cleanup := randConsensusNet(t, n, "consensus_reactor_test", NewTimeoutTicker, newKVStore)
+	defer cleanup()
+	network := p2p.NewMemoryNetwork(0)
+	network.SetDefaultLink(p2p.Link{Latency: 5 * time.Millisecond, Jitter: 5 * time.Millisecond})
+	reactors, blocksSubs, eventBuses := startConsensusNetOver(t, css, n, network)
+	defer stopConsensusNet(log.TestingLogger(), reactors, eventBuses)
+	// wait till everyone makes the first new block
+	timeoutWaitGroup(n, func(j int) {
+		<-blocksSubs[j].Out()
+	})
+
+	// The other validators hold 3/4 of the voting power.
+	network.Partition([]p2p.ID{reactors[n-1].Switch.NodeInfo().ID()})
+	partitionedHeight := css[n-1].GetLastHeight()
+	height := partitionedHeight + 3
+	timeoutWaitGroup(n-1, func(j int) {
+		for css[j].GetLastHeight() < height {
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+	assert.LessOrEqual(t, css[n-1].GetLastHeight(), partitionedHeight+1)
+
+	network.Heal()
+	timeoutWaitGroup(1, func(int) {
+		for css[n-1].GetLastHeight() < height {
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+}
+
 // Ensure we can process blocks with evidence.
 func TestReactorWithEvidence(t *testing.T) {
 	nValidators := 4
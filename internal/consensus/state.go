@@ -42,6 +42,12 @@ type msgInfo struct {
 	ReceiveTime time.Time `json:"receive_time"`
 }
 
+// peerBehavior is a behavior of a peer observed by the consensus state.
+type peerBehavior struct {
+	PeerID   p2p.ID
+	Behavior p2p.PeerBehavior
+}
+
 // internally generated messages which may update the state.
 type timeoutInfo struct {
 	Duration time.Duration         `json:"duration"`
@@ -107,6 +113,10 @@ type State struct {
 	// so statistics can be computed by reactor
 	statsMsgQueue chan msgInfo
 
+	// bad behaviors of peers are written on this channel so the reactor can
+	// report them to the switch
+	behaviorQueue chan peerBehavior
+
 	// we use eventBus to trigger msg broadcasts in the reactor,
 	// and to notify external subscribers, eg. through a websocket
 	eventBus *types.EventBus
@@ -117,6 +127,9 @@ type State struct {
 	replayMode   bool // so we don't log signing errors during replay
 	doWALCatchup bool // determines if we even try to do the catchup
 
+	// records a timeline of consensus events for post-mortem debugging
+	tracer TraceRecorder
+
 	// for tests where we want to limit the number of transitions the state makes
 	nSteps int
 
@@ -135,6 +148,11 @@ type State struct {
 	// for reporting metrics
 	metrics *Metrics
 
+	// propose and vote timeouts, possibly adapted to the observed network latency
+	timeouts *timeoutParams
+	// start times of the steps of the current round
+	roundTimings roundTimings
+
 	// offline state sync height indicating to which height the node synced offline
 	offlineStateSyncHeight int64
 
@@ -165,9 +183,11 @@ func NewState(
 		internalMsgQueue: make(chan msgInfo, msgQueueSize),
 		timeoutTicker:    NewTimeoutTicker(),
 		statsMsgQueue:    make(chan msgInfo, msgQueueSize),
+		behaviorQueue:    make(chan peerBehavior, msgQueueSize),
 		done:             make(chan struct{}),
 		doWALCatchup:     true,
 		wal:              nilWAL{},
+		tracer:           nilTraceRecorder{},
 		evpool:           evpool,
 		evsw:             cmtevents.NewEventSwitch(),
 		metrics:          NopMetrics(),
@@ -175,6 +195,8 @@ func NewState(
 	for _, option := range options {
 		option(cs)
 	}
+	cs.timeouts = newTimeoutParams(config)
+
 	// set function defaults (may be overwritten before calling Start)
 	cs.decideProposal = cs.defaultDecideProposal
 	cs.doPrevote = cs.defaultDoPrevote
@@ -226,6 +248,12 @@ func OfflineStateSyncHeight(height int64) StateOption {
 	return func(cs *State) { cs.offlineStateSyncHeight = height }
 }
 
+// StateTraceRecorder sets the recorder of the consensus timeline. If not
+// set, one is created from the config when the State starts.
+func StateTraceRecorder(tracer TraceRecorder) StateOption {
+	return func(cs *State) { cs.tracer = tracer }
+}
+
 // String returns a string.
 func (*State) String() string {
 	// better not to access shared variables
@@ -398,6 +426,21 @@ func (cs *State) OnStart() error {
 		return err
 	}
 
+	// Events aren't traced during replay, so the trace is only opened once
+	// nothing can fail anymore.
+	if _, ok := cs.tracer.(nilTraceRecorder); ok && cs.config.Trace {
+		tracer, err := NewTraceRecorder(cs.config.TraceFile(), cs.config.TraceMaxSize)
+		if err != nil {
+			cs.Logger.Error("Failed to open consensus trace", "err", err)
+			return err
+		}
+		tracer.SetLogger(cs.Logger.With("trace", cs.config.TraceFile()))
+		cs.tracer = tracer
+	}
+	if err := cs.tracer.Start(); err != nil {
+		return err
+	}
+
 	// now start the receiveRoutine
 	go cs.receiveRoutine(0)
 
@@ -724,6 +767,11 @@ func (cs *State) updateToState(state sm.State) {
 	cs.updateHeight(height)
 	cs.updateRoundStep(0, cstypes.RoundStepNewHeight)
 
+	// Timeouts only change at height boundaries.
+	cs.timeouts.update()
+	cs.metrics.ProposeTimeoutSeconds.Set(cs.timeouts.Propose(0).Seconds())
+	cs.metrics.VoteTimeoutSeconds.Set(cs.timeouts.Prevote(0).Seconds())
+
 	timeoutCommit := state.NextBlockDelay
 	// If the ABCI app didn't set a delay, use the deprecated config value.
 	if timeoutCommit == 0 {
@@ -804,6 +852,12 @@ func (cs *State) receiveRoutine(maxSteps int) {
 		}
 
 		cs.wal.Wait()
+
+		if err := cs.tracer.Stop(); err != nil {
+			cs.Logger.Error("Failed trying to stop consensus trace", "error", err)
+		}
+		cs.tracer.Wait()
+
 		close(cs.done)
 	}
 
@@ -898,10 +952,34 @@ func (cs *State) handleMsg(mi msgInfo) {
 		// will not cause transition.
 		// once proposal is set, we can receive block parts
 		err = cs.setProposal(msg.Proposal, mi.ReceiveTime)
+		if err == nil && peerID != "" && cs.Proposal == msg.Proposal {
+			cs.observeProposalDelay(mi.ReceiveTime)
+		}
+		polRound := msg.Proposal.POLRound
+		cs.trace(TraceEvent{
+			Time:     mi.ReceiveTime,
+			Height:   msg.Proposal.Height,
+			Round:    msg.Proposal.Round,
+			Type:     TraceEventProposal,
+			Peer:     peerID,
+			BlockID:  msg.Proposal.BlockID.String(),
+			POLRound: &polRound,
+		}, err)
 
 	case *BlockPartMessage:
 		// if the proposal is complete, we'll enterPrevote or tryFinalizeCommit
 		added, err = cs.addProposalBlockPart(msg, peerID)
+		if added || err != nil {
+			partIndex := msg.Part.Index
+			cs.trace(TraceEvent{
+				Time:      mi.ReceiveTime,
+				Height:    msg.Height,
+				Round:     msg.Round,
+				Type:      TraceEventBlockPart,
+				Peer:      peerID,
+				PartIndex: &partIndex,
+			}, err)
+		}
 
 		// We unlock here to yield to any routines that need to read the RoundState.
 		// Previously, this code held the lock from the point at which the final block
@@ -941,14 +1019,29 @@ func (cs *State) handleMsg(mi msgInfo) {
 		if added {
 			cs.statsMsgQueue <- mi
 		}
-
-		// if err == ErrAddingVote {
-		// TODO: punish peer
-		// We probably don't want to stop the peer here. The vote does not
-		// necessarily comes from a malicious peer but can be just broadcasted by
-		// a typical peer.
+		if added || err != nil {
+			evType := TraceEventPrevote
+			if msg.Vote.Type == types.PrecommitType {
+				evType = TraceEventPrecommit
+			}
+			cs.trace(TraceEvent{
+				Time:      mi.ReceiveTime,
+				Height:    msg.Vote.Height,
+				Round:     msg.Vote.Round,
+				Type:      evType,
+				Peer:      peerID,
+				BlockID:   msg.Vote.BlockID.String(),
+				Validator: msg.Vote.ValidatorAddress.String(),
+			}, err)
+		}
+
+		// Only votes with an invalid signature are reported: other errors
+		// don't necessarily come from a malicious peer, as the vote can be
+		// just broadcasted by a typical peer.
 		// https://github.com/tendermint/tendermint/issues/1281
-		// }
+		if peerID != "" && errors.Is(err, types.ErrVoteInvalidSignature) {
+			cs.reportPeerBehavior(peerID, p2p.PeerBehaviorBadVote)
+		}
 
 		// NOTE: the vote is broadcast to peers by the reactor listening
 		// for vote events
@@ -987,6 +1080,14 @@ func (cs *State) handleTimeout(ti timeoutInfo, rs cstypes.RoundState) {
 	cs.mtx.Lock()
 	defer cs.mtx.Unlock()
 
+	cs.trace(TraceEvent{
+		Height:   ti.Height,
+		Round:    ti.Round,
+		Type:     TraceEventTimeout,
+		Step:     ti.Step.String(),
+		Duration: ti.Duration,
+	}, nil)
+
 	switch ti.Step {
 	case cstypes.RoundStepNewHeight:
 		// NewRound event fired from enterNewRound.
@@ -1107,6 +1208,9 @@ func (cs *State) enterNewRound(height int64, round int32) {
 
 	cs.Votes.SetRound(cmtmath.SafeAddInt32(round, 1)) // also track next round (round+1) to allow round-skipping
 	cs.TriggeredTimeoutPrecommit = false
+	cs.roundTimings = roundTimings{}
+
+	cs.trace(TraceEvent{Height: height, Round: round, Type: TraceEventNewRound, Validator: propAddress.String()}, nil)
 
 	if err := cs.eventBus.PublishEventNewRound(cs.NewRoundEvent()); err != nil {
 		cs.Logger.Error("Failed publishing new round", "err", err)
@@ -1126,6 +1230,71 @@ func (cs *State) enterNewRound(height int64, round int32) {
 	cs.enterPropose(height, round)
 }
 
+// observeProposalDelay records how long after entering the propose step the
+// proposal of the current round was received.
+func (cs *State) observeProposalDelay(receiveTime time.Time) {
+	if cs.replayMode {
+		return
+	}
+	// A proposal received before we entered the propose step (e.g. during
+	// timeout_commit) says nothing about how long to wait for it, so it's not
+	// taken into account.
+	start := cs.roundTimings.propose
+	if start.IsZero() || receiveTime.Before(start) {
+		return
+	}
+	cs.timeouts.observeProposal(receiveTime.Sub(start))
+}
+
+// observeVoteDelay records how long after receiving +2/3 of any votes of the
+// current round +2/3 for a single value were received. This is the spread
+// timeout_vote is meant to cover. Rounds in which both thresholds are crossed
+// by the same vote, or in which no value gets +2/3, are not taken into
+// account.
+func (cs *State) observeVoteDelay(vote *types.Vote, votes *types.VoteSet) {
+	if cs.replayMode || vote.Height != cs.Height || vote.Round != cs.Round {
+		return
+	}
+	rt := &cs.roundTimings
+	var anyTime *time.Time
+	var observed *bool
+	switch vote.Type {
+	case types.PrevoteType:
+		anyTime, observed = &rt.prevotesAny, &rt.prevotesObserved
+	case types.PrecommitType:
+		anyTime, observed = &rt.precommitsAny, &rt.precommitsObserved
+	default:
+		return
+	}
+	if *observed || !votes.HasTwoThirdsAny() {
+		return
+	}
+
+	now := cmttime.Now()
+	_, hasMaj23 := votes.TwoThirdsMajority()
+	switch {
+	case anyTime.IsZero() && hasMaj23:
+		// Nothing to wait for.
+		*observed = true
+	case anyTime.IsZero():
+		*anyTime = now
+	case hasMaj23:
+		*observed = true
+		cs.timeouts.observeVotes(now.Sub(*anyTime))
+	}
+}
+
+// trace records the event unless we're replaying the WAL.
+func (cs *State) trace(ev TraceEvent, err error) {
+	if cs.replayMode {
+		return
+	}
+	if err != nil {
+		ev.Error = err.Error()
+	}
+	cs.tracer.Record(ev)
+}
+
 // needProofBlock returns true on the first height (so the genesis app hash is signed right away)
 // and where the last block (height-1) caused the app hash to change.
 func (cs *State) needProofBlock(height int64) bool {
@@ -1175,6 +1344,7 @@ func (cs *State) enterPropose(height int64, round int32) {
 	defer func() {
 		// Done enterPropose:
 		cs.updateRoundStep(round, cstypes.RoundStepPropose)
+		cs.roundTimings.propose = cmttime.Now()
 		cs.newStep()
 
 		// If we have the whole proposal + POL, then goto Prevote now.
@@ -1186,7 +1356,7 @@ func (cs *State) enterPropose(height int64, round int32) {
 	}()
 
 	// If we don't get the proposal and all block parts quick enough, enterPrevote
-	cs.scheduleTimeout(cs.config.Propose(round), height, round, cstypes.RoundStepPropose)
+	cs.scheduleTimeout(cs.timeouts.Propose(round), height, round, cstypes.RoundStepPropose)
 
 	// Nothing more to do if we're not a validator
 	if cs.privValidator == nil {
@@ -1598,7 +1768,7 @@ func (cs *State) enterPrevoteWait(height int64, round int32) {
 	}()
 
 	// Wait for some more prevotes; enterPrecommit
-	cs.scheduleTimeout(cs.config.Prevote(round), height, round, cstypes.RoundStepPrevoteWait)
+	cs.scheduleTimeout(cs.timeouts.Prevote(round), height, round, cstypes.RoundStepPrevoteWait)
 }
 
 // Enter: `timeoutPrevote` after any +2/3 prevotes.
@@ -1663,6 +1833,7 @@ func (cs *State) enterPrecommit(height int64, round int32) {
 	if cs.LockedBlock.HashesTo(blockID.Hash) {
 		logger.Debug("Precommit step; +2/3 prevoted locked block; relocking")
 		cs.LockedRound = round
+		cs.trace(TraceEvent{Height: height, Round: round, Type: TraceEventRelock, BlockID: blockID.String()}, nil)
 
 		if err := cs.eventBus.PublishEventRelock(cs.RoundStateEvent()); err != nil {
 			logger.Error("Precommit step; failed publishing event relock", "err", err)
@@ -1686,6 +1857,7 @@ func (cs *State) enterPrecommit(height int64, round int32) {
 		cs.LockedRound = round
 		cs.LockedBlock = cs.ProposalBlock
 		cs.LockedBlockParts = cs.ProposalBlockParts
+		cs.trace(TraceEvent{Height: height, Round: round, Type: TraceEventLock, BlockID: blockID.String()}, nil)
 
 		if err := cs.eventBus.PublishEventLock(cs.RoundStateEvent()); err != nil {
 			logger.Error("Precommit step; failed publishing event lock", "err", err)
@@ -1736,7 +1908,7 @@ func (cs *State) enterPrecommitWait(height int64, round int32) {
 	}()
 
 	// wait for some more precommits; enterNewRound
-	cs.scheduleTimeout(cs.config.Precommit(round), height, round, cstypes.RoundStepPrecommitWait)
+	cs.scheduleTimeout(cs.timeouts.Precommit(round), height, round, cstypes.RoundStepPrecommitWait)
 }
 
 // Enter: +2/3 precommits for block.
@@ -1934,6 +2106,7 @@ func (cs *State) finalizeCommit(height int64) {
 
 	// must be called before we update state
 	cs.recordMetrics(height, block)
+	cs.trace(TraceEvent{Height: height, Round: cs.CommitRound, Type: TraceEventCommit, BlockID: blockID.String()}, nil)
 
 	// NewHeightStep!
 	cs.updateToState(stateCopy)
@@ -2246,6 +2419,15 @@ func (cs *State) handleCompleteProposal(blockHeight int64) {
 	}
 }
 
+// reportPeerBehavior queues the behavior of the peer for the reactor. The
+// behavior is dropped if the queue is full, e.g. when there is no reactor.
+func (cs *State) reportPeerBehavior(peerID p2p.ID, behavior p2p.PeerBehavior) {
+	select {
+	case cs.behaviorQueue <- peerBehavior{PeerID: peerID, Behavior: behavior}:
+	default:
+	}
+}
+
 // Attempt to add the vote. if its a duplicate signature, dupeout the validator.
 func (cs *State) tryAddVote(vote *types.Vote, peerID p2p.ID) (bool, error) {
 	added, err := cs.addVote(vote, peerID)
@@ -2416,6 +2598,7 @@ func (cs *State) addVote(vote *types.Vote, peerID p2p.ID) (added bool, err error
 	switch vote.Type {
 	case types.PrevoteType:
 		prevotes := cs.Votes.Prevotes(vote.Round)
+		cs.observeVoteDelay(vote, prevotes)
 		cs.Logger.Debug("Added vote to prevote", "vote", vote, "prevotes", prevotes.StringShort())
 
 		// Check to see if >2/3 of the voting power on the network voted for any non-nil block.
@@ -2475,6 +2658,7 @@ func (cs *State) addVote(vote *types.Vote, peerID p2p.ID) (added bool, err error
 
 	case types.PrecommitType:
 		precommits := cs.Votes.Precommits(vote.Round)
+		cs.observeVoteDelay(vote, precommits)
 		cs.Logger.Debug("Added vote to precommit",
 			"height", vote.Height,
 			"round", vote.Round,
@@ -0,0 +1,188 @@
+package consensus
+
+import (
+	"sort"
+	"time"
+
+	cfg "github.com/cometbft/cometbft/config"
+)
+
+const (
+	// number of most recent observations the adaptive timeouts are derived from.
+	adaptiveTimeoutWindow = 100
+	// minimum number of observations before the timeouts start to adapt.
+	adaptiveTimeoutMinSamples = 10
+	// the percentile of observed delays the timeouts are based on.
+	adaptiveTimeoutPercentile = 0.95
+	// timeouts are set to the percentile multiplied by this factor, to leave
+	// some headroom for the occasional slower message.
+	adaptiveTimeoutFactor = 1.5
+)
+
+// timeoutParams computes the propose and vote timeouts of the consensus
+// state machine.
+//
+// If adaptive timeouts are disabled, the static values from the config are
+// used. Otherwise the base timeouts are derived from the recently observed
+// proposal and vote delays, clamped to the configured [min, max] bounds. The
+// per-round deltas are always static, so timeouts keep increasing with each
+// round, which guarantees liveness once the network stabilizes.
+//
+// Timeouts only affect liveness, never safety. To keep the behavior of a
+// height predictable (e.g. when replaying the WAL), the base timeouts are only
+// recomputed at height boundaries, and observations are not taken during
+// replay.
+type timeoutParams struct {
+	config *cfg.ConsensusConfig
+
+	proposalDelays *delayWindow
+	voteDelays     *delayWindow
+
+	// base timeouts for the current height
+	propose time.Duration
+	vote    time.Duration
+}
+
+func newTimeoutParams(config *cfg.ConsensusConfig) *timeoutParams {
+	tp := &timeoutParams{
+		config:         config,
+		proposalDelays: newDelayWindow(adaptiveTimeoutWindow),
+		voteDelays:     newDelayWindow(adaptiveTimeoutWindow),
+	}
+	tp.update()
+	return tp
+}
+
+// observeProposal records the delay between entering the propose step and
+// receiving the proposal of another validator.
+func (tp *timeoutParams) observeProposal(d time.Duration) {
+	if tp.config.AdaptiveTimeouts && d >= 0 {
+		tp.proposalDelays.add(d)
+	}
+}
+
+// observeVotes records the delay between receiving +2/3 of any votes of a
+// voting step and receiving +2/3 of the votes for a single value, i.e. the
+// time a node waiting in prevote-wait or precommit-wait would have needed to
+// make progress without timing out.
+func (tp *timeoutParams) observeVotes(d time.Duration) {
+	if tp.config.AdaptiveTimeouts && d >= 0 {
+		tp.voteDelays.add(d)
+	}
+}
+
+// update recomputes the base timeouts. It must be called at height
+// boundaries only.
+func (tp *timeoutParams) update() {
+	tp.propose = tp.config.TimeoutPropose
+	tp.vote = tp.config.TimeoutVote
+	if !tp.config.AdaptiveTimeouts {
+		return
+	}
+	if tp.proposalDelays.len() >= adaptiveTimeoutMinSamples {
+		tp.propose = clampDuration(
+			scaleDuration(tp.proposalDelays.percentile(adaptiveTimeoutPercentile), adaptiveTimeoutFactor),
+			tp.config.TimeoutProposeMin, tp.config.TimeoutProposeMax)
+	}
+	if tp.voteDelays.len() >= adaptiveTimeoutMinSamples {
+		tp.vote = clampDuration(
+			scaleDuration(tp.voteDelays.percentile(adaptiveTimeoutPercentile), adaptiveTimeoutFactor),
+			tp.config.TimeoutVoteMin, tp.config.TimeoutVoteMax)
+	}
+}
+
+// Propose returns the amount of time to wait for a proposal.
+func (tp *timeoutParams) Propose(round int32) time.Duration {
+	if !tp.config.AdaptiveTimeouts {
+		return tp.config.Propose(round)
+	}
+	return tp.propose + tp.config.TimeoutProposeDelta*time.Duration(round)
+}
+
+// Prevote returns the amount of time to wait for straggler votes after
+// receiving any +2/3 prevotes.
+func (tp *timeoutParams) Prevote(round int32) time.Duration {
+	if !tp.config.AdaptiveTimeouts {
+		return tp.config.Prevote(round)
+	}
+	return tp.vote + tp.config.TimeoutVoteDelta*time.Duration(round)
+}
+
+// Precommit returns the amount of time to wait for straggler votes after
+// receiving any +2/3 precommits.
+func (tp *timeoutParams) Precommit(round int32) time.Duration {
+	if !tp.config.AdaptiveTimeouts {
+		return tp.config.Precommit(round)
+	}
+	return tp.vote + tp.config.TimeoutVoteDelta*time.Duration(round)
+}
+
+func scaleDuration(d time.Duration, factor float64) time.Duration {
+	return time.Duration(float64(d) * factor)
+}
+
+func clampDuration(d, minD, maxD time.Duration) time.Duration {
+	if d < minD {
+		return minD
+	}
+	if d > maxD {
+		return maxD
+	}
+	return d
+}
+
+// delayWindow keeps the last `size` observed delays.
+type delayWindow struct {
+	delays []time.Duration
+	next   int
+	size   int
+}
+
+func newDelayWindow(size int) *delayWindow {
+	return &delayWindow{delays: make([]time.Duration, 0, size), size: size}
+}
+
+func (w *delayWindow) add(d time.Duration) {
+	if len(w.delays) < w.size {
+		w.delays = append(w.delays, d)
+		return
+	}
+	w.delays[w.next] = d
+	w.next = (w.next + 1) % w.size
+}
+
+func (w *delayWindow) len() int {
+	return len(w.delays)
+}
+
+// percentile returns the p-th percentile (0 < p <= 1) of the observed delays.
+func (w *delayWindow) percentile(p float64) time.Duration {
+	if len(w.delays) == 0 {
+		return 0
+	}
+	sorted := make([]time.Duration, len(w.delays))
+	copy(sorted, w.delays)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	idx := int(float64(len(sorted))*p+0.5) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
+
+// roundTimings keeps the times of the events of the current round from which
+// the delays of the awaited messages are derived.
+type roundTimings struct {
+	// when the propose step was entered
+	propose time.Time
+
+	// when +2/3 of any prevotes (precommits) were first received
+	prevotesAny   time.Time
+	precommitsAny time.Time
+
+	prevotesObserved   bool
+	precommitsObserved bool
+}
@@ -0,0 +1,207 @@
+package consensus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cfg "github.com/cometbft/cometbft/config"
+	"github.com/cometbft/cometbft/crypto/tmhash"
+	cmtrand "github.com/cometbft/cometbft/internal/rand"
+	"github.com/cometbft/cometbft/libs/metrics"
+	"github.com/cometbft/cometbft/types"
+	cmttime "github.com/cometbft/cometbft/types/time"
+)
+
+func TestTimeoutParamsStatic(t *testing.T) {
+	config := cfg.DefaultConsensusConfig()
+	tp := newTimeoutParams(config)
+
+	for i := 0; i < 2*adaptiveTimeoutMinSamples; i++ {
+		tp.observeProposal(10 * time.Millisecond)
+		tp.observeVotes(10 * time.Millisecond)
+	}
+	tp.update()
+
+	for round := int32(0); round < 3; round++ {
+		assert.Equal(t, config.Propose(round), tp.Propose(round))
+		assert.Equal(t, config.Prevote(round), tp.Prevote(round))
+		assert.Equal(t, config.Precommit(round), tp.Precommit(round))
+	}
+}
+
+func TestTimeoutParamsAdaptive(t *testing.T) {
+	config := cfg.DefaultConsensusConfig()
+	config.AdaptiveTimeouts = true
+	config.TimeoutProposeMin = 100 * time.Millisecond
+	config.TimeoutProposeMax = 2 * time.Second
+	config.TimeoutVoteMin = 100 * time.Millisecond
+	config.TimeoutVoteMax = 300 * time.Millisecond
+	tp := newTimeoutParams(config)
+
+	// Not enough samples yet: static values.
+	for i := 0; i < adaptiveTimeoutMinSamples-1; i++ {
+		tp.observeProposal(400 * time.Millisecond)
+		tp.observeVotes(time.Second)
+	}
+	tp.update()
+	assert.Equal(t, config.TimeoutPropose, tp.Propose(0))
+	assert.Equal(t, config.TimeoutVote, tp.Prevote(0))
+
+	tp.observeProposal(400 * time.Millisecond)
+	tp.observeVotes(time.Second)
+
+	// Observations within a height don't change the timeouts.
+	assert.Equal(t, config.TimeoutPropose, tp.Propose(0))
+
+	tp.update()
+	assert.Equal(t, 600*time.Millisecond, tp.Propose(0))
+	assert.Equal(t, 600*time.Millisecond+config.TimeoutProposeDelta, tp.Propose(1))
+	// Clamped to the configured maximum.
+	assert.Equal(t, 300*time.Millisecond, tp.Prevote(0))
+	assert.Equal(t, 300*time.Millisecond+2*config.TimeoutVoteDelta, tp.Precommit(2))
+
+	// Fast network: clamped to the configured minimum once the old
+	// observations left the window.
+	for i := 0; i < adaptiveTimeoutWindow; i++ {
+		tp.observeProposal(time.Millisecond)
+	}
+	tp.update()
+	assert.Equal(t, 100*time.Millisecond, tp.Propose(0))
+}
+
+func TestDelayWindowPercentile(t *testing.T) {
+	w := newDelayWindow(10)
+	assert.Equal(t, time.Duration(0), w.percentile(0.95))
+
+	for i := 1; i <= 20; i++ {
+		w.add(time.Duration(i) * time.Millisecond)
+	}
+	// Only the last 10 observations (11ms..20ms) are kept.
+	assert.Equal(t, 10, w.len())
+	assert.Equal(t, 20*time.Millisecond, w.percentile(0.95))
+	assert.Equal(t, 15*time.Millisecond, w.percentile(0.5))
+	assert.Equal(t, 11*time.Millisecond, w.percentile(0.01))
+}
+
+// gaugeValue is a metrics.Gauge remembering the last value set.
+type gaugeValue struct {
+	value float64
+}
+
+func (g *gaugeValue) With(...string) metrics.Gauge { return g }
+func (g *gaugeValue) Set(value float64)            { g.value = value }
+func (g *gaugeValue) Add(delta float64)            { g.value += delta }
+
+func TestStateAdaptiveTimeoutsUpdatedAtHeightBoundary(t *testing.T) {
+	cs, _ := randState(1)
+	height, round := cs.Height, cs.Round
+	cs.config.AdaptiveTimeouts = true
+	cs.config.TimeoutProposeMin = 100 * time.Millisecond
+	cs.config.TimeoutProposeMax = 2 * time.Second
+	cs.config.TimeoutVoteMin = 100 * time.Millisecond
+	cs.config.TimeoutVoteMax = 2 * time.Second
+
+	proposeGauge, voteGauge := &gaugeValue{}, &gaugeValue{}
+	cs.metrics.ProposeTimeoutSeconds = proposeGauge
+	cs.metrics.VoteTimeoutSeconds = voteGauge
+
+	for i := 0; i < adaptiveTimeoutMinSamples; i++ {
+		cs.timeouts.observeProposal(400 * time.Millisecond)
+		cs.timeouts.observeVotes(200 * time.Millisecond)
+	}
+
+	newRoundCh := subscribe(cs.eventBus, types.EventQueryNewRound)
+	startTestRound(cs, height, round)
+	ensureNewRound(newRoundCh, height, round)
+	ensureNewRound(newRoundCh, height+1, 0)
+
+	cs.mtx.RLock()
+	defer cs.mtx.RUnlock()
+	assert.Equal(t, 600*time.Millisecond, cs.timeouts.Propose(0))
+	assert.Equal(t, 300*time.Millisecond, cs.timeouts.Prevote(0))
+	assert.InDelta(t, 0.6, proposeGauge.value, 1e-9)
+	assert.InDelta(t, 0.3, voteGauge.value, 1e-9)
+}
+
+func TestStateAdaptiveProposeTimeoutScheduled(t *testing.T) {
+	cs, _ := randState(1)
+	cs.SetPrivValidator(nil)
+	height, round := cs.Height, cs.Round
+	// The static timeout is way longer than the test is willing to wait.
+	cs.config.TimeoutPropose = time.Minute
+	cs.config.AdaptiveTimeouts = true
+	cs.config.TimeoutProposeMin = 10 * time.Millisecond
+	cs.config.TimeoutProposeMax = time.Second
+
+	for i := 0; i < adaptiveTimeoutMinSamples; i++ {
+		cs.timeouts.observeProposal(20 * time.Millisecond)
+	}
+	cs.timeouts.update()
+
+	timeoutCh := subscribe(cs.eventBus, types.EventQueryTimeoutPropose)
+	startTestRound(cs, height, round)
+	ensureNewTimeout(timeoutCh, height, round, (30 * time.Millisecond).Nanoseconds())
+}
+
+func TestStateObserveProposalDelay(t *testing.T) {
+	cs, _ := randState(1)
+	cs.config.AdaptiveTimeouts = true
+
+	now := cmttime.Now()
+	// Not in the propose step yet.
+	cs.observeProposalDelay(now)
+	assert.Equal(t, 0, cs.timeouts.proposalDelays.len())
+
+	cs.roundTimings.propose = now
+	// Received during the previous step.
+	cs.observeProposalDelay(now.Add(-time.Millisecond))
+	assert.Equal(t, 0, cs.timeouts.proposalDelays.len())
+
+	cs.observeProposalDelay(now.Add(30 * time.Millisecond))
+	require.Equal(t, 1, cs.timeouts.proposalDelays.len())
+	assert.Equal(t, 30*time.Millisecond, cs.timeouts.proposalDelays.percentile(1))
+}
+
+func TestStateObserveVoteDelay(t *testing.T) {
+	cs, vss := randState(4)
+	cs.config.AdaptiveTimeouts = true
+	incrementHeight(vss[0])
+	chainID := cs.state.ChainID
+	blockID := types.BlockID{
+		Hash:          cmtrand.Bytes(tmhash.Size),
+		PartSetHeader: types.PartSetHeader{Total: 1, Hash: cmtrand.Bytes(tmhash.Size)},
+	}
+
+	addAndObserve := func(vote *types.Vote) {
+		t.Helper()
+		votes := cs.Votes.Prevotes(vote.Round)
+		if vote.Type == types.PrecommitType {
+			votes = cs.Votes.Precommits(vote.Round)
+		}
+		added, err := votes.AddVote(vote)
+		require.NoError(t, err)
+		require.True(t, added)
+		cs.observeVoteDelay(vote, votes)
+	}
+
+	// +2/3 any prevotes, but no +2/3 majority.
+	addAndObserve(signVote(vss[1], types.PrevoteType, chainID, blockID, false))
+	addAndObserve(signVote(vss[2], types.PrevoteType, chainID, types.BlockID{}, false))
+	addAndObserve(signVote(vss[3], types.PrevoteType, chainID, blockID, false))
+	assert.Equal(t, 0, cs.timeouts.voteDelays.len())
+
+	// The straggler completes the +2/3 majority.
+	time.Sleep(20 * time.Millisecond)
+	addAndObserve(signVote(vss[0], types.PrevoteType, chainID, blockID, false))
+	require.Equal(t, 1, cs.timeouts.voteDelays.len())
+	assert.GreaterOrEqual(t, cs.timeouts.voteDelays.percentile(1), 20*time.Millisecond)
+
+	// +2/3 any and +2/3 majority are reached at once: nothing to wait for.
+	for _, vs := range vss {
+		addAndObserve(signVote(vs, types.PrecommitType, chainID, blockID, true))
+	}
+	assert.Equal(t, 1, cs.timeouts.voteDelays.len())
+}
@@ -0,0 +1,238 @@
+package consensus
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	auto "github.com/cometbft/cometbft/internal/autofile"
+	cmtos "github.com/cometbft/cometbft/internal/os"
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/libs/service"
+	cmtsync "github.com/cometbft/cometbft/libs/sync"
+	"github.com/cometbft/cometbft/p2p"
+	cmttime "github.com/cometbft/cometbft/types/time"
+)
+
+const (
+	// how often the trace should be flushed to disk.
+	traceDefaultFlushInterval = 2 * time.Second
+
+	// size of a single trace file before a new one is started.
+	traceHeadSizeLimit = 10 * 1024 * 1024 // 10MB
+)
+
+// TraceEventType identifies the kind of a TraceEvent.
+type TraceEventType string
+
+const (
+	TraceEventNewRound  TraceEventType = "new_round"
+	TraceEventProposal  TraceEventType = "proposal"
+	TraceEventBlockPart TraceEventType = "block_part"
+	TraceEventPrevote   TraceEventType = "prevote"
+	TraceEventPrecommit TraceEventType = "precommit"
+	TraceEventTimeout   TraceEventType = "timeout"
+	TraceEventLock      TraceEventType = "lock"
+	TraceEventRelock    TraceEventType = "relock"
+	TraceEventCommit    TraceEventType = "commit"
+)
+
+// TraceEvent is a single entry of the consensus timeline.
+//
+// Peer is empty for messages produced by this node. Validator is the signer
+// of a vote or, for new rounds, the proposer. Error is set if the message was
+// received but could not be processed.
+type TraceEvent struct {
+	Time   time.Time      `json:"time"`
+	Height int64          `json:"height"`
+	Round  int32          `json:"round"`
+	Type   TraceEventType `json:"type"`
+
+	Peer      p2p.ID  `json:"peer,omitempty"`
+	BlockID   string  `json:"block_id,omitempty"`
+	Validator string  `json:"validator,omitempty"`
+	PartIndex *uint32 `json:"part_index,omitempty"`
+	POLRound  *int32  `json:"pol_round,omitempty"`
+
+	Step     string        `json:"step,omitempty"`
+	Duration time.Duration `json:"duration,omitempty"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// TraceRecorder records the consensus timeline.
+type TraceRecorder interface {
+	Record(ev TraceEvent)
+
+	// service methods
+	Start() error
+	Stop() error
+	Wait()
+}
+
+// BaseTraceRecorder writes trace events as JSON lines to an autofile.Group.
+// Once the group exceeds its total size limit, the oldest files are removed,
+// so the trace behaves like a bounded on-disk ring buffer.
+type BaseTraceRecorder struct {
+	service.BaseService
+
+	mtx   cmtsync.Mutex
+	group *auto.Group
+
+	flushTicker   *time.Ticker
+	flushInterval time.Duration
+}
+
+var _ TraceRecorder = &BaseTraceRecorder{}
+
+// NewTraceRecorder returns a new recorder writing to traceFile, keeping at
+// most maxSize bytes of history. A maxSize of 0 means no limit.
+func NewTraceRecorder(traceFile string, maxSize int64, groupOptions ...func(*auto.Group)) (*BaseTraceRecorder, error) {
+	if err := cmtos.EnsureDir(filepath.Dir(traceFile), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to ensure trace directory is in place: %w", err)
+	}
+
+	headSize := int64(traceHeadSizeLimit)
+	if maxSize > 0 && headSize > maxSize/2 {
+		headSize = maxSize / 2
+	}
+	groupOptions = append([]func(*auto.Group){
+		auto.GroupHeadSizeLimit(headSize),
+		auto.GroupTotalSizeLimit(maxSize),
+	}, groupOptions...)
+	group, err := auto.OpenGroup(traceFile, groupOptions...)
+	if err != nil {
+		return nil, err
+	}
+	tr := &BaseTraceRecorder{
+		group:         group,
+		flushInterval: traceDefaultFlushInterval,
+	}
+	tr.BaseService = *service.NewBaseService(nil, "traceRecorder", tr)
+	return tr, nil
+}
+
+func (tr *BaseTraceRecorder) SetLogger(l log.Logger) {
+	tr.BaseService.Logger = l
+	tr.group.SetLogger(l)
+}
+
+func (tr *BaseTraceRecorder) OnStart() error {
+	if err := tr.group.Start(); err != nil {
+		return err
+	}
+	tr.flushTicker = time.NewTicker(tr.flushInterval)
+	go tr.processFlushTicks()
+	return nil
+}
+
+func (tr *BaseTraceRecorder) processFlushTicks() {
+	for {
+		select {
+		case <-tr.flushTicker.C:
+			tr.mtx.Lock()
+			err := tr.group.FlushAndSync()
+			tr.mtx.Unlock()
+			if err != nil {
+				tr.Logger.Error("Periodic trace flush failed", "err", err)
+			}
+		case <-tr.Quit():
+			return
+		}
+	}
+}
+
+// OnStop flushes the trace and stops the underlying autofile group.
+func (tr *BaseTraceRecorder) OnStop() {
+	tr.flushTicker.Stop()
+
+	tr.mtx.Lock()
+	defer tr.mtx.Unlock()
+	if err := tr.group.FlushAndSync(); err != nil {
+		tr.Logger.Error("error on flush trace to disk", "error", err)
+	}
+	if err := tr.group.Stop(); err != nil {
+		tr.Logger.Error("error trying to stop trace", "error", err)
+	}
+	tr.group.Close()
+}
+
+// Wait for the underlying autofile group to finish shutting down.
+func (tr *BaseTraceRecorder) Wait() {
+	tr.group.Wait()
+}
+
+// Record appends the event to the trace. It sets the event time if it's
+// missing. Errors are logged, never returned: tracing must not affect
+// consensus.
+func (tr *BaseTraceRecorder) Record(ev TraceEvent) {
+	if ev.Time.IsZero() {
+		ev.Time = cmttime.Now()
+	}
+	bz, err := json.Marshal(ev)
+	if err != nil {
+		tr.Logger.Error("Failed to encode trace event", "err", err)
+		return
+	}
+
+	tr.mtx.Lock()
+	defer tr.mtx.Unlock()
+	if !tr.IsRunning() {
+		return
+	}
+	if err := tr.group.WriteLine(string(bz)); err != nil {
+		tr.Logger.Error("Failed to write trace event", "err", err)
+	}
+}
+
+// ReadTrace returns all events of the trace stored at traceFile whose height
+// lies within [minHeight, maxHeight]. A maxHeight of 0 means no upper bound.
+// Events are returned in the order they were recorded; lines which can't be
+// decoded (e.g. partially written before a crash) are skipped.
+func ReadTrace(traceFile string, minHeight, maxHeight int64) ([]TraceEvent, error) {
+	group, err := auto.OpenGroup(traceFile)
+	if err != nil {
+		return nil, err
+	}
+	defer group.Close()
+
+	rd, err := group.NewReader(group.MinIndex())
+	if err != nil {
+		return nil, err
+	}
+	defer rd.Close()
+
+	return decodeTrace(rd, minHeight, maxHeight)
+}
+
+func decodeTrace(rd io.Reader, minHeight, maxHeight int64) ([]TraceEvent, error) {
+	events := make([]TraceEvent, 0)
+	scanner := bufio.NewScanner(rd)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var ev TraceEvent
+		if err := json.Unmarshal(line, &ev); err != nil {
+			// A line may be incomplete if the node crashed while writing it.
+			continue
+		}
+		if ev.Height < minHeight || (maxHeight > 0 && ev.Height > maxHeight) {
+			continue
+		}
+		events = append(events, ev)
+	}
+	return events, scanner.Err()
+}
+
+type nilTraceRecorder struct{}
+
+var _ TraceRecorder = nilTraceRecorder{}
+
+func (nilTraceRecorder) Record(TraceEvent) {}
+func (nilTraceRecorder) Start() error      { return nil }
+func (nilTraceRecorder) Stop() error       { return nil }
+func (nilTraceRecorder) Wait()             {}
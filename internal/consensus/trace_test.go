@@ -0,0 +1,157 @@
+package consensus
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	auto "github.com/cometbft/cometbft/internal/autofile"
+	"github.com/cometbft/cometbft/types"
+)
+
+func TestTraceRecorderReadWrite(t *testing.T) {
+	traceFile := filepath.Join(t.TempDir(), "cs.trace", "trace")
+	tr, err := NewTraceRecorder(traceFile, 0)
+	require.NoError(t, err)
+	require.NoError(t, tr.Start())
+
+	for h := int64(1); h <= 5; h++ {
+		tr.Record(TraceEvent{Height: h, Type: TraceEventNewRound})
+		tr.Record(TraceEvent{Height: h, Type: TraceEventTimeout, Step: "RoundStepPropose", Duration: time.Second})
+	}
+	require.NoError(t, tr.Stop())
+	tr.Wait()
+
+	// Events recorded after stopping are dropped.
+	tr.Record(TraceEvent{Height: 6, Type: TraceEventNewRound})
+
+	events, err := ReadTrace(traceFile, 2, 3)
+	require.NoError(t, err)
+	require.Len(t, events, 4)
+	for _, ev := range events {
+		assert.Contains(t, []int64{2, 3}, ev.Height)
+		assert.False(t, ev.Time.IsZero())
+	}
+	assert.Equal(t, TraceEventTimeout, events[1].Type)
+	assert.Equal(t, time.Second, events[1].Duration)
+
+	events, err = ReadTrace(traceFile, 0, 0)
+	require.NoError(t, err)
+	assert.Len(t, events, 10)
+}
+
+func TestTraceRecorderReadAcrossFiles(t *testing.T) {
+	traceFile := filepath.Join(t.TempDir(), "trace")
+	tr, err := NewTraceRecorder(traceFile, 0)
+	require.NoError(t, err)
+	require.NoError(t, tr.Start())
+
+	for h := int64(1); h <= 500; h++ {
+		tr.Record(TraceEvent{Height: h, Type: TraceEventCommit, BlockID: "0123456789ABCDEF0123456789ABCDEF"})
+		if h%50 == 0 {
+			// Rotate the way the group does once the head size limit is hit.
+			tr.mtx.Lock()
+			require.NoError(t, tr.group.FlushAndSync())
+			tr.group.RotateFile()
+			tr.mtx.Unlock()
+		}
+	}
+	require.NoError(t, tr.Stop())
+	tr.Wait()
+
+	events, err := ReadTrace(traceFile, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, events, 500)
+	assert.Equal(t, int64(1), events[0].Height)
+	assert.Equal(t, int64(500), events[len(events)-1].Height)
+}
+
+func TestTraceRecorderEvictsOldestEvents(t *testing.T) {
+	const maxSize = 8 * 1024
+	traceFile := filepath.Join(t.TempDir(), "trace")
+	tr, err := NewTraceRecorder(traceFile, maxSize, auto.GroupCheckDuration(time.Millisecond))
+	require.NoError(t, err)
+	require.NoError(t, tr.Start())
+
+	const lastHeight = 500
+	for h := int64(1); h <= lastHeight; h++ {
+		tr.Record(TraceEvent{Height: h, Type: TraceEventCommit, BlockID: "0123456789ABCDEF0123456789ABCDEF"})
+		if h%10 == 0 {
+			// Give the group a chance to enforce its limits.
+			tr.mtx.Lock()
+			require.NoError(t, tr.group.FlushAndSync())
+			tr.mtx.Unlock()
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	require.NoError(t, tr.Stop())
+	tr.Wait()
+
+	events, err := ReadTrace(traceFile, 0, 0)
+	require.NoError(t, err)
+	require.NotEmpty(t, events)
+	assert.Greater(t, events[0].Height, int64(1), "oldest events should be removed")
+	assert.Less(t, len(events), lastHeight)
+	for i, ev := range events {
+		require.Equal(t, events[0].Height+int64(i), ev.Height, "events should be contiguous and in order")
+	}
+	assert.Equal(t, int64(lastHeight), events[len(events)-1].Height)
+}
+
+func TestStateDoesNotOpenTraceIfStartFails(t *testing.T) {
+	cs, _ := randState(1)
+	cs.config.Trace = true
+	cs.config.TraceMaxSize = 1024 * 1024
+	cs.config.TracePath = filepath.Join(t.TempDir(), "trace")
+
+	// Make OnStart fail after the WAL catchup.
+	require.NoError(t, cs.evsw.Start())
+	t.Cleanup(func() { _ = cs.evsw.Stop() })
+
+	require.Error(t, cs.Start())
+	_, ok := cs.tracer.(nilTraceRecorder)
+	assert.True(t, ok, "the trace should not be opened")
+}
+
+func TestStateTracesConsensusEvents(t *testing.T) {
+	cs, _ := randState(1)
+	height, round := cs.Height, cs.Round
+
+	traceFile := filepath.Join(t.TempDir(), "trace")
+	tr, err := NewTraceRecorder(traceFile, 0)
+	require.NoError(t, err)
+	require.NoError(t, tr.Start())
+	cs.tracer = tr
+
+	newBlockCh := subscribe(cs.eventBus, types.EventQueryNewBlock)
+	startTestRound(cs, height, round)
+	ensureNewBlock(newBlockCh, height)
+
+	cs.mtx.Lock()
+	require.NoError(t, tr.Stop())
+	cs.mtx.Unlock()
+	tr.Wait()
+
+	events, err := ReadTrace(traceFile, height, height)
+	require.NoError(t, err)
+
+	seen := make(map[TraceEventType]bool)
+	for _, ev := range events {
+		seen[ev.Type] = true
+		assert.Empty(t, ev.Error)
+	}
+	for _, evType := range []TraceEventType{
+		TraceEventNewRound,
+		TraceEventProposal,
+		TraceEventBlockPart,
+		TraceEventPrevote,
+		TraceEventPrecommit,
+		TraceEventLock,
+		TraceEventCommit,
+	} {
+		assert.True(t, seen[evType], "missing %s event", evType)
+	}
+}
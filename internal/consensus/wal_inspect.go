@@ -0,0 +1,245 @@
+package consensus
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	auto "github.com/cometbft/cometbft/internal/autofile"
+)
+
+// WALFileInfo describes one file of a WAL group.
+type WALFileInfo struct {
+	Index int
+	Path  string
+	Size  int64
+	// Records is the number of records read before the end of the file or
+	// the first corrupted record.
+	Records int
+	// ValidSize is the length of the prefix of the file made of valid records.
+	ValidSize int64
+	// Err is the first data corruption found in the file, if any.
+	Err error
+}
+
+// WALEndHeight locates an EndHeightMessage in a WAL group.
+type WALEndHeight struct {
+	Height int64
+	// File is the index of the group file holding the marker.
+	File int
+	// Offset is the position in the file right after the marker.
+	Offset int64
+}
+
+// WALInfo is the result of InspectWAL.
+type WALInfo struct {
+	Files      []WALFileInfo
+	EndHeights []WALEndHeight
+}
+
+// FirstCorrupted returns the first file holding a corrupted record, or nil if
+// every record of the WAL is valid.
+func (info *WALInfo) FirstCorrupted() *WALFileInfo {
+	for i := range info.Files {
+		if info.Files[i].Err != nil {
+			return &info.Files[i]
+		}
+	}
+	return nil
+}
+
+// LastValid returns the position right after the last record that can be
+// read back without going through a corrupted one.
+func (info *WALInfo) LastValid() (file int, offset int64) {
+	for i, f := range info.Files {
+		if f.Err == nil {
+			continue
+		}
+		if f.ValidSize == 0 && i > 0 {
+			// Use the end of the previous file rather than leaving an empty
+			// head, to which the WAL would add an EndHeightMessage{0} on start.
+			return info.Files[i-1].Index, info.Files[i-1].Size
+		}
+		return f.Index, f.ValidSize
+	}
+	last := info.Files[len(info.Files)-1]
+	return last.Index, last.Size
+}
+
+// EndHeight returns the marker for the given height, if the WAL contains one.
+func (info *WALInfo) EndHeight(height int64) (WALEndHeight, bool) {
+	for _, eh := range info.EndHeights {
+		if eh.Height == height {
+			return eh, true
+		}
+	}
+	return WALEndHeight{}, false
+}
+
+// InspectWAL reads every file of the WAL group stored at walFile, checking
+// the CRC of each record and collecting the EndHeightMessage markers. Files
+// are decoded independently, so a corrupted record does not hide the
+// markers of the following files.
+func InspectWAL(walFile string) (*WALInfo, error) {
+	info := &WALInfo{}
+	files, err := walkWALFiles(walFile, false, func(f *WALFileInfo, offset int64, msg *TimedWALMessage) error {
+		if m, ok := msg.Msg.(EndHeightMessage); ok {
+			info.EndHeights = append(info.EndHeights, WALEndHeight{
+				Height: m.Height,
+				File:   f.Index,
+				Offset: offset,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	info.Files = files
+	return info, nil
+}
+
+// WalkWAL calls fn for every record of the WAL group stored at walFile, in
+// order, along with the height the record belongs to. An EndHeightMessage
+// belongs to the height it ends; records written before the first marker
+// of the group are attributed to that marker's height. WalkWAL stops and
+// returns a DataCorruptionError at the first corrupted record.
+func WalkWAL(walFile string, fn func(height int64, msg *TimedWALMessage) error) error {
+	info, err := InspectWAL(walFile)
+	if err != nil {
+		return err
+	}
+
+	var height int64
+	if len(info.EndHeights) > 0 {
+		height = info.EndHeights[0].Height
+	}
+	files, err := walkWALFiles(walFile, true, func(_ *WALFileInfo, _ int64, msg *TimedWALMessage) error {
+		if m, ok := msg.Msg.(EndHeightMessage); ok {
+			if err := fn(m.Height, msg); err != nil {
+				return err
+			}
+			height = m.Height + 1
+			return nil
+		}
+		return fn(height, msg)
+	})
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if f.Err != nil {
+			return fmt.Errorf("%s: %w", f.Path, f.Err)
+		}
+	}
+	return nil
+}
+
+// TruncateWAL cuts the WAL group stored at walFile at the given offset of the
+// file with the given index, removing every later file. If the truncated
+// file is not the head, it becomes the new head. The WAL must not be in use.
+func TruncateWAL(walFile string, index int, offset int64) error {
+	group, err := auto.OpenGroup(walFile)
+	if err != nil {
+		return err
+	}
+	minIndex, maxIndex := group.MinIndex(), group.MaxIndex()
+	paths := make(map[int]string, maxIndex-minIndex+1)
+	for i := minIndex; i <= maxIndex; i++ {
+		paths[i] = group.FilePath(i)
+	}
+	group.Close()
+
+	if index < minIndex || index > maxIndex {
+		return fmt.Errorf("file index %d out of range [%d, %d]", index, minIndex, maxIndex)
+	}
+	if err := os.Truncate(paths[index], offset); err != nil {
+		return err
+	}
+	for i := maxIndex; i > index; i-- {
+		if err := os.Remove(paths[i]); err != nil {
+			return err
+		}
+	}
+	if index != maxIndex {
+		return os.Rename(paths[index], paths[maxIndex])
+	}
+	return nil
+}
+
+// walkWALFiles decodes the files of the WAL group one by one, calling fn with
+// the offset right after each valid record. Decoding of a file stops at its
+// first corrupted record, which is reported in the returned WALFileInfo. If
+// stopOnCorruption is set, the following files are not read.
+func walkWALFiles(
+	walFile string,
+	stopOnCorruption bool,
+	fn func(f *WALFileInfo, offset int64, msg *TimedWALMessage) error,
+) ([]WALFileInfo, error) {
+	if _, err := os.Stat(filepath.Dir(walFile)); err != nil {
+		return nil, err
+	}
+	group, err := auto.OpenGroup(walFile)
+	if err != nil {
+		return nil, err
+	}
+	defer group.Close()
+
+	files := make([]WALFileInfo, 0, group.MaxIndex()-group.MinIndex()+1)
+	for i := group.MinIndex(); i <= group.MaxIndex(); i++ {
+		files = append(files, WALFileInfo{Index: i, Path: group.FilePath(i)})
+		f := &files[len(files)-1]
+		if err := walkWALFile(f, fn); err != nil {
+			return nil, err
+		}
+		if f.Err != nil && stopOnCorruption {
+			break
+		}
+	}
+	return files, nil
+}
+
+func walkWALFile(f *WALFileInfo, fn func(f *WALFileInfo, offset int64, msg *TimedWALMessage) error) error {
+	file, err := os.Open(f.Path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	f.Size = stat.Size()
+
+	rd := &countingReader{rd: file}
+	dec := NewWALDecoder(rd)
+	for {
+		msg, err := dec.Decode()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			f.Err = err
+			return nil
+		}
+		f.Records++
+		f.ValidSize = rd.n
+		if err := fn(f, rd.n, msg); err != nil {
+			return err
+		}
+	}
+}
+
+type countingReader struct {
+	rd io.Reader
+	n  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.rd.Read(p)
+	r.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,158 @@
+package consensus
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cometbft/cometbft/internal/autofile"
+	"github.com/cometbft/cometbft/libs/log"
+)
+
+// makeRotatedWAL writes numBlocks heights to a WAL, rotating its head every
+// few heights.
+func makeRotatedWAL(t *testing.T, numBlocks int) string {
+	t.Helper()
+
+	data, err := WALWithNBlocks(t, numBlocks, getConfig(t))
+	require.NoError(t, err)
+
+	walFile := filepath.Join(t.TempDir(), "wal")
+	group, err := autofile.OpenGroup(walFile)
+	require.NoError(t, err)
+	defer group.Close()
+
+	dec := NewWALDecoder(bytes.NewReader(data))
+	enc := NewWALEncoder(group)
+	for {
+		msg, err := dec.Decode()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		require.NoError(t, enc.Encode(msg))
+		if m, ok := msg.Msg.(EndHeightMessage); ok && m.Height%4 == 3 {
+			group.RotateFile()
+		}
+	}
+	require.NoError(t, group.FlushAndSync())
+	return walFile
+}
+
+func TestInspectWAL(t *testing.T) {
+	walFile := makeRotatedWAL(t, 20)
+
+	info, err := InspectWAL(walFile)
+	require.NoError(t, err)
+	require.Greater(t, len(info.Files), 1)
+	assert.Nil(t, info.FirstCorrupted())
+
+	require.Len(t, info.EndHeights, 20) // the marker of the last height is excluded
+	for i, eh := range info.EndHeights {
+		assert.EqualValues(t, i, eh.Height)
+	}
+
+	last := info.Files[len(info.Files)-1]
+	file, offset := info.LastValid()
+	assert.Equal(t, last.Index, file)
+	assert.Equal(t, last.Size, offset)
+}
+
+func TestTruncateWALAfterCorruption(t *testing.T) {
+	walFile := makeRotatedWAL(t, 20)
+
+	info, err := InspectWAL(walFile)
+	require.NoError(t, err)
+	require.Greater(t, len(info.Files), 2)
+
+	// Flip a byte in the middle of the second file.
+	corrupted := info.Files[1]
+	bz, err := os.ReadFile(corrupted.Path)
+	require.NoError(t, err)
+	bz[len(bz)/2] ^= 0xff
+	require.NoError(t, os.WriteFile(corrupted.Path, bz, 0o600))
+
+	info, err = InspectWAL(walFile)
+	require.NoError(t, err)
+	f := info.FirstCorrupted()
+	require.NotNil(t, f)
+	assert.Equal(t, corrupted.Index, f.Index)
+	assert.True(t, IsDataCorruptionError(f.Err))
+	assert.Less(t, f.ValidSize, f.Size)
+
+	err = WalkWAL(walFile, func(int64, *TimedWALMessage) error { return nil })
+	var corruption DataCorruptionError
+	assert.ErrorAs(t, err, &corruption)
+
+	file, offset := info.LastValid()
+	require.NoError(t, TruncateWAL(walFile, file, offset))
+
+	info, err = InspectWAL(walFile)
+	require.NoError(t, err)
+	assert.Nil(t, info.FirstCorrupted())
+	require.Len(t, info.Files, 2)
+	assert.Equal(t, walFile, info.Files[1].Path)
+
+	// The truncated WAL can be reopened and searched.
+	wal, err := NewWAL(walFile)
+	require.NoError(t, err)
+	wal.SetLogger(log.TestingLogger())
+	require.NoError(t, wal.Start())
+	defer func() {
+		require.NoError(t, wal.Stop())
+		wal.Wait()
+	}()
+	lastHeight := info.EndHeights[len(info.EndHeights)-1].Height
+	gr, found, err := wal.SearchForEndHeight(lastHeight, &WALSearchOptions{})
+	require.NoError(t, err)
+	assert.True(t, found)
+	gr.Close()
+}
+
+func TestTruncateWALToHeight(t *testing.T) {
+	walFile := makeRotatedWAL(t, 20)
+
+	info, err := InspectWAL(walFile)
+	require.NoError(t, err)
+	eh, ok := info.EndHeight(5)
+	require.True(t, ok)
+	require.NoError(t, TruncateWAL(walFile, eh.File, eh.Offset))
+
+	info, err = InspectWAL(walFile)
+	require.NoError(t, err)
+	require.Len(t, info.EndHeights, 6)
+	assert.EqualValues(t, 5, info.EndHeights[5].Height)
+	_, ok = info.EndHeight(6)
+	assert.False(t, ok)
+
+	last := info.Files[len(info.Files)-1]
+	assert.Equal(t, walFile, last.Path)
+	assert.Equal(t, eh.Offset, last.Size)
+}
+
+func TestWalkWALHeights(t *testing.T) {
+	walFile := makeRotatedWAL(t, 5)
+
+	var height int64
+	err := WalkWAL(walFile, func(h int64, msg *TimedWALMessage) error {
+		require.GreaterOrEqual(t, h, height)
+		height = h
+		switch m := msg.Msg.(type) {
+		case EndHeightMessage:
+			assert.Equal(t, m.Height, h)
+		case msgInfo:
+			if v, ok := m.Msg.(*VoteMessage); ok {
+				assert.Equal(t, v.Vote.Height, h)
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.EqualValues(t, 5, height)
+}
@@ -0,0 +1,130 @@
+package light
+
+import (
+	"fmt"
+	"time"
+
+	cmtbytes "github.com/cometbft/cometbft/libs/bytes"
+	"github.com/cometbft/cometbft/light/provider"
+	"github.com/cometbft/cometbft/types"
+)
+
+// Types of light client attacks, see
+// docs/architecture/adr-047-handling-evidence-from-light-client.md.
+const (
+	AttackLunatic      = "lunatic"
+	AttackEquivocation = "equivocation"
+	AttackAmnesia      = "amnesia"
+)
+
+// AttackReport describes a light client attack detected by the light client,
+// with the evidence reported against the accused provider. It holds what an
+// operator needs to hold the byzantine validators accountable, e.g. in a
+// governance or slashing proposal.
+type AttackReport struct {
+	// Hash of the evidence.
+	Hash       cmtbytes.HexBytes `json:"hash"`
+	ChainID    string            `json:"chain_id"`
+	DetectedAt time.Time         `json:"detected_at"`
+	AttackType string            `json:"attack_type"`
+
+	// Accused is the provider which served the conflicting block, and
+	// ReportedTo the provider which the evidence was sent to.
+	Accused    string `json:"accused"`
+	ReportedTo string `json:"reported_to"`
+
+	CommonHeight        int64              `json:"common_height"`
+	ConflictingHeight   int64              `json:"conflicting_height"`
+	ByzantineValidators []*types.Validator `json:"byzantine_validators"`
+
+	Evidence *types.LightClientAttackEvidence `json:"evidence"`
+	// EvidenceProto is the protobuf encoding of the evidence, as a
+	// cometbft.types.v1.Evidence, to be submitted to the chain.
+	EvidenceProto []byte `json:"evidence_proto"`
+
+	// ConflictingTrace is the trace of light blocks served by the accused
+	// provider, from the last trusted block to the conflicting block, and
+	// TrustedTrace the trace served by the other provider.
+	ConflictingTrace []*types.LightBlock `json:"conflicting_trace"`
+	TrustedTrace     []*types.LightBlock `json:"trusted_trace"`
+}
+
+// AttackJournal records the attacks detected by the light client.
+type AttackJournal interface {
+	RecordAttack(report *AttackReport) error
+}
+
+// EvidenceJournal option records the light client attacks detected by the
+// client, along with the evidence reported to the providers, in the given
+// journal.
+func EvidenceJournal(j AttackJournal) Option {
+	return func(c *Client) {
+		c.attackJournal = j
+	}
+}
+
+// newAttackReport returns the report of an attack by the accused provider,
+// detected at the given time. trusted is the block held as the source of
+// truth at the height of the conflicting block.
+func newAttackReport(
+	chainID string,
+	ev *types.LightClientAttackEvidence,
+	trusted *types.LightBlock,
+	conflictingTrace, trustedTrace []*types.LightBlock,
+	accused, reportedTo provider.Provider,
+	now time.Time,
+) (*AttackReport, error) {
+	evp, err := types.EvidenceToProto(ev)
+	if err != nil {
+		return nil, err
+	}
+	evBytes, err := evp.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	attackType := AttackEquivocation
+	switch {
+	case ev.ConflictingHeaderIsInvalid(trusted.Header):
+		attackType = AttackLunatic
+	case ev.ConflictingBlock.Commit.Round != trusted.Commit.Round:
+		attackType = AttackAmnesia
+	}
+
+	return &AttackReport{
+		Hash:                ev.Hash(),
+		ChainID:             chainID,
+		DetectedAt:          now,
+		AttackType:          attackType,
+		Accused:             fmt.Sprint(accused),
+		ReportedTo:          fmt.Sprint(reportedTo),
+		CommonHeight:        ev.CommonHeight,
+		ConflictingHeight:   ev.ConflictingBlock.Height,
+		ByzantineValidators: ev.ByzantineValidators,
+		Evidence:            ev,
+		EvidenceProto:       evBytes,
+		ConflictingTrace:    conflictingTrace,
+		TrustedTrace:        trustedTrace,
+	}, nil
+}
+
+// recordAttack records the attack in the journal, if any, on a best effort
+// basis.
+func (c *Client) recordAttack(
+	ev *types.LightClientAttackEvidence,
+	trusted *types.LightBlock,
+	conflictingTrace, trustedTrace []*types.LightBlock,
+	accused, reportedTo provider.Provider,
+	now time.Time,
+) {
+	if c.attackJournal == nil {
+		return
+	}
+	report, err := newAttackReport(c.chainID, ev, trusted, conflictingTrace, trustedTrace, accused, reportedTo, now)
+	if err == nil {
+		err = c.attackJournal.RecordAttack(report)
+	}
+	if err != nil {
+		c.logger.Error("Failed to record attack in the evidence journal", "ev", ev, "err", err)
+	}
+}
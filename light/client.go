@@ -155,6 +155,8 @@ type Client struct {
 	pruningSize uint16
 	// See ConfirmationFunction option
 	confirmationFn func(action string) bool
+	// See EvidenceJournal option
+	attackJournal AttackJournal
 
 	quit chan struct{}
 
@@ -252,6 +252,7 @@ func (c *Client) handleConflictingHeaders(
 	c.logger.Error("ATTEMPTED ATTACK DETECTED. Sending evidence against primary by witness", "ev", evidenceAgainstPrimary,
 		"primary", c.primary, "witness", supportingWitness)
 	c.sendEvidence(ctx, evidenceAgainstPrimary, supportingWitness)
+	c.recordAttack(evidenceAgainstPrimary, trustedBlock, primaryTrace, witnessTrace, c.primary, supportingWitness, now)
 
 	if primaryBlock.Commit.Round != witnessTrace[len(witnessTrace)-1].Commit.Round {
 		c.logger.Info("The light client has detected, and prevented, an attempted amnesia attack." +
@@ -280,6 +281,7 @@ func (c *Client) handleConflictingHeaders(
 	c.logger.Error("Sending evidence against witness by primary", "ev", evidenceAgainstWitness,
 		"primary", c.primary, "witness", supportingWitness)
 	c.sendEvidence(ctx, evidenceAgainstWitness, c.primary)
+	c.recordAttack(evidenceAgainstWitness, trustedBlock, witnessTrace, primaryTrace, supportingWitness, c.primary, now)
 	// We return the error and don't process anymore witnesses
 	return ErrLightClientAttack
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	dbm "github.com/cometbft/cometbft-db"
+	cmtproto "github.com/cometbft/cometbft/api/cometbft/types/v1"
 	"github.com/cometbft/cometbft/libs/log"
 	"github.com/cometbft/cometbft/light"
 	"github.com/cometbft/cometbft/light/provider"
@@ -42,6 +43,7 @@ func TestLightClientAttackEvidence_Lunatic(t *testing.T) {
 		primaryValidators[height] = forgedVals
 	}
 	primary := mockp.New(chainID, primaryHeaders, primaryValidators)
+	journal := &memJournal{}
 
 	c, err := light.NewClient(
 		ctx,
@@ -56,6 +58,7 @@ func TestLightClientAttackEvidence_Lunatic(t *testing.T) {
 		dbs.New(dbm.NewMemDB(), chainID),
 		light.Logger(log.TestingLogger()),
 		light.MaxRetryAttempts(1),
+		light.EvidenceJournal(journal),
 	)
 	require.NoError(t, err)
 
@@ -86,6 +89,36 @@ func TestLightClientAttackEvidence_Lunatic(t *testing.T) {
 		CommonHeight: 4,
 	}
 	assert.True(t, primary.HasEvidence(evAgainstWitness))
+
+	// Check both attacks were recorded in the journal.
+	require.Len(t, journal.reports, 2)
+	report := journal.reports[0]
+	assert.Equal(t, light.AttackLunatic, report.AttackType)
+	assert.EqualValues(t, 4, report.CommonHeight)
+	assert.EqualValues(t, 10, report.ConflictingHeight)
+	assert.Equal(t, report.Evidence.Hash(), []byte(report.Hash))
+	assert.NotEmpty(t, report.ByzantineValidators)
+	assert.Equal(t, primaryHeaders[10].Hash(), report.ConflictingTrace[len(report.ConflictingTrace)-1].Hash())
+	assert.Equal(t, witnessHeaders[4].Hash(), report.TrustedTrace[0].Hash())
+	var evp cmtproto.Evidence
+	require.NoError(t, evp.Unmarshal(report.EvidenceProto))
+	ev, err := types.EvidenceFromProto(&evp)
+	require.NoError(t, err)
+	assert.Equal(t, report.Hash.Bytes(), ev.Hash())
+
+	report = journal.reports[1]
+	assert.EqualValues(t, 7, report.ConflictingHeight)
+	assert.Equal(t, witnessHeaders[4].Hash(), report.ConflictingTrace[0].Hash())
+}
+
+// memJournal is a light.AttackJournal keeping the reports in memory.
+type memJournal struct {
+	reports []*light.AttackReport
+}
+
+func (j *memJournal) RecordAttack(report *light.AttackReport) error {
+	j.reports = append(j.reports, report)
+	return nil
 }
 
 func TestLightClientAttackEvidence_Equivocation(t *testing.T) {
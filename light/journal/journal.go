@@ -0,0 +1,110 @@
+// Package journal implements a durable evidence journal for the light client,
+// recording the light client attacks it detects so that operators can list
+// them and export them, e.g. to back a governance or slashing proposal.
+package journal
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cometbft/cometbft/internal/tempfile"
+	cmtjson "github.com/cometbft/cometbft/libs/json"
+	cmtsync "github.com/cometbft/cometbft/libs/sync"
+	"github.com/cometbft/cometbft/light"
+)
+
+const fileExt = ".json"
+
+// ErrReportNotFound is returned when the journal holds no report with the
+// requested hash.
+var ErrReportNotFound = errors.New("attack report not found")
+
+// Journal is a light.AttackJournal keeping each report as a JSON file, named
+// after the hash of its evidence, in a directory.
+type Journal struct {
+	mtx cmtsync.Mutex
+	dir string
+}
+
+var _ light.AttackJournal = (*Journal)(nil)
+
+// New returns a journal stored in dir, creating the directory if needed.
+func New(dir string) (*Journal, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &Journal{dir: dir}, nil
+}
+
+// RecordAttack implements light.AttackJournal. A report replaces the previous
+// one with the same evidence, if any.
+func (j *Journal) RecordAttack(report *light.AttackReport) error {
+	bz, err := cmtjson.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+
+	return tempfile.WriteFileAtomic(j.path(report.Hash), bz, 0o600)
+}
+
+// Report returns the report of the evidence with the given hash.
+func (j *Journal) Report(hash []byte) (*light.AttackReport, error) {
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+
+	return readReport(j.path(hash))
+}
+
+// Reports returns all the reports of the journal, from the oldest to the most
+// recent.
+func (j *Journal) Reports() ([]*light.AttackReport, error) {
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+
+	entries, err := os.ReadDir(j.dir)
+	if err != nil {
+		return nil, err
+	}
+	reports := make([]*light.AttackReport, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), fileExt) {
+			continue
+		}
+		report, err := readReport(filepath.Join(j.dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	sort.SliceStable(reports, func(i, k int) bool {
+		return reports[i].DetectedAt.Before(reports[k].DetectedAt)
+	})
+	return reports, nil
+}
+
+func (j *Journal) path(hash []byte) string {
+	return filepath.Join(j.dir, strings.ToUpper(hex.EncodeToString(hash))+fileExt)
+}
+
+func readReport(path string) (*light.AttackReport, error) {
+	bz, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrReportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	report := &light.AttackReport{}
+	if err := cmtjson.Unmarshal(bz, report); err != nil {
+		return nil, fmt.Errorf("invalid attack report %s: %w", filepath.Base(path), err)
+	}
+	return report, nil
+}
@@ -0,0 +1,88 @@
+package journal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cometbft/cometbft/internal/test"
+	"github.com/cometbft/cometbft/light"
+	"github.com/cometbft/cometbft/types"
+)
+
+func makeReport(t *testing.T, detectedAt time.Time) *light.AttackReport {
+	t.Helper()
+	vals, privVals := test.ValidatorSet(context.Background(), t, 4, 10)
+	header := test.MakeHeader(t, &types.Header{
+		Height:             10,
+		Time:               detectedAt,
+		ValidatorsHash:     vals.Hash(),
+		NextValidatorsHash: vals.Hash(),
+	})
+	blockID := test.MakeBlockIDWithHash(header.Hash())
+	commit, err := test.MakeCommit(blockID, header.Height, 0, vals, privVals, header.ChainID, header.Time)
+	require.NoError(t, err)
+	conflicting := &types.LightBlock{
+		SignedHeader: &types.SignedHeader{Header: header, Commit: commit},
+		ValidatorSet: vals,
+	}
+	ev := &types.LightClientAttackEvidence{
+		ConflictingBlock:    conflicting,
+		CommonHeight:        5,
+		ByzantineValidators: vals.Validators,
+		TotalVotingPower:    vals.TotalVotingPower(),
+		Timestamp:           detectedAt,
+	}
+	return &light.AttackReport{
+		Hash:                ev.Hash(),
+		ChainID:             header.ChainID,
+		DetectedAt:          detectedAt,
+		AttackType:          light.AttackLunatic,
+		Accused:             "http{primary}",
+		ReportedTo:          "http{witness}",
+		CommonHeight:        ev.CommonHeight,
+		ConflictingHeight:   header.Height,
+		ByzantineValidators: ev.ByzantineValidators,
+		Evidence:            ev,
+		EvidenceProto:       []byte{1, 2, 3},
+		ConflictingTrace:    []*types.LightBlock{conflicting},
+	}
+}
+
+func TestJournal(t *testing.T) {
+	dir := t.TempDir()
+	j, err := New(dir)
+	require.NoError(t, err)
+
+	reports, err := j.Reports()
+	require.NoError(t, err)
+	assert.Empty(t, reports)
+
+	newer := makeReport(t, test.DefaultTestTime.Add(time.Hour))
+	older := makeReport(t, test.DefaultTestTime)
+	require.NoError(t, j.RecordAttack(newer))
+	require.NoError(t, j.RecordAttack(older))
+
+	// Reports survive a restart and are sorted by detection time.
+	j, err = New(dir)
+	require.NoError(t, err)
+	reports, err = j.Reports()
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+	assert.Equal(t, older.Hash, reports[0].Hash)
+	assert.Equal(t, newer.Hash, reports[1].Hash)
+
+	report, err := j.Report(older.Hash)
+	require.NoError(t, err)
+	assert.Equal(t, older.Evidence.Hash(), report.Evidence.Hash())
+	assert.Equal(t, older.ConflictingTrace[0].Hash(), report.ConflictingTrace[0].Hash())
+	assert.Equal(t, older.ByzantineValidators[0].PubKey, report.ByzantineValidators[0].PubKey)
+	assert.Equal(t, older.EvidenceProto, report.EvidenceProto)
+	assert.True(t, older.DetectedAt.Equal(report.DetectedAt))
+
+	_, err = j.Report([]byte{0xAB})
+	require.ErrorIs(t, err, ErrReportNotFound)
+}
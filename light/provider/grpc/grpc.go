@@ -0,0 +1,144 @@
+// Package grpc implements a light client provider using the gRPC light block
+// service of a CometBFT node, which returns a complete light block in one
+// call.
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/cometbft/cometbft/light/provider"
+	grpcclient "github.com/cometbft/cometbft/rpc/grpc/client"
+	"github.com/cometbft/cometbft/types"
+)
+
+var (
+	maxRetryAttempts = 5
+	timeout          = 5 * time.Second
+)
+
+// grpc provider uses the light block service of a gRPC client.
+type grpc struct {
+	chainID string
+	remote  string
+	client  grpcclient.LightBlockServiceClient
+}
+
+// New creates a gRPC provider connected to the light block service at the
+// given address, e.g. "127.0.0.1:26670". The 5s timeout is used for all
+// requests. Use grpcclient.WithInsecure to connect without TLS.
+func New(chainID, remote string, opts ...grpcclient.Option) (provider.Provider, error) {
+	c, err := grpcclient.New(context.Background(), remote, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &grpc{
+		chainID: chainID,
+		remote:  remote,
+		client:  c,
+	}, nil
+}
+
+// NewWithClient allows you to provide a custom client.
+func NewWithClient(chainID string, client grpcclient.LightBlockServiceClient) provider.Provider {
+	return &grpc{
+		chainID: chainID,
+		client:  client,
+	}
+}
+
+// ChainID returns a chainID this provider was configured with.
+func (p *grpc) ChainID() string {
+	return p.chainID
+}
+
+func (p *grpc) String() string {
+	return fmt.Sprintf("grpc{%s}", p.remote)
+}
+
+// LightBlock fetches a LightBlock at the given height and checks the
+// chainID matches.
+func (p *grpc) LightBlock(ctx context.Context, height int64) (*types.LightBlock, error) {
+	if height < 0 {
+		return nil, provider.ErrBadLightBlock{Reason: provider.ErrNegativeHeight{Height: height}}
+	}
+
+	lb, err := p.lightBlock(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	if height != 0 && lb.Height != height {
+		return nil, provider.ErrBadLightBlock{
+			Reason: fmt.Errorf("height %d responded doesn't match height %d requested", lb.Height, height),
+		}
+	}
+
+	if err := lb.ValidateBasic(p.chainID); err != nil {
+		return nil, provider.ErrBadLightBlock{Reason: err}
+	}
+
+	return lb, nil
+}
+
+func (p *grpc) lightBlock(ctx context.Context, height int64) (*types.LightBlock, error) {
+	for attempt := 1; attempt <= maxRetryAttempts; attempt++ {
+		reqCtx, cancel := context.WithTimeout(ctx, timeout)
+		lb, err := p.client.GetLightBlock(reqCtx, height)
+		cancel()
+		if err == nil {
+			return lb, nil
+		}
+
+		st, ok := status.FromError(err)
+		switch {
+		case !ok:
+			// Errors not coming from the server come from the conversion of
+			// the light block it returned.
+			return nil, provider.ErrBadLightBlock{Reason: err}
+
+		case st.Code() == codes.OutOfRange:
+			return nil, provider.ErrHeightTooHigh
+
+		case st.Code() == codes.NotFound:
+			return nil, provider.ErrLightBlockNotFound
+
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+
+		case st.Code() == codes.Unavailable, st.Code() == codes.DeadlineExceeded:
+			// we wait and try again with exponential backoff
+			if attempt < maxRetryAttempts {
+				time.Sleep(backoffTimeout(uint16(attempt)))
+			}
+			continue
+
+		default:
+			return nil, err
+		}
+	}
+	return nil, provider.ErrNoResponse
+}
+
+// ReportEvidence reports the evidence to the light block service.
+func (p *grpc) ReportEvidence(ctx context.Context, ev types.Evidence) error {
+	if ev == nil {
+		return errors.New("no evidence to report")
+	}
+	_, err := p.client.ReportEvidence(ctx, ev)
+	return err
+}
+
+// exponential backoff (with jitter)
+// 0.5s -> 2s -> 4.5s -> 8s -> 12.5 with 1s variation.
+func backoffTimeout(attempt uint16) time.Duration {
+	//nolint:gosec // G404: Use of weak random number generator
+	return time.Duration(500*attempt*attempt)*time.Millisecond + time.Duration(rand.Intn(1000))*time.Millisecond
+}
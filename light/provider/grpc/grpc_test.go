@@ -0,0 +1,136 @@
+package grpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ggrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pbsvc "github.com/cometbft/cometbft/api/cometbft/services/light_block/v1"
+	"github.com/cometbft/cometbft/internal/test"
+	"github.com/cometbft/cometbft/light/provider"
+	lightgrpc "github.com/cometbft/cometbft/light/provider/grpc"
+	grpcclient "github.com/cometbft/cometbft/rpc/grpc/client"
+	"github.com/cometbft/cometbft/types"
+)
+
+// lightBlockServer serves the light blocks it holds, up to the latest one.
+type lightBlockServer struct {
+	pbsvc.UnimplementedLightBlockServiceServer
+	blocks   map[int64]*types.LightBlock
+	latest   int64
+	evidence []*types.DuplicateVoteEvidence
+}
+
+func (s *lightBlockServer) GetByHeight(_ context.Context, req *pbsvc.GetByHeightRequest) (*pbsvc.GetByHeightResponse, error) {
+	height := req.Height
+	if height == 0 {
+		height = s.latest
+	}
+	if height > s.latest {
+		return nil, status.Error(codes.OutOfRange, "height too high")
+	}
+	lb, ok := s.blocks[height]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "light block not found")
+	}
+	lbp, err := lb.ToProto()
+	if err != nil {
+		return nil, err
+	}
+	return &pbsvc.GetByHeightResponse{LightBlock: lbp}, nil
+}
+
+func (s *lightBlockServer) ReportEvidence(_ context.Context, req *pbsvc.ReportEvidenceRequest) (*pbsvc.ReportEvidenceResponse, error) {
+	ev, err := types.EvidenceFromProto(req.Evidence)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	s.evidence = append(s.evidence, ev.(*types.DuplicateVoteEvidence))
+	return &pbsvc.ReportEvidenceResponse{Hash: ev.Hash()}, nil
+}
+
+func makeLightBlock(t *testing.T, height int64) *types.LightBlock {
+	t.Helper()
+	vals, privVals := test.ValidatorSet(context.Background(), t, 4, 10)
+	header := test.MakeHeader(t, &types.Header{
+		Height:             height,
+		Time:               test.DefaultTestTime,
+		ValidatorsHash:     vals.Hash(),
+		NextValidatorsHash: vals.Hash(),
+	})
+	blockID := test.MakeBlockIDWithHash(header.Hash())
+	commit, err := test.MakeCommit(blockID, height, 0, vals, privVals, header.ChainID, header.Time)
+	require.NoError(t, err)
+	return &types.LightBlock{
+		SignedHeader: &types.SignedHeader{Header: header, Commit: commit},
+		ValidatorSet: vals,
+	}
+}
+
+func TestProvider(t *testing.T) {
+	srv := &lightBlockServer{
+		blocks: map[int64]*types.LightBlock{
+			2: makeLightBlock(t, 2),
+			3: makeLightBlock(t, 3),
+		},
+		latest: 3,
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := ggrpc.NewServer()
+	pbsvc.RegisterLightBlockServiceServer(server, srv)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	p, err := lightgrpc.New(test.DefaultTestChainID, listener.Addr().String(), grpcclient.WithInsecure())
+	require.NoError(t, err)
+	assert.Equal(t, test.DefaultTestChainID, p.ChainID())
+
+	ctx := context.Background()
+	lb, err := p.LightBlock(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, srv.blocks[3].Hash(), lb.Hash())
+
+	lb, err = p.LightBlock(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, srv.blocks[2].Hash(), lb.Hash())
+
+	_, err = p.LightBlock(ctx, 4)
+	require.ErrorIs(t, err, provider.ErrHeightTooHigh)
+
+	_, err = p.LightBlock(ctx, 1)
+	require.ErrorIs(t, err, provider.ErrLightBlockNotFound)
+
+	_, err = p.LightBlock(ctx, -1)
+	require.ErrorAs(t, err, &provider.ErrBadLightBlock{})
+
+	// Light blocks of another chain are rejected.
+	other := lightgrpc.NewWithClient("other-chain", mustClient(t, listener.Addr().String()))
+	_, err = other.LightBlock(ctx, 2)
+	require.ErrorAs(t, err, &provider.ErrBadLightBlock{})
+
+	// A light block at another height than the requested one is rejected.
+	srv.blocks[1] = srv.blocks[2]
+	_, err = p.LightBlock(ctx, 1)
+	require.ErrorAs(t, err, &provider.ErrBadLightBlock{})
+
+	ev, err := types.NewMockDuplicateVoteEvidence(2, test.DefaultTestTime, test.DefaultTestChainID)
+	require.NoError(t, err)
+	require.NoError(t, p.ReportEvidence(ctx, ev))
+	require.Len(t, srv.evidence, 1)
+	assert.Equal(t, ev.Hash(), srv.evidence[0].Hash())
+}
+
+func mustClient(t *testing.T, addr string) grpcclient.Client {
+	t.Helper()
+	c, err := grpcclient.New(context.Background(), addr, grpcclient.WithInsecure())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
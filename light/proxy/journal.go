@@ -0,0 +1,52 @@
+package proxy
+
+import (
+	"github.com/cometbft/cometbft/light"
+	"github.com/cometbft/cometbft/light/journal"
+	rpcserver "github.com/cometbft/cometbft/rpc/jsonrpc/server"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+)
+
+// ResultAttackReports is the result of light_attack_reports.
+type ResultAttackReports struct {
+	Reports []*light.AttackReport `json:"reports"`
+	Total   int                   `json:"total"`
+}
+
+// ResultAttackReport is the result of light_attack_report.
+type ResultAttackReport struct {
+	Report *light.AttackReport `json:"report"`
+}
+
+// JournalRoutes returns the routes serving the attack reports of the evidence
+// journal.
+func JournalRoutes(j *journal.Journal) map[string]*rpcserver.RPCFunc {
+	return map[string]*rpcserver.RPCFunc{
+		"light_attack_reports": rpcserver.NewRPCFunc(makeAttackReportsFunc(j), ""),
+		"light_attack_report":  rpcserver.NewRPCFunc(makeAttackReportFunc(j), "hash"),
+	}
+}
+
+type rpcAttackReportsFunc func(ctx *rpctypes.Context) (*ResultAttackReports, error)
+
+func makeAttackReportsFunc(j *journal.Journal) rpcAttackReportsFunc {
+	return func(_ *rpctypes.Context) (*ResultAttackReports, error) {
+		reports, err := j.Reports()
+		if err != nil {
+			return nil, err
+		}
+		return &ResultAttackReports{Reports: reports, Total: len(reports)}, nil
+	}
+}
+
+type rpcAttackReportFunc func(ctx *rpctypes.Context, hash []byte) (*ResultAttackReport, error)
+
+func makeAttackReportFunc(j *journal.Journal) rpcAttackReportFunc {
+	return func(_ *rpctypes.Context, hash []byte) (*ResultAttackReport, error) {
+		report, err := j.Report(hash)
+		if err != nil {
+			return nil, err
+		}
+		return &ResultAttackReport{Report: report}, nil
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/cometbft/cometbft/libs/log"
 	cmtpubsub "github.com/cometbft/cometbft/libs/pubsub"
 	"github.com/cometbft/cometbft/light"
+	"github.com/cometbft/cometbft/light/journal"
 	lrpc "github.com/cometbft/cometbft/light/rpc"
 	rpchttp "github.com/cometbft/cometbft/rpc/client/http"
 	rpcserver "github.com/cometbft/cometbft/rpc/jsonrpc/server"
@@ -20,6 +21,9 @@ type Proxy struct {
 	Client   *lrpc.Client
 	Logger   log.Logger
 	Listener net.Listener
+	// Journal, if set, is served by the light_attack_reports and
+	// light_attack_report routes.
+	Journal *journal.Journal
 }
 
 // NewProxy creates the struct used to run an HTTP server for serving light
@@ -88,6 +92,11 @@ func (p *Proxy) listen() (net.Listener, *http.ServeMux, error) {
 
 	// 1) Register regular routes.
 	r := RPCRoutes(p.Client)
+	if p.Journal != nil {
+		for name, fn := range JournalRoutes(p.Journal) {
+			r[name] = fn
+		}
+	}
 	rpcserver.RegisterRPCFuncs(mux, r, p.Logger)
 
 	// 2) Allow websocket connections.
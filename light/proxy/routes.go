@@ -37,6 +37,7 @@ func RPCRoutes(c *lrpc.Client) map[string]*rpcserver.RPCFunc {
 		"dump_consensus_state": rpcserver.NewRPCFunc(makeDumpConsensusStateFunc(c), ""),
 		"consensus_state":      rpcserver.NewRPCFunc(makeConsensusStateFunc(c), ""),
 		"consensus_params":     rpcserver.NewRPCFunc(makeConsensusParamsFunc(c), "height", rpcserver.Cacheable("height")),
+		"proposer_schedule":    rpcserver.NewRPCFunc(makeProposerScheduleFunc(c), "count"),
 		"unconfirmed_tx":       rpcserver.NewRPCFunc(makeUnconfirmedTxFunc(c), "hash"),
 		"unconfirmed_txs":      rpcserver.NewRPCFunc(makeUnconfirmedTxsFunc(c), "limit"),
 		"num_unconfirmed_txs":  rpcserver.NewRPCFunc(makeNumUnconfirmedTxsFunc(c), ""),
@@ -232,6 +233,14 @@ func makeConsensusParamsFunc(c *lrpc.Client) rpcConsensusParamsFunc {
 	}
 }
 
+type rpcProposerScheduleFunc func(ctx *rpctypes.Context, count *int) (*ctypes.ResultProposerSchedule, error)
+
+func makeProposerScheduleFunc(c *lrpc.Client) rpcProposerScheduleFunc {
+	return func(ctx *rpctypes.Context, count *int) (*ctypes.ResultProposerSchedule, error) {
+		return c.ProposerSchedule(ctx.Context(), count)
+	}
+}
+
 type rpcUnconfirmedTxFunc func(ctx *rpctypes.Context, hash []byte) (*ctypes.ResultUnconfirmedTx, error)
 
 func makeUnconfirmedTxFunc(c *lrpc.Client) rpcUnconfirmedTxFunc {
@@ -3,10 +3,12 @@ package rpc
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
 	"time"
 
+	abci "github.com/cometbft/cometbft/abci/types"
 	"github.com/cometbft/cometbft/crypto/merkle"
 	cmtbytes "github.com/cometbft/cometbft/libs/bytes"
 	cmtmath "github.com/cometbft/cometbft/libs/math"
@@ -37,7 +39,7 @@ var _ rpcclient.Client = (*Client)(nil)
 
 // Client is an RPC client, which uses light#Client to verify data (if it can
 // be proved). Note, merkle.DefaultProofRuntime is used to verify values
-// returned by ABCI#Query.
+// returned by ABCI#Query, unless the ProofRuntime option is given.
 type Client struct {
 	service.BaseService
 
@@ -256,6 +258,13 @@ func (c *Client) ConsensusParams(ctx context.Context, height *int64) (*ctypes.Re
 	return res, nil
 }
 
+// ProposerSchedule returns the proposer schedule of the primary as is. It can
+// not be verified, since proposer priorities are not part of the validators
+// hash.
+func (c *Client) ProposerSchedule(ctx context.Context, count *int) (*ctypes.ResultProposerSchedule, error) {
+	return c.next.ProposerSchedule(ctx, count)
+}
+
 func (c *Client) Health(ctx context.Context) (*ctypes.ResultHealth, error) {
 	return c.next.Health(ctx)
 }
@@ -315,28 +324,9 @@ func (c *Client) Block(ctx context.Context, height *int64) (*ctypes.ResultBlock,
 		return nil, err
 	}
 
-	// Validate res.
-	if err := res.BlockID.ValidateBasic(); err != nil {
-		return nil, err
-	}
-	if err := res.Block.ValidateBasic(); err != nil {
-		return nil, err
-	}
-	if bmH, bH := res.BlockID.Hash, res.Block.Hash(); !bytes.Equal(bmH, bH) {
-		return nil, ErrBlockIDMismatch{BlockID: bmH, Block: bH}
-	}
-
-	// Update the light client if we're behind.
-	l, err := c.updateLightClientIfNeededTo(ctx, &res.Block.Height)
-	if err != nil {
+	if err := c.verifyBlock(ctx, res); err != nil {
 		return nil, err
 	}
-
-	// Verify block.
-	if bH, tH := res.Block.Hash(), l.Hash(); !bytes.Equal(bH, tH) {
-		return nil, ErrBlockHeaderMismatch{BlockHeader: bH, TrustedHeader: tH}
-	}
-
 	return res, nil
 }
 
@@ -347,29 +337,36 @@ func (c *Client) BlockByHash(ctx context.Context, hash []byte) (*ctypes.ResultBl
 		return nil, err
 	}
 
+	if err := c.verifyBlock(ctx, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// verifyBlock verifies the block against the trusted header at its height.
+func (c *Client) verifyBlock(ctx context.Context, res *ctypes.ResultBlock) error {
 	// Validate res.
 	if err := res.BlockID.ValidateBasic(); err != nil {
-		return nil, err
+		return err
 	}
 	if err := res.Block.ValidateBasic(); err != nil {
-		return nil, err
+		return err
 	}
 	if bmH, bH := res.BlockID.Hash, res.Block.Hash(); !bytes.Equal(bmH, bH) {
-		return nil, ErrBlockIDMismatch{BlockID: bmH, Block: bH}
+		return ErrBlockIDMismatch{BlockID: bmH, Block: bH}
 	}
 
 	// Update the light client if we're behind.
 	l, err := c.updateLightClientIfNeededTo(ctx, &res.Block.Height)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	// Verify block.
 	if bH, tH := res.Block.Hash(), l.Hash(); !bytes.Equal(bH, tH) {
-		return nil, ErrBlockHeaderMismatch{BlockHeader: bH, TrustedHeader: tH}
+		return ErrBlockHeaderMismatch{BlockHeader: bH, TrustedHeader: tH}
 	}
-
-	return res, nil
+	return nil
 }
 
 // BlockResults returns the block results for the given height. If no height is
@@ -487,6 +484,11 @@ func (c *Client) Tx(ctx context.Context, hash []byte, prove bool) (*ctypes.Resul
 	return res, res.Proof.Validate(l.DataHash)
 }
 
+// TxSearch calls rpcclient#TxSearch, always requesting proofs, and then
+// verifies every returned tx against the trusted header of its block, and its
+// result against the trusted header of the next block. Hence, txs of the
+// latest block cannot be verified until the next block is committed.
+// The proofs are only returned if prove is true.
 func (c *Client) TxSearch(
 	ctx context.Context,
 	query string,
@@ -494,16 +496,90 @@ func (c *Client) TxSearch(
 	page, perPage *int,
 	orderBy string,
 ) (*ctypes.ResultTxSearch, error) {
-	return c.next.TxSearch(ctx, query, prove, page, perPage, orderBy)
+	res, err := c.next.TxSearch(ctx, query, true, page, perPage, orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	// The results of the txs of a block are verified once.
+	blockResults := make(map[int64][]*abci.ExecTxResult)
+	for _, tx := range res.Txs {
+		if err := c.verifyTx(ctx, tx, blockResults); err != nil {
+			return nil, err
+		}
+		if !prove {
+			tx.Proof = types.TxProof{}
+		}
+	}
+	return res, nil
+}
+
+// verifyTx verifies the tx and its result. blockResults caches the verified
+// results of the blocks, by height.
+func (c *Client) verifyTx(ctx context.Context, res *ctypes.ResultTx, blockResults map[int64][]*abci.ExecTxResult) error {
+	// Validate res.
+	if res.Height <= 0 {
+		return ErrNegOrZeroHeight
+	}
+	if tH := res.Tx.Hash(); !bytes.Equal(res.Hash, tH) {
+		return ErrTxHashMismatch{Hash: res.Hash, TxHash: tH}
+	}
+	if !bytes.Equal(res.Proof.Data, res.Tx) || res.Proof.Proof.Index != int64(res.Index) {
+		return ErrInvalidTxProof{Hash: res.Hash, Err: errors.New("proof is for another tx")}
+	}
+
+	// Update the light client if we're behind.
+	l, err := c.updateLightClientIfNeededTo(ctx, &res.Height)
+	if err != nil {
+		return err
+	}
+
+	// Verify the inclusion of the tx.
+	if err := res.Proof.Validate(l.DataHash); err != nil {
+		return ErrInvalidTxProof{Hash: res.Hash, Err: err}
+	}
+
+	// Verify the result of the tx against the verified results of its block.
+	txResults, ok := blockResults[res.Height]
+	if !ok {
+		br, err := c.BlockResults(ctx, &res.Height)
+		if err != nil {
+			return err
+		}
+		txResults = br.TxResults
+		blockResults[res.Height] = txResults
+	}
+	if int(res.Index) >= len(txResults) {
+		return ErrTxResultMismatch{Hash: res.Hash, Height: res.Height, Index: res.Index}
+	}
+	if !bytes.Equal(
+		types.NewResults([]*abci.ExecTxResult{&res.TxResult}).Hash(),
+		types.NewResults(txResults[res.Index:res.Index+1]).Hash(),
+	) {
+		return ErrTxResultMismatch{Hash: res.Hash, Height: res.Height, Index: res.Index}
+	}
+	return nil
 }
 
+// BlockSearch calls rpcclient#BlockSearch and then verifies every returned
+// block against the trusted header at its height.
 func (c *Client) BlockSearch(
 	ctx context.Context,
 	query string,
 	page, perPage *int,
 	orderBy string,
 ) (*ctypes.ResultBlockSearch, error) {
-	return c.next.BlockSearch(ctx, query, page, perPage, orderBy)
+	res, err := c.next.BlockSearch(ctx, query, page, perPage, orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, block := range res.Blocks {
+		if err := c.verifyBlock(ctx, block); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
 }
 
 // Validators fetches and verifies validators.
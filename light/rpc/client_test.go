@@ -0,0 +1,171 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	ics23 "github.com/cosmos/ics23/go"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmtcrypto "github.com/cometbft/cometbft/api/cometbft/crypto/v1"
+	"github.com/cometbft/cometbft/crypto/merkle"
+	"github.com/cometbft/cometbft/crypto/merkle/commitment"
+	"github.com/cometbft/cometbft/internal/test"
+	"github.com/cometbft/cometbft/light/rpc/mocks"
+	rpcmocks "github.com/cometbft/cometbft/rpc/client/mocks"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	"github.com/cometbft/cometbft/types"
+)
+
+// searchFixture is a block at height 2 holding txs, and the light blocks at
+// heights 2 and 3 trusting the txs and their results.
+type searchFixture struct {
+	txs     types.Txs
+	results []*abci.ExecTxResult
+	next    *rpcmocks.Client
+	lc      *mocks.LightClient
+}
+
+func newSearchFixture(t *testing.T) *searchFixture {
+	t.Helper()
+	f := &searchFixture{
+		txs: types.Txs{types.Tx("a=1"), types.Tx("b=2")},
+		results: []*abci.ExecTxResult{
+			{Code: 0, Data: []byte("a"), Log: "not verified"},
+			{Code: 1, Data: []byte("b")},
+		},
+		next: &rpcmocks.Client{},
+		lc:   mocks.NewLightClient(t),
+	}
+	lightBlock := func(header *types.Header) *types.LightBlock {
+		return &types.LightBlock{SignedHeader: &types.SignedHeader{Header: header}}
+	}
+	f.lc.On("VerifyLightBlockAtHeight", mock.Anything, int64(2), mock.Anything).
+		Return(lightBlock(&types.Header{Height: 2, DataHash: f.txs.Hash()}), nil).Maybe()
+	f.lc.On("VerifyLightBlockAtHeight", mock.Anything, int64(3), mock.Anything).
+		Return(lightBlock(&types.Header{Height: 3, LastResultsHash: types.NewResults(f.results).Hash()}), nil).Maybe()
+	return f
+}
+
+// resultTx returns the tx at the given index as returned by tx_search.
+func (f *searchFixture) resultTx(i int) *ctypes.ResultTx {
+	return &ctypes.ResultTx{
+		Hash:     f.txs[i].Hash(),
+		Height:   2,
+		Index:    uint32(i),
+		TxResult: *f.results[i],
+		Tx:       f.txs[i],
+		Proof:    f.txs.Proof(i),
+	}
+}
+
+func (f *searchFixture) search(txs ...*ctypes.ResultTx) (*ctypes.ResultTxSearch, error) {
+	f.next.On("TxSearch", mock.Anything, "tx.height=2", true, mock.Anything, mock.Anything, "").
+		Return(&ctypes.ResultTxSearch{Txs: txs, TotalCount: len(txs)}, nil).Once()
+	f.next.On("BlockResults", mock.Anything, mock.Anything).
+		Return(&ctypes.ResultBlockResults{Height: 2, TxResults: f.results}, nil).Maybe()
+	c := NewClient(f.next, f.lc)
+	return c.TxSearch(context.Background(), "tx.height=2", false, nil, nil, "")
+}
+
+func TestTxSearch(t *testing.T) {
+	f := newSearchFixture(t)
+	res, err := f.search(f.resultTx(0), f.resultTx(1))
+	require.NoError(t, err)
+	require.Len(t, res.Txs, 2)
+	// Proofs are not returned if not requested.
+	require.Empty(t, res.Txs[0].Proof.Data)
+	// The results of the block are fetched once.
+	f.next.AssertNumberOfCalls(t, "BlockResults", 1)
+}
+
+func TestTxSearch_Invalid(t *testing.T) {
+	testCases := map[string]struct {
+		malleate func(f *searchFixture, res *ctypes.ResultTx)
+		target   any
+	}{
+		"other tx": {
+			malleate: func(_ *searchFixture, res *ctypes.ResultTx) { res.Tx = types.Tx("c=3") },
+			target:   &ErrTxHashMismatch{},
+		},
+		"proof of other tx": {
+			malleate: func(f *searchFixture, res *ctypes.ResultTx) { res.Proof = f.txs.Proof(1) },
+			target:   &ErrInvalidTxProof{},
+		},
+		"tx not in block": {
+			malleate: func(_ *searchFixture, res *ctypes.ResultTx) {
+				txs := types.Txs{types.Tx("a=1"), types.Tx("c=3")}
+				res.Proof = txs.Proof(0)
+			},
+			target: &ErrInvalidTxProof{},
+		},
+		"other result": {
+			malleate: func(_ *searchFixture, res *ctypes.ResultTx) { res.TxResult.Code = 1 },
+			target:   &ErrTxResultMismatch{},
+		},
+		"untrusted block results": {
+			malleate: func(f *searchFixture, _ *ctypes.ResultTx) {
+				f.next.On("BlockResults", mock.Anything, mock.Anything).
+					Return(&ctypes.ResultBlockResults{Height: 2, TxResults: f.results[:1]}, nil).Once()
+			},
+			target: &ErrLastResultMismatch{},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			f := newSearchFixture(t)
+			res := f.resultTx(0)
+			tc.malleate(f, res)
+			_, err := f.search(res)
+			require.ErrorAs(t, err, tc.target)
+		})
+	}
+}
+
+func TestBlockSearch(t *testing.T) {
+	makeBlock := func() *ctypes.ResultBlock {
+		block := types.MakeBlock(2, types.Txs{types.Tx("a=1")}, &types.Commit{}, nil)
+		test.MakeHeader(t, &block.Header)
+		return &ctypes.ResultBlock{BlockID: test.MakeBlockIDWithHash(block.Hash()), Block: block}
+	}
+	trusted := makeBlock()
+
+	next := &rpcmocks.Client{}
+	lc := mocks.NewLightClient(t)
+	lc.On("VerifyLightBlockAtHeight", mock.Anything, int64(2), mock.Anything).
+		Return(&types.LightBlock{SignedHeader: &types.SignedHeader{Header: &trusted.Block.Header}}, nil)
+	next.On("BlockSearch", mock.Anything, "block.height=2", mock.Anything, mock.Anything, "").
+		Return(&ctypes.ResultBlockSearch{Blocks: []*ctypes.ResultBlock{trusted}, TotalCount: 1}, nil).Once()
+	c := NewClient(next, lc)
+
+	res, err := c.BlockSearch(context.Background(), "block.height=2", nil, nil, "")
+	require.NoError(t, err)
+	require.Len(t, res.Blocks, 1)
+
+	// A block which is not the trusted one is rejected.
+	next.On("BlockSearch", mock.Anything, "block.height=2", mock.Anything, mock.Anything, "").
+		Return(&ctypes.ResultBlockSearch{Blocks: []*ctypes.ResultBlock{makeBlock()}, TotalCount: 1}, nil).Once()
+	_, err = c.BlockSearch(context.Background(), "block.height=2", nil, nil, "")
+	require.ErrorAs(t, err, &ErrBlockHeaderMismatch{})
+}
+
+func TestNewProofRuntime(t *testing.T) {
+	_, err := NewProofRuntime(merkle.ProofOpValue, "unknown")
+	require.ErrorAs(t, err, &ErrUnknownProofOp{})
+
+	prt, err := NewProofRuntime(commitment.ProofOpIAVLCommitment)
+	require.NoError(t, err)
+	_, err = prt.Decode(commitment.NewIAVLOp([]byte("key"), &ics23.CommitmentProof{}).ProofOp())
+	require.NoError(t, err)
+	_, err = prt.Decode(cmtcrypto.ProofOp{Type: merkle.ProofOpValue})
+	require.ErrorContains(t, err, "unrecognized proof type")
+
+	// All the registered proof operators are known by default.
+	require.Contains(t, ProofOpTypes(), commitment.ProofOpSMTCommitment)
+	prt, err = NewProofRuntime()
+	require.NoError(t, err)
+	_, err = prt.Decode(commitment.NewSMTOp([]byte("key"), &ics23.CommitmentProof{}).ProofOp())
+	require.NoError(t, err)
+}
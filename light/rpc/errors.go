@@ -23,6 +23,14 @@ func (e ErrMissingStoreName) Error() string {
 	return fmt.Sprintf("can't find store name in %s using %s", e.Path, e.Rex)
 }
 
+type ErrUnknownProofOp struct {
+	Type string
+}
+
+func (e ErrUnknownProofOp) Error() string {
+	return "unknown proof operator type " + e.Type
+}
+
 type ErrResponseCode struct {
 	Code uint32
 }
@@ -102,6 +110,38 @@ func (e ErrBlockIDMismatch) Error() string {
 	return fmt.Sprintf("blockID %X does not match with block %X", e.BlockID, e.Block)
 }
 
+type ErrTxHashMismatch struct {
+	Hash   cmtbytes.HexBytes
+	TxHash cmtbytes.HexBytes
+}
+
+func (e ErrTxHashMismatch) Error() string {
+	return fmt.Sprintf("tx hash %X does not match with tx %X", e.Hash, e.TxHash)
+}
+
+type ErrInvalidTxProof struct {
+	Hash cmtbytes.HexBytes
+	Err  error
+}
+
+func (e ErrInvalidTxProof) Error() string {
+	return fmt.Sprintf("invalid proof of tx %X: %v", e.Hash, e.Err)
+}
+
+func (e ErrInvalidTxProof) Unwrap() error {
+	return e.Err
+}
+
+type ErrTxResultMismatch struct {
+	Hash   cmtbytes.HexBytes
+	Height int64
+	Index  uint32
+}
+
+func (e ErrTxResultMismatch) Error() string {
+	return fmt.Sprintf("result of tx %X does not match with trusted result %d of height %d", e.Hash, e.Index, e.Height)
+}
+
 type ErrBuildMerkleKeyPath struct {
 	Err error
 }
@@ -0,0 +1,77 @@
+package rpc
+
+import (
+	"sort"
+
+	"github.com/cometbft/cometbft/crypto/merkle"
+	"github.com/cometbft/cometbft/crypto/merkle/commitment"
+	cmtsync "github.com/cometbft/cometbft/libs/sync"
+)
+
+var (
+	proofOpsMtx cmtsync.RWMutex
+	// proofOps is the registry of the proof operators which can be used to
+	// verify ABCI query responses, by type.
+	proofOps = map[string]merkle.OpDecoder{
+		merkle.ProofOpValue:                      merkle.ValueOpDecoder,
+		commitment.ProofOpIAVLCommitment:         commitment.IAVLOpDecoder,
+		commitment.ProofOpSMTCommitment:          commitment.SMTOpDecoder,
+		commitment.ProofOpSimpleMerkleCommitment: commitment.SimpleMerkleOpDecoder,
+	}
+)
+
+// RegisterProofOp adds a proof operator to the registry, making it available
+// to NewProofRuntime. It panics if an operator is already registered for the
+// type.
+func RegisterProofOp(typ string, dec merkle.OpDecoder) {
+	proofOpsMtx.Lock()
+	defer proofOpsMtx.Unlock()
+
+	if _, ok := proofOps[typ]; ok {
+		panic("proof operator already registered for type " + typ)
+	}
+	proofOps[typ] = dec
+}
+
+// ProofOpTypes returns the types of the registered proof operators, sorted.
+func ProofOpTypes() []string {
+	proofOpsMtx.RLock()
+	defer proofOpsMtx.RUnlock()
+
+	types := make([]string, 0, len(proofOps))
+	for typ := range proofOps {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// NewProofRuntime returns a proof runtime decoding the registered proof
+// operators of the given types, or all of them if no type is given.
+func NewProofRuntime(types ...string) (*merkle.ProofRuntime, error) {
+	if len(types) == 0 {
+		types = ProofOpTypes()
+	}
+
+	proofOpsMtx.RLock()
+	defer proofOpsMtx.RUnlock()
+
+	prt := merkle.NewProofRuntime()
+	for _, typ := range types {
+		dec, ok := proofOps[typ]
+		if !ok {
+			return nil, ErrUnknownProofOp{Type: typ}
+		}
+		prt.RegisterOpDecoder(typ, dec)
+	}
+	return prt, nil
+}
+
+// ProofRuntime option sets the proof runtime used to verify the values
+// returned by ABCIQuery. It defaults to merkle.DefaultProofRuntime, which only
+// knows about simple value proofs; see NewProofRuntime.
+func ProofRuntime(prt *merkle.ProofRuntime) Option {
+	return func(c *Client) {
+		c.prt = prt
+	}
+}
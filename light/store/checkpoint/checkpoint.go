@@ -0,0 +1,240 @@
+// Package checkpoint implements checkpoint bundles: compact files of verified
+// light blocks that a fresh light client can start from, and a light client
+// store backed by such a file.
+//
+// A bundle holds light blocks of a chain in increasing height. Every block
+// after the first one, called an anchor, can be verified from the previous
+// one with skipping verification, within the trusting period measured at the
+// time of the anchor. Hence, trusting the first block of a bundle, through
+// its hash or the genesis validators, is enough to trust the last one.
+package checkpoint
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	cmtproto "github.com/cometbft/cometbft/api/cometbft/types/v1"
+	"github.com/cometbft/cometbft/internal/tempfile"
+	cmtmath "github.com/cometbft/cometbft/libs/math"
+	"github.com/cometbft/cometbft/libs/protoio"
+	"github.com/cometbft/cometbft/light"
+	"github.com/cometbft/cometbft/types"
+)
+
+const (
+	// maxLightBlockSize is the maximum size of an encoded light block.
+	maxLightBlockSize = 16 << 20
+
+	// maxClockDrift is the drift allowed between the time of an anchor and
+	// the time it is verified at, which is the time of the anchor itself.
+	maxClockDrift = 10 * time.Second
+)
+
+// magic starts every bundle file, followed by the length-delimited protobuf
+// encoding of each light block.
+var magic = []byte("cometbft-light-checkpoint-v1\n")
+
+// ErrEmptyBundle is returned when a bundle holds no light block.
+var ErrEmptyBundle = errors.New("empty checkpoint bundle")
+
+// Bundle is a sequence of light blocks of a chain, in increasing height.
+type Bundle struct {
+	LightBlocks []*types.LightBlock
+}
+
+// First returns the first light block of the bundle, which must be trusted.
+func (b *Bundle) First() *types.LightBlock {
+	return b.LightBlocks[0]
+}
+
+// Last returns the last light block of the bundle.
+func (b *Bundle) Last() *types.LightBlock {
+	return b.LightBlocks[len(b.LightBlocks)-1]
+}
+
+// ReadFile reads the bundle stored at path by WriteFile.
+func ReadFile(path string) (*Bundle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// Read reads a bundle written by Write.
+func Read(r io.Reader) (*Bundle, error) {
+	br := bufio.NewReader(r)
+	header := make([]byte, len(magic))
+	if _, err := io.ReadFull(br, header); err != nil || !bytes.Equal(header, magic) {
+		return nil, errors.New("not a checkpoint bundle")
+	}
+
+	b := &Bundle{}
+	rd := protoio.NewDelimitedReader(br, maxLightBlockSize)
+	for {
+		var lbpb cmtproto.LightBlock
+		if n, err := rd.ReadMsg(&lbpb); err != nil {
+			if n == 0 && errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to read light block: %w", err)
+		}
+		lb, err := types.LightBlockFromProto(&lbpb)
+		if err != nil {
+			return nil, fmt.Errorf("invalid light block: %w", err)
+		}
+		if len(b.LightBlocks) > 0 && lb.Height <= b.Last().Height {
+			return nil, fmt.Errorf("light block %d follows light block %d", lb.Height, b.Last().Height)
+		}
+		b.LightBlocks = append(b.LightBlocks, lb)
+	}
+	return b, nil
+}
+
+// WriteFile atomically writes the bundle to path.
+func WriteFile(path string, b *Bundle) error {
+	var buf bytes.Buffer
+	if err := Write(&buf, b); err != nil {
+		return err
+	}
+	return tempfile.WriteFileAtomic(path, buf.Bytes(), 0o644)
+}
+
+// Write writes the bundle to w.
+func Write(w io.Writer, b *Bundle) error {
+	if _, err := w.Write(magic); err != nil {
+		return err
+	}
+	wr := protoio.NewDelimitedWriter(w)
+	for _, lb := range b.LightBlocks {
+		lbpb, err := lb.ToProto()
+		if err != nil {
+			return err
+		}
+		if _, err := wr.WriteMsg(lbpb); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Compact returns a bundle allowing to verify the last of the given blocks
+// from the first one, with skipping verification at the given trust level and
+// within the trusting period. Every anchor is the farthest block that can be
+// verified from the previous one. The blocks must be sorted by height and
+// verified, e.g. be the content of a light client store.
+func Compact(
+	blocks []*types.LightBlock,
+	trustingPeriod time.Duration,
+	trustLevel cmtmath.Fraction,
+) (*Bundle, error) {
+	if len(blocks) == 0 {
+		return nil, ErrEmptyBundle
+	}
+
+	b := &Bundle{LightBlocks: []*types.LightBlock{blocks[0]}}
+	for i := 0; i < len(blocks)-1; {
+		// Pick the farthest block that can be verified from the last anchor.
+		next := -1
+		for j := len(blocks) - 1; j > i; j-- {
+			if verifyAnchor(blocks[i], blocks[j], trustingPeriod, trustLevel) == nil {
+				next = j
+				break
+			}
+		}
+		if next < 0 {
+			return nil, fmt.Errorf("light block %d cannot be verified from light block %d",
+				blocks[i+1].Height, blocks[i].Height)
+		}
+		b.LightBlocks = append(b.LightBlocks, blocks[next])
+		i = next
+	}
+	return b, nil
+}
+
+// VerifyFromHash verifies the bundle, trusting its first light block if it
+// has the given hash.
+func (b *Bundle) VerifyFromHash(
+	chainID string,
+	hash []byte,
+	trustingPeriod time.Duration,
+	trustLevel cmtmath.Fraction,
+) error {
+	if len(b.LightBlocks) == 0 {
+		return ErrEmptyBundle
+	}
+	if !bytes.Equal(b.First().Hash(), hash) {
+		return fmt.Errorf("expected first light block to have hash %X, got %X", hash, b.First().Hash())
+	}
+	if err := verifyFirst(chainID, b.First()); err != nil {
+		return err
+	}
+	return b.verifyAnchors(trustingPeriod, trustLevel)
+}
+
+// VerifyFromGenesis verifies the bundle, trusting its first light block if
+// it is the first block of the chain and is signed by the genesis validators.
+func (b *Bundle) VerifyFromGenesis(
+	genDoc *types.GenesisDoc,
+	trustingPeriod time.Duration,
+	trustLevel cmtmath.Fraction,
+) error {
+	if len(b.LightBlocks) == 0 {
+		return ErrEmptyBundle
+	}
+	first := b.First()
+	if first.Height != genDoc.InitialHeight {
+		return fmt.Errorf("expected first light block at the initial height %d, got %d",
+			genDoc.InitialHeight, first.Height)
+	}
+	vals := make([]*types.Validator, len(genDoc.Validators))
+	for i, val := range genDoc.Validators {
+		vals[i] = types.NewValidator(val.PubKey, val.Power)
+	}
+	if valsHash := types.NewValidatorSet(vals).Hash(); !bytes.Equal(first.ValidatorsHash, valsHash) {
+		return fmt.Errorf("expected first light block to be signed by the genesis validators %X, got %X",
+			valsHash, first.ValidatorsHash)
+	}
+	if err := verifyFirst(genDoc.ChainID, first); err != nil {
+		return err
+	}
+	return b.verifyAnchors(trustingPeriod, trustLevel)
+}
+
+// verifyFirst checks that the first light block is committed by its
+// validators, whose hash has been checked by the caller.
+func verifyFirst(chainID string, lb *types.LightBlock) error {
+	if err := lb.ValidateBasic(chainID); err != nil {
+		return fmt.Errorf("invalid light block %d: %w", lb.Height, err)
+	}
+	if err := lb.ValidatorSet.VerifyCommitLight(chainID, lb.Commit.BlockID, lb.Height, lb.Commit); err != nil {
+		return fmt.Errorf("invalid commit of light block %d: %w", lb.Height, err)
+	}
+	return nil
+}
+
+func (b *Bundle) verifyAnchors(trustingPeriod time.Duration, trustLevel cmtmath.Fraction) error {
+	for i := 1; i < len(b.LightBlocks); i++ {
+		if err := verifyAnchor(b.LightBlocks[i-1], b.LightBlocks[i], trustingPeriod, trustLevel); err != nil {
+			return fmt.Errorf("failed to verify light block %d from light block %d: %w",
+				b.LightBlocks[i].Height, b.LightBlocks[i-1].Height, err)
+		}
+	}
+	return nil
+}
+
+// verifyAnchor verifies untrusted from trusted as a light client would have
+// done at the time of untrusted.
+func verifyAnchor(trusted, untrusted *types.LightBlock, trustingPeriod time.Duration, trustLevel cmtmath.Fraction) error {
+	if err := untrusted.ValidateBasic(trusted.ChainID); err != nil {
+		return err
+	}
+	return light.Verify(trusted.SignedHeader, trusted.ValidatorSet, untrusted.SignedHeader, untrusted.ValidatorSet,
+		trustingPeriod, untrusted.Time, maxClockDrift, trustLevel)
+}
@@ -0,0 +1,149 @@
+package checkpoint
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cometbft/cometbft/internal/test"
+	"github.com/cometbft/cometbft/light"
+	"github.com/cometbft/cometbft/types"
+)
+
+// genLightBlocks returns n light blocks signed by the same validators, one
+// hour apart, and the genesis doc of their chain.
+func genLightBlocks(t *testing.T, n int) ([]*types.LightBlock, *types.GenesisDoc) {
+	t.Helper()
+	vals, privVals := test.ValidatorSet(context.Background(), t, 4, 10)
+	blocks := make([]*types.LightBlock, n)
+	for i := range blocks {
+		height := int64(i + 1)
+		header := test.MakeHeader(t, &types.Header{
+			Height:             height,
+			Time:               test.DefaultTestTime.Add(time.Duration(i) * time.Hour),
+			ValidatorsHash:     vals.Hash(),
+			NextValidatorsHash: vals.Hash(),
+		})
+		blockID := test.MakeBlockIDWithHash(header.Hash())
+		commit, err := test.MakeCommit(blockID, height, 0, vals, privVals, header.ChainID, header.Time)
+		require.NoError(t, err)
+		blocks[i] = &types.LightBlock{
+			SignedHeader: &types.SignedHeader{Header: header, Commit: commit},
+			ValidatorSet: vals,
+		}
+	}
+
+	genDoc := &types.GenesisDoc{
+		ChainID:       test.DefaultTestChainID,
+		GenesisTime:   test.DefaultTestTime,
+		InitialHeight: 1,
+	}
+	for _, val := range vals.Validators {
+		genDoc.Validators = append(genDoc.Validators, types.GenesisValidator{PubKey: val.PubKey, Power: val.VotingPower})
+	}
+	return blocks, genDoc
+}
+
+func TestCompact(t *testing.T) {
+	blocks, _ := genLightBlocks(t, 10)
+
+	// Blocks are an hour apart, so an anchor can verify the next three blocks.
+	b, err := Compact(blocks, 3*time.Hour+30*time.Minute, light.DefaultTrustLevel)
+	require.NoError(t, err)
+	heights := make([]int64, len(b.LightBlocks))
+	for i, lb := range b.LightBlocks {
+		heights[i] = lb.Height
+	}
+	assert.Equal(t, []int64{1, 4, 7, 10}, heights)
+
+	// A trusting period longer than the chain keeps the first and last blocks.
+	b, err = Compact(blocks, 24*time.Hour, light.DefaultTrustLevel)
+	require.NoError(t, err)
+	assert.Len(t, b.LightBlocks, 2)
+
+	// Blocks farther apart than the trusting period cannot be verified.
+	_, err = Compact(blocks, 30*time.Minute, light.DefaultTrustLevel)
+	require.Error(t, err)
+
+	_, err = Compact(nil, time.Hour, light.DefaultTrustLevel)
+	require.ErrorIs(t, err, ErrEmptyBundle)
+}
+
+func TestBundle_ReadWrite(t *testing.T) {
+	blocks, _ := genLightBlocks(t, 3)
+	b := &Bundle{LightBlocks: blocks}
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, b))
+	bz := buf.Bytes()
+
+	read, err := Read(bytes.NewReader(bz))
+	require.NoError(t, err)
+	require.Len(t, read.LightBlocks, len(blocks))
+	for i, lb := range read.LightBlocks {
+		assert.Equal(t, blocks[i].Hash(), lb.Hash())
+	}
+
+	// A truncated bundle is rejected.
+	_, err = Read(bytes.NewReader(bz[:len(bz)-1]))
+	require.Error(t, err)
+
+	// So is a file which is not a bundle.
+	_, err = Read(bytes.NewReader([]byte("not a bundle")))
+	require.Error(t, err)
+
+	// Blocks must be in increasing height.
+	buf.Reset()
+	require.NoError(t, Write(&buf, &Bundle{LightBlocks: []*types.LightBlock{blocks[1], blocks[0]}}))
+	_, err = Read(&buf)
+	require.Error(t, err)
+}
+
+func TestBundle_Verify(t *testing.T) {
+	blocks, genDoc := genLightBlocks(t, 10)
+	trustingPeriod := 3*time.Hour + 30*time.Minute
+	b, err := Compact(blocks, trustingPeriod, light.DefaultTrustLevel)
+	require.NoError(t, err)
+
+	require.NoError(t, b.VerifyFromHash(genDoc.ChainID, blocks[0].Hash(), trustingPeriod, light.DefaultTrustLevel))
+	require.NoError(t, b.VerifyFromGenesis(genDoc, trustingPeriod, light.DefaultTrustLevel))
+
+	// The first block must have the trusted hash.
+	err = b.VerifyFromHash(genDoc.ChainID, blocks[1].Hash(), trustingPeriod, light.DefaultTrustLevel)
+	require.Error(t, err)
+
+	// Anchors must be within the trusting period of each other.
+	err = b.VerifyFromHash(genDoc.ChainID, blocks[0].Hash(), 2*time.Hour, light.DefaultTrustLevel)
+	require.Error(t, err)
+
+	// The first block must be signed by the genesis validators.
+	otherVals, _ := test.ValidatorSet(context.Background(), t, 4, 10)
+	otherGenDoc := *genDoc
+	otherGenDoc.Validators = nil
+	for _, val := range otherVals.Validators {
+		otherGenDoc.Validators = append(otherGenDoc.Validators,
+			types.GenesisValidator{PubKey: val.PubKey, Power: val.VotingPower})
+	}
+	err = b.VerifyFromGenesis(&otherGenDoc, trustingPeriod, light.DefaultTrustLevel)
+	require.Error(t, err)
+
+	// A bundle not starting at the initial height cannot be verified from
+	// genesis.
+	tail := &Bundle{LightBlocks: b.LightBlocks[1:]}
+	err = tail.VerifyFromGenesis(genDoc, trustingPeriod, light.DefaultTrustLevel)
+	require.Error(t, err)
+
+	// A tampered anchor is rejected.
+	tampered := *b.Last().Header
+	tampered.AppHash = test.RandomHash()
+	b.LightBlocks[len(b.LightBlocks)-1] = &types.LightBlock{
+		SignedHeader: &types.SignedHeader{Header: &tampered, Commit: b.Last().Commit},
+		ValidatorSet: b.Last().ValidatorSet,
+	}
+	err = b.VerifyFromHash(genDoc.ChainID, blocks[0].Hash(), trustingPeriod, light.DefaultTrustLevel)
+	require.Error(t, err)
+}
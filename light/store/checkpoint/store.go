@@ -0,0 +1,187 @@
+package checkpoint
+
+import (
+	"errors"
+	"os"
+	"sort"
+
+	cmtsync "github.com/cometbft/cometbft/libs/sync"
+	"github.com/cometbft/cometbft/light/store"
+	"github.com/cometbft/cometbft/types"
+)
+
+// Store is a light client store keeping its light blocks in memory and in a
+// bundle file, rewritten on every change. It is meant for small stores, see
+// light.PruningSize, e.g. on mobile and embedded verifiers, which can start
+// from a verified bundle file instead of a trusted hash.
+type Store struct {
+	mtx     cmtsync.RWMutex
+	path    string
+	heights []int64 // sorted
+	blocks  map[int64]*types.LightBlock
+}
+
+var _ store.Store = (*Store)(nil)
+
+// New returns a store backed by the bundle file at path, loading the light
+// blocks it holds if it exists. The file is trusted: a bundle received from
+// elsewhere must be verified, with Bundle.VerifyFromHash or
+// Bundle.VerifyFromGenesis, before being used.
+func New(path string) (*Store, error) {
+	s := &Store{path: path, blocks: make(map[int64]*types.LightBlock)}
+	b, err := ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, store.ErrStore{Err: err}
+	}
+	for _, lb := range b.LightBlocks {
+		s.heights = append(s.heights, lb.Height)
+		s.blocks[lb.Height] = lb
+	}
+	return s, nil
+}
+
+// CONTRACT: s.mtx must be locked.
+func (s *Store) save() error {
+	b := &Bundle{LightBlocks: make([]*types.LightBlock, len(s.heights))}
+	for i, height := range s.heights {
+		b.LightBlocks[i] = s.blocks[height]
+	}
+	if err := WriteFile(s.path, b); err != nil {
+		return store.ErrStore{Err: err}
+	}
+	return nil
+}
+
+// SaveLightBlock implements store.Store.
+//
+// Safe for concurrent use by multiple goroutines.
+func (s *Store) SaveLightBlock(lb *types.LightBlock) error {
+	if lb.Height <= 0 {
+		panic("negative or zero height")
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if _, ok := s.blocks[lb.Height]; !ok {
+		i := sort.Search(len(s.heights), func(i int) bool { return s.heights[i] > lb.Height })
+		s.heights = append(s.heights, 0)
+		copy(s.heights[i+1:], s.heights[i:])
+		s.heights[i] = lb.Height
+	}
+	s.blocks[lb.Height] = lb
+	return s.save()
+}
+
+// DeleteLightBlock implements store.Store.
+//
+// Safe for concurrent use by multiple goroutines.
+func (s *Store) DeleteLightBlock(height int64) error {
+	if height <= 0 {
+		panic("negative or zero height")
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if _, ok := s.blocks[height]; !ok {
+		return nil
+	}
+	delete(s.blocks, height)
+	i := sort.Search(len(s.heights), func(i int) bool { return s.heights[i] >= height })
+	s.heights = append(s.heights[:i], s.heights[i+1:]...)
+	return s.save()
+}
+
+// LightBlock implements store.Store.
+//
+// Safe for concurrent use by multiple goroutines.
+func (s *Store) LightBlock(height int64) (*types.LightBlock, error) {
+	if height <= 0 {
+		panic("negative or zero height")
+	}
+
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	lb, ok := s.blocks[height]
+	if !ok {
+		return nil, store.ErrLightBlockNotFound
+	}
+	return lb, nil
+}
+
+// LastLightBlockHeight implements store.Store.
+//
+// Safe for concurrent use by multiple goroutines.
+func (s *Store) LastLightBlockHeight() (int64, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	if len(s.heights) == 0 {
+		return -1, nil
+	}
+	return s.heights[len(s.heights)-1], nil
+}
+
+// FirstLightBlockHeight implements store.Store.
+//
+// Safe for concurrent use by multiple goroutines.
+func (s *Store) FirstLightBlockHeight() (int64, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	if len(s.heights) == 0 {
+		return -1, nil
+	}
+	return s.heights[0], nil
+}
+
+// LightBlockBefore implements store.Store.
+//
+// Safe for concurrent use by multiple goroutines.
+func (s *Store) LightBlockBefore(height int64) (*types.LightBlock, error) {
+	if height <= 0 {
+		panic("negative or zero height")
+	}
+
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	i := sort.Search(len(s.heights), func(i int) bool { return s.heights[i] >= height })
+	if i == 0 {
+		return nil, store.ErrLightBlockNotFound
+	}
+	return s.blocks[s.heights[i-1]], nil
+}
+
+// Prune implements store.Store, removing the oldest light blocks.
+//
+// Safe for concurrent use by multiple goroutines.
+func (s *Store) Prune(size uint16) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if len(s.heights) <= int(size) {
+		return nil
+	}
+	numToPrune := len(s.heights) - int(size)
+	for _, height := range s.heights[:numToPrune] {
+		delete(s.blocks, height)
+	}
+	s.heights = append([]int64(nil), s.heights[numToPrune:]...)
+	return s.save()
+}
+
+// Size implements store.Store.
+//
+// Safe for concurrent use by multiple goroutines.
+func (s *Store) Size() uint16 {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	return uint16(len(s.heights))
+}
@@ -0,0 +1,70 @@
+package checkpoint
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cometbft/cometbft/light/store"
+)
+
+func TestStore(t *testing.T) {
+	blocks, _ := genLightBlocks(t, 5)
+	path := filepath.Join(t.TempDir(), "light.checkpoint")
+
+	s, err := New(path)
+	require.NoError(t, err)
+	height, err := s.LastLightBlockHeight()
+	require.NoError(t, err)
+	assert.EqualValues(t, -1, height)
+	height, err = s.FirstLightBlockHeight()
+	require.NoError(t, err)
+	assert.EqualValues(t, -1, height)
+	_, err = s.LightBlock(1)
+	require.ErrorIs(t, err, store.ErrLightBlockNotFound)
+
+	// Save blocks out of order.
+	for _, i := range []int{2, 0, 4, 1, 3} {
+		require.NoError(t, s.SaveLightBlock(blocks[i]))
+	}
+	assert.EqualValues(t, 5, s.Size())
+	height, err = s.FirstLightBlockHeight()
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, height)
+	height, err = s.LastLightBlockHeight()
+	require.NoError(t, err)
+	assert.EqualValues(t, 5, height)
+
+	lb, err := s.LightBlock(3)
+	require.NoError(t, err)
+	assert.Equal(t, blocks[2].Hash(), lb.Hash())
+
+	require.NoError(t, s.DeleteLightBlock(3))
+	_, err = s.LightBlock(3)
+	require.ErrorIs(t, err, store.ErrLightBlockNotFound)
+	lb, err = s.LightBlockBefore(4)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, lb.Height)
+	_, err = s.LightBlockBefore(1)
+	require.ErrorIs(t, err, store.ErrLightBlockNotFound)
+
+	// Pruning removes the oldest blocks.
+	require.NoError(t, s.Prune(2))
+	assert.EqualValues(t, 2, s.Size())
+	height, err = s.FirstLightBlockHeight()
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, height)
+
+	// The blocks are persisted in the bundle file.
+	s, err = New(path)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, s.Size())
+	lb, err = s.LightBlock(5)
+	require.NoError(t, err)
+	assert.Equal(t, blocks[4].Hash(), lb.Hash())
+	b, err := ReadFile(path)
+	require.NoError(t, err)
+	assert.Len(t, b.LightBlocks, 2)
+}
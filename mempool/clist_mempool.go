@@ -38,6 +38,7 @@ type CListMempool struct {
 	notifiedTxsAvailable atomic.Bool
 	txsAvailable         chan struct{} // fires once for each height, when the mempool is not empty
 	onNewTx              func(types.Tx)
+	onInvalidTx          func(tx types.Tx, sender p2p.ID)
 
 	config *config.MempoolConfig
 
@@ -417,6 +418,9 @@ func (mem *CListMempool) handleCheckTxResponse(tx types.Tx, sender p2p.ID) func(
 				"err", postCheckErr,
 			)
 			mem.metrics.FailedTxs.Add(1)
+			if mem.onInvalidTx != nil && sender != noSender {
+				mem.onInvalidTx(tx, sender)
+			}
 
 			if postCheckErr != nil {
 				return postCheckErr
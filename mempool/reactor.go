@@ -43,6 +43,7 @@ func NewReactor(config *cfg.MempoolConfig, mempool *CListMempool, waitSync bool)
 		waitSync: atomic.Bool{},
 	}
 	memR.BaseReactor = *p2p.NewBaseReactor("Mempool", memR)
+	mempool.onInvalidTx = memR.reportInvalidTx
 	if waitSync {
 		memR.waitSync.Store(true)
 		memR.waitSyncCh = make(chan struct{})
@@ -188,6 +189,16 @@ func (memR *Reactor) TryAddTx(tx types.Tx, sender p2p.Peer) (*abcicli.ReqRes, er
 	return reqRes, nil
 }
 
+// reportInvalidTx reports the sender of a transaction which failed CheckTx.
+func (memR *Reactor) reportInvalidTx(_ types.Tx, sender p2p.ID) {
+	if memR.Switch == nil {
+		return
+	}
+	if peer := memR.Switch.Peers().Get(sender); peer != nil {
+		memR.Switch.ReportBehavior(peer, p2p.PeerBehaviorBadTx)
+	}
+}
+
 func (memR *Reactor) EnableInOutTxs() {
 	memR.Logger.Info("Enabling inbound and outbound transactions")
 	if !memR.waitSync.CompareAndSwap(true, false) {
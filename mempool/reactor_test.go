@@ -288,6 +288,55 @@ func TestMempoolReactorMaxTxBytes(t *testing.T) {
 	require.Nil(t, reqRes)
 }
 
+// TestMempoolReactorMaxTxBytesCompressed checks that a tx of the max size,
+// which doesn't compress and is thus sent with the compression header only, is
+// received on a compressed channel.
+func TestMempoolReactorMaxTxBytesCompressed(t *testing.T) {
+	for _, compression := range []string{"zstd", "snappy"} {
+		t.Run(compression, func(t *testing.T) {
+			config := cfg.TestConfig()
+			config.P2P.Compression = []string{compression}
+
+			reactors, switches := makeAndConnectReactors(config, 2, mempoolLogger("info"))
+			defer func() {
+				for _, r := range reactors {
+					if err := r.Stop(); err != nil {
+						require.NoError(t, err)
+					}
+				}
+			}()
+			for _, r := range reactors {
+				for _, peer := range r.Switch.Peers().Copy() {
+					peer.Set(types.PeerStateKey, peerState{1})
+				}
+			}
+
+			// Random bytes, except for the kvstore separators.
+			tx := cmtrand.Bytes(config.Mempool.MaxTxBytes)
+			for i, b := range tx {
+				if b == '=' || b == ':' {
+					tx[i] = 0
+				}
+			}
+			copy(tx, "k=")
+			reqRes, err := reactors[0].TryAddTx(tx, nil)
+			require.NoError(t, err)
+			require.False(t, reqRes.Response.GetCheckTx().IsErr())
+			waitForReactors(t, []types.Tx{tx}, reactors, checkTxsInOrder)
+
+			for _, peer := range switches[0].Peers().Copy() {
+				for _, status := range peer.Status().Channels {
+					if status.ID == MempoolChannel {
+						assert.EqualValues(t, compression, status.Compression)
+						// The tx was sent uncompressed.
+						assert.Greater(t, status.CompressedBytesSent, status.UncompressedBytesSent)
+					}
+				}
+			}
+		})
+	}
+}
+
 func TestBroadcastTxForPeerStopsWhenPeerStops(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping test in short mode.")
@@ -9,12 +9,15 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/cors"
 
+	dbm "github.com/cometbft/cometbft-db"
+
 	_ "net/http/pprof" //nolint: gosec
 
 	abcicli "github.com/cometbft/cometbft/abci/client"
@@ -59,6 +62,7 @@ type Node struct {
 	transport   *p2p.MultiplexTransport
 	sw          *p2p.Switch  // p2p connections
 	addrBook    pex.AddrBook // known peers
+	addrBookDB  dbm.DB       // nil unless the address book is saved to a db
 	nodeInfo    p2p.NodeInfo
 	nodeKey     *p2p.NodeKey // our node privkey
 	isListening bool
@@ -157,6 +161,77 @@ func StateProvider(stateProvider statesync.StateProvider) Option {
 //
 // If the block store is not empty, the function returns an error.
 func BootstrapState(ctx context.Context, config *cfg.Config, dbProvider cfg.DBProvider, genProvider GenesisDocProvider, height uint64, appHash []byte) (err error) {
+	return bootstrapStores(ctx, config, dbProvider, genProvider, "",
+		func(ctx context.Context, stateProvider statesync.StateProvider, logger log.Logger) (sm.State, *types.Commit, error) {
+			state, err := stateProvider.State(ctx, height)
+			if err != nil {
+				return sm.State{}, nil, err
+			}
+			if appHash == nil {
+				logger.Info("warning: cannot verify appHash. Verification will happen when node boots up!")
+			} else if !bytes.Equal(appHash, state.AppHash) {
+				return sm.State{}, nil, ErrMismatchAppHash{Expected: appHash, Actual: state.AppHash}
+			}
+
+			commit, err := stateProvider.Commit(ctx, height)
+			if err != nil {
+				return sm.State{}, nil, err
+			}
+			return state, commit, nil
+		})
+}
+
+// BootstrapStateFromArchive restores the state sync snapshot stored in the
+// archive at archivePath into the application created by clientCreator, then
+// synchronizes the stores with it, like BootstrapState. The snapshot is
+// verified with a light client configured by the [statesync] section of the
+// config or, if lightBlocksPath is not empty, offline with the
+// statesync.LightBlocksFile at lightBlocksPath, against the trusted height and
+// hash of the config. It is expected that the block store and state store are
+// empty at the time the function is called.
+//
+// See statesync.RestoreFromArchive for the format of the archive.
+func BootstrapStateFromArchive(
+	ctx context.Context,
+	config *cfg.Config,
+	dbProvider cfg.DBProvider,
+	genProvider GenesisDocProvider,
+	clientCreator proxy.ClientCreator,
+	archivePath string,
+	lightBlocksPath string,
+) error {
+	return bootstrapStores(ctx, config, dbProvider, genProvider, lightBlocksPath,
+		func(ctx context.Context, stateProvider statesync.StateProvider, logger log.Logger) (sm.State, *types.Commit, error) {
+			proxyApp := proxy.NewAppConns(clientCreator, proxy.NopMetrics())
+			proxyApp.SetLogger(logger.With("module", "proxy"))
+			if err := proxyApp.Start(); err != nil {
+				return sm.State{}, nil, fmt.Errorf("error starting proxy app connections: %w", err)
+			}
+			defer func() {
+				if err := proxyApp.Stop(); err != nil {
+					logger.Error("Failed to stop proxy app connections", "err", err)
+				}
+			}()
+
+			// The exporter is only needed by the snapshots taken by CometBFT.
+			stateExporter, _ := proxy.StateExporter(clientCreator)
+			return statesync.RestoreFromArchive(ctx, *config.StateSync, logger.With("module", "statesync"),
+				proxyApp.Snapshot(), proxyApp.Query(), stateProvider, stateExporter, archivePath)
+		})
+}
+
+// bootstrapStores checks that the stores are empty, runs sync with a light
+// client state provider, or an offline state provider reading lightBlocksPath
+// if not empty, and saves the returned state and commit, marking the height as
+// synced offline.
+func bootstrapStores(
+	ctx context.Context,
+	config *cfg.Config,
+	dbProvider cfg.DBProvider,
+	genProvider GenesisDocProvider,
+	lightBlocksPath string,
+	sync func(ctx context.Context, stateProvider statesync.StateProvider, logger log.Logger) (sm.State, *types.Commit, error),
+) (err error) {
 	logger := log.NewTMLogger(log.NewSyncWriter(os.Stdout))
 	if ctx == nil {
 		ctx = context.Background()
@@ -219,36 +294,27 @@ func BootstrapState(ctx context.Context, config *cfg.Config, dbProvider cfg.DBPr
 		return err
 	}
 
-	stateProvider, err := statesync.NewLightClientStateProviderWithDBKeyVersion(
-		ctx,
-		genState.ChainID, genState.Version, genState.InitialHeight,
-		config.StateSync.RPCServers, light.TrustOptions{
-			Period: config.StateSync.TrustPeriod,
-			Height: config.StateSync.TrustHeight,
-			Hash:   config.StateSync.TrustHashBytes(),
-		}, logger.With("module", "light"),
-		config.Storage.ExperimentalKeyLayout)
-	if err != nil {
-		return ErrLightClientStateProvider{Err: err}
+	trustOptions := light.TrustOptions{
+		Period: config.StateSync.TrustPeriod,
+		Height: config.StateSync.TrustHeight,
+		Hash:   config.StateSync.TrustHashBytes(),
 	}
-
-	state, err = stateProvider.State(ctx, height)
-	if err != nil {
-		return err
+	var stateProvider statesync.StateProvider
+	if lightBlocksPath != "" {
+		stateProvider, err = statesync.NewOfflineStateProvider(
+			genState.ChainID, genState.InitialHeight, trustOptions, lightBlocksPath)
+	} else {
+		stateProvider, err = statesync.NewLightClientStateProviderWithDBKeyVersion(
+			ctx,
+			genState.ChainID, genState.Version, genState.InitialHeight,
+			config.StateSync.RPCServers, trustOptions, logger.With("module", "light"),
+			config.Storage.ExperimentalKeyLayout)
 	}
-	if appHash == nil {
-		logger.Info("warning: cannot verify appHash. Verification will happen when node boots up!")
-	} else if !bytes.Equal(appHash, state.AppHash) {
-		if err := blockStore.Close(); err != nil {
-			logger.Error("failed to close blockstore: %w", err)
-		}
-		if err := stateStore.Close(); err != nil {
-			logger.Error("failed to close statestore: %w", err)
-		}
-		return ErrMismatchAppHash{Expected: appHash, Actual: state.AppHash}
+	if err != nil {
+		return ErrLightClientStateProvider{Err: err}
 	}
 
-	commit, err := stateProvider.Commit(ctx, height)
+	state, commit, err := sync(ctx, stateProvider, logger)
 	if err != nil {
 		return err
 	}
@@ -378,7 +444,15 @@ func NewNodeWithCliParams(ctx context.Context,
 
 	// If an address is provided, listen on the socket for a connection from an
 	// external signing process.
-	if config.PrivValidatorListenAddr != "" {
+	switch {
+	case config.PrivValidatorThresholdKey != "":
+		// FIXME: we should start services inside OnStart
+		privValidator, err = createAndStartThresholdPrivValidatorClient(
+			config.PrivValidatorThresholdKeyFile(), config.PrivValidatorListenAddr, genDoc.ChainID, logger)
+		if err != nil {
+			return nil, ErrPrivValidatorSocketClient{Err: err}
+		}
+	case config.PrivValidatorListenAddr != "":
 		// FIXME: we should start services inside OnStart
 		privValidator, err = createAndStartPrivValidatorSocketClient(config.PrivValidatorListenAddr, genDoc.ChainID, logger)
 		if err != nil {
@@ -446,6 +520,41 @@ func NewNodeWithCliParams(ctx context.Context,
 		return nil, ErrCreatePruner{Err: err}
 	}
 
+	blockExecOpts := []sm.BlockExecutorOption{
+		sm.BlockExecutorWithPruner(pruner),
+		sm.BlockExecutorWithMetrics(smMetrics),
+	}
+	// The progress of state sync is kept in the state sync temp dir if set,
+	// since it holds the downloaded chunks.
+	ssProgressDir := filepath.Join(config.DBDir(), "statesync")
+	if config.StateSync.TempDir != "" {
+		ssProgressDir = filepath.Join(config.StateSync.TempDir, "cometbft-statesync")
+	}
+	ssOpts := []statesync.ReactorOption{statesync.WithProgressDir(ssProgressDir)}
+	// The state exporter is also needed to restore the snapshots taken by
+	// other nodes, so it is passed to the state sync reactor if available.
+	stateExporter, ok := proxy.StateExporter(clientCreator)
+	if ok {
+		ssOpts = append(ssOpts, statesync.WithStateExporter(stateExporter))
+	}
+	if config.StateSync.SnapshotInterval > 0 {
+		if !ok {
+			return nil, errors.New("statesync.snapshot_interval requires an in-process application implementing abci.StateExporter")
+		}
+		snapshotter, err := statesync.NewSnapshotter(
+			filepath.Join(config.DBDir(), "snapshots"),
+			stateExporter,
+			config.StateSync.SnapshotInterval,
+			config.StateSync.SnapshotKeepRecent,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create snapshotter: %w", err)
+		}
+		snapshotter.SetLogger(logger.With("module", "statesync"))
+		blockExecOpts = append(blockExecOpts, sm.BlockExecutorWithSnapshotter(snapshotter))
+		ssOpts = append(ssOpts, statesync.WithSnapshotter(snapshotter))
+	}
+
 	// make block executor for consensus and blocksync reactors to execute blocks
 	blockExec := sm.NewBlockExecutor(
 		stateStore,
@@ -454,8 +563,7 @@ func NewNodeWithCliParams(ctx context.Context,
 		mempool,
 		evidencePool,
 		blockStore,
-		sm.BlockExecutorWithPruner(pruner),
-		sm.BlockExecutorWithMetrics(smMetrics),
+		blockExecOpts...,
 	)
 
 	offlineStateSyncHeight := int64(0)
@@ -490,20 +598,32 @@ func NewNodeWithCliParams(ctx context.Context,
 		proxyApp.Snapshot(),
 		proxyApp.Query(),
 		ssMetrics,
+		ssOpts...,
 	)
 	stateSyncReactor.SetLogger(logger.With("module", "statesync"))
 
+	// Enforce the sentry topology, if any, before the p2p settings are used.
+	applyP2PTopology(config.P2P)
+
 	nodeInfo, err := makeNodeInfo(config, nodeKey, txIndexer, genDoc, state)
 	if err != nil {
 		return nil, err
 	}
 
-	transport, peerFilters := createTransport(config, nodeInfo, nodeKey, proxyApp)
-
 	p2pLogger := logger.With("module", "p2p")
+
+	admissionPolicy, err := createAdmissionPolicy(config.P2P)
+	if err != nil {
+		return nil, err
+	}
+	admission := p2p.NewAdmission(admissionPolicy)
+	admission.SetLogger(p2pLogger)
+
+	transport, peerFilters := createTransport(config, nodeInfo, nodeKey, proxyApp, admission)
+
 	sw := createSwitch(
 		config, transport, p2pMetrics, peerFilters, mempoolReactor, bcReactor,
-		stateSyncReactor, consensusReactor, evidenceReactor, nodeInfo, nodeKey, p2pLogger,
+		stateSyncReactor, consensusReactor, evidenceReactor, nodeInfo, nodeKey, admission, p2pLogger,
 	)
 
 	err = sw.AddPersistentPeers(splitAndTrimEmpty(config.P2P.PersistentPeers, ",", " "))
@@ -516,7 +636,7 @@ func NewNodeWithCliParams(ctx context.Context,
 		return nil, ErrAddUnconditionalPeerIDs{Err: err}
 	}
 
-	addrBook, err := createAddrBookAndSetOnSwitch(config, sw, p2pLogger, nodeKey)
+	addrBook, addrBookDB, err := createAddrBookAndSetOnSwitch(config, dbProvider, sw, p2pLogger, nodeKey)
 	if err != nil {
 		return nil, ErrCreateAddrBook{Err: err}
 	}
@@ -546,11 +666,12 @@ func NewNodeWithCliParams(ctx context.Context,
 		genesisDoc:    genDoc,
 		privValidator: privValidator,
 
-		transport: transport,
-		sw:        sw,
-		addrBook:  addrBook,
-		nodeInfo:  nodeInfo,
-		nodeKey:   nodeKey,
+		transport:  transport,
+		sw:         sw,
+		addrBook:   addrBook,
+		addrBookDB: addrBookDB,
+		nodeInfo:   nodeInfo,
+		nodeKey:    nodeKey,
 
 		stateStore:       stateStore,
 		blockStore:       blockStore,
@@ -617,6 +738,15 @@ func (n *Node) OnStart() error {
 	if err := n.transport.Listen(*addr); err != nil {
 		return err
 	}
+	if quicLAddr := n.config.P2P.QUICListenAddress; quicLAddr != "" {
+		addr, err := p2p.NewNetAddressString("quic://" + p2p.IDAddressString(n.nodeKey.ID(), quicLAddr))
+		if err != nil {
+			return err
+		}
+		if err := n.transport.Listen(*addr); err != nil {
+			return err
+		}
+	}
 
 	n.isListening = true
 
@@ -725,6 +855,12 @@ func (n *Node) OnStop() {
 			n.Logger.Error("problem closing evidencestore", "err", err)
 		}
 	}
+	if n.addrBookDB != nil {
+		n.Logger.Info("Closing addrbook db")
+		if err := n.addrBookDB.Close(); err != nil {
+			n.Logger.Error("problem closing addrbook db", "err", err)
+		}
+	}
 }
 
 // ConfigureRPC makes sure RPC has all the objects it needs to operate.
@@ -871,6 +1007,12 @@ func (n *Node) startRPC() ([]net.Listener, error) {
 		if n.config.GRPC.BlockResultsService.Enabled {
 			opts = append(opts, grpcserver.WithBlockResultsService(n.blockStore, n.stateStore, n.Logger))
 		}
+		if n.config.GRPC.ProposerService.Enabled {
+			opts = append(opts, grpcserver.WithProposerService(n.stateStore, n.Logger))
+		}
+		if n.config.GRPC.LightBlockService.Enabled {
+			opts = append(opts, grpcserver.WithLightBlockService(n.blockStore, n.stateStore, n.evidencePool, n.eventBus, n.Logger))
+		}
 		go func() {
 			if err := grpcserver.Serve(listener, opts...); err != nil {
 				n.Logger.Error("Error starting gRPC server", "err", err)
@@ -944,6 +1086,17 @@ func (n *Node) Switch() *p2p.Switch {
 	return n.sw
 }
 
+// ReloadAdmissionPolicy replaces the admission policy of the node by the one
+// of config, and disconnects the peers it refuses.
+func (n *Node) ReloadAdmissionPolicy(config *cfg.P2PConfig) error {
+	policy, err := createAdmissionPolicy(config)
+	if err != nil {
+		return err
+	}
+	n.sw.SetAdmissionPolicy(policy)
+	return nil
+}
+
 // BlockStore returns the Node's BlockStore.
 func (n *Node) BlockStore() *store.BlockStore {
 	return n.blockStore
@@ -1056,6 +1209,16 @@ func makeNodeInfo(
 		nodeInfo.Channels = append(nodeInfo.Channels, pex.PexChannel)
 	}
 
+	if len(config.P2P.Compression) > 0 {
+		nodeInfo.Other.Compression = config.P2P.Compression
+		// Compress the channels carrying the bulk of the traffic.
+		nodeInfo.Other.CompressedChannels = []byte{
+			bc.BlocksyncChannel,
+			cs.DataChannel,
+			mempl.MempoolChannel,
+		}
+	}
+
 	lAddr := config.P2P.ExternalAddress
 
 	if lAddr == "" {
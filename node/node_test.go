@@ -82,6 +82,36 @@ func TestNodeStartStop(t *testing.T) {
 	}
 }
 
+func TestNodeAddrBookDB(t *testing.T) {
+	config := test.ResetTestRoot("node_addrbook_db_test")
+	defer os.RemoveAll(config.RootDir)
+	config.P2P.AddrBookBackend = cfg.AddrBookBackendDB
+
+	n, err := DefaultNewNode(config, log.TestingLogger(), CliParams{}, nil)
+	require.NoError(t, err)
+	require.NotNil(t, n.addrBookDB)
+	require.NoError(t, n.Start())
+	require.NoError(t, n.Stop())
+}
+
+func TestNodeReloadAdmissionPolicy(t *testing.T) {
+	config := test.ResetTestRoot("node_admission_test")
+	defer os.RemoveAll(config.RootDir)
+
+	n, err := DefaultNewNode(config, log.TestingLogger(), CliParams{}, nil)
+	require.NoError(t, err)
+	require.NoError(t, n.Start())
+	defer n.Stop() //nolint:errcheck // ignore for tests
+
+	p2pConfig := *config.P2P
+	p2pConfig.AdmissionDenyCIDRs = []string{"192.0.2.0/24"}
+	p2pConfig.AdmissionMinBlockVersion = 11
+	require.NoError(t, n.ReloadAdmissionPolicy(&p2pConfig))
+
+	p2pConfig.AdmissionDenyCIDRs = []string{"192.0.2.1"}
+	require.Error(t, n.ReloadAdmissionPolicy(&p2pConfig))
+}
+
 func TestSplitAndTrimEmpty(t *testing.T) {
 	testCases := []struct {
 		s        string
@@ -101,6 +131,39 @@ func TestSplitAndTrimEmpty(t *testing.T) {
 	}
 }
 
+func TestApplyP2PTopology(t *testing.T) {
+	const (
+		validator = "3e0c9c5d1e5a41f1b9b8a1b0a0d0e0f0a0b0c0d0"
+		sentry1   = "1d9e2b3c4a5f60718293a4b5c6d7e8f901234567"
+		sentry2   = "7a6b5c4d3e2f1a0b9c8d7e6f5a4b3c2d1e0f9a8b"
+		other     = "0123456789abcdef0123456789abcdef01234567"
+	)
+
+	config := cfg.TestP2PConfig()
+	config.Topology = cfg.TopologyValidator
+	config.TopologyPeers = sentry1 + "@10.0.0.1:26656," + sentry2 + "@10.0.0.2:26656"
+	config.PersistentPeers = other + "@1.2.3.4:26656"
+	config.Seeds = other + "@1.2.3.4:26656"
+	applyP2PTopology(config)
+	applyP2PTopology(config)
+	assert.Equal(t, config.TopologyPeers, config.PersistentPeers)
+	assert.Equal(t, sentry1+","+sentry2, config.UnconditionalPeerIDs)
+	assert.False(t, config.PexReactor)
+	assert.Empty(t, config.Seeds)
+
+	config = cfg.TestP2PConfig()
+	config.Topology = cfg.TopologySentry
+	config.TopologyPeers = validator + "@10.0.0.3:26656"
+	config.PersistentPeers = other + "@1.2.3.4:26656"
+	config.PrivatePeerIDs = other
+	applyP2PTopology(config)
+	applyP2PTopology(config)
+	assert.Equal(t, other+"@1.2.3.4:26656,"+validator+"@10.0.0.3:26656", config.PersistentPeers)
+	assert.Equal(t, validator, config.UnconditionalPeerIDs)
+	assert.Equal(t, other+","+validator, config.PrivatePeerIDs)
+	assert.True(t, config.PexReactor)
+}
+
 func TestCompanionInitialHeightSetup(t *testing.T) {
 	config := test.ResetTestRoot("companion_initial_height")
 	defer os.RemoveAll(config.RootDir)
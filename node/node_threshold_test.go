@@ -0,0 +1,69 @@
+//go:build bls12381
+
+package node
+
+import (
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cometbft/cometbft/internal/test"
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/privval"
+)
+
+func TestNodeSetThresholdPrivValOfflineSigners(t *testing.T) {
+	config := test.ResetTestRoot("node_threshold_priv_val_test")
+	defer os.RemoveAll(config.RootDir)
+
+	key, _, err := privval.GenThresholdKey(2, 3)
+	require.NoError(t, err)
+	config.BaseConfig.PrivValidatorThresholdKey = "config/threshold_key.json"
+	require.NoError(t, key.Save(config.PrivValidatorThresholdKeyFile()))
+
+	addrs := []string{testFreeAddr(t), testFreeAddr(t), testFreeAddr(t)}
+	laddrs := make([]string, len(addrs))
+	for i, addr := range addrs {
+		laddrs[i] = "tcp://" + addr
+	}
+	config.BaseConfig.PrivValidatorListenAddr = strings.Join(laddrs, ",")
+
+	// None of the share holders is online.
+	start := time.Now()
+	n, err := DefaultNewNode(config, log.TestingLogger(), CliParams{}, nil)
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 10*time.Second)
+
+	tsc, ok := n.PrivValidator().(*privval.ThresholdSignerClient)
+	require.True(t, ok)
+	pubKey, err := tsc.GetPubKey()
+	require.NoError(t, err)
+	assert.Equal(t, key.PubKey.Bytes(), pubKey.Bytes())
+
+	// Stopping the client closes the listeners of the share holders.
+	require.NoError(t, tsc.Stop())
+	for _, addr := range addrs {
+		ln, err := net.Listen("tcp", addr)
+		require.NoError(t, err)
+		ln.Close()
+	}
+}
+
+func TestNodeSetThresholdPrivValWrongNumberOfAddrs(t *testing.T) {
+	config := test.ResetTestRoot("node_threshold_priv_val_test")
+	defer os.RemoveAll(config.RootDir)
+
+	key, _, err := privval.GenThresholdKey(2, 3)
+	require.NoError(t, err)
+	config.BaseConfig.PrivValidatorThresholdKey = "config/threshold_key.json"
+	require.NoError(t, key.Save(config.PrivValidatorThresholdKeyFile()))
+	config.BaseConfig.PrivValidatorListenAddr = "tcp://" + testFreeAddr(t)
+
+	_, err = DefaultNewNode(config, log.TestingLogger(), CliParams{}, nil)
+	require.ErrorAs(t, err, &ErrPrivValidatorSocketClient{})
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -24,6 +25,9 @@ import (
 	"github.com/cometbft/cometbft/internal/evidence"
 	"github.com/cometbft/cometbft/libs/log"
 	"github.com/cometbft/cometbft/light"
+	lightprovider "github.com/cometbft/cometbft/light/provider"
+	lighthttp "github.com/cometbft/cometbft/light/provider/http"
+	lightdb "github.com/cometbft/cometbft/light/store/db"
 	mempl "github.com/cometbft/cometbft/mempool"
 	"github.com/cometbft/cometbft/p2p"
 	"github.com/cometbft/cometbft/p2p/pex"
@@ -358,7 +362,17 @@ func createBlocksyncReactor(config *cfg.Config,
 ) (bcReactor p2p.Reactor, err error) {
 	switch config.BlockSync.Version {
 	case "v0":
-		bcReactor = blocksync.NewReactor(state.Copy(), blockExec, blockStore, blockSync, localAddr, metrics, offlineStateSyncHeight)
+		options := []blocksync.ReactorOption{
+			blocksync.WithPeerScoresFile(filepath.Join(config.DBDir(), "blocksync_peer_scores.json")),
+		}
+		if config.BlockSync.VerifyWithLightClient {
+			newLightClient, err := createBlocksyncLightClient(config, state.ChainID, logger.With("module", "light"))
+			if err != nil {
+				return nil, fmt.Errorf("failed to set up block sync light client: %w", err)
+			}
+			options = append(options, blocksync.WithLightClientFunc(newLightClient))
+		}
+		bcReactor = blocksync.NewReactor(state.Copy(), blockExec, blockStore, blockSync, localAddr, metrics, offlineStateSyncHeight, options...)
 	case "v1", "v2":
 		return nil, fmt.Errorf("block sync version %s has been deprecated. Please use v0", config.BlockSync.Version)
 	default:
@@ -369,6 +383,40 @@ func createBlocksyncReactor(config *cfg.Config,
 	return bcReactor, nil
 }
 
+// createBlocksyncLightClient returns the function creating the light client
+// used to verify the blocks synced by the block sync reactor. The client is
+// created once block sync starts, as creating it requests the RPC servers.
+func createBlocksyncLightClient(
+	config *cfg.Config,
+	chainID string,
+	logger log.Logger,
+) (func(ctx context.Context) (blocksync.LightClient, error), error) {
+	providers := make([]lightprovider.Provider, 0, len(config.BlockSync.RPCServers))
+	for _, server := range config.BlockSync.RPCServers {
+		provider, err := lighthttp.New(chainID, server)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set up RPC client: %w", err)
+		}
+		providers = append(providers, provider)
+	}
+
+	return func(ctx context.Context) (blocksync.LightClient, error) {
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		lc, err := light.NewClient(ctx, chainID, light.TrustOptions{
+			Period: config.BlockSync.TrustPeriod,
+			Height: config.BlockSync.TrustHeight,
+			Hash:   config.BlockSync.TrustHashBytes(),
+		}, providers[0], providers[1:],
+			lightdb.NewWithDBVersion(dbm.NewMemDB(), "", config.Storage.ExperimentalKeyLayout),
+			light.Logger(logger), light.MaxRetryAttempts(5))
+		if err != nil {
+			return nil, err
+		}
+		return lc, nil
+	}, nil
+}
+
 func createConsensusReactor(config *cfg.Config,
 	state sm.State,
 	blockExec *sm.BlockExecutor,
@@ -409,6 +457,7 @@ func createTransport(
 	nodeInfo p2p.NodeInfo,
 	nodeKey *p2p.NodeKey,
 	proxyApp proxy.AppConns,
+	admission *p2p.Admission,
 ) (
 	*p2p.MultiplexTransport,
 	[]p2p.PeerFilterFunc,
@@ -464,7 +513,20 @@ func createTransport(
 		)
 	}
 
+	// A validator in a sentry topology refuses any peer but its sentries.
+	if config.P2P.Topology == cfg.TopologyValidator {
+		sentries := []p2p.ID{}
+		for _, id := range config.P2P.TopologyPeerIDs() {
+			sentries = append(sentries, p2p.ID(id))
+		}
+		peerFilters = append(peerFilters, p2p.PeerIDFilter(sentries...))
+	}
+
 	p2p.MultiplexTransportConnFilters(connFilters...)(transport)
+	p2p.MultiplexTransportAdmission(admission)(transport)
+	if config.P2P.NoiseHandshake {
+		p2p.MultiplexTransportNoiseHandshake()(transport)
+	}
 
 	// Limit the number of incoming connections.
 	max := config.P2P.MaxNumInboundPeers + len(splitAndTrimEmpty(config.P2P.UnconditionalPeerIDs, ",", " "))
@@ -473,6 +535,29 @@ func createTransport(
 	return transport, peerFilters
 }
 
+// createAdmissionPolicy returns the admission policy of the p2p config.
+func createAdmissionPolicy(config *cfg.P2PConfig) (*p2p.AdmissionPolicy, error) {
+	allowCIDRs, denyCIDRs, err := config.AdmissionCIDRs()
+	if err != nil {
+		return nil, err
+	}
+	channels, err := config.AdmissionChannels()
+	if err != nil {
+		return nil, err
+	}
+	return &p2p.AdmissionPolicy{
+		AllowCIDRs:          allowCIDRs,
+		DenyCIDRs:           denyCIDRs,
+		MinBlockVersion:     config.AdmissionMinBlockVersion,
+		MinAppVersion:       config.AdmissionMinAppVersion,
+		RequiredChannels:    channels,
+		DenyMonikers:        config.AdmissionDenyMonikers,
+		MaxConnsPerSubnet:   config.AdmissionMaxConnsPerSubnet,
+		SubnetPrefixLenIPv4: config.AdmissionSubnetPrefixLenIPv4,
+		SubnetPrefixLenIPv6: config.AdmissionSubnetPrefixLenIPv6,
+	}, nil
+}
+
 func createSwitch(config *cfg.Config,
 	transport p2p.Transport,
 	p2pMetrics *p2p.Metrics,
@@ -484,6 +569,7 @@ func createSwitch(config *cfg.Config,
 	evidenceReactor *evidence.Reactor,
 	nodeInfo p2p.NodeInfo,
 	nodeKey *p2p.NodeKey,
+	admission *p2p.Admission,
 	p2pLogger log.Logger,
 ) *p2p.Switch {
 	sw := p2p.NewSwitch(
@@ -491,6 +577,7 @@ func createSwitch(config *cfg.Config,
 		transport,
 		p2p.WithMetrics(p2pMetrics),
 		p2p.SwitchPeerFilters(peerFilters...),
+		p2p.SwitchAdmission(admission),
 	)
 	sw.SetLogger(p2pLogger)
 	if config.Mempool.Type != cfg.MempoolTypeNop {
@@ -508,31 +595,45 @@ func createSwitch(config *cfg.Config,
 	return sw
 }
 
-func createAddrBookAndSetOnSwitch(config *cfg.Config, sw *p2p.Switch,
+func createAddrBookAndSetOnSwitch(config *cfg.Config, dbProvider cfg.DBProvider, sw *p2p.Switch,
 	p2pLogger log.Logger, nodeKey *p2p.NodeKey,
-) (pex.AddrBook, error) {
-	addrBook := pex.NewAddrBook(config.P2P.AddrBookFile(), config.P2P.AddrBookStrict)
-	addrBook.SetLogger(p2pLogger.With("book", config.P2P.AddrBookFile()))
+) (pex.AddrBook, dbm.DB, error) {
+	var (
+		addrBook   pex.AddrBook
+		addrBookDB dbm.DB
+	)
+	if config.P2P.AddrBookBackend == cfg.AddrBookBackendDB {
+		var err error
+		addrBookDB, err = dbProvider(&cfg.DBContext{ID: "addrbook", Config: config})
+		if err != nil {
+			return nil, nil, err
+		}
+		addrBook = pex.NewDBAddrBook(addrBookDB, config.P2P.AddrBookFile(), config.P2P.AddrBookStrict)
+		addrBook.SetLogger(p2pLogger.With("book", "addrbook.db"))
+	} else {
+		addrBook = pex.NewAddrBook(config.P2P.AddrBookFile(), config.P2P.AddrBookStrict)
+		addrBook.SetLogger(p2pLogger.With("book", config.P2P.AddrBookFile()))
+	}
 
 	// Add ourselves to addrbook to prevent dialing ourselves
 	if config.P2P.ExternalAddress != "" {
 		addr, err := p2p.NewNetAddressString(p2p.IDAddressString(nodeKey.ID(), config.P2P.ExternalAddress))
 		if err != nil {
-			return nil, fmt.Errorf("p2p.external_address is incorrect: %w", err)
+			return nil, nil, fmt.Errorf("p2p.external_address is incorrect: %w", err)
 		}
 		addrBook.AddOurAddress(addr)
 	}
 	if config.P2P.ListenAddress != "" {
 		addr, err := p2p.NewNetAddressString(p2p.IDAddressString(nodeKey.ID(), config.P2P.ListenAddress))
 		if err != nil {
-			return nil, fmt.Errorf("p2p.laddr is incorrect: %w", err)
+			return nil, nil, fmt.Errorf("p2p.laddr is incorrect: %w", err)
 		}
 		addrBook.AddOurAddress(addr)
 	}
 
 	sw.SetAddrBook(addrBook)
 
-	return addrBook, nil
+	return addrBook, addrBookDB, nil
 }
 
 func createPEXReactorAndAddToSwitch(addrBook pex.AddrBook, config *cfg.Config,
@@ -728,6 +829,84 @@ func createAndStartPrivValidatorSocketClient(
 	return pvscWithRetries, nil
 }
 
+// createAndStartThresholdPrivValidatorClient listens for connections from
+// the key share holders of a threshold key and returns a coordinator
+// combining their signatures. listenAddrs is a comma-separated list of
+// addresses, the i-th of which is used by the holder of the i-th share in the
+// key file. Share holders may connect at any time: the node starts as long as
+// the listeners can be opened.
+func createAndStartThresholdPrivValidatorClient(
+	keyFile,
+	listenAddrs,
+	chainID string,
+	logger log.Logger,
+) (types.PrivValidator, error) {
+	key, err := privval.LoadThresholdKey(keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := splitAndTrimEmpty(listenAddrs, ",", " ")
+	if len(addrs) != len(key.Shares) {
+		return nil, fmt.Errorf("got %d listen addresses for %d key shares", len(addrs), len(key.Shares))
+	}
+	signers := make(map[uint32]types.PrivValidator, len(addrs))
+	for i, addr := range addrs {
+		pve, err := privval.NewSignerListener(addr, logger.With("share", key.Shares[i].Index))
+		if err != nil {
+			return nil, fmt.Errorf("failed to start private validator: %w", err)
+		}
+		pvsc, err := privval.NewSignerClient(pve, chainID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to start private validator: %w", err)
+		}
+		signers[key.Shares[i].Index] = pvsc
+	}
+
+	tsc, err := privval.NewThresholdSignerClient(key, signers)
+	if err != nil {
+		return nil, err
+	}
+	tsc.SetLogger(logger.With("module", "privval"))
+	if err := tsc.Start(); err != nil {
+		return nil, err
+	}
+	return tsc, nil
+}
+
+// applyP2PTopology enforces the sentry topology of config, if any. A validator
+// only connects to its sentries, without PEX nor seeds, while a sentry keeps
+// unconditional connections to the validators it shields and never gossips
+// their addresses. Applying it more than once has no further effect.
+func applyP2PTopology(config *cfg.P2PConfig) {
+	ids := strings.Join(config.TopologyPeerIDs(), ",")
+	switch config.Topology {
+	case cfg.TopologyValidator:
+		config.PersistentPeers = config.TopologyPeers
+		config.UnconditionalPeerIDs = ids
+		config.PexReactor = false
+		config.Seeds = ""
+	case cfg.TopologySentry:
+		config.PersistentPeers = joinUnique(config.PersistentPeers, config.TopologyPeers)
+		config.UnconditionalPeerIDs = joinUnique(config.UnconditionalPeerIDs, ids)
+		config.PrivatePeerIDs = joinUnique(config.PrivatePeerIDs, ids)
+	}
+}
+
+// joinUnique joins the comma separated lists a and b, dropping duplicates.
+func joinUnique(a, b string) string {
+	elems := append(splitAndTrimEmpty(a, ",", " "), splitAndTrimEmpty(b, ",", " ")...)
+	unique := make([]string, 0, len(elems))
+	seen := make(map[string]struct{}, len(elems))
+	for _, elem := range elems {
+		if _, ok := seen[elem]; !ok {
+			seen[elem] = struct{}{}
+			unique = append(unique, elem)
+		}
+	}
+	return strings.Join(unique, ",")
+}
+
 // splitAndTrimEmpty slices s into all subslices separated by sep and returns a
 // slice of the string s with all leading and trailing Unicode code points
 // contained in cutset removed. If sep is empty, SplitAndTrim splits after each
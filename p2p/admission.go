@@ -0,0 +1,169 @@
+package p2p
+
+import (
+	"fmt"
+	"net"
+	"sync/atomic"
+
+	"github.com/cometbft/cometbft/libs/log"
+)
+
+// AdmissionPolicy is a declarative policy deciding which peers the node
+// accepts connections from and connects to. The zero value admits any peer.
+type AdmissionPolicy struct {
+	// AllowCIDRs, if not empty, are the only IP ranges of the peers.
+	AllowCIDRs []*net.IPNet
+	// DenyCIDRs are IP ranges of refused peers. They take precedence over
+	// AllowCIDRs.
+	DenyCIDRs []*net.IPNet
+
+	// MinBlockVersion and MinAppVersion are the minimum protocol versions of
+	// the peers.
+	MinBlockVersion uint64
+	MinAppVersion   uint64
+	// RequiredChannels are the channels the peers must support.
+	RequiredChannels []byte
+	// DenyMonikers are the monikers of refused peers. As the peers choose
+	// their moniker, it only keeps out the peers honestly identifying
+	// themselves.
+	DenyMonikers []string
+
+	// MaxConnsPerSubnet is the maximum number of connections with the IPs of
+	// a subnet, or 0 for no limit. The subnets are the IPv4 and IPv6 prefixes
+	// of SubnetPrefixLenIPv4 and SubnetPrefixLenIPv6 bits.
+	MaxConnsPerSubnet   int
+	SubnetPrefixLenIPv4 int
+	SubnetPrefixLenIPv6 int
+}
+
+// checkIP returns an error if ip is not in an allowed range, or in a denied
+// one.
+func (p *AdmissionPolicy) checkIP(ip net.IP) error {
+	for _, cidr := range p.DenyCIDRs {
+		if cidr.Contains(ip) {
+			return fmt.Errorf("ip %v is in denied range %v", ip, cidr)
+		}
+	}
+	if len(p.AllowCIDRs) == 0 {
+		return nil
+	}
+	for _, cidr := range p.AllowCIDRs {
+		if cidr.Contains(ip) {
+			return nil
+		}
+	}
+	return fmt.Errorf("ip %v is not in an allowed range", ip)
+}
+
+// subnet returns the subnet of ip MaxConnsPerSubnet applies to.
+func (p *AdmissionPolicy) subnet(ip net.IP) *net.IPNet {
+	if ip4 := ip.To4(); ip4 != nil {
+		mask := net.CIDRMask(p.SubnetPrefixLenIPv4, 8*net.IPv4len)
+		return &net.IPNet{IP: ip4.Mask(mask), Mask: mask}
+	}
+	mask := net.CIDRMask(p.SubnetPrefixLenIPv6, 8*net.IPv6len)
+	return &net.IPNet{IP: ip.Mask(mask), Mask: mask}
+}
+
+// checkConn checks the ips of a new connection, given the existing ones.
+func (p *AdmissionPolicy) checkConn(conns ConnSet, ips []net.IP) error {
+	for _, ip := range ips {
+		if err := p.checkIP(ip); err != nil {
+			return err
+		}
+		if p.MaxConnsPerSubnet > 0 {
+			subnet := p.subnet(ip)
+			if n := conns.NumIPsIn(subnet); n >= p.MaxConnsPerSubnet {
+				return fmt.Errorf("already %d connections with subnet %v", n, subnet)
+			}
+		}
+	}
+	return nil
+}
+
+// checkPeer checks the IP and the NodeInfo of a peer.
+func (p *AdmissionPolicy) checkPeer(peer Peer) error {
+	if len(p.AllowCIDRs) > 0 || len(p.DenyCIDRs) > 0 {
+		if err := p.checkIP(peer.RemoteIP()); err != nil {
+			return err
+		}
+	}
+
+	if p.MinBlockVersion == 0 && p.MinAppVersion == 0 &&
+		len(p.RequiredChannels) == 0 && len(p.DenyMonikers) == 0 {
+		return nil
+	}
+	info, ok := peer.NodeInfo().(DefaultNodeInfo)
+	if !ok {
+		return fmt.Errorf("unsupported node info type %T", peer.NodeInfo())
+	}
+	if info.ProtocolVersion.Block < p.MinBlockVersion {
+		return fmt.Errorf("block version %d is lower than %d", info.ProtocolVersion.Block, p.MinBlockVersion)
+	}
+	if info.ProtocolVersion.App < p.MinAppVersion {
+		return fmt.Errorf("app version %d is lower than %d", info.ProtocolVersion.App, p.MinAppVersion)
+	}
+	for _, chID := range p.RequiredChannels {
+		if !info.HasChannel(chID) {
+			return fmt.Errorf("channel %#x is not supported", chID)
+		}
+	}
+	for _, moniker := range p.DenyMonikers {
+		if info.Moniker == moniker {
+			return fmt.Errorf("moniker %q is denied", moniker)
+		}
+	}
+	return nil
+}
+
+// Admission enforces an AdmissionPolicy on the connections of a
+// MultiplexTransport and the peers of a Switch. The policy can be replaced at
+// runtime with SetPolicy, or Switch.SetAdmissionPolicy to also disconnect the
+// peers it refuses.
+type Admission struct {
+	policy atomic.Pointer[AdmissionPolicy]
+	logger log.Logger
+}
+
+// NewAdmission returns an Admission enforcing policy.
+func NewAdmission(policy *AdmissionPolicy) *Admission {
+	a := &Admission{logger: log.NewNopLogger()}
+	a.SetPolicy(policy)
+	return a
+}
+
+// SetLogger sets the logger the decisions are logged to.
+func (a *Admission) SetLogger(logger log.Logger) {
+	a.logger = logger
+}
+
+// Policy returns the enforced policy.
+func (a *Admission) Policy() *AdmissionPolicy {
+	return a.policy.Load()
+}
+
+// SetPolicy replaces the enforced policy. A nil policy admits any peer.
+func (a *Admission) SetPolicy(policy *AdmissionPolicy) {
+	if policy == nil {
+		policy = &AdmissionPolicy{}
+	}
+	a.policy.Store(policy)
+}
+
+func (a *Admission) filterConn(conns ConnSet, c net.Conn, ips []net.IP) error {
+	if err := a.Policy().checkConn(conns, ips); err != nil {
+		a.logger.Info("Connection refused by admission policy", "addr", c.RemoteAddr(), "err", err)
+		return err
+	}
+	a.logger.Debug("Connection admitted", "addr", c.RemoteAddr())
+	return nil
+}
+
+func (a *Admission) filterPeer(p Peer) error {
+	if err := a.Policy().checkPeer(p); err != nil {
+		a.logger.Info("Peer refused by admission policy", "peer", p.ID(), "addr", p.RemoteAddr(), "err", err)
+		return err
+	}
+	a.logger.Debug("Peer admitted", "peer", p.ID())
+	return nil
+}
@@ -0,0 +1,152 @@
+package p2p
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cometbft/cometbft/config"
+)
+
+// nodeInfoPeer is a mockPeer with a NodeInfo.
+type nodeInfoPeer struct {
+	*mockPeer
+	info DefaultNodeInfo
+}
+
+func (p nodeInfoPeer) NodeInfo() NodeInfo { return p.info }
+
+// addrConn is a testTransportConn with a remote address.
+type addrConn struct {
+	*testTransportConn
+	addr net.Addr
+}
+
+func (c addrConn) RemoteAddr() net.Addr { return c.addr }
+
+func mustParseCIDRs(t *testing.T, cidrs ...string) []*net.IPNet {
+	t.Helper()
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		require.NoError(t, err)
+		nets = append(nets, ipNet)
+	}
+	return nets
+}
+
+func TestAdmissionPolicyCIDRs(t *testing.T) {
+	policy := &AdmissionPolicy{
+		AllowCIDRs: mustParseCIDRs(t, "10.0.0.0/8", "2001:db8::/32"),
+		DenyCIDRs:  mustParseCIDRs(t, "10.1.0.0/16"),
+	}
+
+	testCases := []struct {
+		ip      net.IP
+		allowed bool
+	}{
+		{net.ParseIP("10.0.0.1"), true},
+		{net.ParseIP("2001:db8::1"), true},
+		{net.ParseIP("10.1.0.1"), false},
+		{net.ParseIP("192.168.0.1"), false},
+		{net.ParseIP("2001:db9::1"), false},
+	}
+	for _, tc := range testCases {
+		err := policy.checkIP(tc.ip)
+		if tc.allowed {
+			assert.NoError(t, err, tc.ip)
+		} else {
+			assert.Error(t, err, tc.ip)
+		}
+		err = policy.checkPeer(newMockPeer(tc.ip))
+		assert.Equal(t, tc.allowed, err == nil, tc.ip)
+	}
+
+	// The zero policy admits any peer.
+	require.NoError(t, (&AdmissionPolicy{}).checkIP(net.ParseIP("192.168.0.1")))
+}
+
+func TestAdmissionPolicyMaxConnsPerSubnet(t *testing.T) {
+	policy := &AdmissionPolicy{
+		MaxConnsPerSubnet:   2,
+		SubnetPrefixLenIPv4: 24,
+		SubnetPrefixLenIPv6: 48,
+	}
+	conns := NewConnSet()
+	addConn := func(ip string) {
+		addr := &net.TCPAddr{IP: net.ParseIP(ip), Port: 26656}
+		conns.Set(addrConn{addr: addr}, []net.IP{addr.IP})
+	}
+
+	addConn("1.2.3.1")
+	require.NoError(t, policy.checkConn(conns, []net.IP{net.ParseIP("1.2.3.2")}))
+	addConn("1.2.3.2")
+	require.Error(t, policy.checkConn(conns, []net.IP{net.ParseIP("1.2.3.3")}))
+	require.NoError(t, policy.checkConn(conns, []net.IP{net.ParseIP("1.2.4.1")}))
+
+	addConn("2001:db8:1:1::1")
+	addConn("2001:db8:1:2::1")
+	require.Error(t, policy.checkConn(conns, []net.IP{net.ParseIP("2001:db8:1:3::1")}))
+	require.NoError(t, policy.checkConn(conns, []net.IP{net.ParseIP("2001:db8:2::1")}))
+}
+
+func TestAdmissionPolicyNodeInfo(t *testing.T) {
+	policy := &AdmissionPolicy{
+		MinBlockVersion:  11,
+		MinAppVersion:    2,
+		RequiredChannels: []byte{0x20, 0x30},
+		DenyMonikers:     []string{"crawler"},
+	}
+	newPeer := func(modify func(*DefaultNodeInfo)) Peer {
+		info := DefaultNodeInfo{
+			ProtocolVersion: NewProtocolVersion(8, 11, 2),
+			Channels:        []byte{0x20, 0x21, 0x30},
+			Moniker:         "node",
+		}
+		modify(&info)
+		return nodeInfoPeer{mockPeer: newMockPeer(nil), info: info}
+	}
+
+	require.NoError(t, policy.checkPeer(newPeer(func(*DefaultNodeInfo) {})))
+	require.Error(t, policy.checkPeer(newPeer(func(info *DefaultNodeInfo) {
+		info.ProtocolVersion.Block = 10
+	})))
+	require.Error(t, policy.checkPeer(newPeer(func(info *DefaultNodeInfo) {
+		info.ProtocolVersion.App = 1
+	})))
+	require.Error(t, policy.checkPeer(newPeer(func(info *DefaultNodeInfo) {
+		info.Channels = []byte{0x20, 0x21}
+	})))
+	require.Error(t, policy.checkPeer(newPeer(func(info *DefaultNodeInfo) {
+		info.Moniker = "crawler"
+	})))
+}
+
+func TestSwitchSetAdmissionPolicy(t *testing.T) {
+	cfg := config.DefaultP2PConfig()
+	cfg.AllowDuplicateIP = true
+	admission := NewAdmission(nil)
+	switches := MakeSwitches(cfg, 2, initSwitchFunc)
+	switches[0] = MakeSwitch(cfg, 0, initSwitchFunc, SwitchAdmission(admission))
+	StartAndConnectSwitches(switches, Connect2Switches)
+	t.Cleanup(func() {
+		for _, sw := range switches {
+			if err := sw.Stop(); err != nil {
+				t.Error(err)
+			}
+		}
+	})
+	sw := switches[0]
+	require.Equal(t, 1, sw.Peers().Size())
+
+	// A policy admitting the peer keeps it.
+	sw.SetAdmissionPolicy(&AdmissionPolicy{DenyMonikers: []string{"node2"}})
+	require.Equal(t, 1, sw.Peers().Size())
+
+	sw.SetAdmissionPolicy(&AdmissionPolicy{DenyMonikers: []string{"node1"}})
+	assert.Eventually(t, func() bool { return sw.Peers().Size() == 0 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"node1"}, admission.Policy().DenyMonikers)
+}
@@ -0,0 +1,155 @@
+package conn
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression is an algorithm compressing the messages sent on a channel.
+type Compression string
+
+const (
+	CompressionNone   Compression = ""
+	CompressionSnappy Compression = "snappy"
+	CompressionZstd   Compression = "zstd"
+)
+
+// Compression algorithms supported by the connections, in order of
+// preference.
+var SupportedCompressions = []Compression{CompressionZstd, CompressionSnappy}
+
+// minCompressSize is the size below which messages are sent uncompressed, as
+// compressing them wouldn't save much.
+const minCompressSize = 256
+
+// CompressionHeaderSize is the size of the header starting each message on a
+// channel with compression.
+const CompressionHeaderSize = 1
+
+// On a channel with compression, each message starts with a byte telling how
+// the rest of the message is compressed.
+const (
+	compressionHeaderNone byte = iota
+	compressionHeaderSnappy
+	compressionHeaderZstd
+)
+
+// ValidateCompression returns an error if c is not a supported compression
+// algorithm.
+func ValidateCompression(c Compression) error {
+	for _, supported := range SupportedCompressions {
+		if c == supported {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported compression %q", c)
+}
+
+var (
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+)
+
+// zstdCodec returns the zstd encoder and decoder shared by the connections.
+// Both are safe for concurrent use with EncodeAll and DecodeAll.
+func zstdCodec() (*zstd.Encoder, *zstd.Decoder) {
+	zstdOnce.Do(func() {
+		var err error
+		zstdEncoder, err = zstd.NewWriter(nil,
+			zstd.WithEncoderLevel(zstd.SpeedFastest),
+			zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			panic(err)
+		}
+		zstdDecoder, err = zstd.NewReader(nil,
+			zstd.WithDecoderConcurrency(0),
+			zstd.WithDecodeAllCapLimit(true))
+		if err != nil {
+			panic(err)
+		}
+	})
+	return zstdEncoder, zstdDecoder
+}
+
+// compressMessage compresses msgBytes with c, prefixed with the compression
+// header. Messages which are small or don't compress well are sent
+// uncompressed.
+func compressMessage(c Compression, msgBytes []byte) []byte {
+	if len(msgBytes) >= minCompressSize {
+		var compressed []byte
+		switch c {
+		case CompressionSnappy:
+			buf := make([]byte, 1+snappy.MaxEncodedLen(len(msgBytes)))
+			buf[0] = compressionHeaderSnappy
+			compressed = buf[:1+len(snappy.Encode(buf[1:], msgBytes))]
+		case CompressionZstd:
+			encoder, _ := zstdCodec()
+			compressed = encoder.EncodeAll(msgBytes, []byte{compressionHeaderZstd})
+		}
+		if compressed != nil && len(compressed) < len(msgBytes) {
+			return compressed
+		}
+	}
+	return append([]byte{compressionHeaderNone}, msgBytes...)
+}
+
+// decompressMessage decompresses a message compressed by compressMessage. To
+// guard against decompression bombs, the size of the decompressed message is
+// checked against maxSize before decompressing it.
+func decompressMessage(msgBytes []byte, maxSize int) ([]byte, error) {
+	if len(msgBytes) == 0 {
+		return nil, ErrDecompress{Source: errors.New("missing compression header")}
+	}
+	header, payload := msgBytes[0], msgBytes[1:]
+	switch header {
+	case compressionHeaderNone:
+		return payload, nil
+
+	case compressionHeaderSnappy:
+		size, err := snappy.DecodedLen(payload)
+		if err != nil {
+			return nil, ErrDecompress{Source: err}
+		}
+		if size > maxSize {
+			return nil, ErrPacketTooBig{Max: maxSize, Received: size}
+		}
+		decompressed, err := snappy.Decode(make([]byte, size), payload)
+		if err != nil {
+			return nil, ErrDecompress{Source: err}
+		}
+		return decompressed, nil
+
+	case compressionHeaderZstd:
+		var frame zstd.Header
+		if err := frame.Decode(payload); err != nil {
+			return nil, ErrDecompress{Source: err}
+		}
+		if !frame.HasFCS {
+			return nil, ErrDecompress{Source: errors.New("missing zstd frame content size")}
+		}
+		if frame.FrameContentSize > uint64(maxSize) {
+			size := math.MaxInt
+			if frame.FrameContentSize < math.MaxInt {
+				size = int(frame.FrameContentSize)
+			}
+			return nil, ErrPacketTooBig{Max: maxSize, Received: size}
+		}
+		// The decoder is limited to the capacity of the buffer, so the
+		// message can't be bigger than the frame content size.
+		_, decoder := zstdCodec()
+		decompressed, err := decoder.DecodeAll(payload, make([]byte, 0, frame.FrameContentSize))
+		if err != nil {
+			return nil, ErrDecompress{Source: err}
+		}
+		return decompressed, nil
+
+	default:
+		return nil, ErrDecompress{Source: fmt.Errorf("unknown compression header %X", header)}
+	}
+}
@@ -0,0 +1,74 @@
+package conn
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressMessage(t *testing.T) {
+	compressible := bytes.Repeat([]byte("block part "), 1000)
+	small := []byte("vote")
+
+	for _, compression := range SupportedCompressions {
+		t.Run(string(compression), func(t *testing.T) {
+			compressed := compressMessage(compression, compressible)
+			assert.Less(t, len(compressed), len(compressible))
+			msgBytes, err := decompressMessage(compressed, len(compressible))
+			require.NoError(t, err)
+			assert.Equal(t, compressible, msgBytes)
+
+			// Small messages are sent uncompressed.
+			compressed = compressMessage(compression, small)
+			assert.Equal(t, append([]byte{compressionHeaderNone}, small...), compressed)
+			msgBytes, err = decompressMessage(compressed, len(small))
+			require.NoError(t, err)
+			assert.Equal(t, small, msgBytes)
+		})
+	}
+}
+
+func TestDecompressMessageTooBig(t *testing.T) {
+	bomb := make([]byte, 1<<20)
+
+	for _, compression := range SupportedCompressions {
+		t.Run(string(compression), func(t *testing.T) {
+			compressed := compressMessage(compression, bomb)
+			_, err := decompressMessage(compressed, len(bomb)-1)
+			var errTooBig ErrPacketTooBig
+			require.ErrorAs(t, err, &errTooBig)
+			assert.Equal(t, len(bomb)-1, errTooBig.Max)
+			assert.Equal(t, len(bomb), errTooBig.Received)
+		})
+	}
+
+	// A snappy message lying about its decompressed size.
+	compressed := snappy.Encode(nil, bomb)
+	_, n := binary.Uvarint(compressed)
+	lying := binary.AppendUvarint([]byte{compressionHeaderSnappy}, 16)
+	lying = append(lying, compressed[n:]...)
+	_, err := decompressMessage(lying, 1024)
+	require.ErrorAs(t, err, &ErrDecompress{})
+}
+
+func TestDecompressMessageInvalid(t *testing.T) {
+	testCases := []struct {
+		name     string
+		msgBytes []byte
+	}{
+		{"empty", nil},
+		{"unknown header", []byte{0xff, 0x01}},
+		{"invalid snappy", []byte{compressionHeaderSnappy, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{"invalid zstd", []byte{compressionHeaderZstd, 0x01, 0x02, 0x03}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := decompressMessage(tc.msgBytes, 1024)
+			require.ErrorAs(t, err, &ErrDecompress{})
+		})
+	}
+}
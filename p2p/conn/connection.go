@@ -2,6 +2,7 @@ package conn
 
 import (
 	"bufio"
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
@@ -32,12 +33,17 @@ const (
 	minWriteBufferSize = 65536
 	updateStats        = 2 * time.Second
 
+	// throttledSendRetry is how long the sendRoutine waits before retrying to
+	// send on channels throttled by their send rate.
+	throttledSendRetry = 20 * time.Millisecond
+
 	// some of these defaults are written in the user config
 	// flushThrottle, sendRate, recvRate
 	// TODO: remove values present in config.
 	defaultFlushThrottle = 10 * time.Millisecond
 
 	defaultSendQueueCapacity   = 1
+	defaultRecvQueueCapacity   = 100
 	defaultRecvBufferCapacity  = 4096
 	defaultRecvMessageCapacity = 22020096      // 21MB
 	defaultSendRate            = int64(512000) // 500KB/s
@@ -115,6 +121,10 @@ type MConnection struct {
 
 	chStatsTimer *time.Ticker // update channel stats periodically
 
+	// set while a retry of the channels throttled by their send rate is
+	// scheduled.
+	throttledRetryScheduled atomic.Bool
+
 	created time.Time // time of creation
 
 	_maxPacketMsgSize int
@@ -236,6 +246,11 @@ func (c *MConnection) OnStart() error {
 	c.quitRecvRoutine = make(chan struct{})
 	go c.sendRoutine()
 	go c.recvRoutine()
+	for _, channel := range c.channels {
+		if channel.recvQueue != nil {
+			go c.deliverRoutine(channel)
+		}
+	}
 	return nil
 }
 
@@ -528,9 +543,12 @@ func (c *MConnection) sendBatchPacketMsgs(w protoio.Writer, batchSize int) bool
 		}
 	}()
 	for i := 0; i < batchSize; i++ {
-		channel := selectChannelToGossipOn(c.channels)
+		channel, throttled := selectChannelToGossipOn(c.channels)
 		// nothing to send across any channel.
 		if channel == nil {
+			if throttled {
+				c.scheduleThrottledRetry()
+			}
 			return true
 		}
 		bytesWritten, err := c.sendPacketMsgOnChannel(w, channel)
@@ -542,31 +560,65 @@ func (c *MConnection) sendBatchPacketMsgs(w protoio.Writer, batchSize int) bool
 	return false
 }
 
-// selects a channel to gossip our next message on.
+// selects a channel to gossip our next message on. It also returns true if a
+// channel with pending messages was skipped because of its send rate.
 // TODO: Make "batchChannelToGossipOn", so we can do our proto marshaling overheads in parallel,
 // and we can avoid re-checking for `isSendPending`.
 // We can easily mock the recentlySent differences for the batch choosing.
-func selectChannelToGossipOn(channels []*Channel) *Channel {
+func selectChannelToGossipOn(channels []*Channel) (*Channel, bool) {
 	// Choose a channel to create a PacketMsg from.
-	// The chosen channel will be the one whose recentlySent/priority is the least.
-	var leastRatio float32 = math.MaxFloat32
-	var leastChannel *Channel
+	// Channels below their minimum send rate are chosen first. Among them, or
+	// among all the channels if none is below its minimum send rate, the
+	// chosen channel will be the one whose recentlySent/priority is the least.
+	var (
+		leastRatio, leastGuaranteedRatio     float32 = math.MaxFloat32, math.MaxFloat32
+		leastChannel, leastGuaranteedChannel *Channel
+		throttled                            bool
+	)
 	for _, channel := range channels {
 		// If nothing to send, skip this channel
 		// TODO: Skip continually looking for isSendPending on channels we've already skipped in this batch-send.
 		if !channel.isSendPending() {
 			continue
 		}
+		// If the channel exceeded its send rate, skip it until the next sample.
+		if channel.isSendThrottled() {
+			throttled = true
+			continue
+		}
 		// Get ratio, and keep track of lowest ratio.
 		// TODO: RecentlySent right now is bytes. This should be refactored to num messages to fix
 		// gossip prioritization bugs.
-		ratio := float32(channel.recentlySent) / float32(channel.desc.Priority)
+		ratio := float32(atomic.LoadInt64(&channel.recentlySent)) / float32(channel.desc.Priority)
 		if ratio < leastRatio {
 			leastRatio = ratio
 			leastChannel = channel
 		}
+		if channel.isBelowMinSendRate() && ratio < leastGuaranteedRatio {
+			leastGuaranteedRatio = ratio
+			leastGuaranteedChannel = channel
+		}
+	}
+	if leastGuaranteedChannel != nil {
+		return leastGuaranteedChannel, throttled
 	}
-	return leastChannel
+	return leastChannel, throttled
+}
+
+// scheduleThrottledRetry wakes up the sendRoutine after throttledSendRetry,
+// so that the messages of the channels throttled by their send rate are
+// eventually sent even if no new message is queued.
+func (c *MConnection) scheduleThrottledRetry() {
+	if !c.throttledRetryScheduled.CompareAndSwap(false, true) {
+		return
+	}
+	time.AfterFunc(throttledSendRetry, func() {
+		c.throttledRetryScheduled.Store(false)
+		select {
+		case c.send <- struct{}{}:
+		default:
+		}
+	})
 }
 
 // returns (num_bytes_written, error_occurred).
@@ -672,7 +724,19 @@ FOR_LOOP:
 				}
 				break FOR_LOOP
 			}
-			if msgBytes != nil {
+			channel.bytesReceived.Add(int64(_n))
+			if channel.recvQueue == nil {
+				channel.recvMonitor.Update(_n)
+			}
+			if msgBytes != nil && channel.recvQueue != nil {
+				// The message is delivered by the deliverRoutine of the
+				// channel, which enforces its receive rate.
+				select {
+				case channel.recvQueue <- bytes.Clone(msgBytes):
+				case <-c.quitRecvRoutine:
+					break FOR_LOOP
+				}
+			} else if msgBytes != nil {
 				c.Logger.Debug("Received bytes", "chID", channelID, "msgBytes", msgBytes)
 				// NOTE: This means the reactor.Receive runs in the same thread as the p2p recv routine
 				c.onReceive(channelID, msgBytes)
@@ -689,6 +753,27 @@ FOR_LOOP:
 	close(c.pong)
 }
 
+// deliverRoutine delivers the messages received on a channel with a receive
+// rate, waiting for the channel to be back under its rate before each message.
+// Only this channel is throttled: the recvRoutine keeps reading the messages
+// of the other channels while the channel's queue is not full. Once it is
+// full, the recvRoutine blocks until a message is delivered.
+func (c *MConnection) deliverRoutine(channel *Channel) {
+	defer c._recover()
+
+	for {
+		select {
+		case msgBytes := <-channel.recvQueue:
+			channel.recvMonitor.Limit(len(msgBytes), channel.desc.RecvRate, true)
+			channel.recvMonitor.Update(len(msgBytes))
+			c.Logger.Debug("Received bytes", "chID", channel.desc.ID, "msgBytes", msgBytes)
+			c.onReceive(channel.desc.ID, msgBytes)
+		case <-c.quitRecvRoutine:
+			return
+		}
+	}
+}
+
 // not goroutine-safe.
 func (c *MConnection) stopPongTimer() {
 	if c.pongTimer != nil {
@@ -723,6 +808,17 @@ type ChannelStatus struct {
 	SendQueueSize     int
 	Priority          int
 	RecentlySent      int64
+	Compression       Compression
+	// Number of bytes of the messages sent on the channel, before and after
+	// compression. Only counted on channels with compression.
+	UncompressedBytesSent int64
+	CompressedBytesSent   int64
+	// Total number of bytes sent and received on the channel.
+	BytesSent     int64
+	BytesReceived int64
+	// Current send and receive rates of the channel, in bytes per second.
+	CurSendRate int64
+	CurRecvRate int64
 }
 
 func (c *MConnection) Status() ConnectionStatus {
@@ -733,11 +829,18 @@ func (c *MConnection) Status() ConnectionStatus {
 	status.Channels = make([]ChannelStatus, len(c.channels))
 	for i, channel := range c.channels {
 		status.Channels[i] = ChannelStatus{
-			ID:                channel.desc.ID,
-			SendQueueCapacity: cap(channel.sendQueue),
-			SendQueueSize:     int(atomic.LoadInt32(&channel.sendQueueSize)),
-			Priority:          channel.desc.Priority,
-			RecentlySent:      atomic.LoadInt64(&channel.recentlySent),
+			ID:                    channel.desc.ID,
+			SendQueueCapacity:     cap(channel.sendQueue),
+			SendQueueSize:         int(atomic.LoadInt32(&channel.sendQueueSize)),
+			Priority:              channel.desc.Priority,
+			RecentlySent:          atomic.LoadInt64(&channel.recentlySent),
+			Compression:           channel.desc.Compression,
+			UncompressedBytesSent: channel.uncompressedBytesSent.Load(),
+			CompressedBytesSent:   channel.compressedBytesSent.Load(),
+			BytesSent:             channel.bytesSent.Load(),
+			BytesReceived:         channel.bytesReceived.Load(),
+			CurSendRate:           channel.sendMonitor.Status().CurRate,
+			CurRecvRate:           channel.recvMonitor.Status().CurRate,
 		}
 	}
 	return status
@@ -752,6 +855,21 @@ type ChannelDescriptor struct {
 	RecvBufferCapacity  int
 	RecvMessageCapacity int
 	MessageType         proto.Message
+
+	// SendRate and RecvRate cap the rates, in bytes per second, at which
+	// messages are sent and received on the channel. 0 means no cap other
+	// than the rates of the connection.
+	SendRate int64
+	RecvRate int64
+	// MinSendRate is the rate, in bytes per second, up to which the channel is
+	// served before the channels of higher priority. It guarantees a minimum
+	// bandwidth to the channel when the connection is saturated. 0 means no
+	// guarantee.
+	MinSendRate int64
+	// Compression is the algorithm compressing the messages sent on the
+	// channel. It is negotiated with each peer during the handshake, and set
+	// by the transport. CompressionNone means the messages are sent as is.
+	Compression Compression
 }
 
 func (chDesc ChannelDescriptor) FillDefaults() (filled ChannelDescriptor) {
@@ -775,9 +893,12 @@ type Channel struct {
 	desc          ChannelDescriptor
 	sendQueue     chan []byte
 	sendQueueSize int32 // atomic.
-	recving       []byte
-	sending       []byte
-	recentlySent  int64 // exponential moving average
+	// messages received on a channel with a receive rate, to be delivered
+	// by its deliverRoutine. nil if the channel has no receive rate.
+	recvQueue    chan []byte
+	recving      []byte
+	sending      []byte
+	recentlySent int64 // exponential moving average
 
 	nextPacketMsg           *tmp2p.PacketMsg
 	nextP2pWrapperPacketMsg *tmp2p.Packet_PacketMsg
@@ -785,6 +906,14 @@ type Channel struct {
 
 	maxPacketMsgPayloadSize int
 
+	sendMonitor   *flow.Monitor
+	recvMonitor   *flow.Monitor
+	bytesSent     atomic.Int64
+	bytesReceived atomic.Int64
+
+	uncompressedBytesSent atomic.Int64
+	compressedBytesSent   atomic.Int64
+
 	Logger log.Logger
 }
 
@@ -793,15 +922,22 @@ func newChannel(conn *MConnection, desc ChannelDescriptor) *Channel {
 	if desc.Priority <= 0 {
 		panic("Channel default priority must be a positive integer")
 	}
+	var recvQueue chan []byte
+	if desc.RecvRate > 0 {
+		recvQueue = make(chan []byte, defaultRecvQueueCapacity)
+	}
 	return &Channel{
 		conn:                    conn,
 		desc:                    desc,
 		sendQueue:               make(chan []byte, desc.SendQueueCapacity),
+		recvQueue:               recvQueue,
 		recving:                 make([]byte, 0, desc.RecvBufferCapacity),
 		nextPacketMsg:           &tmp2p.PacketMsg{ChannelID: int32(desc.ID)},
 		nextP2pWrapperPacketMsg: &tmp2p.Packet_PacketMsg{},
 		nextPacket:              &tmp2p.Packet{},
 		maxPacketMsgPayloadSize: conn.config.MaxPacketMsgPayloadSize,
+		sendMonitor:             flow.New(0, 0),
+		recvMonitor:             flow.New(0, 0),
 	}
 }
 
@@ -854,11 +990,37 @@ func (ch *Channel) isSendPending() bool {
 		if len(ch.sendQueue) == 0 {
 			return false
 		}
-		ch.sending = <-ch.sendQueue
+		ch.sending = ch.compress(<-ch.sendQueue)
 	}
 	return true
 }
 
+// Compresses a message to send on the channel, if the channel has
+// compression.
+// Not goroutine-safe.
+func (ch *Channel) compress(msgBytes []byte) []byte {
+	if ch.desc.Compression == CompressionNone {
+		return msgBytes
+	}
+	compressed := compressMessage(ch.desc.Compression, msgBytes)
+	ch.uncompressedBytesSent.Add(int64(len(msgBytes)))
+	ch.compressedBytesSent.Add(int64(len(compressed)))
+	return compressed
+}
+
+// Returns true if the channel exceeded its send rate in the current sample.
+// Goroutine-safe.
+func (ch *Channel) isSendThrottled() bool {
+	return ch.desc.SendRate > 0 && ch.sendMonitor.Limit(1, ch.desc.SendRate, false) == 0
+}
+
+// Returns true if the channel is below its minimum send rate in the current
+// sample.
+// Goroutine-safe.
+func (ch *Channel) isBelowMinSendRate() bool {
+	return ch.desc.MinSendRate > 0 && ch.sendMonitor.Limit(1, ch.desc.MinSendRate, false) > 0
+}
+
 // Updates the nextPacket proto message for us to send.
 // Not goroutine-safe.
 func (ch *Channel) updateNextPacket() {
@@ -888,11 +1050,14 @@ func (ch *Channel) writePacketMsgTo(w protoio.Writer) (n int, err error) {
 	}
 
 	atomic.AddInt64(&ch.recentlySent, int64(n))
+	ch.sendMonitor.Update(n)
+	ch.bytesSent.Add(int64(n))
 	return n, err
 }
 
 // Handles incoming PacketMsgs. It returns a message bytes if message is
 // complete. NOTE message bytes may change on next call to recvPacketMsg.
+// Messages received on a channel with compression are decompressed.
 // Not goroutine-safe.
 func (ch *Channel) recvPacketMsg(packet tmp2p.PacketMsg) ([]byte, error) {
 	ch.Logger.Debug("Read PacketMsg", "conn", ch.conn, "packet", packet)
@@ -910,6 +1075,10 @@ func (ch *Channel) recvPacketMsg(packet tmp2p.PacketMsg) ([]byte, error) {
 		//   suggests this could be a memory leak, but we might as well keep the memory for the channel until it closes,
 		//	at which point the recving slice stops being used and should be garbage collected
 		ch.recving = ch.recving[:0] // make([]byte, 0, ch.desc.RecvBufferCapacity)
+		if ch.desc.Compression != CompressionNone {
+			// RecvMessageCapacity includes the compression header.
+			return decompressMessage(msgBytes, ch.desc.RecvMessageCapacity-CompressionHeaderSize)
+		}
 		return msgBytes, nil
 	}
 	return nil, nil
@@ -1,6 +1,7 @@
 package conn
 
 import (
+	"bytes"
 	"encoding/hex"
 	"net"
 	"testing"
@@ -163,6 +164,210 @@ func TestMConnectionStatus(t *testing.T) {
 	assert.Zero(t, status.Channels[0].SendQueueSize)
 }
 
+func TestSelectChannelToGossipOn(t *testing.T) {
+	server, client := NetPipe()
+	defer server.Close()
+	defer client.Close()
+
+	mconn := createTestMConnection(client)
+	newPendingChannel := func(desc ChannelDescriptor, recentlySent int64) *Channel {
+		ch := newChannel(mconn, desc)
+		ch.sending = []byte("pending")
+		ch.recentlySent = recentlySent
+		return ch
+	}
+
+	// The channel with the least recentlySent/priority is chosen.
+	high := newPendingChannel(ChannelDescriptor{ID: 0x01, Priority: 10}, 1000)
+	low := newPendingChannel(ChannelDescriptor{ID: 0x02, Priority: 1}, 1000)
+	ch, throttled := selectChannelToGossipOn([]*Channel{low, high})
+	assert.Equal(t, high, ch)
+	assert.False(t, throttled)
+
+	// A channel below its minimum send rate is chosen first.
+	guaranteed := newPendingChannel(ChannelDescriptor{ID: 0x03, Priority: 1, MinSendRate: 10000}, 1000)
+	ch, throttled = selectChannelToGossipOn([]*Channel{high, guaranteed})
+	assert.Equal(t, guaranteed, ch)
+	assert.False(t, throttled)
+
+	// Once it reached its minimum send rate, it's back to the priorities.
+	guaranteed.sendMonitor.Update(10000)
+	ch, throttled = selectChannelToGossipOn([]*Channel{high, guaranteed})
+	assert.Equal(t, high, ch)
+	assert.False(t, throttled)
+
+	// A channel above its send rate is skipped.
+	capped := newPendingChannel(ChannelDescriptor{ID: 0x04, Priority: 10, SendRate: 1000}, 0)
+	capped.sendMonitor.Update(1000)
+	ch, throttled = selectChannelToGossipOn([]*Channel{low, capped})
+	assert.Equal(t, low, ch)
+	assert.True(t, throttled)
+
+	ch, throttled = selectChannelToGossipOn([]*Channel{capped})
+	assert.Nil(t, ch)
+	assert.True(t, throttled)
+}
+
+func TestMConnectionChannelSendRate(t *testing.T) {
+	server, client := NetPipe()
+	defer server.Close()
+	defer client.Close()
+
+	receivedCh := make(chan []byte, 10)
+	onReceive := func(_ byte, msgBytes []byte) {
+		receivedCh <- append([]byte(nil), msgBytes...)
+	}
+	onError := func(r any) {
+		t.Error(r)
+	}
+	cfg := DefaultMConnConfig()
+	chDescs := []*ChannelDescriptor{{ID: 0x01, Priority: 1, SendQueueCapacity: 10, SendRate: 2000}}
+	mconn1 := NewMConnectionWithConfig(client, chDescs, onReceive, onError, cfg)
+	mconn1.SetLogger(log.TestingLogger())
+	err := mconn1.Start()
+	require.NoError(t, err)
+	defer mconn1.Stop() //nolint:errcheck // ignore for tests
+
+	mconn2 := NewMConnectionWithConfig(server, chDescs, onReceive, onError, cfg)
+	mconn2.SetLogger(log.TestingLogger())
+	err = mconn2.Start()
+	require.NoError(t, err)
+	defer mconn2.Stop() //nolint:errcheck // ignore for tests
+
+	// 200 bytes per sample of 100ms: the messages are sent over several
+	// samples, and must all be received even if nothing else is queued.
+	msg := make([]byte, 200)
+	const numMsgs = 5
+	start := time.Now()
+	for i := 0; i < numMsgs; i++ {
+		require.True(t, mconn1.Send(0x01, msg))
+	}
+	for i := 0; i < numMsgs; i++ {
+		select {
+		case receivedBytes := <-receivedCh:
+			assert.Equal(t, msg, receivedBytes)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Did not receive message %d in 5s", i)
+		}
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 300*time.Millisecond)
+
+	sentStatus := mconn1.Status().Channels[0]
+	assert.GreaterOrEqual(t, sentStatus.BytesSent, int64(numMsgs*len(msg)))
+	receivedStatus := mconn2.Status().Channels[0]
+	assert.Equal(t, sentStatus.BytesSent, receivedStatus.BytesReceived)
+}
+
+func TestMConnectionCompression(t *testing.T) {
+	server, client := NetPipe()
+	defer server.Close()
+	defer client.Close()
+
+	receivedCh := make(chan []byte)
+	onReceive := func(_ byte, msgBytes []byte) {
+		receivedCh <- append([]byte(nil), msgBytes...)
+	}
+	onError := func(r any) {
+		t.Error(r)
+	}
+	cfg := DefaultMConnConfig()
+	chDescs := []*ChannelDescriptor{{ID: 0x01, Priority: 1, Compression: CompressionZstd}}
+	mconn1 := NewMConnectionWithConfig(client, chDescs, onReceive, onError, cfg)
+	mconn1.SetLogger(log.TestingLogger())
+	err := mconn1.Start()
+	require.NoError(t, err)
+	defer mconn1.Stop() //nolint:errcheck // ignore for tests
+
+	mconn2 := NewMConnectionWithConfig(server, chDescs, onReceive, onError, cfg)
+	mconn2.SetLogger(log.TestingLogger())
+	err = mconn2.Start()
+	require.NoError(t, err)
+	defer mconn2.Stop() //nolint:errcheck // ignore for tests
+
+	// Compressible, and bigger than a packet when uncompressed.
+	msg := bytes.Repeat([]byte("Hulk"), 1000)
+	for _, m := range [][]byte{msg, []byte("Thor")} {
+		require.True(t, mconn1.Send(0x01, m))
+		select {
+		case receivedBytes := <-receivedCh:
+			assert.Equal(t, m, receivedBytes)
+		case <-time.After(500 * time.Millisecond):
+			t.Fatalf("Did not receive %d bytes message in 500ms", len(m))
+		}
+	}
+
+	status := mconn1.Status().Channels[0]
+	assert.Equal(t, CompressionZstd, status.Compression)
+	assert.Equal(t, int64(len(msg)+len("Thor")), status.UncompressedBytesSent)
+	assert.Less(t, status.CompressedBytesSent, status.UncompressedBytesSent)
+	assert.Less(t, status.BytesSent, int64(len(msg)))
+}
+
+func TestMConnectionChannelRecvRate(t *testing.T) {
+	server, client := NetPipe()
+	defer server.Close()
+	defer client.Close()
+
+	type received struct {
+		chID  byte
+		bytes []byte
+		at    time.Time
+	}
+	receivedCh := make(chan received, 10)
+	onReceive := func(chID byte, msgBytes []byte) {
+		receivedCh <- received{chID, append([]byte(nil), msgBytes...), time.Now()}
+	}
+	onError := func(r any) {
+		t.Error(r)
+	}
+	cfg := DefaultMConnConfig()
+	mconn1 := NewMConnectionWithConfig(client, []*ChannelDescriptor{
+		{ID: 0x01, Priority: 1, SendQueueCapacity: 10},
+		{ID: 0x02, Priority: 1, SendQueueCapacity: 10},
+	}, onReceive, onError, cfg)
+	mconn1.SetLogger(log.TestingLogger())
+	err := mconn1.Start()
+	require.NoError(t, err)
+	defer mconn1.Stop() //nolint:errcheck // ignore for tests
+
+	// Only the receiver caps the rate of the first channel.
+	mconn2 := NewMConnectionWithConfig(server, []*ChannelDescriptor{
+		{ID: 0x01, Priority: 1, SendQueueCapacity: 10, RecvRate: 2000},
+		{ID: 0x02, Priority: 1, SendQueueCapacity: 10},
+	}, onReceive, onError, cfg)
+	mconn2.SetLogger(log.TestingLogger())
+	err = mconn2.Start()
+	require.NoError(t, err)
+	defer mconn2.Stop() //nolint:errcheck // ignore for tests
+
+	// 200 bytes per sample of 100ms: the messages of the first channel are
+	// delivered over several samples, without delaying the second channel.
+	msg := make([]byte, 200)
+	const numMsgs = 5
+	start := time.Now()
+	for i := 0; i < numMsgs; i++ {
+		require.True(t, mconn1.Send(0x01, msg))
+	}
+	require.True(t, mconn1.Send(0x02, []byte("unthrottled")))
+
+	var last time.Time
+	for i := 0; i < numMsgs+1; i++ {
+		select {
+		case r := <-receivedCh:
+			if r.chID == 0x02 {
+				assert.Equal(t, []byte("unthrottled"), r.bytes)
+				assert.Less(t, r.at.Sub(start), 200*time.Millisecond)
+				continue
+			}
+			assert.Equal(t, msg, r.bytes)
+			last = r.at
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Did not receive message %d in 5s", i)
+		}
+	}
+	assert.GreaterOrEqual(t, last.Sub(start), 300*time.Millisecond)
+}
+
 func TestMConnectionPongTimeoutResultsInError(t *testing.T) {
 	server, client := net.Pipe()
 	defer server.Close()
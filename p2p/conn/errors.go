@@ -54,6 +54,20 @@ func (e ErrPacketTooBig) Error() string {
 	return fmt.Sprintf("received message exceeds available capacity (max: %d, got: %d)", e.Max, e.Received)
 }
 
+// ErrDecompress is returned when a message received on a channel with
+// compression cannot be decompressed.
+type ErrDecompress struct {
+	Source error
+}
+
+func (e ErrDecompress) Error() string {
+	return fmt.Sprintf("failed to decompress message: %v", e.Source)
+}
+
+func (e ErrDecompress) Unwrap() error {
+	return e.Source
+}
+
 type ErrChunkTooBig struct {
 	Received int
 	Max      int
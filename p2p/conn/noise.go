@@ -0,0 +1,359 @@
+package conn
+
+import (
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+)
+
+// The Noise Protocol Framework (https://noiseprotocol.org/noise.html) with the
+// XX handshake pattern, X25519, ChaChaPoly and SHA256.
+
+// NoiseProtocolName is the name of the Noise protocol of NoiseConnection.
+const NoiseProtocolName = "Noise_XX_25519_ChaChaPoly_SHA256"
+
+const (
+	noiseDHLen      = curve25519.PointSize
+	noiseHashLen    = sha256.Size
+	noiseMaxMsgSize = math.MaxUint16
+)
+
+var (
+	errNoiseNonceExhausted = errors.New("noise: nonce exhausted")
+	errNoiseShortMessage   = errors.New("noise: message too short")
+	errNoiseMessageTooBig  = errors.New("noise: message too big")
+	errNoiseHandshakeDone  = errors.New("noise: handshake already completed")
+	errNoiseOutOfTurn      = errors.New("noise: handshake message out of turn")
+)
+
+// noiseXX are the tokens of the messages of the XX pattern:
+//
+//	-> e
+//	<- e, ee, s, es
+//	-> s, se
+var noiseXX = [][]string{
+	{"e"},
+	{"e", "ee", "s", "es"},
+	{"s", "se"},
+}
+
+// noiseKeyPair is a X25519 key pair.
+type noiseKeyPair struct {
+	priv [noiseDHLen]byte
+	pub  [noiseDHLen]byte
+}
+
+// newNoiseKeyPair returns the key pair of the private key read from rand.
+func newNoiseKeyPair(rand io.Reader) (noiseKeyPair, error) {
+	var kp noiseKeyPair
+	if _, err := io.ReadFull(rand, kp.priv[:]); err != nil {
+		return kp, err
+	}
+	pub, err := curve25519.X25519(kp.priv[:], curve25519.Basepoint)
+	if err != nil {
+		return kp, err
+	}
+	copy(kp.pub[:], pub)
+	return kp, nil
+}
+
+// noiseDH returns the X25519 shared secret of priv and pub. It fails if pub
+// is a low order point.
+func noiseDH(priv, pub [noiseDHLen]byte) ([]byte, error) {
+	secret, err := curve25519.X25519(priv[:], pub[:])
+	if err != nil {
+		return nil, ErrSmallOrderRemotePubKey
+	}
+	return secret, nil
+}
+
+// noiseHKDF returns the first two outputs of the HKDF of the Noise
+// specification.
+func noiseHKDF(chainingKey, ikm []byte) (out1, out2 []byte) {
+	mac := hmac.New(sha256.New, chainingKey)
+	mac.Write(ikm)
+	tempKey := mac.Sum(nil)
+
+	mac = hmac.New(sha256.New, tempKey)
+	mac.Write([]byte{0x01})
+	out1 = mac.Sum(nil)
+
+	mac = hmac.New(sha256.New, tempKey)
+	mac.Write(out1)
+	mac.Write([]byte{0x02})
+	out2 = mac.Sum(nil)
+	return out1, out2
+}
+
+// noiseCipherState encrypts with a key and a counter nonce. It does not
+// encrypt until it has a key.
+type noiseCipherState struct {
+	aead  cipher.AEAD
+	nonce uint64
+}
+
+func (cs *noiseCipherState) initializeKey(key []byte) {
+	aead, err := chacha20poly1305.New(key[:aeadKeySize])
+	if err != nil {
+		panic(err) // the key has the right size
+	}
+	cs.aead = aead
+	cs.nonce = 0
+}
+
+func (cs *noiseCipherState) hasKey() bool {
+	return cs.aead != nil
+}
+
+// aeadNonce returns the ChaChaPoly nonce of the current counter: 32 bits of
+// zeros followed by the little-endian counter.
+func (cs *noiseCipherState) aeadNonce() ([]byte, error) {
+	// The maximum nonce is reserved by the specification.
+	if cs.nonce == math.MaxUint64 {
+		return nil, errNoiseNonceExhausted
+	}
+	nonce := make([]byte, aeadNonceSize)
+	binary.LittleEndian.PutUint64(nonce[4:], cs.nonce)
+	return nonce, nil
+}
+
+// encryptWithAd appends the encryption of plaintext to out.
+func (cs *noiseCipherState) encryptWithAd(out, ad, plaintext []byte) ([]byte, error) {
+	if !cs.hasKey() {
+		return append(out, plaintext...), nil
+	}
+	nonce, err := cs.aeadNonce()
+	if err != nil {
+		return nil, err
+	}
+	cs.nonce++
+	return cs.aead.Seal(out, nonce, plaintext, ad), nil
+}
+
+// decryptWithAd appends the decryption of ciphertext to out.
+func (cs *noiseCipherState) decryptWithAd(out, ad, ciphertext []byte) ([]byte, error) {
+	if !cs.hasKey() {
+		return append(out, ciphertext...), nil
+	}
+	nonce, err := cs.aeadNonce()
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := cs.aead.Open(out, nonce, ciphertext, ad)
+	if err != nil {
+		return nil, ErrDecryptFrame{Source: err}
+	}
+	cs.nonce++
+	return plaintext, nil
+}
+
+// noiseSymmetricState holds the chaining key and the handshake hash.
+type noiseSymmetricState struct {
+	cs noiseCipherState
+	ck []byte
+	h  []byte
+}
+
+func (ss *noiseSymmetricState) initialize(protocolName string) {
+	if len(protocolName) <= noiseHashLen {
+		ss.h = make([]byte, noiseHashLen)
+		copy(ss.h, protocolName)
+	} else {
+		sum := sha256.Sum256([]byte(protocolName))
+		ss.h = sum[:]
+	}
+	ss.ck = append([]byte{}, ss.h...)
+}
+
+func (ss *noiseSymmetricState) mixKey(ikm []byte) {
+	var tempKey []byte
+	ss.ck, tempKey = noiseHKDF(ss.ck, ikm)
+	ss.cs.initializeKey(tempKey)
+}
+
+func (ss *noiseSymmetricState) mixHash(data []byte) {
+	h := sha256.New()
+	h.Write(ss.h)
+	h.Write(data)
+	ss.h = h.Sum(nil)
+}
+
+func (ss *noiseSymmetricState) encryptAndHash(out, plaintext []byte) ([]byte, error) {
+	res, err := ss.cs.encryptWithAd(out, ss.h, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	ss.mixHash(res[len(out):])
+	return res, nil
+}
+
+func (ss *noiseSymmetricState) decryptAndHash(ciphertext []byte) ([]byte, error) {
+	plaintext, err := ss.cs.decryptWithAd(nil, ss.h, ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	ss.mixHash(ciphertext)
+	return plaintext, nil
+}
+
+// split returns the cipher states of the messages sent by the initiator and
+// by the responder.
+func (ss *noiseSymmetricState) split() (initiator, responder *noiseCipherState) {
+	k1, k2 := noiseHKDF(ss.ck, nil)
+	initiator, responder = &noiseCipherState{}, &noiseCipherState{}
+	initiator.initializeKey(k1)
+	responder.initializeKey(k2)
+	return initiator, responder
+}
+
+// noiseHandshake is the state of a XX handshake. The initiator writes the
+// first and the last message, and the responder reads them.
+type noiseHandshake struct {
+	ss        noiseSymmetricState
+	initiator bool
+	msgIdx    int
+
+	s, e   noiseKeyPair
+	rs, re [noiseDHLen]byte
+}
+
+// newNoiseHandshake returns the XX handshake state of a node with the static
+// key pair s and the ephemeral key pair e.
+func newNoiseHandshake(initiator bool, prologue []byte, s, e noiseKeyPair) *noiseHandshake {
+	hs := &noiseHandshake{initiator: initiator, s: s, e: e}
+	hs.ss.initialize(NoiseProtocolName)
+	hs.ss.mixHash(prologue)
+	return hs
+}
+
+// done returns true once the three messages of the handshake were exchanged.
+func (hs *noiseHandshake) done() bool {
+	return hs.msgIdx == len(noiseXX)
+}
+
+// dh performs the DH of the token, from the point of view of the node.
+func (hs *noiseHandshake) dh(token string) ([]byte, error) {
+	switch {
+	case token == "ee":
+		return noiseDH(hs.e.priv, hs.re)
+	case token == "es" && hs.initiator, token == "se" && !hs.initiator:
+		return noiseDH(hs.e.priv, hs.rs)
+	default: // "es" by the responder, "se" by the initiator
+		return noiseDH(hs.s.priv, hs.re)
+	}
+}
+
+// initiatorsTurn returns true if the next message is the initiator's.
+func (hs *noiseHandshake) initiatorsTurn() bool {
+	return hs.msgIdx%2 == 0
+}
+
+// writeMessage returns the next handshake message, carrying payload.
+func (hs *noiseHandshake) writeMessage(payload []byte) ([]byte, error) {
+	if hs.done() {
+		return nil, errNoiseHandshakeDone
+	}
+	if hs.initiatorsTurn() != hs.initiator {
+		return nil, errNoiseOutOfTurn
+	}
+	msg := []byte{}
+	for _, token := range noiseXX[hs.msgIdx] {
+		var err error
+		switch token {
+		case "e":
+			msg = append(msg, hs.e.pub[:]...)
+			hs.ss.mixHash(hs.e.pub[:])
+		case "s":
+			msg, err = hs.ss.encryptAndHash(msg, hs.s.pub[:])
+		default:
+			var secret []byte
+			if secret, err = hs.dh(token); err == nil {
+				hs.ss.mixKey(secret)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	msg, err := hs.ss.encryptAndHash(msg, payload)
+	if err != nil {
+		return nil, err
+	}
+	if len(msg) > noiseMaxMsgSize {
+		return nil, errNoiseMessageTooBig
+	}
+	hs.msgIdx++
+	return msg, nil
+}
+
+// readMessage processes the next handshake message, and returns its payload.
+func (hs *noiseHandshake) readMessage(msg []byte) ([]byte, error) {
+	if hs.done() {
+		return nil, errNoiseHandshakeDone
+	}
+	if hs.initiatorsTurn() == hs.initiator {
+		return nil, errNoiseOutOfTurn
+	}
+	for _, token := range noiseXX[hs.msgIdx] {
+		var err error
+		switch token {
+		case "e":
+			if len(msg) < noiseDHLen {
+				return nil, errNoiseShortMessage
+			}
+			copy(hs.re[:], msg[:noiseDHLen])
+			msg = msg[noiseDHLen:]
+			hs.ss.mixHash(hs.re[:])
+		case "s":
+			n := noiseDHLen
+			if hs.ss.cs.hasKey() {
+				n += aeadSizeOverhead
+			}
+			if len(msg) < n {
+				return nil, errNoiseShortMessage
+			}
+			var rs []byte
+			if rs, err = hs.ss.decryptAndHash(msg[:n]); err == nil {
+				copy(hs.rs[:], rs)
+				msg = msg[n:]
+			}
+		default:
+			var secret []byte
+			if secret, err = hs.dh(token); err == nil {
+				hs.ss.mixKey(secret)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	payload, err := hs.ss.decryptAndHash(msg)
+	if err != nil {
+		return nil, err
+	}
+	hs.msgIdx++
+	return payload, nil
+}
+
+// split returns the cipher states the node sends and receives with once the
+// handshake is done.
+func (hs *noiseHandshake) split() (send, recv *noiseCipherState) {
+	initiator, responder := hs.ss.split()
+	if hs.initiator {
+		return initiator, responder
+	}
+	return responder, initiator
+}
+
+// handshakeHash returns the hash of the handshake, which identifies the
+// session.
+func (hs *noiseHandshake) handshakeHash() []byte {
+	return hs.ss.h
+}
@@ -0,0 +1,280 @@
+package conn
+
+import (
+	"bufio"
+	crand "crypto/rand"
+	"encoding/binary"
+	"io"
+	"net"
+	"time"
+
+	tmp2p "github.com/cometbft/cometbft/api/cometbft/p2p/v1"
+	"github.com/cometbft/cometbft/crypto"
+	"github.com/cometbft/cometbft/crypto/ed25519"
+	cryptoenc "github.com/cometbft/cometbft/crypto/encoding"
+	cmtsync "github.com/cometbft/cometbft/libs/sync"
+)
+
+const (
+	// Messages are prefixed with their big-endian length.
+	noiseLenSize          = 2
+	noiseMaxPlaintextSize = noiseMaxMsgSize - aeadSizeOverhead
+)
+
+var (
+	// noisePrologue starts the prologue of the handshakes negotiated by
+	// MakeAuthenticatedConnection.
+	noisePrologue = []byte("COMETBFT_NOISE_PROLOGUE")
+
+	// noiseStaticKeySigPrefix prefixes the Noise static key signed by the
+	// node key.
+	noiseStaticKeySigPrefix = []byte("COMETBFT_NOISE_STATIC_KEY:")
+)
+
+// NoiseConnection implements net.Conn.
+// It encrypts the data with the keys of a Noise_XX_25519_ChaChaPoly_SHA256
+// handshake (see https://noiseprotocol.org/noise.html), in which each node
+// authenticates with its ed25519 key by signing its Noise static key. The
+// public key and the signature are the payload of the second and the third
+// handshake messages, encoded as an AuthSigMessage.
+//
+// The handshake and transport messages are prefixed with their length, as a
+// big-endian uint16.
+//
+// Consumers of the NoiseConnection are responsible for authenticating the
+// remote peer's pubkey against known information, like a nodeID.
+type NoiseConnection struct {
+	remPubKey crypto.PubKey
+
+	conn       io.ReadWriteCloser
+	connWriter *bufio.Writer
+	connReader io.Reader
+
+	// All .Read are covered by recvMtx, all .Write are covered by sendMtx.
+	recvMtx    cmtsync.Mutex
+	recvCipher *noiseCipherState
+	recvBuffer []byte
+	recvMsg    []byte
+	recvPlain  []byte
+
+	sendMtx    cmtsync.Mutex
+	sendCipher *noiseCipherState
+	sendMsg    []byte
+}
+
+// MakeNoiseConnection performs a Noise XX handshake, as the initiator or the
+// responder, and returns a new authenticated NoiseConnection.
+// Caller should call conn.Close().
+func MakeNoiseConnection(
+	conn io.ReadWriteCloser,
+	locPrivKey crypto.PrivKey,
+	initiator bool,
+	prologue []byte,
+) (*NoiseConnection, error) {
+	// The static key is only used to authenticate the node key, so it can be
+	// as short-lived as the ephemeral key.
+	s, err := newNoiseKeyPair(crand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	e, err := newNoiseKeyPair(crand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return makeNoiseConnection(conn, locPrivKey, newNoiseHandshake(initiator, prologue, s, e))
+}
+
+func makeNoiseConnection(
+	conn io.ReadWriteCloser,
+	locPrivKey crypto.PrivKey,
+	hs *noiseHandshake,
+) (*NoiseConnection, error) {
+	nc := &NoiseConnection{
+		conn:       conn,
+		connWriter: bufio.NewWriterSize(conn, defaultWriteBufferSize),
+		connReader: bufio.NewReaderSize(conn, defaultReadBufferSize),
+		recvMsg:    make([]byte, noiseMaxMsgSize),
+		recvPlain:  make([]byte, 0, noiseMaxPlaintextSize),
+		sendMsg:    make([]byte, noiseLenSize, noiseLenSize+noiseMaxMsgSize),
+	}
+
+	locPayload, err := noiseIdentityPayload(locPrivKey, hs.s.pub)
+	if err != nil {
+		return nil, err
+	}
+
+	// The first message only carries the ephemeral key of the initiator, in
+	// the clear. The identities are sent in the next two, encrypted.
+	var remPayload []byte
+	for !hs.done() {
+		if hs.initiatorsTurn() == hs.initiator {
+			var payload []byte
+			if hs.msgIdx > 0 {
+				payload = locPayload
+			}
+			msg, err := hs.writeMessage(payload)
+			if err != nil {
+				return nil, err
+			}
+			if err := nc.writeMessage(msg); err != nil {
+				return nil, err
+			}
+		} else {
+			msg, err := nc.readMessage()
+			if err != nil {
+				return nil, err
+			}
+			payload, err := hs.readMessage(msg)
+			if err != nil {
+				return nil, err
+			}
+			if hs.msgIdx > 1 {
+				remPayload = payload
+			}
+		}
+	}
+
+	remPubKey, err := verifyNoiseIdentityPayload(remPayload, hs.rs)
+	if err != nil {
+		return nil, err
+	}
+
+	// We've authorized.
+	nc.remPubKey = remPubKey
+	nc.sendCipher, nc.recvCipher = hs.split()
+	return nc, nil
+}
+
+// noiseIdentityPayload returns the public key of the node and its signature of
+// the Noise static key.
+func noiseIdentityPayload(locPrivKey crypto.PrivKey, staticPubKey [noiseDHLen]byte) ([]byte, error) {
+	sig, err := locPrivKey.Sign(append(append([]byte{}, noiseStaticKeySigPrefix...), staticPubKey[:]...))
+	if err != nil {
+		return nil, err
+	}
+	pbpk, err := cryptoenc.PubKeyToProto(locPrivKey.PubKey())
+	if err != nil {
+		return nil, err
+	}
+	msg := tmp2p.AuthSigMessage{PubKey: pbpk, Sig: sig}
+	return msg.Marshal()
+}
+
+// verifyNoiseIdentityPayload returns the public key of the remote node, if it
+// signed its Noise static key.
+func verifyNoiseIdentityPayload(payload []byte, remStaticPubKey [noiseDHLen]byte) (crypto.PubKey, error) {
+	var msg tmp2p.AuthSigMessage
+	if err := msg.Unmarshal(payload); err != nil {
+		return nil, err
+	}
+	remPubKey, err := cryptoenc.PubKeyFromProto(msg.PubKey)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := remPubKey.(ed25519.PubKey); !ok {
+		return nil, ErrUnexpectedPubKeyType{
+			Expected: ed25519.KeyType,
+			Got:      remPubKey.Type(),
+		}
+	}
+	signed := append(append([]byte{}, noiseStaticKeySigPrefix...), remStaticPubKey[:]...)
+	if !remPubKey.VerifySignature(signed, msg.Sig) {
+		return nil, ErrChallengeVerification
+	}
+	return remPubKey, nil
+}
+
+// writeMessage writes a length-prefixed message.
+func (nc *NoiseConnection) writeMessage(msg []byte) error {
+	var msgLen [noiseLenSize]byte
+	binary.BigEndian.PutUint16(msgLen[:], uint16(len(msg)))
+	if _, err := nc.connWriter.Write(msgLen[:]); err != nil {
+		return err
+	}
+	if _, err := nc.connWriter.Write(msg); err != nil {
+		return err
+	}
+	return nc.connWriter.Flush()
+}
+
+// readMessage reads a length-prefixed message into recvMsg.
+func (nc *NoiseConnection) readMessage() ([]byte, error) {
+	var msgLen [noiseLenSize]byte
+	if _, err := io.ReadFull(nc.connReader, msgLen[:]); err != nil {
+		return nil, err
+	}
+	msg := nc.recvMsg[:binary.BigEndian.Uint16(msgLen[:])]
+	if _, err := io.ReadFull(nc.connReader, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// RemotePubKey returns authenticated remote pubkey.
+func (nc *NoiseConnection) RemotePubKey() crypto.PubKey {
+	return nc.remPubKey
+}
+
+// Write encrypts data in messages of at most noiseMaxPlaintextSize bytes.
+// CONTRACT: data smaller than noiseMaxPlaintextSize is written atomically.
+func (nc *NoiseConnection) Write(data []byte) (n int, err error) {
+	nc.sendMtx.Lock()
+	defer nc.sendMtx.Unlock()
+
+	for len(data) > 0 {
+		chunk := data
+		if len(chunk) > noiseMaxPlaintextSize {
+			chunk = data[:noiseMaxPlaintextSize]
+		}
+		data = data[len(chunk):]
+
+		msg, err := nc.sendCipher.encryptWithAd(nc.sendMsg[:noiseLenSize], nil, chunk)
+		if err != nil {
+			return n, err
+		}
+		binary.BigEndian.PutUint16(msg, uint16(len(msg)-noiseLenSize))
+		if _, err := nc.connWriter.Write(msg); err != nil {
+			return n, err
+		}
+		n += len(chunk)
+	}
+	return n, nc.connWriter.Flush()
+}
+
+// CONTRACT: data smaller than noiseMaxPlaintextSize is read atomically.
+func (nc *NoiseConnection) Read(data []byte) (n int, err error) {
+	nc.recvMtx.Lock()
+	defer nc.recvMtx.Unlock()
+
+	// read off and update the recvBuffer, if non-empty
+	if len(nc.recvBuffer) > 0 {
+		n = copy(data, nc.recvBuffer)
+		nc.recvBuffer = nc.recvBuffer[n:]
+		return n, nil
+	}
+
+	msg, err := nc.readMessage()
+	if err != nil {
+		return 0, err
+	}
+	plaintext, err := nc.recvCipher.decryptWithAd(nc.recvPlain[:0], nil, msg)
+	if err != nil {
+		return 0, err
+	}
+	n = copy(data, plaintext)
+	nc.recvBuffer = plaintext[n:]
+	return n, nil
+}
+
+// Implements net.Conn.
+func (nc *NoiseConnection) Close() error                  { return nc.conn.Close() }
+func (nc *NoiseConnection) LocalAddr() net.Addr           { return nc.conn.(net.Conn).LocalAddr() }
+func (nc *NoiseConnection) RemoteAddr() net.Addr          { return nc.conn.(net.Conn).RemoteAddr() }
+func (nc *NoiseConnection) SetDeadline(t time.Time) error { return nc.conn.(net.Conn).SetDeadline(t) }
+func (nc *NoiseConnection) SetReadDeadline(t time.Time) error {
+	return nc.conn.(net.Conn).SetReadDeadline(t)
+}
+
+func (nc *NoiseConnection) SetWriteDeadline(t time.Time) error {
+	return nc.conn.(net.Conn).SetWriteDeadline(t)
+}
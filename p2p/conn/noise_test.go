@@ -0,0 +1,299 @@
+package conn
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cometbft/cometbft/crypto"
+	"github.com/cometbft/cometbft/crypto/ed25519"
+	"github.com/cometbft/cometbft/internal/async"
+)
+
+// hexBytes is a hex encoded JSON string.
+type hexBytes []byte
+
+func (b *hexBytes) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	decoded, err := hex.DecodeString(s)
+	*b = decoded
+	return err
+}
+
+func (b hexBytes) String() string {
+	return hex.EncodeToString(b)
+}
+
+// noiseVector is a test vector in the format of the Noise test vectors of
+// https://github.com/noiseprotocol/noise_wiki/wiki/Test-vectors. The messages
+// alternate between the initiator and the responder, starting with the
+// initiator.
+type noiseVector struct {
+	ProtocolName  string   `json:"protocol_name"`
+	InitPrologue  hexBytes `json:"init_prologue"`
+	InitStatic    hexBytes `json:"init_static"`
+	InitEphemeral hexBytes `json:"init_ephemeral"`
+	RespPrologue  hexBytes `json:"resp_prologue"`
+	RespStatic    hexBytes `json:"resp_static"`
+	RespEphemeral hexBytes `json:"resp_ephemeral"`
+	HandshakeHash hexBytes `json:"handshake_hash"`
+	Messages      []struct {
+		Payload    hexBytes `json:"payload"`
+		Ciphertext hexBytes `json:"ciphertext"`
+	} `json:"messages"`
+}
+
+func noiseTestKeyPair(t *testing.T, priv []byte) noiseKeyPair {
+	t.Helper()
+	kp, err := newNoiseKeyPair(bytes.NewReader(priv))
+	require.NoError(t, err)
+	return kp
+}
+
+// TestNoiseVectors checks the handshake and the transport messages against
+// test vectors generated with an independent implementation.
+func TestNoiseVectors(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "noise_vectors.json"))
+	require.NoError(t, err)
+	var file struct {
+		Vectors []noiseVector `json:"vectors"`
+	}
+	require.NoError(t, json.Unmarshal(data, &file))
+	require.NotEmpty(t, file.Vectors)
+
+	for i, v := range file.Vectors {
+		require.Equal(t, NoiseProtocolName, v.ProtocolName)
+
+		init := newNoiseHandshake(true, v.InitPrologue,
+			noiseTestKeyPair(t, v.InitStatic), noiseTestKeyPair(t, v.InitEphemeral))
+		resp := newNoiseHandshake(false, v.RespPrologue,
+			noiseTestKeyPair(t, v.RespStatic), noiseTestKeyPair(t, v.RespEphemeral))
+
+		var initSend, initRecv, respSend, respRecv *noiseCipherState
+		for j, msg := range v.Messages {
+			sender, receiver := init, resp
+			if j%2 == 1 {
+				sender, receiver = resp, init
+			}
+
+			if !init.done() {
+				ciphertext, err := sender.writeMessage(msg.Payload)
+				require.NoError(t, err, "vector %d, message %d", i, j)
+				require.Equal(t, []byte(msg.Ciphertext), ciphertext, "vector %d, message %d", i, j)
+				payload, err := receiver.readMessage(ciphertext)
+				require.NoError(t, err, "vector %d, message %d", i, j)
+				require.Equal(t, msg.Payload.String(), hexBytes(payload).String(), "vector %d, message %d", i, j)
+
+				if init.done() {
+					require.Equal(t, []byte(v.HandshakeHash), init.handshakeHash(), "vector %d", i)
+					require.Equal(t, []byte(v.HandshakeHash), resp.handshakeHash(), "vector %d", i)
+					initSend, initRecv = init.split()
+					respSend, respRecv = resp.split()
+				}
+				continue
+			}
+
+			send, recv := initSend, respRecv
+			if j%2 == 1 {
+				send, recv = respSend, initRecv
+			}
+			ciphertext, err := send.encryptWithAd(nil, nil, msg.Payload)
+			require.NoError(t, err, "vector %d, message %d", i, j)
+			require.Equal(t, []byte(msg.Ciphertext), ciphertext, "vector %d, message %d", i, j)
+			payload, err := recv.decryptWithAd(nil, nil, ciphertext)
+			require.NoError(t, err, "vector %d, message %d", i, j)
+			require.Equal(t, msg.Payload.String(), hexBytes(payload).String(), "vector %d, message %d", i, j)
+		}
+		require.True(t, init.done(), "vector %d", i)
+	}
+}
+
+func TestNoiseHandshakeErrors(t *testing.T) {
+	newPair := func() (init, resp *noiseHandshake) {
+		kp := func(b byte) noiseKeyPair {
+			return noiseTestKeyPair(t, bytes.Repeat([]byte{b}, noiseDHLen))
+		}
+		return newNoiseHandshake(true, nil, kp(1), kp(2)), newNoiseHandshake(false, nil, kp(3), kp(4))
+	}
+
+	// Messages out of turn.
+	init, resp := newPair()
+	_, err := resp.writeMessage(nil)
+	require.ErrorIs(t, err, errNoiseOutOfTurn)
+	_, err = init.readMessage(make([]byte, noiseDHLen))
+	require.ErrorIs(t, err, errNoiseOutOfTurn)
+
+	// Truncated and tampered messages.
+	init, resp = newPair()
+	msg, err := init.writeMessage(nil)
+	require.NoError(t, err)
+	_, err = resp.readMessage(msg[:noiseDHLen-1])
+	require.ErrorIs(t, err, errNoiseShortMessage)
+
+	init, resp = newPair()
+	msg, err = init.writeMessage(nil)
+	require.NoError(t, err)
+	_, err = resp.readMessage(msg)
+	require.NoError(t, err)
+	msg, err = resp.writeMessage([]byte("payload"))
+	require.NoError(t, err)
+	msg[len(msg)-1] ^= 1
+	_, err = init.readMessage(msg)
+	require.Error(t, err)
+
+	// A different prologue fails the handshake.
+	init, resp = newPair()
+	resp.ss.mixHash([]byte("another prologue"))
+	msg, err = init.writeMessage(nil)
+	require.NoError(t, err)
+	_, err = resp.readMessage(msg)
+	require.NoError(t, err)
+	msg, err = resp.writeMessage(nil)
+	require.NoError(t, err)
+	_, err = init.readMessage(msg)
+	require.Error(t, err)
+}
+
+func makeNoiseConnPair(t *testing.T, fooNoise, barNoise bool) (fooConn, barConn AuthenticatedConnection) {
+	t.Helper()
+	var (
+		fooRaw, barRaw = makeKVStoreConnPair()
+		fooPrvKey      = ed25519.GenPrivKey()
+		barPrvKey      = ed25519.GenPrivKey()
+	)
+
+	trs, ok := async.Parallel(
+		func(_ int) (val any, abort bool, err error) {
+			fooConn, err = MakeAuthenticatedConnection(fooRaw, fooPrvKey, fooNoise)
+			return nil, err != nil, err
+		},
+		func(_ int) (val any, abort bool, err error) {
+			barConn, err = MakeAuthenticatedConnection(barRaw, barPrvKey, barNoise)
+			return nil, err != nil, err
+		},
+	)
+	require.True(t, ok)
+	require.NoError(t, trs.FirstError())
+	require.Equal(t, barPrvKey.PubKey(), fooConn.RemotePubKey())
+	require.Equal(t, fooPrvKey.PubKey(), barConn.RemotePubKey())
+	return fooConn, barConn
+}
+
+func TestMakeAuthenticatedConnection(t *testing.T) {
+	testCases := []struct {
+		fooNoise, barNoise bool
+		noise              bool
+	}{
+		{true, true, true},
+		{true, false, false},
+		{false, true, false},
+		{false, false, false},
+	}
+	for _, tc := range testCases {
+		fooConn, barConn := makeNoiseConnPair(t, tc.fooNoise, tc.barNoise)
+		if tc.noise {
+			assert.IsType(t, &NoiseConnection{}, fooConn)
+			assert.IsType(t, &NoiseConnection{}, barConn)
+		} else {
+			assert.IsType(t, &SecretConnection{}, fooConn)
+			assert.IsType(t, &SecretConnection{}, barConn)
+		}
+
+		// Messages bigger than a Noise message are split.
+		data := bytes.Repeat([]byte("0123456789"), 10000)
+		go func() {
+			_, err := fooConn.Write(data)
+			assert.NoError(t, err)
+		}()
+		received := make([]byte, len(data))
+		_, err := io.ReadFull(barConn, received)
+		require.NoError(t, err)
+		require.Equal(t, data, received)
+
+		require.NoError(t, fooConn.Close())
+		require.NoError(t, barConn.Close())
+	}
+}
+
+func TestMakeAuthenticatedConnectionWithSecretConnection(t *testing.T) {
+	fooRaw, barRaw := makeKVStoreConnPair()
+	fooPrvKey, barPrvKey := ed25519.GenPrivKey(), ed25519.GenPrivKey()
+	defer fooRaw.Close()
+	defer barRaw.Close()
+
+	// A node not supporting Noise ignores the offer.
+	var fooConn AuthenticatedConnection
+	done := make(chan error)
+	go func() {
+		var err error
+		fooConn, err = MakeAuthenticatedConnection(fooRaw, fooPrvKey, true)
+		done <- err
+	}()
+	barConn, err := MakeSecretConnection(barRaw, barPrvKey)
+	require.NoError(t, err)
+	require.NoError(t, <-done)
+	assert.IsType(t, &SecretConnection{}, fooConn)
+	assert.Equal(t, fooPrvKey.PubKey(), barConn.RemotePubKey())
+}
+
+func TestNoiseConnectionRejectsBadIdentity(t *testing.T) {
+	fooRaw, barRaw := makeKVStoreConnPair()
+	defer fooRaw.Close()
+	defer barRaw.Close()
+
+	// bar signs with a key which isn't its advertised key.
+	barPrvKey := badSignerPrivKey{ed25519.GenPrivKey(), ed25519.GenPrivKey()}
+	go func() {
+		_, _ = MakeNoiseConnection(barRaw, barPrvKey, false, nil)
+	}()
+	_, err := MakeNoiseConnection(fooRaw, ed25519.GenPrivKey(), true, nil)
+	require.ErrorIs(t, err, ErrChallengeVerification)
+}
+
+// badSignerPrivKey advertises the public key of one key, but signs with
+// another.
+type badSignerPrivKey struct {
+	crypto.PrivKey
+	signer crypto.PrivKey
+}
+
+func (pk badSignerPrivKey) Sign(msg []byte) ([]byte, error) { return pk.signer.Sign(msg) }
+
+func BenchmarkNoiseConnection(b *testing.B) {
+	fooRaw, barRaw := makeKVStoreConnPair()
+	fooPrvKey, barPrvKey := ed25519.GenPrivKey(), ed25519.GenPrivKey()
+	var barConn *NoiseConnection
+	done := make(chan struct{})
+	go func() {
+		barConn, _ = MakeNoiseConnection(barRaw, barPrvKey, false, nil)
+		close(done)
+	}()
+	fooConn, err := MakeNoiseConnection(fooRaw, fooPrvKey, true, nil)
+	require.NoError(b, err)
+	<-done
+
+	go func() {
+		_, _ = io.Copy(io.Discard, barConn)
+	}()
+	data := make([]byte, 1024)
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := fooConn.Write(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+	fooConn.Close()
+	barConn.Close()
+}
@@ -0,0 +1,419 @@
+package conn
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/quic-go/quic-go"
+
+	flow "github.com/cometbft/cometbft/internal/flowrate"
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/libs/service"
+	cmtsync "github.com/cometbft/cometbft/libs/sync"
+)
+
+/*
+QUICConnection multiplexes the channels of a peer over a QUIC connection, the
+QUIC counterpart of MConnection.
+
+Each channel is sent on its own unidirectional stream, opened on the first
+message of the channel and starting with the channel id. Messages are
+length-prefixed with a uvarint. As QUIC streams are independent, a lost
+packet only delays the channel it belongs to, e.g. consensus votes don't wait
+for the retransmission of a mempool transaction.
+
+Liveness is ensured by QUIC keep-alives and idle timeout, set up by the
+transport, instead of MConnection's pings and pongs.
+
+The send and receive rates of the channels are capped as with MConnection.
+MinSendRate is ignored, since the streams are not scheduled by the
+connection. Messages are compressed as with MConnection.
+*/
+type QUICConnection struct {
+	service.BaseService
+
+	conn        *quic.Conn
+	sendMonitor *flow.Monitor
+	recvMonitor *flow.Monitor
+	channels    []*quicChannel
+	channelsIdx map[byte]*quicChannel
+	onReceive   receiveCbFunc
+	onError     errorCbFunc
+	errored     uint32
+	config      MConnConfig
+
+	// Closing quitSendRoutines will cause the sendRoutines to quit, once they
+	// have flushed their queues if flush is set.
+	quitSendRoutines chan struct{}
+	flush            atomic.Bool
+	sendRoutines     sync.WaitGroup
+
+	// cancelRecv stops accepting streams and reading them.
+	cancelRecv context.CancelFunc
+
+	// used to ensure FlushStop and OnStop
+	// are safe to call concurrently.
+	stopMtx cmtsync.Mutex
+	stopped bool
+
+	created time.Time // time of creation
+}
+
+type quicChannel struct {
+	desc          ChannelDescriptor
+	sendQueue     chan []byte
+	recentlySent  atomic.Int64
+	sendMonitor   *flow.Monitor
+	recvMonitor   *flow.Monitor
+	bytesSent     atomic.Int64
+	bytesReceived atomic.Int64
+
+	uncompressedBytesSent atomic.Int64
+	compressedBytesSent   atomic.Int64
+}
+
+// NewQUICConnection creates a multiplex connection over the given QUIC
+// connection. SendRate and RecvRate are the only fields of config in use.
+func NewQUICConnection(
+	conn *quic.Conn,
+	chDescs []*ChannelDescriptor,
+	onReceive receiveCbFunc,
+	onError errorCbFunc,
+	config MConnConfig,
+) *QUICConnection {
+	c := &QUICConnection{
+		conn:        conn,
+		sendMonitor: flow.New(0, 0),
+		recvMonitor: flow.New(0, 0),
+		channelsIdx: make(map[byte]*quicChannel, len(chDescs)),
+		onReceive:   onReceive,
+		onError:     onError,
+		config:      config,
+		created:     time.Now(),
+	}
+	for _, desc := range chDescs {
+		desc := desc.FillDefaults()
+		ch := &quicChannel{
+			desc:        desc,
+			sendQueue:   make(chan []byte, desc.SendQueueCapacity),
+			sendMonitor: flow.New(0, 0),
+			recvMonitor: flow.New(0, 0),
+		}
+		c.channels = append(c.channels, ch)
+		c.channelsIdx[desc.ID] = ch
+	}
+	c.BaseService = *service.NewBaseService(nil, "QUICConnection", c)
+	return c
+}
+
+// OnStart implements BaseService.
+func (c *QUICConnection) OnStart() error {
+	if err := c.BaseService.OnStart(); err != nil {
+		return err
+	}
+	c.quitSendRoutines = make(chan struct{})
+	for _, ch := range c.channels {
+		c.sendRoutines.Add(1)
+		go c.sendRoutine(ch)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancelRecv = cancel
+	go c.acceptRoutine(ctx)
+	return nil
+}
+
+// stopServices stops the send and receive routines. It returns true if they
+// were already stopped.
+func (c *QUICConnection) stopServices(flush bool) (alreadyStopped bool) {
+	c.stopMtx.Lock()
+	defer c.stopMtx.Unlock()
+
+	if c.stopped {
+		return true
+	}
+	c.stopped = true
+
+	c.BaseService.OnStop()
+	c.cancelRecv()
+	c.flush.Store(flush)
+	close(c.quitSendRoutines)
+	return false
+}
+
+// FlushStop replicates the logic of OnStop. It additionally ensures that all
+// successful .Send() calls are written to their streams before closing the
+// connection.
+func (c *QUICConnection) FlushStop() {
+	if c.stopServices(true) {
+		return
+	}
+	c.sendRoutines.Wait()
+	_ = c.conn.CloseWithError(0, "")
+}
+
+// OnStop implements BaseService.
+func (c *QUICConnection) OnStop() {
+	if c.stopServices(false) {
+		return
+	}
+	_ = c.conn.CloseWithError(0, "")
+}
+
+func (c *QUICConnection) String() string {
+	return fmt.Sprintf("QUICConn{%v}", c.conn.RemoteAddr())
+}
+
+// Send queues a message to be sent to the channel. It blocks until the
+// message is queued, or times out after defaultSendTimeout.
+func (c *QUICConnection) Send(chID byte, msgBytes []byte) bool {
+	if !c.IsRunning() {
+		return false
+	}
+
+	c.Logger.Debug("Send", "channel", chID, "conn", c, "msgBytes", log.NewLazySprintf("%X", msgBytes))
+
+	ch, ok := c.channelsIdx[chID]
+	if !ok {
+		c.Logger.Error(fmt.Sprintf("Cannot send bytes, unknown channel %X", chID))
+		return false
+	}
+
+	select {
+	case ch.sendQueue <- msgBytes:
+		return true
+	case <-time.After(defaultSendTimeout):
+		c.Logger.Debug("Send failed", "channel", chID, "conn", c, "msgBytes", log.NewLazySprintf("%X", msgBytes))
+		return false
+	case <-c.quitSendRoutines:
+		return false
+	}
+}
+
+// TrySend queues a message to be sent to the channel.
+// Nonblocking, returns true if successful.
+func (c *QUICConnection) TrySend(chID byte, msgBytes []byte) bool {
+	if !c.IsRunning() {
+		return false
+	}
+
+	c.Logger.Debug("TrySend", "channel", chID, "conn", c, "msgBytes", log.NewLazySprintf("%X", msgBytes))
+
+	ch, ok := c.channelsIdx[chID]
+	if !ok {
+		c.Logger.Error(fmt.Sprintf("Cannot send bytes, unknown channel %X", chID))
+		return false
+	}
+
+	select {
+	case ch.sendQueue <- msgBytes:
+		return true
+	default:
+		return false
+	}
+}
+
+// CanSend returns true if you can send more data onto the chID, false
+// otherwise. Use only as a heuristic.
+func (c *QUICConnection) CanSend(chID byte) bool {
+	if !c.IsRunning() {
+		return false
+	}
+
+	ch, ok := c.channelsIdx[chID]
+	if !ok {
+		c.Logger.Error(fmt.Sprintf("Unknown channel %X", chID))
+		return false
+	}
+	return len(ch.sendQueue) < cap(ch.sendQueue)
+}
+
+// Status returns the status of the connection, as MConnection.Status.
+func (c *QUICConnection) Status() ConnectionStatus {
+	var status ConnectionStatus
+	status.Duration = time.Since(c.created)
+	status.SendMonitor = c.sendMonitor.Status()
+	status.RecvMonitor = c.recvMonitor.Status()
+	status.Channels = make([]ChannelStatus, len(c.channels))
+	for i, ch := range c.channels {
+		status.Channels[i] = ChannelStatus{
+			ID:                    ch.desc.ID,
+			SendQueueCapacity:     cap(ch.sendQueue),
+			SendQueueSize:         len(ch.sendQueue),
+			Priority:              ch.desc.Priority,
+			RecentlySent:          ch.recentlySent.Load(),
+			Compression:           ch.desc.Compression,
+			UncompressedBytesSent: ch.uncompressedBytesSent.Load(),
+			CompressedBytesSent:   ch.compressedBytesSent.Load(),
+			BytesSent:             ch.bytesSent.Load(),
+			BytesReceived:         ch.bytesReceived.Load(),
+			CurSendRate:           ch.sendMonitor.Status().CurRate,
+			CurRecvRate:           ch.recvMonitor.Status().CurRate,
+		}
+	}
+	return status
+}
+
+// sendRoutine writes the messages queued for the channel to its stream.
+func (c *QUICConnection) sendRoutine(ch *quicChannel) {
+	defer c.sendRoutines.Done()
+	defer c._recover()
+
+	var stream *quic.SendStream
+	defer func() {
+		if stream != nil {
+			_ = stream.Close()
+		}
+	}()
+
+	send := func(msgBytes []byte) error {
+		if stream == nil {
+			var err error
+			stream, err = c.conn.OpenUniStreamSync(c.conn.Context())
+			if err != nil {
+				return err
+			}
+			if _, err := stream.Write([]byte{ch.desc.ID}); err != nil {
+				return err
+			}
+		}
+		if ch.desc.Compression != CompressionNone {
+			compressed := compressMessage(ch.desc.Compression, msgBytes)
+			ch.uncompressedBytesSent.Add(int64(len(msgBytes)))
+			ch.compressedBytesSent.Add(int64(len(compressed)))
+			msgBytes = compressed
+		}
+		frame := binary.AppendUvarint(make([]byte, 0, binary.MaxVarintLen64+len(msgBytes)), uint64(len(msgBytes)))
+		frame = append(frame, msgBytes...)
+		ch.sendMonitor.Limit(len(frame), ch.desc.SendRate, true)
+		c.sendMonitor.Limit(len(frame), atomic.LoadInt64(&c.config.SendRate), true)
+		n, err := stream.Write(frame)
+		c.sendMonitor.Update(n)
+		ch.sendMonitor.Update(n)
+		ch.bytesSent.Add(int64(n))
+		ch.recentlySent.Add(int64(n))
+		return err
+	}
+
+	for {
+		select {
+		case msgBytes := <-ch.sendQueue:
+			if err := send(msgBytes); err != nil {
+				c.stopForError(err)
+				return
+			}
+		case <-c.quitSendRoutines:
+			if !c.flush.Load() {
+				return
+			}
+			for {
+				select {
+				case msgBytes := <-ch.sendQueue:
+					if err := send(msgBytes); err != nil {
+						return
+					}
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// acceptRoutine accepts the streams opened by the peer.
+func (c *QUICConnection) acceptRoutine(ctx context.Context) {
+	for {
+		stream, err := c.conn.AcceptUniStream(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				c.stopForError(err)
+			}
+			return
+		}
+		go c.recvRoutine(ctx, stream)
+	}
+}
+
+// recvRoutine reads the messages of a channel from its stream.
+func (c *QUICConnection) recvRoutine(ctx context.Context, stream *quic.ReceiveStream) {
+	defer c._recover()
+
+	err := c.readStream(stream)
+	if err != nil && ctx.Err() == nil {
+		stream.CancelRead(0)
+		c.stopForError(err)
+	}
+}
+
+func (c *QUICConnection) readStream(stream *quic.ReceiveStream) error {
+	r := bufio.NewReaderSize(stream, minReadBufferSize)
+
+	chID, err := r.ReadByte()
+	if err != nil {
+		return err
+	}
+	ch, ok := c.channelsIdx[chID]
+	if !ok {
+		return fmt.Errorf("unknown channel %X", chID)
+	}
+
+	for {
+		size, err := binary.ReadUvarint(r)
+		if errors.Is(err, io.EOF) {
+			// The peer is done sending on the channel.
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if size > uint64(ch.desc.RecvMessageCapacity) {
+			return fmt.Errorf("received message exceeds available capacity: %v < %v",
+				ch.desc.RecvMessageCapacity, size)
+		}
+		ch.recvMonitor.Limit(int(size), ch.desc.RecvRate, true)
+		c.recvMonitor.Limit(int(size), atomic.LoadInt64(&c.config.RecvRate), true)
+		msgBytes := make([]byte, size)
+		n, err := io.ReadFull(r, msgBytes)
+		c.recvMonitor.Update(n)
+		ch.recvMonitor.Update(n)
+		ch.bytesReceived.Add(int64(n))
+		if err != nil {
+			return err
+		}
+		if ch.desc.Compression != CompressionNone {
+			// RecvMessageCapacity includes the compression header.
+			msgBytes, err = decompressMessage(msgBytes, ch.desc.RecvMessageCapacity-CompressionHeaderSize)
+			if err != nil {
+				return err
+			}
+		}
+		c.Logger.Debug("Received bytes", "chID", chID, "msgBytes", log.NewLazySprintf("%X", msgBytes))
+		c.onReceive(chID, msgBytes)
+	}
+}
+
+// Catch panics, usually caused by the onReceive callback.
+func (c *QUICConnection) _recover() {
+	if r := recover(); r != nil {
+		c.Logger.Error("QUICConnection panicked", "err", r, "stack", string(debug.Stack()))
+		c.stopForError(fmt.Errorf("recovered from panic: %v", r))
+	}
+}
+
+func (c *QUICConnection) stopForError(r any) {
+	if err := c.Stop(); err != nil {
+		c.Logger.Error("Error stopping connection", "err", err)
+	}
+	if atomic.CompareAndSwapUint32(&c.errored, 0, 1) {
+		if c.onError != nil {
+			c.onError(r)
+		}
+	}
+}
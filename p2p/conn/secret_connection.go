@@ -99,19 +99,74 @@ type SecretConnection struct {
 // Caller should call conn.Close()
 // See docs/sts-final.pdf for more information.
 func MakeSecretConnection(conn io.ReadWriteCloser, locPrivKey crypto.PrivKey) (*SecretConnection, error) {
-	locPubKey := locPrivKey.PubKey()
-
 	// Generate ephemeral keys for perfect forward secrecy.
 	locEphPub, locEphPriv := genEphKeys()
 
 	// Write local ephemeral pubkey and receive one too.
 	// NOTE: every 32-byte string is accepted as a Curve25519 public key (see
 	// DJB's Curve25519 paper: http://cr.yp.to/ecdh/curve25519-20060209.pdf)
-	remEphPub, err := shareEphPubKey(conn, locEphPub)
+	remEphPub, _, err := shareEphPubKey(conn, locEphPub, nil)
 	if err != nil {
 		return nil, err
 	}
 
+	return makeSecretConnection(conn, locPrivKey, locEphPub, locEphPriv, remEphPub)
+}
+
+// AuthenticatedConnection is a net.Conn authenticated by the key of the
+// remote node: a SecretConnection or a NoiseConnection.
+type AuthenticatedConnection interface {
+	net.Conn
+	RemotePubKey() crypto.PubKey
+}
+
+// MakeAuthenticatedConnection performs the handshake of a SecretConnection,
+// offering to switch to a NoiseConnection if noise is true.
+//
+// The offer is the Noise protocol name, sent after the ephemeral key of the
+// SecretConnection handshake, which nodes not supporting Noise ignore. If both
+// nodes offer Noise, they perform a Noise handshake instead of carrying on with
+// the SecretConnection one. The node with the lower ephemeral key is the
+// initiator, and the ephemeral keys are the prologue. Otherwise, the
+// SecretConnection handshake carries on.
+func MakeAuthenticatedConnection(
+	conn io.ReadWriteCloser,
+	locPrivKey crypto.PrivKey,
+	noise bool,
+) (AuthenticatedConnection, error) {
+	var offer []byte
+	if noise {
+		offer = []byte(NoiseProtocolName)
+	}
+
+	locEphPub, locEphPriv := genEphKeys()
+	remEphPub, remOffer, err := shareEphPubKey(conn, locEphPub, offer)
+	if err != nil {
+		return nil, err
+	}
+
+	if noise && bytes.Equal(offer, remOffer) {
+		loEphPub, hiEphPub := sort32(locEphPub, remEphPub)
+		initiator := bytes.Equal(locEphPub[:], loEphPub[:])
+		prologue := make([]byte, 0, len(noisePrologue)+2*len(loEphPub))
+		prologue = append(prologue, noisePrologue...)
+		prologue = append(prologue, loEphPub[:]...)
+		prologue = append(prologue, hiEphPub[:]...)
+		return MakeNoiseConnection(conn, locPrivKey, initiator, prologue)
+	}
+
+	return makeSecretConnection(conn, locPrivKey, locEphPub, locEphPriv, remEphPub)
+}
+
+// makeSecretConnection carries on with the handshake once the ephemeral keys
+// were exchanged.
+func makeSecretConnection(
+	conn io.ReadWriteCloser,
+	locPrivKey crypto.PrivKey,
+	locEphPub, locEphPriv, remEphPub *[32]byte,
+) (*SecretConnection, error) {
+	locPubKey := locPrivKey.PubKey()
+
 	// Sort by lexical order.
 	loEphPub, hiEphPub := sort32(locEphPub, remEphPub)
 
@@ -316,12 +371,15 @@ func genEphKeys() (ephPub, ephPriv *[32]byte) {
 	return ephPub, ephPriv
 }
 
-func shareEphPubKey(conn io.ReadWriter, locEphPub *[32]byte) (remEphPub *[32]byte, err error) {
+// shareEphPubKey sends our ephemeral pubkey followed by ext, and receives the
+// ephemeral pubkey of the remote node followed by its ext. Nodes which don't
+// know about ext only read the pubkey.
+func shareEphPubKey(conn io.ReadWriter, locEphPub *[32]byte, ext []byte) (remEphPub *[32]byte, remExt []byte, err error) {
 	// Send our pubkey and receive theirs in tandem.
 	trs, _ := async.Parallel(
 		func(_ int) (val any, abort bool, err error) {
 			lc := *locEphPub
-			_, err = protoio.NewDelimitedWriter(conn).WriteMsg(&gogotypes.BytesValue{Value: lc[:]})
+			_, err = protoio.NewDelimitedWriter(conn).WriteMsg(&gogotypes.BytesValue{Value: append(lc[:], ext...)})
 			if err != nil {
 				return nil, true, err // abort
 			}
@@ -336,19 +394,28 @@ func shareEphPubKey(conn io.ReadWriter, locEphPub *[32]byte) (remEphPub *[32]byt
 
 			var _remEphPub [32]byte
 			copy(_remEphPub[:], bytes.Value)
-			return _remEphPub, false, nil
+			var _remExt []byte
+			if len(bytes.Value) > len(_remEphPub) {
+				_remExt = bytes.Value[len(_remEphPub):]
+			}
+			return ephPubKeyMessage{pub: _remEphPub, ext: _remExt}, false, nil
 		},
 	)
 
 	// If error:
 	if trs.FirstError() != nil {
 		err = trs.FirstError()
-		return remEphPub, err
+		return remEphPub, nil, err
 	}
 
 	// Otherwise:
-	_remEphPub := trs.FirstValue().([32]byte)
-	return &_remEphPub, nil
+	msg := trs.FirstValue().(ephPubKeyMessage)
+	return &msg.pub, msg.ext, nil
+}
+
+type ephPubKeyMessage struct {
+	pub [32]byte
+	ext []byte
 }
 
 func deriveSecrets(
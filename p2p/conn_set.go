@@ -10,6 +10,8 @@ import (
 type ConnSet interface {
 	Has(conn net.Conn) bool
 	HasIP(ip net.IP) bool
+	// NumIPsIn returns the number of connections with an IP in subnet.
+	NumIPsIn(subnet *net.IPNet) int
 	Set(conn net.Conn, ip []net.IP)
 	Remove(conn net.Conn)
 	RemoveAddr(addr net.Addr)
@@ -57,6 +59,23 @@ func (cs *connSet) HasIP(ip net.IP) bool {
 	return false
 }
 
+func (cs *connSet) NumIPsIn(subnet *net.IPNet) int {
+	cs.RLock()
+	defer cs.RUnlock()
+
+	n := 0
+	for _, c := range cs.conns {
+		for _, ip := range c.ips {
+			if subnet.Contains(ip) {
+				n++
+				break
+			}
+		}
+	}
+
+	return n
+}
+
 func (cs *connSet) Remove(c net.Conn) {
 	cs.Lock()
 	defer cs.Unlock()
@@ -274,6 +274,15 @@ func (e ErrInvalidRPCAddress) Error() string {
 	return fmt.Sprintf("rpc address must be valid ASCII text without tabs, but got %v", e.RPCAddress)
 }
 
+type ErrInvalidCompression struct {
+	Compression []string
+}
+
+func (e ErrInvalidCompression) Error() string {
+	return fmt.Sprintf("compression must be at most %d algorithms in valid ASCII text without tabs, but got %v",
+		maxNumCompressions, e.Compression)
+}
+
 type ErrInvalidNodeInfoType struct {
 	Type     string
 	Expected string
@@ -332,3 +341,15 @@ type ErrInvalidPeerIDLength struct {
 func (e ErrInvalidPeerIDLength) Error() string {
 	return fmt.Sprintf("invalid peer ID length, got %d, expected %d", e.Expected, e.Got)
 }
+
+// ErrPeerScoreTooLow is the reason for disconnecting a peer whose score fell
+// below a threshold.
+type ErrPeerScoreTooLow struct {
+	Score     float64
+	Threshold float64
+	Behavior  PeerBehavior
+}
+
+func (e ErrPeerScoreTooLow) Error() string {
+	return fmt.Sprintf("peer score %.2f is below %.2f after %s", e.Score, e.Threshold, e.Behavior.Reason)
+}
@@ -0,0 +1,605 @@
+package p2p
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math/rand"
+	"net"
+	"os"
+	"time"
+
+	cmtsync "github.com/cometbft/cometbft/libs/sync"
+)
+
+const (
+	defaultRetransmitTimeout = 200 * time.Millisecond
+
+	// memoryListenerBacklog is the number of connections waiting to be
+	// accepted before the dialers block.
+	memoryListenerBacklog = 16
+	// memoryFirstEphemeralPort is the port of the first dialed connection.
+	memoryFirstEphemeralPort = 32768
+	// memoryPipeCapacity is the number of bytes written to a connection and
+	// not read yet by the other node, in flight or buffered, above which
+	// writes block, like those of a TCP connection with a full window.
+	memoryPipeCapacity = 4 << 20 // 4 MiB
+)
+
+var (
+	// ErrMemoryConnRefused is returned when dialing an address of a
+	// MemoryNetwork nobody listens on.
+	ErrMemoryConnRefused = errors.New("connection refused")
+	// ErrMemoryNetUnreachable is returned when dialing a node of a
+	// MemoryNetwork the link with which is down.
+	ErrMemoryNetUnreachable = errors.New("network unreachable")
+)
+
+// Link is the model of the link between two nodes of a MemoryNetwork. The zero
+// value is a perfect link.
+//
+// As the connections are streams, like TCP connections, the data is never
+// lost nor reordered: lost data is delivered after RetransmitTimeout, and the
+// data sent while the link is down is delivered once it is up again, unless
+// the connection is closed before. Nor is it dropped when the other node is
+// slow to read it: writes block while memoryPipeCapacity bytes are unread.
+type Link struct {
+	// Latency is the time taken by the data to reach the other node.
+	Latency time.Duration
+	// Jitter is the maximum random delay added to Latency.
+	Jitter time.Duration
+	// Loss is the probability a write is lost, and delivered after
+	// RetransmitTimeout more.
+	Loss              float64
+	RetransmitTimeout time.Duration
+	// Bandwidth is the number of bytes sent per second, or 0 for no limit.
+	// Writes block until their data is sent.
+	Bandwidth int64
+	// Down cuts the link: the nodes can't dial each other, and the data of
+	// the existing connections is held until the link is up again.
+	Down bool
+}
+
+// delay returns the random delay of a write, on top of Latency.
+func (l Link) delay(rnd *rand.Rand) time.Duration {
+	var d time.Duration
+	if l.Jitter > 0 {
+		d += time.Duration(rnd.Int63n(int64(l.Jitter)))
+	}
+	if l.Loss > 0 && rnd.Float64() < l.Loss {
+		if l.RetransmitTimeout > 0 {
+			d += l.RetransmitTimeout
+		} else {
+			d += defaultRetransmitTimeout
+		}
+	}
+	return d
+}
+
+// linkKey identifies the link between two nodes.
+type linkKey struct {
+	a, b ID
+}
+
+func newLinkKey(a, b ID) linkKey {
+	if b < a {
+		a, b = b, a
+	}
+	return linkKey{a, b}
+}
+
+// MemoryNetwork is an in-memory network, simulating the links between its
+// nodes, for many nodes to run in a single process. The nodes are identified
+// by the ID of the addresses they listen on.
+//
+// A MultiplexTransport listens on and dials the network instead of TCP with
+// MultiplexTransportMemoryNetwork. The random delays of each direction of a
+// connection are drawn from a source of its own, seeded by the seed of the
+// network, the nodes and the number of connections dialed between them before,
+// for tests to be reproducible whatever the order of the writes to the
+// different connections.
+type MemoryNetwork struct {
+	mtx         cmtsync.Mutex
+	seed        int64
+	dials       map[linkKey]int
+	listeners   map[string]*memoryListener
+	defaultLink Link
+	links       map[linkKey]Link
+	partition   map[ID]int
+	nextPort    int
+
+	// changed is closed and replaced whenever a link changes.
+	changed chan struct{}
+}
+
+// NewMemoryNetwork returns a network of perfect links, with the random delays
+// drawn from a source seeded by seed.
+func NewMemoryNetwork(seed int64) *MemoryNetwork {
+	return &MemoryNetwork{
+		seed:      seed,
+		dials:     make(map[linkKey]int),
+		listeners: make(map[string]*memoryListener),
+		links:     make(map[linkKey]Link),
+		nextPort:  memoryFirstEphemeralPort,
+		changed:   make(chan struct{}),
+	}
+}
+
+// SetDefaultLink sets the model of the links without one set by SetLink.
+func (mn *MemoryNetwork) SetDefaultLink(link Link) {
+	mn.mtx.Lock()
+	defer mn.mtx.Unlock()
+	mn.defaultLink = link
+	mn.notifyChange()
+}
+
+// SetLink sets the model of the link between the nodes a and b, in both
+// directions.
+func (mn *MemoryNetwork) SetLink(a, b ID, link Link) {
+	mn.mtx.Lock()
+	defer mn.mtx.Unlock()
+	mn.links[newLinkKey(a, b)] = link
+	mn.notifyChange()
+}
+
+// Partition cuts the links between the nodes of different groups. The nodes
+// which aren't in any group form one more group.
+func (mn *MemoryNetwork) Partition(groups ...[]ID) {
+	mn.mtx.Lock()
+	defer mn.mtx.Unlock()
+	mn.partition = make(map[ID]int)
+	for i, group := range groups {
+		for _, id := range group {
+			mn.partition[id] = i + 1
+		}
+	}
+	mn.notifyChange()
+}
+
+// Heal removes the partition set by Partition.
+func (mn *MemoryNetwork) Heal() {
+	mn.mtx.Lock()
+	defer mn.mtx.Unlock()
+	mn.partition = nil
+	mn.notifyChange()
+}
+
+// link returns the model of the link between a and b, down if they are in
+// different groups of the partition.
+// CONTRACT: mn.mtx is locked.
+func (mn *MemoryNetwork) link(a, b ID) Link {
+	link, ok := mn.links[newLinkKey(a, b)]
+	if !ok {
+		link = mn.defaultLink
+	}
+	if mn.partition != nil && mn.partition[a] != mn.partition[b] {
+		link.Down = true
+	}
+	return link
+}
+
+// rand returns the source of the random delays of the data sent from a to b
+// on the n-th connection dialed between them.
+func (mn *MemoryNetwork) rand(from, to ID, n int) *rand.Rand {
+	h := fnv.New64a()
+	_ = binary.Write(h, binary.BigEndian, mn.seed)
+	_, _ = h.Write([]byte(from))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(to))
+	_ = binary.Write(h, binary.BigEndian, int64(n))
+	return rand.New(rand.NewSource(int64(h.Sum64()))) //nolint:gosec // reproducible delays
+}
+
+// CONTRACT: mn.mtx is locked.
+func (mn *MemoryNetwork) notifyChange() {
+	close(mn.changed)
+	mn.changed = make(chan struct{})
+}
+
+// Listen returns a listener accepting the connections dialed to addr.
+func (mn *MemoryNetwork) Listen(addr NetAddress) (net.Listener, error) {
+	mn.mtx.Lock()
+	defer mn.mtx.Unlock()
+
+	key := (&net.TCPAddr{IP: addr.IP, Port: int(addr.Port)}).String()
+	if _, ok := mn.listeners[key]; ok {
+		return nil, fmt.Errorf("listen %v: address already in use", key)
+	}
+	ln := &memoryListener{
+		network: mn,
+		id:      addr.ID,
+		addr:    &net.TCPAddr{IP: addr.IP, Port: int(addr.Port)},
+		acceptc: make(chan net.Conn, memoryListenerBacklog),
+		closec:  make(chan struct{}),
+	}
+	mn.listeners[key] = ln
+	return ln, nil
+}
+
+// Dial connects the node listening on from to the node listening on to.
+func (mn *MemoryNetwork) Dial(from, to NetAddress) (net.Conn, error) {
+	mn.mtx.Lock()
+	ln, ok := mn.listeners[(&net.TCPAddr{IP: to.IP, Port: int(to.Port)}).String()]
+	if !ok {
+		mn.mtx.Unlock()
+		return nil, fmt.Errorf("dial %v: %w", to.DialString(), ErrMemoryConnRefused)
+	}
+	if mn.link(from.ID, ln.id).Down {
+		mn.mtx.Unlock()
+		return nil, fmt.Errorf("dial %v: %w", to.DialString(), ErrMemoryNetUnreachable)
+	}
+	localAddr := &net.TCPAddr{IP: from.IP, Port: mn.nextPort}
+	mn.nextPort++
+	key := newLinkKey(from.ID, ln.id)
+	n := mn.dials[key]
+	mn.dials[key]++
+	toDialer := newMemoryPipe(mn, ln.id, from.ID, mn.rand(ln.id, from.ID, n))
+	toListener := newMemoryPipe(mn, from.ID, ln.id, mn.rand(from.ID, ln.id, n))
+	mn.mtx.Unlock()
+
+	dialerConn := &memoryConn{in: toDialer, out: toListener, localAddr: localAddr, remoteAddr: ln.addr}
+	listenerConn := &memoryConn{in: toListener, out: toDialer, localAddr: ln.addr, remoteAddr: localAddr}
+
+	select {
+	case ln.acceptc <- listenerConn:
+		return dialerConn, nil
+	case <-ln.closec:
+		_ = dialerConn.Close()
+		_ = listenerConn.Close()
+		return nil, fmt.Errorf("dial %v: %w", to.DialString(), ErrMemoryConnRefused)
+	}
+}
+
+// memoryListener implements net.Listener.
+type memoryListener struct {
+	network *MemoryNetwork
+	id      ID
+	addr    *net.TCPAddr
+	acceptc chan net.Conn
+	closec  chan struct{}
+
+	mtx    cmtsync.Mutex
+	closed bool
+}
+
+var _ net.Listener = (*memoryListener)(nil)
+
+func (ln *memoryListener) Accept() (net.Conn, error) {
+	select {
+	case c := <-ln.acceptc:
+		return c, nil
+	case <-ln.closec:
+		return nil, net.ErrClosed
+	}
+}
+
+func (ln *memoryListener) Close() error {
+	ln.mtx.Lock()
+	defer ln.mtx.Unlock()
+	if ln.closed {
+		return net.ErrClosed
+	}
+	ln.closed = true
+	close(ln.closec)
+
+	ln.network.mtx.Lock()
+	delete(ln.network.listeners, ln.addr.String())
+	ln.network.mtx.Unlock()
+
+	// Refuse the connections not accepted yet.
+	for {
+		select {
+		case c := <-ln.acceptc:
+			_ = c.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+func (ln *memoryListener) Addr() net.Addr { return ln.addr }
+
+// memorySegment is the data of a write, or the end of the stream if fin.
+type memorySegment struct {
+	data      []byte
+	deliverAt time.Time
+	fin       bool
+}
+
+// memoryPipe carries the data sent by a node to another one, delivering it
+// according to the model of their link.
+type memoryPipe struct {
+	network  *MemoryNetwork
+	from, to ID
+
+	mtx         cmtsync.Mutex
+	rnd         *rand.Rand // source of the random delays of the writes
+	queue       []memorySegment
+	unread      int           // bytes in queue and buf
+	drained     chan struct{} // closed and replaced when data is read from buf
+	queued      chan struct{} // signals a new segment in queue
+	sentAt      time.Time     // when the data written so far is sent
+	deliveredAt time.Time     // when the data written so far is delivered
+
+	buf      bytes.Buffer
+	readable chan struct{} // signals new data in buf, a new deadline or EOF
+	eof      bool
+
+	readDeadline  time.Time
+	writeDeadline time.Time
+
+	writeClosed bool
+	readClosed  chan struct{}
+}
+
+func newMemoryPipe(network *MemoryNetwork, from, to ID, rnd *rand.Rand) *memoryPipe {
+	p := &memoryPipe{
+		network:    network,
+		from:       from,
+		to:         to,
+		rnd:        rnd,
+		drained:    make(chan struct{}),
+		queued:     make(chan struct{}, 1),
+		readable:   make(chan struct{}, 1),
+		readClosed: make(chan struct{}),
+	}
+	go p.deliverRoutine()
+	return p
+}
+
+func memoryNotify(c chan struct{}) {
+	select {
+	case c <- struct{}{}:
+	default:
+	}
+}
+
+// deadlineTimer returns a channel receiving at deadline, or nil if there's no
+// deadline.
+func deadlineTimer(deadline time.Time) (<-chan time.Time, func() bool) {
+	if deadline.IsZero() {
+		return nil, func() bool { return false }
+	}
+	timer := time.NewTimer(time.Until(deadline))
+	return timer.C, timer.Stop
+}
+
+// write queues data for delivery, blocking while memoryPipeCapacity bytes
+// are unread. A write larger than the capacity is queued once all the data
+// written before is read.
+func (p *memoryPipe) write(data []byte) (int, error) {
+	for {
+		p.mtx.Lock()
+		if p.writeClosed {
+			p.mtx.Unlock()
+			return 0, net.ErrClosed
+		}
+		select {
+		case <-p.readClosed:
+			p.mtx.Unlock()
+			return 0, io.ErrClosedPipe
+		default:
+		}
+		deadline := p.writeDeadline
+		if !deadline.IsZero() && !time.Now().Before(deadline) {
+			p.mtx.Unlock()
+			return 0, os.ErrDeadlineExceeded
+		}
+		if p.unread == 0 || p.unread+len(data) <= memoryPipeCapacity {
+			break // p.mtx stays locked
+		}
+		drained := p.drained
+		p.mtx.Unlock()
+
+		timeout, stop := deadlineTimer(deadline)
+		select {
+		case <-drained:
+		case <-timeout:
+		case <-p.readClosed:
+		}
+		stop()
+	}
+
+	p.network.mtx.Lock()
+	link := p.network.link(p.from, p.to)
+	p.network.mtx.Unlock()
+	delay := link.delay(p.rnd)
+
+	now := time.Now()
+	if p.sentAt.Before(now) {
+		p.sentAt = now
+	}
+	if link.Bandwidth > 0 {
+		p.sentAt = p.sentAt.Add(time.Duration(int64(len(data)) * int64(time.Second) / link.Bandwidth))
+	}
+	sentAt := p.sentAt
+	deliverAt := sentAt.Add(link.Latency + delay)
+	if deliverAt.Before(p.deliveredAt) {
+		deliverAt = p.deliveredAt
+	}
+	p.deliveredAt = deliverAt
+	p.queue = append(p.queue, memorySegment{data: append([]byte{}, data...), deliverAt: deliverAt})
+	p.unread += len(data)
+	memoryNotify(p.queued)
+	p.mtx.Unlock()
+
+	// Block until the data is sent.
+	if wait := time.Until(sentAt); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-p.readClosed:
+			return 0, io.ErrClosedPipe
+		}
+	}
+	return len(data), nil
+}
+
+func (p *memoryPipe) read(data []byte) (int, error) {
+	for {
+		p.mtx.Lock()
+		select {
+		case <-p.readClosed:
+			p.mtx.Unlock()
+			return 0, net.ErrClosed
+		default:
+		}
+		if p.buf.Len() > 0 {
+			n, err := p.buf.Read(data)
+			p.unread -= n
+			close(p.drained)
+			p.drained = make(chan struct{})
+			p.mtx.Unlock()
+			return n, err
+		}
+		if p.eof {
+			p.mtx.Unlock()
+			return 0, io.EOF
+		}
+		deadline := p.readDeadline
+		p.mtx.Unlock()
+
+		if !deadline.IsZero() && !time.Now().Before(deadline) {
+			return 0, os.ErrDeadlineExceeded
+		}
+		timeout, stop := deadlineTimer(deadline)
+		select {
+		case <-p.readable:
+		case <-timeout:
+		case <-p.readClosed:
+		}
+		stop()
+	}
+}
+
+// closeWrite ends the stream, once the data written so far is delivered.
+func (p *memoryPipe) closeWrite() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if p.writeClosed {
+		return
+	}
+	p.writeClosed = true
+	deliverAt := p.deliveredAt
+	if now := time.Now(); deliverAt.Before(now) {
+		deliverAt = now
+	}
+	p.queue = append(p.queue, memorySegment{deliverAt: deliverAt, fin: true})
+	memoryNotify(p.queued)
+}
+
+// closeRead discards the data not read yet, and stops the delivery.
+func (p *memoryPipe) closeRead() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	select {
+	case <-p.readClosed:
+	default:
+		close(p.readClosed)
+		p.buf.Reset()
+	}
+}
+
+// deliverRoutine delivers the segments of the queue in order, once their
+// delivery time is reached and the link is up.
+func (p *memoryPipe) deliverRoutine() {
+	for {
+		p.mtx.Lock()
+		if len(p.queue) == 0 {
+			p.mtx.Unlock()
+			select {
+			case <-p.queued:
+				continue
+			case <-p.readClosed:
+				return
+			}
+		}
+		seg := p.queue[0]
+		p.mtx.Unlock()
+
+		if wait := time.Until(seg.deliverAt); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-p.readClosed:
+				timer.Stop()
+				return
+			}
+		}
+
+		// Hold the data while the link is down.
+		for {
+			p.network.mtx.Lock()
+			down := p.network.link(p.from, p.to).Down
+			changed := p.network.changed
+			p.network.mtx.Unlock()
+			if !down {
+				break
+			}
+			select {
+			case <-changed:
+			case <-p.readClosed:
+				return
+			}
+		}
+
+		p.mtx.Lock()
+		p.queue = p.queue[1:]
+		if seg.fin {
+			p.eof = true
+		} else {
+			p.buf.Write(seg.data)
+		}
+		memoryNotify(p.readable)
+		p.mtx.Unlock()
+		if seg.fin {
+			return
+		}
+	}
+}
+
+// memoryConn is a connection of a MemoryNetwork. It implements net.Conn.
+type memoryConn struct {
+	in, out    *memoryPipe
+	localAddr  net.Addr
+	remoteAddr net.Addr
+}
+
+var _ net.Conn = (*memoryConn)(nil)
+
+func (c *memoryConn) Read(data []byte) (int, error)  { return c.in.read(data) }
+func (c *memoryConn) Write(data []byte) (int, error) { return c.out.write(data) }
+func (c *memoryConn) LocalAddr() net.Addr            { return c.localAddr }
+func (c *memoryConn) RemoteAddr() net.Addr           { return c.remoteAddr }
+
+func (c *memoryConn) Close() error {
+	c.out.closeWrite()
+	c.in.closeRead()
+	return nil
+}
+
+func (c *memoryConn) SetDeadline(t time.Time) error {
+	if err := c.SetReadDeadline(t); err != nil {
+		return err
+	}
+	return c.SetWriteDeadline(t)
+}
+
+func (c *memoryConn) SetReadDeadline(t time.Time) error {
+	c.in.mtx.Lock()
+	c.in.readDeadline = t
+	c.in.mtx.Unlock()
+	memoryNotify(c.in.readable)
+	return nil
+}
+
+func (c *memoryConn) SetWriteDeadline(t time.Time) error {
+	c.out.mtx.Lock()
+	c.out.writeDeadline = t
+	c.out.mtx.Unlock()
+	return nil
+}
@@ -0,0 +1,301 @@
+package p2p
+
+import (
+	"io"
+	"math/rand"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	p2pproto "github.com/cometbft/cometbft/api/cometbft/p2p/v1"
+	"github.com/cometbft/cometbft/crypto/ed25519"
+)
+
+func testMemoryAddr(t *testing.T, hostPort string) NetAddress {
+	t.Helper()
+	id := PubKeyToID(ed25519.GenPrivKey().PubKey())
+	addr, err := NewNetAddressString(IDAddressString(id, hostPort))
+	require.NoError(t, err)
+	return *addr
+}
+
+// testMemoryConnPair returns a connection dialed by a to b, and the
+// connection accepted by b.
+func testMemoryConnPair(t *testing.T, network *MemoryNetwork, a, b NetAddress) (net.Conn, net.Conn) {
+	t.Helper()
+	ln, err := network.Listen(b)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+
+	dialed, err := network.Dial(a, b)
+	require.NoError(t, err)
+	accepted, err := ln.Accept()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = dialed.Close()
+		_ = accepted.Close()
+	})
+	return dialed, accepted
+}
+
+func TestMemoryNetworkDial(t *testing.T) {
+	network := NewMemoryNetwork(0)
+	a, b := testMemoryAddr(t, "10.0.0.1:26656"), testMemoryAddr(t, "10.0.0.2:26656")
+
+	_, err := network.Dial(a, b)
+	require.ErrorIs(t, err, ErrMemoryConnRefused)
+
+	dialed, accepted := testMemoryConnPair(t, network, a, b)
+	assert.Equal(t, "10.0.0.2:26656", dialed.RemoteAddr().String())
+	assert.Equal(t, dialed.LocalAddr(), accepted.RemoteAddr())
+	assert.Equal(t, "10.0.0.1", accepted.RemoteAddr().(*net.TCPAddr).IP.String())
+
+	_, err = network.Listen(b)
+	require.Error(t, err, "address already in use")
+
+	go func() {
+		_, err := dialed.Write([]byte("hello"))
+		assert.NoError(t, err)
+		assert.NoError(t, dialed.Close())
+	}()
+	data, err := io.ReadAll(accepted)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	_, err = dialed.Write([]byte("closed"))
+	require.ErrorIs(t, err, net.ErrClosed)
+	_, err = accepted.Write([]byte("peer closed"))
+	require.ErrorIs(t, err, io.ErrClosedPipe)
+}
+
+func TestMemoryNetworkDeadline(t *testing.T) {
+	network := NewMemoryNetwork(0)
+	a, b := testMemoryAddr(t, "10.0.0.1:26656"), testMemoryAddr(t, "10.0.0.2:26656")
+	dialed, _ := testMemoryConnPair(t, network, a, b)
+
+	require.NoError(t, dialed.SetReadDeadline(time.Now().Add(50*time.Millisecond)))
+	_, err := dialed.Read(make([]byte, 1))
+	require.ErrorIs(t, err, os.ErrDeadlineExceeded)
+	var netErr net.Error
+	require.ErrorAs(t, err, &netErr)
+	assert.True(t, netErr.Timeout())
+}
+
+func TestMemoryNetworkLatencyAndBandwidth(t *testing.T) {
+	network := NewMemoryNetwork(0)
+	a, b := testMemoryAddr(t, "10.0.0.1:26656"), testMemoryAddr(t, "10.0.0.2:26656")
+	network.SetLink(a.ID, b.ID, Link{
+		Latency:   100 * time.Millisecond,
+		Bandwidth: 100_000,
+	})
+	dialed, accepted := testMemoryConnPair(t, network, a, b)
+
+	// Sending 10 kB takes 100 ms, and delivering them 100 ms more.
+	start := time.Now()
+	_, err := dialed.Write(make([]byte, 10_000))
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+
+	_, err = io.ReadFull(accepted, make([]byte, 10_000))
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+}
+
+func TestMemoryNetworkLoss(t *testing.T) {
+	network := NewMemoryNetwork(0)
+	a, b := testMemoryAddr(t, "10.0.0.1:26656"), testMemoryAddr(t, "10.0.0.2:26656")
+	network.SetLink(a.ID, b.ID, Link{
+		Latency:           10 * time.Millisecond,
+		Loss:              1,
+		RetransmitTimeout: 100 * time.Millisecond,
+	})
+	dialed, accepted := testMemoryConnPair(t, network, a, b)
+
+	// The lost write is delivered after the retransmit timeout.
+	start := time.Now()
+	_, err := dialed.Write([]byte("lost"))
+	require.NoError(t, err)
+	data := make([]byte, 4)
+	_, err = io.ReadFull(accepted, data)
+	require.NoError(t, err)
+	assert.Equal(t, "lost", string(data))
+	assert.GreaterOrEqual(t, time.Since(start), 110*time.Millisecond)
+}
+
+func TestMemoryNetworkOrdering(t *testing.T) {
+	network := NewMemoryNetwork(0)
+	a, b := testMemoryAddr(t, "10.0.0.1:26656"), testMemoryAddr(t, "10.0.0.2:26656")
+	network.SetLink(a.ID, b.ID, Link{
+		Latency:           10 * time.Millisecond,
+		Jitter:            20 * time.Millisecond,
+		Loss:              0.3,
+		RetransmitTimeout: 30 * time.Millisecond,
+	})
+	dialed, accepted := testMemoryConnPair(t, network, a, b)
+
+	// Despite the random delays, the writes are delivered in order.
+	const numWrites = 100
+	go func() {
+		for i := 0; i < numWrites; i++ {
+			_, err := dialed.Write([]byte{byte(i)})
+			assert.NoError(t, err)
+		}
+	}()
+	data := make([]byte, numWrites)
+	_, err := io.ReadFull(accepted, data)
+	require.NoError(t, err)
+	for i, b := range data {
+		require.Equal(t, byte(i), b)
+	}
+}
+
+func TestMemoryNetworkCapacity(t *testing.T) {
+	network := NewMemoryNetwork(0)
+	a, b := testMemoryAddr(t, "10.0.0.1:26656"), testMemoryAddr(t, "10.0.0.2:26656")
+	dialed, accepted := testMemoryConnPair(t, network, a, b)
+
+	// Writes block once the other node has memoryPipeCapacity bytes to read.
+	_, err := dialed.Write(make([]byte, memoryPipeCapacity))
+	require.NoError(t, err)
+	require.NoError(t, dialed.SetWriteDeadline(time.Now().Add(100*time.Millisecond)))
+	_, err = dialed.Write([]byte{1})
+	require.ErrorIs(t, err, os.ErrDeadlineExceeded)
+
+	// Reading unblocks them.
+	require.NoError(t, dialed.SetWriteDeadline(time.Time{}))
+	written := make(chan error, 1)
+	go func() {
+		_, err := dialed.Write([]byte{1})
+		written <- err
+	}()
+	_, err = io.ReadFull(accepted, make([]byte, 1024))
+	require.NoError(t, err)
+	select {
+	case err := <-written:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("write still blocked after reading")
+	}
+}
+
+func TestMemoryNetworkReproducible(t *testing.T) {
+	a, b := testMemoryAddr(t, "10.0.0.1:26656"), testMemoryAddr(t, "10.0.0.2:26656")
+	c, d := testMemoryAddr(t, "10.0.0.3:26656"), testMemoryAddr(t, "10.0.0.4:26656")
+	rnd := func(conn net.Conn) *rand.Rand { return conn.(*memoryConn).out.rnd }
+
+	// The delays of a connection don't depend on the other connections.
+	network1, network2 := NewMemoryNetwork(1), NewMemoryNetwork(1)
+	ab1, ba1 := testMemoryConnPair(t, network1, a, b)
+	cd1, _ := testMemoryConnPair(t, network1, c, d)
+	_, _ = testMemoryConnPair(t, network2, c, d)
+	ab2, ba2 := testMemoryConnPair(t, network2, a, b)
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, rnd(ab1).Int63(), rnd(ab2).Int63())
+		assert.Equal(t, rnd(ba1).Int63(), rnd(ba2).Int63())
+	}
+
+	// Each connection, in each direction, has its own delays, drawn from the
+	// seed of the network.
+	network3 := NewMemoryNetwork(1)
+	ab3, ba3 := testMemoryConnPair(t, network3, a, b)
+	ab3Next, err := network3.Dial(a, b)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ab3Next.Close() })
+	ab4, _ := testMemoryConnPair(t, NewMemoryNetwork(2), a, b)
+	first := rnd(ab3).Int63()
+	assert.NotEqual(t, first, rnd(ba3).Int63())
+	assert.NotEqual(t, first, rnd(cd1).Int63())
+	assert.NotEqual(t, first, rnd(ab3Next).Int63())
+	assert.NotEqual(t, first, rnd(ab4).Int63())
+}
+
+func TestMemoryNetworkPartition(t *testing.T) {
+	network := NewMemoryNetwork(0)
+	a, b := testMemoryAddr(t, "10.0.0.1:26656"), testMemoryAddr(t, "10.0.0.2:26656")
+	dialed, accepted := testMemoryConnPair(t, network, a, b)
+
+	network.Partition([]ID{a.ID})
+	_, err := network.Dial(a, b)
+	require.ErrorIs(t, err, ErrMemoryNetUnreachable)
+
+	// The data is held until the partition heals.
+	_, err = dialed.Write([]byte("held"))
+	require.NoError(t, err)
+	require.NoError(t, accepted.SetReadDeadline(time.Now().Add(100*time.Millisecond)))
+	_, err = accepted.Read(make([]byte, 4))
+	require.ErrorIs(t, err, os.ErrDeadlineExceeded)
+
+	network.Heal()
+	require.NoError(t, accepted.SetReadDeadline(time.Time{}))
+	data := make([]byte, 4)
+	_, err = io.ReadFull(accepted, data)
+	require.NoError(t, err)
+	assert.Equal(t, "held", string(data))
+
+	// A link can be cut without partitioning the network.
+	network.SetLink(a.ID, b.ID, Link{Down: true})
+	_, err = network.Dial(a, b)
+	require.ErrorIs(t, err, ErrMemoryNetUnreachable)
+}
+
+func TestLinkDelay(t *testing.T) {
+	link := Link{Jitter: 10 * time.Millisecond, Loss: 0.5, RetransmitTimeout: time.Second}
+
+	// The delays are reproducible.
+	rnd1, rnd2 := rand.New(rand.NewSource(1)), rand.New(rand.NewSource(1))
+	lost := 0
+	for i := 0; i < 100; i++ {
+		d := link.delay(rnd1)
+		require.Equal(t, d, link.delay(rnd2))
+		if d >= time.Second {
+			lost++
+			d -= time.Second
+		}
+		require.Less(t, d, link.Jitter)
+	}
+	assert.Positive(t, lost)
+	assert.Less(t, lost, 100)
+
+	assert.Zero(t, Link{}.delay(rnd1))
+}
+
+func TestSwitchesOverMemoryNetwork(t *testing.T) {
+	network := NewMemoryNetwork(0)
+	network.SetDefaultLink(Link{Latency: 20 * time.Millisecond, Jitter: 10 * time.Millisecond})
+	switches := StartAndConnectSwitches(
+		MakeMemorySwitches(cfg, 3, network, initSwitchFunc),
+		Dial2Switches,
+	)
+	t.Cleanup(func() {
+		for _, sw := range switches {
+			if err := sw.Stop(); err != nil {
+				t.Error(err)
+			}
+		}
+	})
+	for _, sw := range switches {
+		require.Equal(t, 2, sw.Peers().Size())
+	}
+
+	// Cut the third switch off from the others.
+	network.Partition([]ID{switches[2].NodeInfo().ID()})
+
+	msg := &p2pproto.PexAddrs{Addrs: []p2pproto.NetAddress{{ID: "1"}}}
+	switches[0].Broadcast(Envelope{ChannelID: 0x00, Message: msg})
+	received := func(i int) func() bool {
+		return func() bool {
+			return len(switches[i].Reactor("foo").(*TestReactor).getMsgs(0x00)) > 0
+		}
+	}
+	require.Eventually(t, received(1), 5*time.Second, 10*time.Millisecond)
+	time.Sleep(200 * time.Millisecond)
+	assert.False(t, received(2)())
+
+	// The message is delivered once the partition heals.
+	network.Heal()
+	require.Eventually(t, received(2), 5*time.Second, 10*time.Millisecond)
+}
@@ -50,6 +50,30 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Name:      "send_rate_limiter_delay",
 			Help:      "Time in seconds spent sleeping by the send rate limiter",
 		}, append(labels, "peer_id")).With(labelsAndValues...),
+		PeerBehaviors: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "peer_behaviors",
+			Help:      "Number of behaviors of peers reported by the reactors, by reason.",
+		}, append(labels, "reason")).With(labelsAndValues...),
+		ChannelSendBytesTotal: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "channel_send_bytes_total",
+			Help:      "Number of bytes sent to a given peer, by channel.",
+		}, append(labels, "peer_id", "ch_id")).With(labelsAndValues...),
+		ChannelReceiveBytesTotal: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "channel_receive_bytes_total",
+			Help:      "Number of bytes received from a given peer, by channel.",
+		}, append(labels, "peer_id", "ch_id")).With(labelsAndValues...),
+		ChannelCompressionRatio: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "channel_compression_ratio",
+			Help:      "Ratio of the size of the messages sent to a given peer before and after compression, by channel.",
+		}, append(labels, "peer_id", "ch_id")).With(labelsAndValues...),
 	}
 }
 
@@ -61,5 +85,9 @@ func NopMetrics() *Metrics {
 		MessageSendBytesTotal:    discard.NewCounter(),
 		RecvRateLimiterDelay:     discard.NewCounter(),
 		SendRateLimiterDelay:     discard.NewCounter(),
+		PeerBehaviors:            discard.NewCounter(),
+		ChannelSendBytesTotal:    discard.NewCounter(),
+		ChannelReceiveBytesTotal: discard.NewCounter(),
+		ChannelCompressionRatio:  discard.NewGauge(),
 	}
 }
@@ -36,6 +36,15 @@ type Metrics struct {
 	RecvRateLimiterDelay metrics.Counter `metrics_labels:"peer_id"`
 	// Time in seconds spent sleeping by the send rate limiter
 	SendRateLimiterDelay metrics.Counter `metrics_labels:"peer_id"`
+	// Number of behaviors of peers reported by the reactors, by reason.
+	PeerBehaviors metrics.Counter `metrics_labels:"reason"`
+	// Number of bytes sent to a given peer, by channel.
+	ChannelSendBytesTotal metrics.Counter `metrics_labels:"peer_id, ch_id"`
+	// Number of bytes received from a given peer, by channel.
+	ChannelReceiveBytesTotal metrics.Counter `metrics_labels:"peer_id, ch_id"`
+	// Ratio of the size of the messages sent to a given peer before and after
+	// compression, by channel.
+	ChannelCompressionRatio metrics.Gauge `metrics_labels:"peer_id, ch_id"`
 }
 
 type peerPendingMetricsCache struct {
@@ -19,12 +19,21 @@ import (
 // EmptyNetAddress defines the string representation of an empty NetAddress.
 const EmptyNetAddress = "<nil-NetAddress>"
 
+// ProtocolQUIC is the protocol of the addresses of peers reachable over QUIC,
+// e.g. quic://ID@IP:Port. Addresses without a protocol, or with another one,
+// are reached over TCP.
+const ProtocolQUIC = "quic"
+
 // NetAddress defines information about a peer on the network
 // including its ID, IP address, and port.
 type NetAddress struct {
 	ID   ID     `json:"id"`
 	IP   net.IP `json:"ip"`
 	Port uint16 `json:"port"`
+	// Protocol is ProtocolQUIC for QUIC addresses, empty for TCP ones. It is
+	// not part of the protobuf encoding: addresses shared with other peers
+	// are TCP addresses.
+	Protocol string `json:"protocol,omitempty"`
 }
 
 // IDAddressString returns id@hostPort. It strips the leading
@@ -34,16 +43,25 @@ func IDAddressString(id ID, protocolHostPort string) string {
 	return fmt.Sprintf("%s@%s", id, hostPort)
 }
 
-// NewNetAddress returns a new NetAddress using the provided TCP
-// address. When testing, other net.Addr (except TCP) will result in
-// using 0.0.0.0:0. When normal run, other net.Addr (except TCP) will
-// panic. Panics if ID is invalid.
+// NewNetAddress returns a new NetAddress using the provided TCP address, or
+// UDP address of a QUIC connection. When testing, other net.Addr will result
+// in using 0.0.0.0:0. When normal run, other net.Addr will panic. Panics if ID
+// is invalid.
 // TODO: socks proxies?
 func NewNetAddress(id ID, addr net.Addr) *NetAddress {
-	tcpAddr, ok := addr.(*net.TCPAddr)
-	if !ok {
+	var (
+		ip       net.IP
+		port     int
+		protocol string
+	)
+	switch addr := addr.(type) {
+	case *net.TCPAddr:
+		ip, port = addr.IP, addr.Port
+	case *net.UDPAddr:
+		ip, port, protocol = addr.IP, addr.Port, ProtocolQUIC
+	default:
 		if flag.Lookup("test.v") == nil { // normal run
-			panic(fmt.Sprintf("Only TCPAddrs are supported. Got: %v", addr))
+			panic(fmt.Sprintf("Only TCPAddrs and UDPAddrs are supported. Got: %v", addr))
 		}
 		// in testing
 		netAddr := NewNetAddressIPPort(net.IP("127.0.0.1"), 0)
@@ -55,15 +73,15 @@ func NewNetAddress(id ID, addr net.Addr) *NetAddress {
 		panic(fmt.Sprintf("Invalid ID %v: %v (addr: %v)", id, err, addr))
 	}
 
-	ip := tcpAddr.IP
-	port := uint16(tcpAddr.Port)
-	na := NewNetAddressIPPort(ip, port)
+	na := NewNetAddressIPPort(ip, uint16(port))
 	na.ID = id
+	na.Protocol = protocol
 	return na
 }
 
 // NewNetAddressString returns a new NetAddress using the provided address in
-// the form of "ID@IP:Port".
+// the form of "ID@IP:Port", optionally prefixed by a protocol, e.g.
+// "quic://ID@IP:Port".
 // Also resolves the host if host is not an IP.
 // Errors are of type ErrNetAddressXxx where Xxx is in (NoID, Invalid, Lookup).
 func NewNetAddressString(addr string) (*NetAddress, error) {
@@ -108,6 +126,9 @@ func NewNetAddressString(addr string) (*NetAddress, error) {
 
 	na := NewNetAddressIPPort(ip, uint16(port))
 	na.ID = id
+	if protocolOf(addr) == ProtocolQUIC {
+		na.Protocol = ProtocolQUIC
+	}
 	return na, nil
 }
 
@@ -208,7 +229,8 @@ func (na *NetAddress) Same(other any) bool {
 	return false
 }
 
-// String representation: <ID>@<IP>:<PORT>.
+// String representation: <ID>@<IP>:<PORT>, prefixed by quic:// for QUIC
+// addresses.
 func (na *NetAddress) String() string {
 	if na == nil {
 		return EmptyNetAddress
@@ -218,6 +240,9 @@ func (na *NetAddress) String() string {
 	if na.ID != "" {
 		addrStr = IDAddressString(na.ID, addrStr)
 	}
+	if na.Protocol != "" {
+		addrStr = na.Protocol + "://" + addrStr
+	}
 
 	return addrStr
 }
@@ -399,6 +424,14 @@ func (na *NetAddress) RFC6052() bool     { return rfc6052.Contains(na.IP) }
 func (na *NetAddress) RFC6145() bool     { return rfc6145.Contains(na.IP) }
 func (na *NetAddress) OnionCatTor() bool { return onionCatNet.Contains(na.IP) }
 
+// protocolOf returns the protocol of addr, if defined.
+func protocolOf(addr string) string {
+	if strings.Contains(addr, "://") {
+		return strings.Split(addr, "://")[0]
+	}
+	return ""
+}
+
 func removeProtocolIfDefined(addr string) string {
 	if strings.Contains(addr, "://") {
 		return strings.Split(addr, "://")[1]
@@ -8,12 +8,15 @@ import (
 	tmp2p "github.com/cometbft/cometbft/api/cometbft/p2p/v1"
 	cmtstrings "github.com/cometbft/cometbft/internal/strings"
 	cmtbytes "github.com/cometbft/cometbft/libs/bytes"
+	"github.com/cometbft/cometbft/p2p/conn"
 	"github.com/cometbft/cometbft/version"
 )
 
 const (
 	maxNodeInfoSize = 10240 // 10KB
 	maxNumChannels  = 16    // plenty of room for upgrades, for now
+
+	maxNumCompressions = 8
 )
 
 // Max size of the NodeInfo struct.
@@ -98,6 +101,11 @@ type DefaultNodeInfo struct {
 type DefaultNodeInfoOther struct {
 	TxIndex    string `json:"tx_index"`
 	RPCAddress string `json:"rpc_address"`
+	// Compression algorithms supported by the node, in order of preference.
+	Compression []string `json:"compression"`
+	// Channels on which the node compresses messages, if the peer supports
+	// one of its compression algorithms.
+	CompressedChannels cmtbytes.HexBytes `json:"compressed_channels"`
 }
 
 // ID returns the node's peer ID.
@@ -167,10 +175,56 @@ func (info DefaultNodeInfo) Validate() error {
 	if len(rpcAddr) > 0 && (!cmtstrings.IsASCIIText(rpcAddr) || cmtstrings.ASCIITrim(rpcAddr) == "") {
 		return ErrInvalidRPCAddress{RPCAddress: rpcAddr}
 	}
+	// Unknown compression algorithms are allowed, they may be supported by
+	// newer versions.
+	if len(other.Compression) > maxNumCompressions {
+		return ErrInvalidCompression{Compression: other.Compression}
+	}
+	for _, compression := range other.Compression {
+		if !cmtstrings.IsASCIIText(compression) {
+			return ErrInvalidCompression{Compression: other.Compression}
+		}
+	}
+	if len(other.CompressedChannels) > maxNumChannels {
+		return ErrChannelsTooLong{Length: len(other.CompressedChannels), Max: maxNumChannels}
+	}
 
 	return nil
 }
 
+// NegotiateCompression returns the compression algorithm to use on each of
+// the channels when sending messages to a peer with the given NodeInfo. A
+// channel is compressed if both nodes compress it, with the first algorithm
+// of ours supported by the peer. Channels without compression are omitted.
+func (info DefaultNodeInfo) NegotiateCompression(otherInfo NodeInfo) map[byte]conn.Compression {
+	other, ok := otherInfo.(DefaultNodeInfo)
+	if !ok {
+		return nil
+	}
+
+	var compression conn.Compression
+OUTER_LOOP:
+	for _, ours := range info.Other.Compression {
+		for _, theirs := range other.Other.Compression {
+			if ours == theirs {
+				compression = conn.Compression(ours)
+				break OUTER_LOOP
+			}
+		}
+	}
+	if compression == conn.CompressionNone {
+		return nil
+	}
+
+	channels := make(map[byte]conn.Compression)
+	for _, ch := range info.Other.CompressedChannels {
+		if bytes.Contains(other.Other.CompressedChannels, []byte{ch}) {
+			channels[ch] = compression
+		}
+	}
+	return channels
+}
+
 // CompatibleWith checks if two DefaultNodeInfo are compatible with each other.
 // CONTRACT: two nodes are compatible if the Block version and network match
 // and they have at least one channel in common.
@@ -251,8 +305,10 @@ func (info DefaultNodeInfo) ToProto() *tmp2p.DefaultNodeInfo {
 	dni.Channels = info.Channels
 	dni.Moniker = info.Moniker
 	dni.Other = tmp2p.DefaultNodeInfoOther{
-		TxIndex:    info.Other.TxIndex,
-		RPCAddress: info.Other.RPCAddress,
+		TxIndex:            info.Other.TxIndex,
+		RPCAddress:         info.Other.RPCAddress,
+		Compression:        info.Other.Compression,
+		CompressedChannels: info.Other.CompressedChannels,
 	}
 
 	return dni
@@ -276,8 +332,10 @@ func DefaultNodeInfoFromToProto(pb *tmp2p.DefaultNodeInfo) (DefaultNodeInfo, err
 		Channels:      pb.Channels,
 		Moniker:       pb.Moniker,
 		Other: DefaultNodeInfoOther{
-			TxIndex:    pb.Other.TxIndex,
-			RPCAddress: pb.Other.RPCAddress,
+			TxIndex:            pb.Other.TxIndex,
+			RPCAddress:         pb.Other.RPCAddress,
+			Compression:        pb.Other.Compression,
+			CompressedChannels: pb.Other.CompressedChannels,
 		},
 	}
 
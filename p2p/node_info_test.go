@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/cometbft/cometbft/crypto/ed25519"
+	"github.com/cometbft/cometbft/p2p/conn"
 )
 
 func TestNodeInfoValidate(t *testing.T) {
@@ -64,6 +65,17 @@ func TestNodeInfoValidate(t *testing.T) {
 		{"Empty space RPCAddress", func(ni *DefaultNodeInfo) { ni.Other.RPCAddress = emptySpace }, true},
 		{"Empty RPCAddress", func(ni *DefaultNodeInfo) { ni.Other.RPCAddress = "" }, false},
 		{"Good RPCAddress", func(ni *DefaultNodeInfo) { ni.Other.RPCAddress = "0.0.0.0:26657" }, false},
+
+		{"Non-ASCII Compression", func(ni *DefaultNodeInfo) { ni.Other.Compression = []string{nonASCII} }, true},
+		{"Too Many Compressions", func(ni *DefaultNodeInfo) { ni.Other.Compression = make([]string, maxNumCompressions+1) }, true},
+		{"Unknown Compression", func(ni *DefaultNodeInfo) { ni.Other.Compression = []string{"brotli"} }, false},
+		{"Good Compression", func(ni *DefaultNodeInfo) { ni.Other.Compression = []string{"zstd", "snappy"} }, false},
+		{
+			"Too Many CompressedChannels",
+			func(ni *DefaultNodeInfo) { ni.Other.CompressedChannels = append(channels, byte(maxNumChannels)) }, //nolint: makezero
+			true,
+		},
+		{"Good CompressedChannels", func(ni *DefaultNodeInfo) { ni.Other.CompressedChannels = channels[:3] }, false},
 	}
 
 	nodeKey := NodeKey{PrivKey: ed25519.GenPrivKey()}
@@ -124,3 +136,30 @@ func TestNodeInfoCompatible(t *testing.T) {
 		require.Error(t, ni1.CompatibleWith(ni))
 	}
 }
+
+func TestNodeInfoNegotiateCompression(t *testing.T) {
+	nodeKey1 := NodeKey{PrivKey: ed25519.GenPrivKey()}
+	nodeKey2 := NodeKey{PrivKey: ed25519.GenPrivKey()}
+	ni1 := testNodeInfo(nodeKey1.ID(), "testing").(DefaultNodeInfo)
+	ni2 := testNodeInfo(nodeKey2.ID(), "testing").(DefaultNodeInfo)
+
+	// no compression
+	assert.Empty(t, ni1.NegotiateCompression(ni2))
+
+	ni1.Other.Compression = []string{"zstd", "snappy"}
+	ni1.Other.CompressedChannels = []byte{0x01, 0x02}
+	ni2.Other.Compression = []string{"brotli", "snappy", "zstd"}
+	ni2.Other.CompressedChannels = []byte{0x02, 0x03}
+
+	// our first algorithm supported by the peer, on the channels compressed by both
+	assert.Equal(t, map[byte]conn.Compression{0x02: conn.CompressionZstd}, ni1.NegotiateCompression(ni2))
+	assert.Equal(t, map[byte]conn.Compression{0x02: conn.CompressionSnappy}, ni2.NegotiateCompression(ni1))
+
+	// no common algorithm
+	ni2.Other.Compression = []string{"brotli"}
+	assert.Empty(t, ni1.NegotiateCompression(ni2))
+
+	// wrong NodeInfo type
+	_, netAddr := CreateRoutableAddr()
+	assert.Empty(t, ni1.NegotiateCompression(mockNodeInfo{netAddr}))
+}
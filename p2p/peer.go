@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/cosmos/gogoproto/proto"
+	"github.com/quic-go/quic-go"
 
 	"github.com/cometbft/cometbft/internal/cmap"
 	"github.com/cometbft/cometbft/libs/log"
@@ -52,6 +53,23 @@ type Peer interface {
 
 // ----------------------------------------------------------
 
+// multiplexConn multiplexes the channels of a peer over its connection: a
+// cmtconn.MConnection over a SecretConnection, or a cmtconn.QUICConnection.
+type multiplexConn interface {
+	service.Service
+	FlushStop()
+
+	Send(chID byte, msgBytes []byte) bool
+	TrySend(chID byte, msgBytes []byte) bool
+	CanSend(chID byte) bool
+	Status() cmtconn.ConnectionStatus
+}
+
+var (
+	_ multiplexConn = (*cmtconn.MConnection)(nil)
+	_ multiplexConn = (*cmtconn.QUICConnection)(nil)
+)
+
 // peerConn contains the raw connection and its config.
 type peerConn struct {
 	outbound   bool
@@ -77,10 +95,10 @@ func newPeerConn(
 	}
 }
 
-// ID only exists for SecretConnection.
-// NOTE: Will panic if conn is not *SecretConnection.
+// ID only exists for SecretConnection and NoiseConnection.
+// NOTE: Will panic if conn is not a cmtconn.AuthenticatedConnection.
 func (pc peerConn) ID() ID {
-	return PubKeyToID(pc.conn.(*cmtconn.SecretConnection).RemotePubKey())
+	return PubKeyToID(pc.conn.(cmtconn.AuthenticatedConnection).RemotePubKey())
 }
 
 // Return the IP from the connection RemoteAddr.
@@ -112,7 +130,7 @@ type peer struct {
 
 	// raw peerConn and the multiplex connection
 	peerConn
-	mconn *cmtconn.MConnection
+	mconn multiplexConn
 
 	// peer's node info and the channel it knows about
 	// channels = nodeInfo.Channels
@@ -141,6 +159,49 @@ func newPeer(
 	chDescs []*cmtconn.ChannelDescriptor,
 	onPeerError func(Peer, any),
 	options ...PeerOption,
+) *peer {
+	return newPeerWithConn(pc, nodeInfo, func(p *peer) multiplexConn {
+		return createMConnection(
+			pc.conn,
+			p,
+			reactorsByCh,
+			msgTypeByChID,
+			chDescs,
+			onPeerError,
+			mConfig,
+		)
+	}, options...)
+}
+
+// newQUICPeer returns a peer multiplexing its channels over the streams of a
+// QUIC connection.
+func newQUICPeer(
+	pc peerConn,
+	qc *quic.Conn,
+	mConfig cmtconn.MConnConfig,
+	nodeInfo NodeInfo,
+	reactorsByCh map[byte]Reactor,
+	msgTypeByChID map[byte]proto.Message,
+	chDescs []*cmtconn.ChannelDescriptor,
+	onPeerError func(Peer, any),
+	options ...PeerOption,
+) *peer {
+	return newPeerWithConn(pc, nodeInfo, func(p *peer) multiplexConn {
+		return cmtconn.NewQUICConnection(
+			qc,
+			chDescs,
+			p.receiveFunc(reactorsByCh, msgTypeByChID),
+			func(r any) { onPeerError(p, r) },
+			mConfig,
+		)
+	}, options...)
+}
+
+func newPeerWithConn(
+	pc peerConn,
+	nodeInfo NodeInfo,
+	newMConn func(p *peer) multiplexConn,
+	options ...PeerOption,
 ) *peer {
 	p := &peer{
 		peerConn:       pc,
@@ -151,15 +212,7 @@ func newPeer(
 		pendingMetrics: newPeerPendingMetricsCache(),
 	}
 
-	p.mconn = createMConnection(
-		pc.conn,
-		p,
-		reactorsByCh,
-		msgTypeByChID,
-		chDescs,
-		onPeerError,
-		mConfig,
-	)
+	p.mconn = newMConn(p)
 	p.BaseService = *service.NewBaseService(nil, "Peer", p)
 	for _, option := range options {
 		option(p)
@@ -360,6 +413,10 @@ func (p *peer) metricsReporter() {
 	metricsTicker := time.NewTicker(metricsTickerDuration)
 	defer metricsTicker.Stop()
 
+	// bytes sent and received per channel, as of the last interval.
+	lastBytesSent := make(map[byte]int64)
+	lastBytesReceived := make(map[byte]int64)
+
 	for {
 		select {
 		case <-metricsTicker.C:
@@ -367,6 +424,26 @@ func (p *peer) metricsReporter() {
 			var sendQueueSize float64
 			for _, chStatus := range status.Channels {
 				sendQueueSize += float64(chStatus.SendQueueSize)
+
+				// Report per peer, per channel total bytes, since the last interval
+				chID := fmt.Sprintf("%#x", chStatus.ID)
+				if sent := chStatus.BytesSent - lastBytesSent[chStatus.ID]; sent > 0 {
+					p.metrics.ChannelSendBytesTotal.
+						With("peer_id", string(p.ID()), "ch_id", chID).
+						Add(float64(sent))
+					lastBytesSent[chStatus.ID] = chStatus.BytesSent
+				}
+				if received := chStatus.BytesReceived - lastBytesReceived[chStatus.ID]; received > 0 {
+					p.metrics.ChannelReceiveBytesTotal.
+						With("peer_id", string(p.ID()), "ch_id", chID).
+						Add(float64(received))
+					lastBytesReceived[chStatus.ID] = chStatus.BytesReceived
+				}
+				if chStatus.CompressedBytesSent > 0 {
+					p.metrics.ChannelCompressionRatio.
+						With("peer_id", string(p.ID()), "ch_id", chID).
+						Set(float64(chStatus.UncompressedBytesSent) / float64(chStatus.CompressedBytesSent))
+				}
 			}
 
 			p.metrics.RecvRateLimiterDelay.With("peer_id", string(p.ID())).
@@ -413,7 +490,26 @@ func createMConnection(
 	onPeerError func(Peer, any),
 	config cmtconn.MConnConfig,
 ) *cmtconn.MConnection {
-	onReceive := func(chID byte, msgBytes []byte) {
+	onError := func(r any) {
+		onPeerError(p, r)
+	}
+
+	return cmtconn.NewMConnectionWithConfig(
+		conn,
+		chDescs,
+		p.receiveFunc(reactorsByCh, msgTypeByChID),
+		onError,
+		config,
+	)
+}
+
+// receiveFunc returns the function decoding the messages received by the
+// peer and passing them to the reactors.
+func (p *peer) receiveFunc(
+	reactorsByCh map[byte]Reactor,
+	msgTypeByChID map[byte]proto.Message,
+) func(chID byte, msgBytes []byte) {
+	return func(chID byte, msgBytes []byte) {
 		reactor := reactorsByCh[chID]
 		if reactor == nil {
 			// Note that its ok to panic here as it's caught in the conn._recover,
@@ -439,16 +535,4 @@ func createMConnection(
 			Message:   msg,
 		})
 	}
-
-	onError := func(r any) {
-		onPeerError(p, r)
-	}
-
-	return cmtconn.NewMConnectionWithConfig(
-		conn,
-		chDescs,
-		onReceive,
-		onError,
-		config,
-	)
 }
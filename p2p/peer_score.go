@@ -0,0 +1,125 @@
+package p2p
+
+import (
+	"math"
+	"time"
+
+	cmtsync "github.com/cometbft/cometbft/libs/sync"
+)
+
+// MaxPeerScore is the maximum score of a peer. Capping the score ensures a
+// peer which behaved well for a long time can't misbehave without
+// consequences.
+const MaxPeerScore = 100
+
+// PeerBehavior is a behavior of a peer, good or bad, reported to the Switch by
+// a reactor. See Switch.ReportBehavior.
+type PeerBehavior struct {
+	// Reason describes the behavior, e.g. "bad_block". It is used as a
+	// metrics label.
+	Reason string
+	// Score is added to the score of the peer: positive for a good behavior,
+	// negative for a bad one.
+	Score float64
+}
+
+// Behaviors reported by the reactors.
+var (
+	// PeerBehaviorBadMessage is reported when a peer sends a message which
+	// cannot be decoded or is invalid.
+	PeerBehaviorBadMessage = PeerBehavior{Reason: "bad_message", Score: -100}
+	// PeerBehaviorBadBlock is reported when a peer sends an invalid block.
+	PeerBehaviorBadBlock = PeerBehavior{Reason: "bad_block", Score: -100}
+	// PeerBehaviorBadVote is reported when a peer sends a vote with an invalid
+	// signature.
+	PeerBehaviorBadVote = PeerBehavior{Reason: "bad_vote", Score: -20}
+	// PeerBehaviorBadTx is reported when a transaction sent by a peer fails
+	// CheckTx. Transactions may become invalid while they are gossiped, so the
+	// penalty is low.
+	PeerBehaviorBadTx = PeerBehavior{Reason: "bad_tx", Score: -2}
+	// PeerBehaviorSlowResponse is reported when a peer doesn't respond to a
+	// request in time.
+	PeerBehaviorSlowResponse = PeerBehavior{Reason: "slow_response", Score: -10}
+	// PeerBehaviorGoodVotes is reported when a peer sent a batch of new valid
+	// votes. The good behaviors are reported in batches, as reporting every
+	// message would be costly.
+	PeerBehaviorGoodVotes = PeerBehavior{Reason: "good_votes", Score: 10}
+	// PeerBehaviorGoodBlockParts is reported when a peer sent a batch of new
+	// valid block parts.
+	PeerBehaviorGoodBlockParts = PeerBehavior{Reason: "good_block_parts", Score: 10}
+)
+
+// PeerScore is the score of a peer at a point in time. The score decays
+// toward zero over time, so that the past behaviors of a peer are eventually
+// forgotten.
+type PeerScore struct {
+	Value float64   `json:"value"`
+	Time  time.Time `json:"time"`
+}
+
+// At returns the score at time t, halving every halfLife. A zero halfLife
+// disables the decay.
+func (s PeerScore) At(t time.Time, halfLife time.Duration) PeerScore {
+	if halfLife <= 0 || !t.After(s.Time) {
+		return s
+	}
+	halvings := float64(t.Sub(s.Time)) / float64(halfLife)
+	return PeerScore{Value: s.Value * math.Exp2(-halvings), Time: t}
+}
+
+// peerScorer keeps the scores of the peers.
+type peerScorer struct {
+	mtx      cmtsync.Mutex
+	halfLife time.Duration
+	scores   map[ID]PeerScore
+	// load returns the score of a peer unknown to the scorer, e.g. from the
+	// address book after a restart.
+	load func(ID) (PeerScore, bool)
+}
+
+func newPeerScorer(halfLife time.Duration, load func(ID) (PeerScore, bool)) *peerScorer {
+	return &peerScorer{
+		halfLife: halfLife,
+		scores:   make(map[ID]PeerScore),
+		load:     load,
+	}
+}
+
+// Score returns the score of the peer at time t.
+func (s *peerScorer) Score(id ID, t time.Time) PeerScore {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.score(id, t)
+}
+
+// Report adds delta to the score of the peer at time t, and returns the new
+// score.
+func (s *peerScorer) Report(id ID, delta float64, t time.Time) PeerScore {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	score := s.score(id, t)
+	score.Value = math.Min(score.Value+delta, MaxPeerScore)
+	score.Time = t
+	s.scores[id] = score
+	return score
+}
+
+// Remove forgets the score of the peer. The score recorded in the address
+// book, if any, is loaded if the peer is reported again.
+func (s *peerScorer) Remove(id ID) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	delete(s.scores, id)
+}
+
+func (s *peerScorer) score(id ID, t time.Time) PeerScore {
+	score, ok := s.scores[id]
+	if !ok && s.load != nil {
+		score, ok = s.load(id)
+	}
+	if !ok {
+		return PeerScore{Time: t}
+	}
+	return score.At(t, s.halfLife)
+}
@@ -0,0 +1,46 @@
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeerScoreAt(t *testing.T) {
+	t0 := time.Now()
+	score := PeerScore{Value: -100, Time: t0}
+
+	assert.Equal(t, score, score.At(t0, time.Hour))
+	assert.Equal(t, score, score.At(t0.Add(-time.Hour), time.Hour), "no decay in the past")
+	assert.Equal(t, score, score.At(t0.Add(time.Hour), 0), "no decay without half-life")
+	assert.Equal(t, PeerScore{Value: -50, Time: t0.Add(time.Hour)}, score.At(t0.Add(time.Hour), time.Hour))
+	assert.InDelta(t, -25, score.At(t0.Add(2*time.Hour), time.Hour).Value, 1e-9)
+}
+
+func TestPeerScorer(t *testing.T) {
+	var (
+		t0    = time.Now()
+		known = ID("known")
+		other = ID("other")
+	)
+	load := func(id ID) (PeerScore, bool) {
+		if id == known {
+			return PeerScore{Value: -40, Time: t0}, true
+		}
+		return PeerScore{}, false
+	}
+	s := newPeerScorer(time.Hour, load)
+
+	// Unknown peers start at 0.
+	assert.Equal(t, PeerScore{Time: t0}, s.Score(other, t0))
+	assert.Equal(t, PeerScore{Value: -10, Time: t0}, s.Report(other, -10, t0))
+	assert.Equal(t, -5.0, s.Score(other, t0.Add(time.Hour)).Value)
+
+	// Scores are loaded, and decay from the time they were recorded.
+	assert.Equal(t, -20.0, s.Score(known, t0.Add(time.Hour)).Value)
+	assert.Equal(t, -30.0, s.Report(known, -10, t0.Add(time.Hour)).Value)
+
+	// Scores are capped.
+	assert.Equal(t, float64(MaxPeerScore), s.Report(other, 2*MaxPeerScore, t0.Add(time.Hour)).Value)
+}
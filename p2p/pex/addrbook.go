@@ -16,6 +16,8 @@ import (
 
 	"github.com/minio/highwayhash"
 
+	dbm "github.com/cometbft/cometbft-db"
+
 	"github.com/cometbft/cometbft/crypto"
 	cmtrand "github.com/cometbft/cometbft/internal/rand"
 	"github.com/cometbft/cometbft/libs/log"
@@ -67,6 +69,18 @@ type AddrBook interface {
 	// Add bad peers back to addrBook
 	ReinstateBadPeers()
 
+	// Record the scores of peers
+	SetPeerScore(id p2p.ID, score p2p.PeerScore)
+	PeerScore(id p2p.ID) (p2p.PeerScore, bool)
+
+	// Record metadata about the peers the node connected to
+	MarkConnected(id p2p.ID, nodeInfo p2p.NodeInfo, latency time.Duration)
+	MarkDisconnected(id p2p.ID, uptime time.Duration, reason any)
+	MarkDialFailed(addr *p2p.NetAddress, err error)
+
+	// Query the addresses matching a filter, e.g. peers with a given app version
+	Query(filter AddrFilter) []AddrInfo
+
 	IsGood(addr *p2p.NetAddress) bool
 	IsBanned(addr *p2p.NetAddress) bool
 
@@ -100,8 +114,12 @@ type addrBook struct {
 	nOld       int
 	nNew       int
 
+	// addresses changed since the last save, when backed by a database
+	dirty map[p2p.ID]struct{}
+
 	// immutable after creation
 	filePath          string
+	db                dbm.DB
 	key               string // random prefix for bucket placement
 	routabilityStrict bool
 	hasher            hash.Hash64
@@ -135,6 +153,18 @@ func NewAddrBook(filePath string, routabilityStrict bool) AddrBook {
 	return am
 }
 
+// NewDBAddrBook creates a new address book saved to db. Unlike the file of
+// NewAddrBook, which is rewritten as a whole, only the addresses which
+// changed are saved, which suits books with many addresses. If db is empty,
+// the book is imported from the file at filePath, if any.
+// Use Start to begin processing asynchronous address updates.
+func NewDBAddrBook(db dbm.DB, filePath string, routabilityStrict bool) AddrBook {
+	am := NewAddrBook(filePath, routabilityStrict).(*addrBook)
+	am.db = db
+	am.dirty = make(map[p2p.ID]struct{})
+	return am
+}
+
 // Initialize the buckets.
 // When modifying this, don't forget to update loadFromFile().
 func (a *addrBook) init() {
@@ -154,7 +184,15 @@ func (a *addrBook) init() {
 
 // OnStart implements Service.
 func (a *addrBook) OnStart() error {
-	a.loadFromFile(a.filePath)
+	if a.db == nil {
+		a.loadFromFile(a.filePath)
+	} else if !a.loadFromDB() && a.loadFromFile(a.filePath) {
+		a.Logger.Info("Importing AddrBook file to db", "file", a.filePath)
+		for id := range a.addrLookup {
+			a.markDirty(id)
+		}
+		a.saveToDB()
+	}
 
 	a.wg.Add(1)
 	go a.saveRoutine()
@@ -328,6 +366,7 @@ func (a *addrBook) MarkGood(id p2p.ID) {
 		return
 	}
 	ka.markGood()
+	a.markDirty(id)
 	if ka.isNew() {
 		a.moveToOld(ka)
 	}
@@ -343,6 +382,7 @@ func (a *addrBook) MarkAttempt(addr *p2p.NetAddress) {
 		return
 	}
 	ka.markAttempt()
+	a.markDirty(addr.ID)
 }
 
 // MarkBad implements AddrBook. Kicks address out from book, places
@@ -356,8 +396,84 @@ func (a *addrBook) MarkBad(addr *p2p.NetAddress, banTime time.Duration) {
 	}
 }
 
+// SetPeerScore implements AddrBook - it records the score of a known or
+// banned peer.
+func (a *addrBook) SetPeerScore(id p2p.ID, score p2p.PeerScore) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if ka := a.knownAddress(id); ka != nil {
+		ka.Score = &score
+		a.markDirty(id)
+	}
+}
+
+// PeerScore implements AddrBook - it returns the recorded score of a known or
+// banned peer.
+func (a *addrBook) PeerScore(id p2p.ID) (p2p.PeerScore, bool) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	ka := a.knownAddress(id)
+	if ka == nil || ka.Score == nil {
+		return p2p.PeerScore{}, false
+	}
+	return *ka.Score, true
+}
+
+// MarkConnected implements AddrBook - it records the NodeInfo of a known
+// peer the node connected to, and the duration of the dial if non-zero.
+func (a *addrBook) MarkConnected(id p2p.ID, nodeInfo p2p.NodeInfo, latency time.Duration) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if ka := a.knownAddress(id); ka != nil {
+		ka.markConnected(nodeInfo, latency)
+		a.markDirty(id)
+	}
+}
+
+// MarkDisconnected implements AddrBook - it adds the uptime of a connection
+// to a known peer, and records the reason it was closed, if any.
+func (a *addrBook) MarkDisconnected(id p2p.ID, uptime time.Duration, reason any) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if ka := a.knownAddress(id); ka != nil {
+		ka.markDisconnected(uptime, reason)
+		a.markDirty(id)
+	}
+}
+
+// MarkDialFailed implements AddrBook - it records why dialing a known peer
+// failed.
+func (a *addrBook) MarkDialFailed(addr *p2p.NetAddress, err error) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if ka := a.knownAddress(addr.ID); ka != nil {
+		ka.markDialFailed(err)
+		a.markDirty(addr.ID)
+	}
+}
+
+// Query implements AddrBook - it returns the addresses in the book, banned
+// ones excluded, matching filter.
+func (a *addrBook) Query(filter AddrFilter) []AddrInfo {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	infos := []AddrInfo{}
+	for _, ka := range a.addrLookup {
+		if info := ka.info(); filter(info) {
+			infos = append(infos, info)
+		}
+	}
+	return infos
+}
+
 // ReinstateBadPeers removes bad peers from ban list and places them into a new
-// bucket.
+// bucket, clearing their score.
 func (a *addrBook) ReinstateBadPeers() {
 	a.mtx.Lock()
 	defer a.mtx.Unlock()
@@ -367,6 +483,8 @@ func (a *addrBook) ReinstateBadPeers() {
 			continue
 		}
 
+		// The peer starts afresh once its ban expired.
+		ka.Score = nil
 		bucket := a.calcNewBucket(ka.Addr, ka.Src)
 
 		if err := a.addToNewBucket(ka, bucket); err != nil {
@@ -481,6 +599,10 @@ func (a *addrBook) size() int {
 
 // Save persists the address book to disk.
 func (a *addrBook) Save() {
+	if a.db != nil {
+		a.saveToDB() // thread safe
+		return
+	}
 	a.saveToFile(a.filePath) // thread safe
 }
 
@@ -541,6 +663,7 @@ func (a *addrBook) addToNewBucket(ka *knownAddress, bucketIdx int) error {
 	if ka.addBucketRef(bucketIdx) == 1 {
 		a.nNew++
 	}
+	a.markDirty(ka.ID())
 
 	// Add it to addrLookup
 	a.addrLookup[ka.ID()] = ka
@@ -577,6 +700,7 @@ func (a *addrBook) addToOldBucket(ka *knownAddress, bucketIdx int) bool {
 	if ka.addBucketRef(bucketIdx) == 1 {
 		a.nOld++
 	}
+	a.markDirty(ka.ID())
 
 	// Ensure in addrLookup
 	a.addrLookup[ka.ID()] = ka
@@ -591,6 +715,7 @@ func (a *addrBook) removeFromBucket(ka *knownAddress, bucketType byte, bucketIdx
 	}
 	bucket := a.getBucket(bucketType, bucketIdx)
 	delete(bucket, ka.Addr.String())
+	a.markDirty(ka.ID())
 	if ka.removeBucketRef(bucketIdx) == 0 {
 		if bucketType == bucketTypeNew {
 			a.nNew--
@@ -613,6 +738,7 @@ func (a *addrBook) removeFromAllBuckets(ka *knownAddress) {
 		a.nOld--
 	}
 	delete(a.addrLookup, ka.ID())
+	a.markDirty(ka.ID())
 }
 
 // ----------------------------------------------------------
@@ -791,6 +917,14 @@ func (a *addrBook) removeAddress(addr *p2p.NetAddress) {
 	a.removeFromAllBuckets(ka)
 }
 
+// knownAddress returns the known or banned address of the peer, if any.
+func (a *addrBook) knownAddress(id p2p.ID) *knownAddress {
+	if ka := a.addrLookup[id]; ka != nil {
+		return ka
+	}
+	return a.badPeers[id]
+}
+
 func (a *addrBook) addBadPeer(addr *p2p.NetAddress, banTime time.Duration) bool {
 	// check it exists in addrbook
 	ka := a.addrLookup[addr.ID]
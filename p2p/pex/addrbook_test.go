@@ -2,6 +2,7 @@ package pex
 
 import (
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math"
 	"net"
@@ -12,6 +13,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	dbm "github.com/cometbft/cometbft-db"
+
 	cmtrand "github.com/cometbft/cometbft/internal/rand"
 	"github.com/cometbft/cometbft/libs/log"
 	cmtmath "github.com/cometbft/cometbft/libs/math"
@@ -442,6 +445,159 @@ func TestBanBadPeers(t *testing.T) {
 	assert.False(t, book.IsGood(addr))
 }
 
+func TestAddrBookPeerScore(t *testing.T) {
+	fname := createTempFileName()
+	defer deleteTempFile(fname)
+
+	book := NewAddrBook(fname, true)
+	book.SetLogger(log.TestingLogger())
+
+	addr, unknown := randIPv4Address(t), randIPv4Address(t)
+	require.NoError(t, book.AddAddress(addr, addr))
+
+	score := p2p.PeerScore{Value: -42, Time: time.Now().Round(0).UTC()}
+	book.SetPeerScore(addr.ID, score)
+	book.SetPeerScore(unknown.ID, score)
+	_, ok := book.PeerScore(unknown.ID)
+	assert.False(t, ok, "scores of unknown peers are not recorded")
+
+	// The score is saved.
+	book.Save()
+	book = NewAddrBook(fname, true)
+	book.SetLogger(log.TestingLogger())
+	require.NoError(t, book.Start())
+	t.Cleanup(func() { _ = book.Stop() })
+	got, ok := book.PeerScore(addr.ID)
+	require.True(t, ok)
+	assert.Equal(t, score, got)
+
+	// The score is kept while the peer is banned.
+	book.MarkBad(addr, time.Second)
+	got, ok = book.PeerScore(addr.ID)
+	require.True(t, ok)
+	assert.Equal(t, score, got)
+
+	// The score is cleared once the ban expired.
+	time.Sleep(time.Second)
+	book.ReinstateBadPeers()
+	_, ok = book.PeerScore(addr.ID)
+	assert.False(t, ok)
+}
+
+func TestAddrBookDBSaveLoad(t *testing.T) {
+	db := dbm.NewMemDB()
+
+	book := NewDBAddrBook(db, "", true)
+	book.SetLogger(log.TestingLogger())
+	require.NoError(t, book.Start())
+
+	randAddrs := randNetAddressPairs(t, 100)
+	for _, addrSrc := range randAddrs {
+		require.NoError(t, book.AddAddress(addrSrc.addr, addrSrc.src))
+	}
+	book.MarkGood(randAddrs[0].addr.ID)
+	book.RemoveAddress(randAddrs[1].addr)
+	require.NoError(t, book.Stop())
+
+	// Only the changed addresses are saved.
+	book = NewDBAddrBook(db, "", true)
+	book.SetLogger(log.TestingLogger())
+	require.NoError(t, book.Start())
+	assert.Equal(t, 99, book.Size())
+	assert.True(t, book.IsGood(randAddrs[0].addr))
+	assert.False(t, book.HasAddress(randAddrs[1].addr))
+	assert.Empty(t, book.(*addrBook).dirty)
+
+	book.RemoveAddress(randAddrs[2].addr)
+	assert.Len(t, book.(*addrBook).dirty, 1)
+	require.NoError(t, book.Stop())
+
+	book = NewDBAddrBook(db, "", true)
+	book.SetLogger(log.TestingLogger())
+	require.NoError(t, book.Start())
+	t.Cleanup(func() { _ = book.Stop() })
+	assert.Equal(t, 98, book.Size())
+}
+
+func TestAddrBookDBImportFile(t *testing.T) {
+	fname := createTempFileName()
+	defer deleteTempFile(fname)
+
+	book := NewAddrBook(fname, true)
+	book.SetLogger(log.TestingLogger())
+	for _, addrSrc := range randNetAddressPairs(t, 10) {
+		require.NoError(t, book.AddAddress(addrSrc.addr, addrSrc.src))
+	}
+	book.Save()
+
+	db := dbm.NewMemDB()
+	book = NewDBAddrBook(db, fname, true)
+	book.SetLogger(log.TestingLogger())
+	require.NoError(t, book.Start())
+	assert.Equal(t, 10, book.Size())
+	addr := randIPv4Address(t)
+	require.NoError(t, book.AddAddress(addr, addr))
+	require.NoError(t, book.Stop())
+
+	// Once imported, the db is used instead of the file.
+	book = NewDBAddrBook(db, fname, true)
+	book.SetLogger(log.TestingLogger())
+	require.NoError(t, book.Start())
+	t.Cleanup(func() { _ = book.Stop() })
+	assert.Equal(t, 11, book.Size())
+}
+
+func TestAddrBookMetadata(t *testing.T) {
+	db := dbm.NewMemDB()
+	book := NewDBAddrBook(db, "", true)
+	book.SetLogger(log.TestingLogger())
+	require.NoError(t, book.Start())
+
+	addr1, addr2, unknown := randIPv4Address(t), randIPv4Address(t), randIPv4Address(t)
+	require.NoError(t, book.AddAddress(addr1, addr1))
+	require.NoError(t, book.AddAddress(addr2, addr2))
+
+	nodeInfo := func(app uint64) p2p.NodeInfo {
+		return p2p.DefaultNodeInfo{
+			ProtocolVersion: p2p.NewProtocolVersion(9, 11, app),
+			Network:         "test-chain",
+			Version:         "1.0.0",
+		}
+	}
+	book.MarkConnected(addr1.ID, nodeInfo(1), 50*time.Millisecond)
+	book.MarkDisconnected(addr1.ID, time.Minute, errors.New("pong timeout"))
+	book.MarkConnected(addr1.ID, nodeInfo(1), 0)
+	book.MarkDisconnected(addr1.ID, time.Minute, nil)
+	book.MarkConnected(addr2.ID, nodeInfo(2), 0)
+	book.MarkDialFailed(addr2, errors.New("connection refused"))
+	book.MarkConnected(unknown.ID, nodeInfo(1), 0)
+	require.NoError(t, book.Stop())
+
+	// The metadata is saved.
+	book = NewDBAddrBook(db, "", true)
+	book.SetLogger(log.TestingLogger())
+	require.NoError(t, book.Start())
+	t.Cleanup(func() { _ = book.Stop() })
+
+	infos := book.Query(WithAppVersion(1))
+	require.Len(t, infos, 1)
+	assert.Equal(t, addr1, infos[0].Addr)
+	metadata := infos[0].Metadata
+	assert.Equal(t, 50*time.Millisecond, metadata.Latency)
+	assert.Equal(t, "test-chain", metadata.ChainID)
+	assert.Equal(t, "1.0.0", metadata.Version)
+	assert.Equal(t, 2*time.Minute, metadata.Uptime)
+	assert.Equal(t, "pong timeout", metadata.LastFailure)
+
+	infos = book.Query(WithAppVersion(2))
+	require.Len(t, infos, 1)
+	assert.Equal(t, addr2, infos[0].Addr)
+	assert.Equal(t, "connection refused", infos[0].Metadata.LastFailure)
+
+	assert.Len(t, book.Query(WithChainID("test-chain")), 2)
+	assert.Empty(t, book.Query(WithChainID("other-chain")))
+}
+
 func TestAddrBookEmpty(t *testing.T) {
 	fname := createTempFileName()
 	defer deleteTempFile(fname)
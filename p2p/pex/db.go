@@ -0,0 +1,103 @@
+package pex
+
+import (
+	"encoding/json"
+	"fmt"
+
+	dbm "github.com/cometbft/cometbft-db"
+
+	"github.com/cometbft/cometbft/p2p"
+)
+
+/* Loading & Saving to a database */
+
+// Unlike the file, which is rewritten as a whole, the database only stores the
+// addresses which changed since the last save, each under its own key.
+var (
+	dbKeyBucketKey  = []byte("key")
+	dbKeyAddrPrefix = []byte("addr/")
+)
+
+func dbKeyAddr(id p2p.ID) []byte {
+	return append(append([]byte{}, dbKeyAddrPrefix...), id...)
+}
+
+// markDirty records that the address of the peer changed, to be saved to the
+// database.
+func (a *addrBook) markDirty(id p2p.ID) {
+	if a.db != nil {
+		a.dirty[id] = struct{}{}
+	}
+}
+
+func (a *addrBook) saveToDB() {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	a.Logger.Info("Saving AddrBook to db", "size", a.size(), "changed", len(a.dirty))
+
+	batch := a.db.NewBatch()
+	defer batch.Close()
+
+	if err := batch.Set(dbKeyBucketKey, []byte(a.key)); err != nil {
+		a.Logger.Error("Failed to save AddrBook to db", "err", err)
+		return
+	}
+	for id := range a.dirty {
+		ka := a.addrLookup[id]
+		if ka == nil {
+			if err := batch.Delete(dbKeyAddr(id)); err != nil {
+				a.Logger.Error("Failed to save AddrBook to db", "err", err)
+				return
+			}
+			continue
+		}
+		kaBytes, err := json.Marshal(ka)
+		if err != nil {
+			a.Logger.Error("Failed to save AddrBook to db", "err", err)
+			return
+		}
+		if err := batch.Set(dbKeyAddr(id), kaBytes); err != nil {
+			a.Logger.Error("Failed to save AddrBook to db", "err", err)
+			return
+		}
+	}
+	if err := batch.WriteSync(); err != nil {
+		a.Logger.Error("Failed to save AddrBook to db", "err", err)
+		return
+	}
+	a.dirty = make(map[p2p.ID]struct{})
+}
+
+// Returns false if the database is empty.
+// Panics if the database is corrupt.
+func (a *addrBook) loadFromDB() bool {
+	key, err := a.db.Get(dbKeyBucketKey)
+	if err != nil {
+		panic(fmt.Sprintf("Error reading AddrBook db: %v", err))
+	}
+	if key == nil {
+		return false
+	}
+
+	it, err := dbm.IteratePrefix(a.db, dbKeyAddrPrefix)
+	if err != nil {
+		panic(fmt.Sprintf("Error reading AddrBook db: %v", err))
+	}
+	defer it.Close()
+
+	var addrs []*knownAddress
+	for ; it.Valid(); it.Next() {
+		ka := &knownAddress{}
+		if err := json.Unmarshal(it.Value(), ka); err != nil {
+			panic(fmt.Sprintf("Error reading AddrBook db entry %q: %v", it.Key(), err))
+		}
+		addrs = append(addrs, ka)
+	}
+	if err := it.Error(); err != nil {
+		panic(fmt.Sprintf("Error reading AddrBook db: %v", err))
+	}
+
+	a.restore(string(key), addrs)
+	return true
+}
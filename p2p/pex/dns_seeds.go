@@ -0,0 +1,73 @@
+package pex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/cometbft/cometbft/p2p"
+)
+
+// DNSSeedPrefix marks the seeds given as a DNS name, e.g.
+// "dns://seeds.example.com". Each TXT record of the name lists seeds as
+// id@host:port entries separated by commas or spaces, so that seed operators
+// can change their addresses without nodes changing their configuration.
+const DNSSeedPrefix = "dns://"
+
+const (
+	// dnsSeedResolvePeriod is the minimum time between two resolutions of the
+	// DNS seeds.
+	dnsSeedResolvePeriod = 10 * time.Minute
+
+	// dnsSeedLookupTimeout is the timeout of the lookup of a DNS seed.
+	dnsSeedLookupTimeout = 10 * time.Second
+)
+
+// SeedResolver looks up the TXT records of DNS seeds. *net.Resolver
+// implements it.
+type SeedResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// splitSeeds splits seeds into the names of the DNS seeds and the other
+// seeds.
+func splitSeeds(seeds []string) (dnsSeeds, addrSeeds []string, err error) {
+	for _, seed := range seeds {
+		name, ok := strings.CutPrefix(seed, DNSSeedPrefix)
+		if !ok {
+			addrSeeds = append(addrSeeds, seed)
+			continue
+		}
+		if name == "" {
+			return nil, nil, fmt.Errorf("missing DNS name in seed %q", seed)
+		}
+		dnsSeeds = append(dnsSeeds, name)
+	}
+	return dnsSeeds, addrSeeds, nil
+}
+
+// resolveDNSSeed returns the seeds listed in the TXT records of name, along
+// with the errors of the invalid entries.
+func resolveDNSSeed(resolver SeedResolver, name string) ([]*p2p.NetAddress, []error, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dnsSeedLookupTimeout)
+	defer cancel()
+
+	records, err := resolver.LookupTXT(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	var entries []string
+	for _, record := range records {
+		entries = append(entries, strings.FieldsFunc(record, func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		})...)
+	}
+	if len(entries) == 0 {
+		return nil, nil, errors.New("no seeds in TXT records")
+	}
+	addrs, errs := p2p.NewNetAddressStrings(entries)
+	return addrs, errs, nil
+}
@@ -0,0 +1,204 @@
+package pex
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+
+	"github.com/cometbft/cometbft/p2p"
+)
+
+const (
+	testSeed1 = "ed3dfd27bfc4af18f67a49862f04cc100696e84d@127.0.0.1:26656"
+	testSeed2 = "d824b13cb5d40fa1d8a614e089357c7eff31b670@127.0.0.2:26656"
+	testSeed3 = "8c3e0a8a1b4d5e6f708192a3b4c5d6e7f8091a2b@127.0.0.3:26656"
+)
+
+// mapResolver resolves the names in the map, and fails for the others.
+type mapResolver map[string][]string
+
+func (r mapResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	records, ok := r[name]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	return records, nil
+}
+
+func TestResolveDNSSeed(t *testing.T) {
+	resolver := mapResolver{
+		"seeds.test":   {testSeed1 + "," + testSeed2, testSeed3},
+		"invalid.test": {testSeed1 + " not-a-seed"},
+		"empty.test":   {""},
+	}
+
+	addrs, errs, err := resolveDNSSeed(resolver, "seeds.test")
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+	require.Len(t, addrs, 3)
+	assert.Equal(t, testSeed1, addrs[0].String())
+	assert.Equal(t, testSeed2, addrs[1].String())
+	assert.Equal(t, testSeed3, addrs[2].String())
+
+	addrs, errs, err = resolveDNSSeed(resolver, "invalid.test")
+	require.NoError(t, err)
+	assert.Len(t, errs, 1)
+	assert.Len(t, addrs, 1)
+
+	_, _, err = resolveDNSSeed(resolver, "empty.test")
+	require.Error(t, err)
+	_, _, err = resolveDNSSeed(resolver, "unknown.test")
+	require.Error(t, err)
+}
+
+func TestPEXReactorResolvesDNSSeedsPeriodically(t *testing.T) {
+	resolver := mapResolver{"seeds.test": {testSeed1}}
+	r, book := createReactor(&ReactorConfig{
+		Seeds:        []string{DNSSeedPrefix + "seeds.test", testSeed2},
+		SeedResolver: resolver,
+	})
+	defer teardownReactor(book)
+
+	numOnline, seedAddrs, err := r.checkSeeds()
+	require.NoError(t, err)
+	assert.Equal(t, 2, numOnline)
+	r.seedAddrs = seedAddrs
+	assert.ElementsMatch(t, []string{testSeed1, testSeed2}, addrStrings(r.seeds()))
+
+	// The DNS seeds are not resolved again before dnsSeedResolvePeriod.
+	resolver["seeds.test"] = []string{testSeed3}
+	assert.False(t, r.resolveDNSSeeds())
+	assert.ElementsMatch(t, []string{testSeed1, testSeed2}, addrStrings(r.seeds()))
+
+	r.dnsSeedsResolved = time.Now().Add(-dnsSeedResolvePeriod)
+	assert.True(t, r.resolveDNSSeeds())
+	assert.ElementsMatch(t, []string{testSeed3, testSeed2}, addrStrings(r.seeds()))
+
+	// A DNS seed failing to resolve keeps its last addresses.
+	delete(resolver, "seeds.test")
+	r.dnsSeedsResolved = time.Now().Add(-dnsSeedResolvePeriod)
+	assert.True(t, r.resolveDNSSeeds())
+	assert.ElementsMatch(t, []string{testSeed3, testSeed2}, addrStrings(r.seeds()))
+
+	r, book = createReactor(&ReactorConfig{Seeds: []string{DNSSeedPrefix}})
+	defer teardownReactor(book)
+	_, _, err = r.checkSeeds()
+	require.ErrorAs(t, err, &ErrSeedNodeConfig{})
+}
+
+func TestPEXReactorUsesDNSSeeds(t *testing.T) {
+	// directory to store address books
+	dir, err := os.MkdirTemp("", "pex_reactor")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	seed := testCreateSeed(dir, 0, []*p2p.NetAddress{}, []*p2p.NetAddress{})
+	require.NoError(t, seed.Start())
+	defer seed.Stop() //nolint:errcheck // ignore for tests
+
+	dnsAddr := startStubDNSServer(t, map[string][]string{
+		"seeds.test.": {seed.NetAddress().String()},
+	})
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "udp", dnsAddr)
+		},
+	}
+
+	peer := testCreatePeerWithConfig(dir, 1, &ReactorConfig{
+		Seeds:        []string{DNSSeedPrefix + "seeds.test."},
+		SeedResolver: resolver,
+	})
+	require.NoError(t, peer.Start())
+	defer peer.Stop() //nolint:errcheck // ignore for tests
+
+	assertPeersWithTimeout(t, []*p2p.Switch{peer}, 3*time.Second, 1)
+}
+
+// startStubDNSServer starts a DNS server answering the TXT queries of the
+// names in records, and returns its address.
+func startStubDNSServer(t *testing.T, records map[string][]string) string {
+	t.Helper()
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { pc.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := pc.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var parser dnsmessage.Parser
+			header, err := parser.Start(buf[:n])
+			if err != nil {
+				continue
+			}
+			question, err := parser.Question()
+			if err != nil {
+				continue
+			}
+
+			txts, ok := records[question.Name.String()]
+			rcode := dnsmessage.RCodeSuccess
+			if !ok {
+				rcode = dnsmessage.RCodeNameError
+			}
+			builder := dnsmessage.NewBuilder(nil, dnsmessage.Header{
+				ID:                 header.ID,
+				Response:           true,
+				Authoritative:      true,
+				RecursionAvailable: true,
+				RCode:              rcode,
+			})
+			if err := builder.StartQuestions(); err != nil {
+				continue
+			}
+			if err := builder.Question(question); err != nil {
+				continue
+			}
+			if err := builder.StartAnswers(); err != nil {
+				continue
+			}
+			if question.Type == dnsmessage.TypeTXT {
+				for _, txt := range txts {
+					err := builder.TXTResource(dnsmessage.ResourceHeader{
+						Name:  question.Name,
+						Type:  dnsmessage.TypeTXT,
+						Class: dnsmessage.ClassINET,
+						TTL:   60,
+					}, dnsmessage.TXTResource{TXT: []string{txt}})
+					if err != nil {
+						continue
+					}
+				}
+			}
+			msg, err := builder.Finish()
+			if err != nil {
+				continue
+			}
+			_, _ = pc.WriteTo(msg, addr)
+		}
+	}()
+
+	return pc.LocalAddr().String()
+}
+
+func addrStrings(addrs []*p2p.NetAddress) []string {
+	strs := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		strs = append(strs, addr.String())
+	}
+	return strs
+}
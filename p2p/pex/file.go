@@ -63,11 +63,15 @@ func (a *addrBook) loadFromFile(filePath string) bool {
 		panic(fmt.Sprintf("Error reading file %s: %v", filePath, err))
 	}
 
-	// Restore all the fields...
-	// Restore the key
-	a.key = aJSON.Key
+	a.restore(aJSON.Key, aJSON.Addrs)
+	return true
+}
+
+// restore restores the bucket key and the addresses of a saved book.
+func (a *addrBook) restore(key string, addrs []*knownAddress) {
+	a.key = key
 	// Restore .bucketsNew & .bucketsOld
-	for _, ka := range aJSON.Addrs {
+	for _, ka := range addrs {
 		for _, bucketIndex := range ka.Buckets {
 			bucket := a.getBucket(ka.BucketType, bucketIndex)
 			bucket[ka.Addr.String()] = ka
@@ -79,5 +83,4 @@ func (a *addrBook) loadFromFile(filePath string) bool {
 			a.nOld++
 		}
 	}
-	return true
 }
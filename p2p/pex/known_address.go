@@ -1,6 +1,7 @@
 package pex
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/cometbft/cometbft/p2p"
@@ -17,6 +18,59 @@ type knownAddress struct {
 	LastAttempt time.Time       `json:"last_attempt"`
 	LastSuccess time.Time       `json:"last_success"`
 	LastBanTime time.Time       `json:"last_ban_time"`
+	// Score is the last score of the peer, if any.
+	Score *p2p.PeerScore `json:"score,omitempty"`
+	// Metadata recorded once the node connected to the peer, if ever.
+	Metadata *PeerMetadata `json:"metadata,omitempty"`
+}
+
+// PeerMetadata is what the address book records about a peer the node
+// connected to.
+type PeerMetadata struct {
+	// Duration of the last dial of the peer, including the handshake.
+	Latency time.Duration `json:"latency,omitempty"`
+	// Chain ID, CometBFT version and application version the peer advertised
+	// in the handshake.
+	ChainID    string `json:"chain_id,omitempty"`
+	Version    string `json:"version,omitempty"`
+	AppVersion uint64 `json:"app_version,omitempty"`
+	// Total time the node was connected to the peer.
+	Uptime time.Duration `json:"uptime,omitempty"`
+	// Reason of the last failed dial or of the last disconnection caused by
+	// an error, and when it happened.
+	LastFailure     string    `json:"last_failure,omitempty"`
+	LastFailureTime time.Time `json:"last_failure_time"`
+}
+
+// AddrInfo is what the address book knows about an address.
+type AddrInfo struct {
+	Addr        *p2p.NetAddress
+	Old         bool
+	Attempts    int32
+	LastAttempt time.Time
+	LastSuccess time.Time
+	Score       *p2p.PeerScore
+	// Metadata is nil if the node never connected to the peer.
+	Metadata *PeerMetadata
+}
+
+// AddrFilter selects addresses in AddrBook.Query.
+type AddrFilter func(AddrInfo) bool
+
+// WithAppVersion returns a filter selecting the peers last seen running the
+// application version app.
+func WithAppVersion(app uint64) AddrFilter {
+	return func(info AddrInfo) bool {
+		return info.Metadata != nil && info.Metadata.AppVersion == app
+	}
+}
+
+// WithChainID returns a filter selecting the peers last seen on the chain
+// chainID.
+func WithChainID(chainID string) AddrFilter {
+	return func(info AddrInfo) bool {
+		return info.Metadata != nil && info.Metadata.ChainID == chainID
+	}
 }
 
 func newKnownAddress(addr *p2p.NetAddress, src *p2p.NetAddress) *knownAddress {
@@ -34,6 +88,22 @@ func (ka *knownAddress) ID() p2p.ID {
 	return ka.Addr.ID
 }
 
+func (ka *knownAddress) info() AddrInfo {
+	info := AddrInfo{
+		Addr:        ka.Addr,
+		Old:         ka.isOld(),
+		Attempts:    ka.Attempts,
+		LastAttempt: ka.LastAttempt,
+		LastSuccess: ka.LastSuccess,
+		Score:       ka.Score,
+	}
+	if ka.Metadata != nil {
+		metadata := *ka.Metadata
+		info.Metadata = &metadata
+	}
+	return info
+}
+
 func (ka *knownAddress) isOld() bool {
 	return ka.BucketType == bucketTypeOld
 }
@@ -55,6 +125,40 @@ func (ka *knownAddress) markGood() {
 	ka.LastSuccess = now
 }
 
+func (ka *knownAddress) metadata() *PeerMetadata {
+	if ka.Metadata == nil {
+		ka.Metadata = &PeerMetadata{}
+	}
+	return ka.Metadata
+}
+
+func (ka *knownAddress) markConnected(nodeInfo p2p.NodeInfo, latency time.Duration) {
+	m := ka.metadata()
+	if latency > 0 {
+		m.Latency = latency
+	}
+	if ni, ok := nodeInfo.(p2p.DefaultNodeInfo); ok {
+		m.ChainID = ni.Network
+		m.Version = ni.Version
+		m.AppVersion = ni.ProtocolVersion.App
+	}
+}
+
+func (ka *knownAddress) markDisconnected(uptime time.Duration, reason any) {
+	m := ka.metadata()
+	m.Uptime += uptime
+	if reason != nil {
+		m.LastFailure = fmt.Sprint(reason)
+		m.LastFailureTime = time.Now()
+	}
+}
+
+func (ka *knownAddress) markDialFailed(err error) {
+	m := ka.metadata()
+	m.LastFailure = err.Error()
+	m.LastFailureTime = time.Now()
+}
+
 func (ka *knownAddress) ban(banTime time.Duration) {
 	if ka.LastBanTime.Before(time.Now().Add(banTime)) {
 		ka.LastBanTime = time.Now().Add(banTime)
@@ -3,6 +3,8 @@ package pex
 import (
 	"errors"
 	"fmt"
+	"net"
+	"sort"
 	"sync"
 	"time"
 
@@ -11,6 +13,7 @@ import (
 	cmtrand "github.com/cometbft/cometbft/internal/rand"
 	cmtmath "github.com/cometbft/cometbft/libs/math"
 	"github.com/cometbft/cometbft/libs/service"
+	cmtsync "github.com/cometbft/cometbft/libs/sync"
 	"github.com/cometbft/cometbft/p2p"
 	"github.com/cometbft/cometbft/p2p/conn"
 )
@@ -76,6 +79,11 @@ type Reactor struct {
 
 	seedAddrs []*p2p.NetAddress
 
+	// DNS seeds, by name, with the addresses they last resolved to
+	dnsSeedsMtx      cmtsync.Mutex
+	dnsSeeds         map[string][]*p2p.NetAddress
+	dnsSeedsResolved time.Time
+
 	attemptsToDial sync.Map // address (string) -> {number of attempts (int), last time dialed (time.Time)}
 
 	// seed/crawled mode fields
@@ -103,7 +111,12 @@ type ReactorConfig struct {
 
 	// Seeds is a list of addresses reactor may use
 	// if it can't connect to peers in the addrbook.
+	// Seeds prefixed with DNSSeedPrefix are DNS names, periodically resolved
+	// to the seeds listed in their TXT records.
 	Seeds []string
+
+	// SeedResolver looks up the DNS seeds. Defaults to net.DefaultResolver.
+	SeedResolver SeedResolver
 }
 
 type _attemptsToDial struct {
@@ -120,6 +133,7 @@ func NewReactor(b AddrBook, config *ReactorConfig) *Reactor {
 		ensurePeersPeriod:    defaultEnsurePeersPeriod,
 		requestsSent:         cmap.NewCMap(),
 		lastReceivedRequests: cmap.NewCMap(),
+		dnsSeeds:             make(map[string][]*p2p.NetAddress),
 		crawlPeerInfos:       make(map[p2p.ID]crawlPeerInfo),
 	}
 	r.BaseReactor = *p2p.NewBaseReactor("PEX", r)
@@ -363,7 +377,7 @@ func (r *Reactor) ReceiveAddrs(addrs []*p2p.NetAddress, src Peer) error {
 	}
 
 	// Try to connect to addresses coming from a seed node without waiting (#2093)
-	for _, seedAddr := range r.seedAddrs {
+	for _, seedAddr := range r.seeds() {
 		if seedAddr.Equals(srcAddr) {
 			select {
 			case r.ensurePeersCh <- struct{}{}:
@@ -455,10 +469,12 @@ func (r *Reactor) ensurePeers() {
 	newBias := cmtmath.MinInt(out, 8)*10 + 10
 
 	toDial := make(map[p2p.ID]*p2p.NetAddress)
-	// Try maxAttempts times to pick numToDial addresses to dial
+	// Try maxAttempts times to pick numCandidates addresses, of which the
+	// numToDial ones of the peers with the highest scores are dialed.
 	maxAttempts := numToDial * 3
+	numCandidates := numToDial * 2
 
-	for i := 0; i < maxAttempts && len(toDial) < numToDial; i++ {
+	for i := 0; i < maxAttempts && len(toDial) < numCandidates; i++ {
 		if !r.IsRunning() || !r.book.IsRunning() {
 			return
 		}
@@ -478,6 +494,7 @@ func (r *Reactor) ensurePeers() {
 		// before dialing again, or have dialed too many times already
 		toDial[try.ID] = try
 	}
+	r.keepBestScores(toDial, numToDial)
 
 	// Dial picked addresses
 	for _, addr := range toDial {
@@ -517,6 +534,24 @@ func (r *Reactor) ensurePeers() {
 	}
 }
 
+// keepBestScores removes from toDial the addresses of the peers with the
+// lowest scores, keeping at most n addresses.
+func (r *Reactor) keepBestScores(toDial map[p2p.ID]*p2p.NetAddress, n int) {
+	if len(toDial) <= n {
+		return
+	}
+	scores := make(map[p2p.ID]float64, len(toDial))
+	ids := make([]p2p.ID, 0, len(toDial))
+	for id := range toDial {
+		scores[id] = r.Switch.PeerScore(id)
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return scores[ids[i]] > scores[ids[j]] })
+	for _, id := range ids[n:] {
+		delete(toDial, id)
+	}
+}
+
 func (r *Reactor) dialAttemptsInfo(addr *p2p.NetAddress) (attempts int, lastDialed time.Time) {
 	_attempts, ok := r.attemptsToDial.Load(addr.DialString())
 	if !ok {
@@ -581,13 +616,17 @@ func (r *Reactor) maxBackoffDurationForPeer(addr *p2p.NetAddress, planned time.D
 // return err if user provided any badly formatted seed addresses.
 // Doesn't error if the seed node can't be reached.
 // numOnline returns -1 if no seed nodes were in the initial configuration.
+// DNS seeds count as online if they resolve to at least one address.
 func (r *Reactor) checkSeeds() (numOnline int, netAddrs []*p2p.NetAddress, err error) {
-	lSeeds := len(r.config.Seeds)
-	if lSeeds == 0 {
+	if len(r.config.Seeds) == 0 {
 		return -1, nil, nil
 	}
-	netAddrs, errs := p2p.NewNetAddressStrings(r.config.Seeds)
-	numOnline = lSeeds - len(errs)
+	dnsSeeds, addrSeeds, err := splitSeeds(r.config.Seeds)
+	if err != nil {
+		return 0, nil, ErrSeedNodeConfig{Err: err}
+	}
+	netAddrs, errs := p2p.NewNetAddressStrings(addrSeeds)
+	numOnline = len(addrSeeds) - len(errs)
 	for _, err := range errs {
 		switch e := err.(type) {
 		case p2p.ErrNetAddressLookup:
@@ -596,16 +635,84 @@ func (r *Reactor) checkSeeds() (numOnline int, netAddrs []*p2p.NetAddress, err e
 			return 0, nil, ErrSeedNodeConfig{Err: err}
 		}
 	}
+
+	r.dnsSeedsMtx.Lock()
+	for _, name := range dnsSeeds {
+		r.dnsSeeds[name] = nil
+	}
+	r.dnsSeedsMtx.Unlock()
+	r.resolveDNSSeeds()
+	r.dnsSeedsMtx.Lock()
+	for _, addrs := range r.dnsSeeds {
+		if len(addrs) > 0 {
+			numOnline++
+		}
+	}
+	r.dnsSeedsMtx.Unlock()
+
 	return numOnline, netAddrs, nil
 }
 
+// resolveDNSSeeds resolves the DNS seeds, unless they were resolved less than
+// dnsSeedResolvePeriod ago. A DNS seed which fails to resolve keeps the
+// addresses it last resolved to. Returns true if the seeds were resolved.
+func (r *Reactor) resolveDNSSeeds() bool {
+	r.dnsSeedsMtx.Lock()
+	if len(r.dnsSeeds) == 0 || time.Since(r.dnsSeedsResolved) < dnsSeedResolvePeriod {
+		r.dnsSeedsMtx.Unlock()
+		return false
+	}
+	r.dnsSeedsResolved = time.Now()
+	names := make([]string, 0, len(r.dnsSeeds))
+	for name := range r.dnsSeeds {
+		names = append(names, name)
+	}
+	r.dnsSeedsMtx.Unlock()
+
+	var resolver SeedResolver = net.DefaultResolver
+	if r.config.SeedResolver != nil {
+		resolver = r.config.SeedResolver
+	}
+	for _, name := range names {
+		addrs, errs, err := resolveDNSSeed(resolver, name)
+		if err != nil {
+			r.Logger.Error("Resolving DNS seed failed", "name", name, "err", err)
+			continue
+		}
+		for _, err := range errs {
+			r.Logger.Error("Invalid seed in DNS seed", "name", name, "err", err)
+		}
+		r.Logger.Info("Resolved DNS seed", "name", name, "seeds", len(addrs))
+
+		r.dnsSeedsMtx.Lock()
+		r.dnsSeeds[name] = addrs
+		r.dnsSeedsMtx.Unlock()
+	}
+	return true
+}
+
+// seeds returns the addresses of the seeds, including the addresses the DNS
+// seeds last resolved to.
+func (r *Reactor) seeds() []*p2p.NetAddress {
+	r.dnsSeedsMtx.Lock()
+	defer r.dnsSeedsMtx.Unlock()
+
+	seeds := append([]*p2p.NetAddress{}, r.seedAddrs...)
+	for _, addrs := range r.dnsSeeds {
+		seeds = append(seeds, addrs...)
+	}
+	return seeds
+}
+
 // randomly dial seeds until we connect to one or exhaust them.
 func (r *Reactor) dialSeeds() {
-	perm := cmtrand.Perm(len(r.seedAddrs))
+	r.resolveDNSSeeds()
+	seeds := r.seeds()
+	perm := cmtrand.Perm(len(seeds))
 	// perm := r.Switch.rng.Perm(lSeeds)
 	for _, i := range perm {
 		// dial a random seed
-		seedAddr := r.seedAddrs[i]
+		seedAddr := seeds[i]
 		err := r.Switch.DialPeerWithAddress(seedAddr)
 
 		switch err.(type) {
@@ -615,7 +722,7 @@ func (r *Reactor) dialSeeds() {
 		r.Switch.Logger.Error("Error dialing seed", "err", err, "seed", seedAddr)
 	}
 	// do not write error message if there were no seeds specified in config
-	if len(r.seedAddrs) > 0 {
+	if len(seeds) > 0 {
 		r.Switch.Logger.Error("Couldn't connect to any seeds")
 	}
 }
@@ -639,7 +746,7 @@ func (r *Reactor) crawlPeersRoutine() {
 	defer r.peersRoutineWg.Done()
 
 	// If we have any seed nodes, consult them first
-	if len(r.seedAddrs) > 0 {
+	if len(r.seeds()) > 0 {
 		r.dialSeeds()
 	} else {
 		// Do an initial crawl
@@ -652,6 +759,11 @@ func (r *Reactor) crawlPeersRoutine() {
 	for {
 		select {
 		case <-ticker.C:
+			// Consult the seeds again when the DNS seeds are resolved, as
+			// they may list new seeds.
+			if r.resolveDNSSeeds() {
+				r.dialSeeds()
+			}
 			r.attemptDisconnects()
 			r.crawlPeers(r.book.GetSelection())
 			r.cleanupCrawlPeerInfos()
@@ -54,8 +54,22 @@ type AddrBook interface {
 	AddOurAddress(addr *NetAddress)
 	OurAddress(addr *NetAddress) bool
 	MarkGood(id ID)
+	MarkBad(addr *NetAddress, dur time.Duration)
 	RemoveAddress(addr *NetAddress)
 	HasAddress(addr *NetAddress) bool
+	// SetPeerScore records the score of a peer, to be restored after a
+	// restart.
+	SetPeerScore(id ID, score PeerScore)
+	// PeerScore returns the recorded score of a peer, if any.
+	PeerScore(id ID) (PeerScore, bool)
+	// MarkConnected records the NodeInfo of a peer the node connected to,
+	// and the duration of the dial, or zero if the peer dialed the node.
+	MarkConnected(id ID, nodeInfo NodeInfo, latency time.Duration)
+	// MarkDisconnected records the uptime of a connection to a peer and the
+	// reason it was closed, nil if the peer was stopped gracefully.
+	MarkDisconnected(id ID, uptime time.Duration, reason any)
+	// MarkDialFailed records why dialing a peer failed.
+	MarkDialFailed(addr *NetAddress, err error)
 	Save()
 }
 
@@ -63,6 +77,21 @@ type AddrBook interface {
 // fully setup.
 type PeerFilterFunc func(IPeerSet, Peer) error
 
+// PeerIDFilter returns a PeerFilterFunc refusing the peers whose ID is not
+// one of ids.
+func PeerIDFilter(ids ...ID) PeerFilterFunc {
+	allowed := make(map[ID]struct{}, len(ids))
+	for _, id := range ids {
+		allowed[id] = struct{}{}
+	}
+	return func(_ IPeerSet, p Peer) error {
+		if _, ok := allowed[p.ID()]; !ok {
+			return fmt.Errorf("peer %v is not allowed", p.ID())
+		}
+		return nil
+	}
+}
+
 // -----------------------------------------------------------------------------
 
 // Switch handles peer connections and exposes an API to receive incoming messages
@@ -91,9 +120,12 @@ type Switch struct {
 
 	filterTimeout time.Duration
 	peerFilters   []PeerFilterFunc
+	admission     *Admission
 
 	rng *rand.Rand // seed for randomizing dial times and orders
 
+	scores *peerScorer
+
 	metrics *Metrics
 }
 
@@ -131,6 +163,13 @@ func NewSwitch(
 	// Ensure we have a completely undeterministic PRNG.
 	sw.rng = rand.NewRand()
 
+	sw.scores = newPeerScorer(cfg.PeerScoreHalfLife, func(id ID) (PeerScore, bool) {
+		if sw.addrBook == nil {
+			return PeerScore{}, false
+		}
+		return sw.addrBook.PeerScore(id)
+	})
+
 	sw.BaseService = *service.NewBaseService(nil, "P2P Switch", sw)
 
 	for _, option := range options {
@@ -150,6 +189,12 @@ func SwitchPeerFilters(filters ...PeerFilterFunc) SwitchOption {
 	return func(sw *Switch) { sw.peerFilters = filters }
 }
 
+// SwitchAdmission sets the Admission enforced on new peers. It should be the
+// one of the transport, for SetAdmissionPolicy to apply to both.
+func SwitchAdmission(admission *Admission) SwitchOption {
+	return func(sw *Switch) { sw.admission = admission }
+}
+
 // WithMetrics sets the metrics.
 func WithMetrics(metrics *Metrics) SwitchOption {
 	return func(sw *Switch) { sw.metrics = metrics }
@@ -158,15 +203,23 @@ func WithMetrics(metrics *Metrics) SwitchOption {
 // ---------------------------------------------------------------------
 // Switch setup
 
-// AddReactor adds the given reactor to the switch.
+// AddReactor adds the given reactor to the switch. The rates configured for
+// its channels in channel_rates override those of its channel descriptors.
 // NOTE: Not goroutine safe.
 func (sw *Switch) AddReactor(name string, reactor Reactor) Reactor {
+	// Already validated by P2PConfig.ValidateBasic.
+	rates, _ := sw.config.ChannelRateLimits()
 	for _, chDesc := range reactor.GetChannels() {
 		chID := chDesc.ID
 		// No two reactors can share the same channel.
 		if sw.reactorsByCh[chID] != nil {
 			panic(fmt.Sprintf("Channel %X has multiple reactors %v & %v", chID, sw.reactorsByCh[chID], reactor))
 		}
+		if rate, ok := rates[chID]; ok {
+			desc := *chDesc
+			desc.SendRate, desc.RecvRate = rate.SendRate, rate.RecvRate
+			chDesc = &desc
+		}
 		sw.chDescs = append(sw.chDescs, chDesc)
 		sw.reactorsByCh[chID] = reactor
 		sw.msgTypeByChID[chID] = chDesc.MessageType
@@ -363,6 +416,10 @@ func (sw *Switch) stopAndRemovePeer(peer Peer, reason any) {
 	for _, reactor := range sw.reactors {
 		reactor.RemovePeer(peer, reason)
 	}
+	sw.scores.Remove(peer.ID())
+	if sw.addrBook != nil {
+		sw.addrBook.MarkDisconnected(peer.ID(), peer.Status().Duration, reason)
+	}
 
 	// Removing a peer should go last to avoid a situation where a peer
 	// reconnect to our node and the switch calls InitPeer before
@@ -448,6 +505,51 @@ func (sw *Switch) MarkPeerAsGood(peer Peer) {
 	}
 }
 
+// ReportBehavior reports a good or bad behavior of the peer, updating its
+// score. The score is recorded in the address book. If the score falls below
+// p2p.peer_score_disconnect_threshold, the peer is disconnected, and if it
+// falls below p2p.peer_score_ban_threshold, it is also banned for
+// p2p.peer_score_ban_time. Unconditional peers are never disconnected.
+func (sw *Switch) ReportBehavior(peer Peer, behavior PeerBehavior) {
+	score := sw.scores.Report(peer.ID(), behavior.Score, time.Now())
+	sw.metrics.PeerBehaviors.With("reason", behavior.Reason).Add(1)
+	if sw.addrBook != nil {
+		sw.addrBook.SetPeerScore(peer.ID(), score)
+	}
+	if !peer.IsRunning() {
+		// The peer was removed, along with its score.
+		sw.scores.Remove(peer.ID())
+		return
+	}
+
+	if behavior.Score >= 0 || sw.IsPeerUnconditional(peer.ID()) {
+		return
+	}
+	switch {
+	case score.Value <= sw.config.PeerScoreBanThreshold:
+		sw.StopPeerForError(peer, ErrPeerScoreTooLow{
+			Score:     score.Value,
+			Threshold: sw.config.PeerScoreBanThreshold,
+			Behavior:  behavior,
+		})
+		if sw.addrBook != nil {
+			sw.addrBook.MarkBad(peer.SocketAddr(), sw.config.PeerScoreBanTime)
+		}
+	case score.Value <= sw.config.PeerScoreDisconnectThreshold:
+		sw.StopPeerForError(peer, ErrPeerScoreTooLow{
+			Score:     score.Value,
+			Threshold: sw.config.PeerScoreDisconnectThreshold,
+			Behavior:  behavior,
+		})
+	}
+}
+
+// PeerScore returns the current score of the peer with the given ID, which
+// need not be connected. Peers start with a score of 0.
+func (sw *Switch) PeerScore(id ID) float64 {
+	return sw.scores.Score(id, time.Now()).Value
+}
+
 // ---------------------------------------------------------------------
 // Dialing
 
@@ -707,6 +809,10 @@ func (sw *Switch) acceptRoutine() {
 				"err", err,
 				"id", p.ID(),
 			)
+			continue
+		}
+		if sw.addrBook != nil {
+			sw.addrBook.MarkConnected(p.ID(), p.NodeInfo(), 0)
 		}
 	}
 }
@@ -728,6 +834,7 @@ func (sw *Switch) addOutboundPeerWithConfig(
 		return errors.New("dial err (peerConfig.DialFail == true)")
 	}
 
+	start := time.Now()
 	p, err := sw.transport.Dial(*addr, peerConfig{
 		chDescs:       sw.chDescs,
 		onPeerError:   sw.StopPeerForError,
@@ -736,6 +843,7 @@ func (sw *Switch) addOutboundPeerWithConfig(
 		msgTypeByChID: sw.msgTypeByChID,
 		metrics:       sw.metrics,
 	})
+	latency := time.Since(start)
 	if err != nil {
 		if e, ok := err.(ErrRejected); ok {
 			if e.IsSelf() {
@@ -747,6 +855,9 @@ func (sw *Switch) addOutboundPeerWithConfig(
 				return err
 			}
 		}
+		if sw.addrBook != nil {
+			sw.addrBook.MarkDialFailed(addr, err)
+		}
 
 		// retry persistent peers after
 		// any dial error besides IsSelf()
@@ -764,6 +875,9 @@ func (sw *Switch) addOutboundPeerWithConfig(
 		}
 		return err
 	}
+	if sw.addrBook != nil {
+		sw.addrBook.MarkConnected(p.ID(), p.NodeInfo(), latency)
+	}
 
 	return nil
 }
@@ -774,6 +888,12 @@ func (sw *Switch) filterPeer(p Peer) error {
 		return ErrRejected{id: p.ID(), isDuplicate: true}
 	}
 
+	if sw.admission != nil {
+		if err := sw.admission.filterPeer(p); err != nil {
+			return ErrRejected{id: p.ID(), err: err, isFiltered: true}
+		}
+	}
+
 	errc := make(chan error, len(sw.peerFilters))
 
 	for _, f := range sw.peerFilters {
@@ -796,6 +916,24 @@ func (sw *Switch) filterPeer(p Peer) error {
 	return nil
 }
 
+// SetAdmissionPolicy replaces the admission policy enforced on new peers and
+// connections, and disconnects the connected peers it refuses. It does nothing
+// if the switch was created without SwitchAdmission.
+func (sw *Switch) SetAdmissionPolicy(policy *AdmissionPolicy) {
+	if sw.admission == nil {
+		sw.Logger.Error("Can't set the admission policy of a switch without admission")
+		return
+	}
+	sw.admission.SetPolicy(policy)
+	sw.Logger.Info("Admission policy updated")
+
+	for _, p := range sw.peers.Copy() {
+		if err := sw.admission.filterPeer(p); err != nil {
+			sw.stopAndRemovePeer(p, ErrRejected{id: p.ID(), err: err, isFiltered: true})
+		}
+	}
+}
+
 // addPeer starts up the Peer and adds it to the Switch. Error is returned if
 // the peer is filtered out or failed to start or can't be added.
 func (sw *Switch) addPeer(p Peer) error {
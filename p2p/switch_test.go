@@ -204,6 +204,27 @@ func assertMsgReceivedWithTimeout(
 	}
 }
 
+func TestSwitchChannelRates(t *testing.T) {
+	rateCfg := *cfg
+	rateCfg.ChannelRates = []string{"0x01:1024:2048"}
+	channels := []*conn.ChannelDescriptor{
+		{ID: byte(0x00), Priority: 10, MessageType: &p2pproto.Message{}, RecvRate: 4096},
+		{ID: byte(0x01), Priority: 10, MessageType: &p2pproto.Message{}, RecvRate: 4096},
+	}
+	sw := MakeSwitch(&rateCfg, 1, func(_ int, sw *Switch) *Switch {
+		sw.AddReactor("foo", NewTestReactor(channels, false))
+		return sw
+	})
+
+	require.Len(t, sw.chDescs, 2)
+	assert.EqualValues(t, 0, sw.chDescs[0].SendRate)
+	assert.EqualValues(t, 4096, sw.chDescs[0].RecvRate)
+	assert.EqualValues(t, 1024, sw.chDescs[1].SendRate)
+	assert.EqualValues(t, 2048, sw.chDescs[1].RecvRate)
+	// The descriptors of the reactor are left untouched.
+	assert.EqualValues(t, 4096, channels[1].RecvRate)
+}
+
 func TestSwitchFiltersOutItself(t *testing.T) {
 	s1 := MakeSwitch(cfg, 1, initSwitchFunc)
 
@@ -278,6 +299,14 @@ func TestSwitchPeerFilter(t *testing.T) {
 	}
 }
 
+func TestPeerIDFilter(t *testing.T) {
+	sentry, other := newMockPeer(nil), newMockPeer(nil)
+	filter := PeerIDFilter(sentry.ID())
+
+	require.NoError(t, filter(NewPeerSet(), sentry))
+	require.Error(t, filter(NewPeerSet(), other))
+}
+
 func TestSwitchPeerFilterTimeout(t *testing.T) {
 	var (
 		filters = []PeerFilterFunc{
@@ -410,6 +439,55 @@ func TestSwitchStopsNonPersistentPeerOnError(t *testing.T) {
 	assert.False(p.IsRunning())
 }
 
+func TestSwitchReportBehavior(t *testing.T) {
+	sw := MakeSwitch(cfg, 1, initSwitchFunc)
+	require.NoError(t, sw.Start())
+	t.Cleanup(func() {
+		if err := sw.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	// simulate remote peers
+	addPeer := func() Peer {
+		rp := &remotePeer{PrivKey: ed25519.GenPrivKey(), Config: cfg}
+		rp.Start()
+		t.Cleanup(rp.Stop)
+
+		p, err := sw.transport.Dial(*rp.Addr(), peerConfig{
+			chDescs:      sw.chDescs,
+			onPeerError:  sw.StopPeerForError,
+			isPersistent: sw.IsPeerPersistent,
+			reactorsByCh: sw.reactorsByCh,
+		})
+		require.NoError(t, err)
+		require.NoError(t, sw.addPeer(p))
+		return p
+	}
+
+	p := addPeer()
+	sw.ReportBehavior(p, PeerBehaviorGoodVotes)
+	sw.ReportBehavior(p, PeerBehaviorBadBlock)
+	assert.InDelta(t, -90, sw.PeerScore(p.ID()), 0.01)
+	assert.True(t, p.IsRunning(), "the score is above the disconnect threshold")
+
+	sw.ReportBehavior(p, PeerBehaviorBadBlock)
+	assert.False(t, p.IsRunning())
+	assert.Nil(t, sw.Peers().Get(p.ID()))
+	// The score of the removed peer is forgotten.
+	assert.Empty(t, sw.scores.scores)
+	assert.Zero(t, sw.PeerScore(p.ID()))
+
+	// Unconditional peers are not disconnected.
+	up := addPeer()
+	require.NoError(t, sw.AddUnconditionalPeerIDs([]string{string(up.ID())}))
+	sw.ReportBehavior(up, PeerBehaviorBadBlock)
+	sw.ReportBehavior(up, PeerBehaviorBadBlock)
+	sw.ReportBehavior(up, PeerBehaviorBadBlock)
+	assert.InDelta(t, -300, sw.PeerScore(up.ID()), 0.01)
+	assert.True(t, up.IsRunning())
+}
+
 func TestSwitchStopPeerForError(t *testing.T) {
 	s := httptest.NewServer(promhttp.Handler())
 	defer s.Close()
@@ -178,6 +178,48 @@ func ConnectStarSwitches(c int) func([]*Switch, int, int) {
 	}
 }
 
+// MakeMemorySwitches returns n switches, initialized according to the
+// initSwitch function, whose transports listen on network. The i'th switch
+// listens on MemorySwitchAddr(i). Connect them with Dial2Switches.
+func MakeMemorySwitches(
+	cfg *config.P2PConfig,
+	n int,
+	network *MemoryNetwork,
+	initSwitch func(int, *Switch) *Switch,
+) []*Switch {
+	switches := make([]*Switch, n)
+	for i := 0; i < n; i++ {
+		switches[i] = makeSwitch(cfg, i, MemorySwitchAddr(i), initSwitch,
+			[]MultiplexTransportOption{MultiplexTransportMemoryNetwork(network)})
+	}
+	return switches
+}
+
+// MemorySwitchAddr returns the address the i'th switch of MakeMemorySwitches
+// listens on.
+func MemorySwitchAddr(i int) string {
+	return fmt.Sprintf("10.%d.%d.%d:26656", (i+1)>>16&0xff, (i+1)>>8&0xff, (i+1)&0xff)
+}
+
+// Dial2Switches makes switch i dial switch j over their transports.
+// Blocks until both switches have added the peer.
+// NOTE: caller ensures i and j are within bounds.
+func Dial2Switches(switches []*Switch, i, j int) {
+	switchI := switches[i]
+	switchJ := switches[j]
+
+	if err := switchI.DialPeerWithAddress(switchJ.NetAddress()); err != nil {
+		panic(err)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for !switchJ.Peers().Has(switchI.NodeInfo().ID()) {
+		if time.Now().After(deadline) {
+			panic(fmt.Sprintf("switch %d did not add switch %d as a peer", j, i))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
 func (sw *Switch) addPeerWithConnection(conn net.Conn) error {
 	pc, err := testInboundPeerConn(conn, sw.config, sw.nodeKey.PrivKey)
 	if err != nil {
@@ -195,13 +237,18 @@ func (sw *Switch) addPeerWithConnection(conn net.Conn) error {
 		return err
 	}
 
+	chDescs := sw.chDescs
+	if ourNI, ok := sw.nodeInfo.(DefaultNodeInfo); ok {
+		chDescs = withCompression(chDescs, ourNI.NegotiateCompression(ni))
+	}
+
 	p := newPeer(
 		pc,
 		MConnConfig(sw.config),
 		ni,
 		sw.reactorsByCh,
 		sw.msgTypeByChID,
-		sw.chDescs,
+		chDescs,
 		sw.StopPeerForError,
 	)
 
@@ -230,11 +277,29 @@ func MakeSwitch(
 	i int,
 	initSwitch func(int, *Switch) *Switch,
 	opts ...SwitchOption,
+) *Switch {
+	return makeSwitch(cfg, i, "", initSwitch, nil, opts...)
+}
+
+// makeSwitch returns a switch listening on listenAddr, or a free local port if
+// it's empty.
+func makeSwitch(
+	cfg *config.P2PConfig,
+	i int,
+	listenAddr string,
+	initSwitch func(int, *Switch) *Switch,
+	transportOpts []MultiplexTransportOption,
+	opts ...SwitchOption,
 ) *Switch {
 	nodeKey := NodeKey{
 		PrivKey: ed25519.GenPrivKey(),
 	}
 	nodeInfo := testNodeInfo(nodeKey.ID(), fmt.Sprintf("node%d", i))
+	if listenAddr != "" {
+		ni := nodeInfo.(DefaultNodeInfo)
+		ni.ListenAddr = listenAddr
+		nodeInfo = ni
+	}
 	addr, err := NewNetAddressString(
 		IDAddressString(nodeKey.ID(), nodeInfo.(DefaultNodeInfo).ListenAddr),
 	)
@@ -243,6 +308,9 @@ func MakeSwitch(
 	}
 
 	t := NewMultiplexTransport(nodeInfo, nodeKey, MConnConfig(cfg))
+	for _, opt := range transportOpts {
+		opt(t)
+	}
 
 	if err := t.Listen(*addr); err != nil {
 		panic(err)
@@ -255,7 +323,13 @@ func MakeSwitch(
 
 	ni := nodeInfo.(DefaultNodeInfo)
 	for ch := range sw.reactorsByCh {
-		ni.Channels = append(ni.Channels, ch)
+		if !ni.HasChannel(ch) {
+			ni.Channels = append(ni.Channels, ch)
+		}
+	}
+	if len(cfg.Compression) > 0 {
+		ni.Other.Compression = cfg.Compression
+		ni.Other.CompressedChannels = ni.Channels
 	}
 	nodeInfo = ni
 
@@ -291,7 +365,7 @@ func testPeerConn(
 	}
 
 	// Encrypt connection
-	conn, err = upgradeSecretConn(conn, cfg.HandshakeTimeout, ourNodePrivKey)
+	conn, err = upgradeSecretConn(conn, cfg.HandshakeTimeout, ourNodePrivKey, cfg.NoiseHandshake)
 	if err != nil {
 		return pc, fmt.Errorf("error creating peer: %w", err)
 	}
@@ -348,7 +422,13 @@ func (book *AddrBookMock) OurAddress(addr *NetAddress) bool {
 	_, ok := book.OurAddrs[addr.String()]
 	return ok
 }
-func (*AddrBookMock) MarkGood(ID) {}
+func (*AddrBookMock) MarkGood(ID)                               {}
+func (*AddrBookMock) MarkBad(*NetAddress, time.Duration)        {}
+func (*AddrBookMock) SetPeerScore(ID, PeerScore)                {}
+func (*AddrBookMock) PeerScore(ID) (PeerScore, bool)            { return PeerScore{}, false }
+func (*AddrBookMock) MarkConnected(ID, NodeInfo, time.Duration) {}
+func (*AddrBookMock) MarkDisconnected(ID, time.Duration, any)   {}
+func (*AddrBookMock) MarkDialFailed(*NetAddress, error)         {}
 func (book *AddrBookMock) HasAddress(addr *NetAddress) bool {
 	_, ok := book.Addrs[addr.String()]
 	return ok
@@ -2,11 +2,14 @@ package p2p
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net"
+	"sync"
 	"time"
 
 	"github.com/cosmos/gogoproto/proto"
+	"github.com/quic-go/quic-go"
 	"golang.org/x/net/netutil"
 
 	tmp2p "github.com/cometbft/cometbft/api/cometbft/p2p/v1"
@@ -112,6 +115,26 @@ func MultiplexTransportConnFilters(
 	return func(mt *MultiplexTransport) { mt.connFilters = filters }
 }
 
+// MultiplexTransportAdmission sets the Admission enforced on new
+// connections.
+func MultiplexTransportAdmission(admission *Admission) MultiplexTransportOption {
+	return func(mt *MultiplexTransport) { mt.admission = admission }
+}
+
+// MultiplexTransportNoiseHandshake makes the transport offer a Noise handshake
+// to the peers of TCP connections, instead of the SecretConnection one. The
+// SecretConnection handshake is performed with the peers not supporting it.
+func MultiplexTransportNoiseHandshake() MultiplexTransportOption {
+	return func(mt *MultiplexTransport) { mt.noiseHandshake = true }
+}
+
+// MultiplexTransportMemoryNetwork makes the transport listen on and dial the
+// TCP addresses of an in-memory network, instead of the operating system's
+// network. It must listen before dialing, for the network to identify it.
+func MultiplexTransportMemoryNetwork(network *MemoryNetwork) MultiplexTransportOption {
+	return func(mt *MultiplexTransport) { mt.memoryNetwork = network }
+}
+
 // MultiplexTransportFilterTimeout sets the timeout waited for filter calls to
 // return.
 func MultiplexTransportFilterTimeout(
@@ -133,10 +156,13 @@ func MultiplexTransportMaxIncomingConnections(n int) MultiplexTransportOption {
 }
 
 // MultiplexTransport accepts and dials tcp connections and upgrades them to
-// multiplexed peers.
+// multiplexed peers. It also accepts and dials QUIC connections, multiplexing
+// the channels of the peers over QUIC streams, for the addresses with
+// ProtocolQUIC.
 type MultiplexTransport struct {
 	netAddr                NetAddress
 	listener               net.Listener
+	quicListener           *quic.Listener
 	maxIncomingConnections int // see MaxIncomingConnections
 
 	acceptc chan accept
@@ -145,14 +171,22 @@ type MultiplexTransport struct {
 	// Lookup table for duplicate ip and id checks.
 	conns       ConnSet
 	connFilters []ConnFilterFunc
+	admission   *Admission
 
 	dialTimeout      time.Duration
 	filterTimeout    time.Duration
 	handshakeTimeout time.Duration
+	noiseHandshake   bool
 	nodeInfo         NodeInfo
 	nodeKey          NodeKey
 	resolver         IPResolver
 
+	memoryNetwork *MemoryNetwork
+
+	quicTLSOnce sync.Once
+	quicTLS     *tls.Config
+	quicTLSErr  error
+
 	// TODO(xla): This config is still needed as we parameterise peerConn and
 	// peer currently. All relevant configuration should be refactored into options
 	// with sane defaults.
@@ -213,12 +247,23 @@ func (mt *MultiplexTransport) Dial(
 	addr NetAddress,
 	cfg peerConfig,
 ) (Peer, error) {
-	c, err := addr.DialTimeout(mt.dialTimeout)
+	var (
+		c   net.Conn
+		err error
+	)
+	switch {
+	case addr.Protocol == ProtocolQUIC:
+		c, err = mt.dialQUIC(addr)
+	case mt.memoryNetwork != nil:
+		c, err = mt.memoryNetwork.Dial(mt.netAddr, addr)
+	default:
+		c, err = addr.DialTimeout(mt.dialTimeout)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	if mt.mConfig.TestFuzz {
+	if mt.mConfig.TestFuzz && addr.Protocol != ProtocolQUIC {
 		// so we have time to do peer handshakes and get set up.
 		c = FuzzConnAfterFromConfig(c, 10*time.Second, mt.mConfig.TestFuzzConfig)
 	}
@@ -228,14 +273,14 @@ func (mt *MultiplexTransport) Dial(
 		return nil, err
 	}
 
-	secretConn, nodeInfo, err := mt.upgrade(c, &addr)
+	upgradedConn, nodeInfo, err := mt.upgrade(c, &addr)
 	if err != nil {
 		return nil, err
 	}
 
 	cfg.outbound = true
 
-	p := mt.wrapPeer(secretConn, nodeInfo, cfg, &addr)
+	p := mt.wrapPeer(upgradedConn, nodeInfo, cfg, &addr)
 
 	return p, nil
 }
@@ -244,6 +289,11 @@ func (mt *MultiplexTransport) Dial(
 func (mt *MultiplexTransport) Close() error {
 	close(mt.closec)
 
+	if mt.quicListener != nil {
+		if err := mt.quicListener.Close(); err != nil {
+			return err
+		}
+	}
 	if mt.listener != nil {
 		return mt.listener.Close()
 	}
@@ -251,9 +301,27 @@ func (mt *MultiplexTransport) Close() error {
 	return nil
 }
 
-// Listen implements transportLifecycle.
+// Listen implements transportLifecycle. It listens over QUIC for an address
+// with ProtocolQUIC, over TCP otherwise. It can be called once per protocol,
+// NetAddress returning the first address.
 func (mt *MultiplexTransport) Listen(addr NetAddress) error {
-	ln, err := net.Listen("tcp", addr.DialString())
+	if mt.listener == nil && mt.quicListener == nil {
+		mt.netAddr = addr
+	}
+
+	if addr.Protocol == ProtocolQUIC {
+		return mt.listenQUIC(addr)
+	}
+
+	var (
+		ln  net.Listener
+		err error
+	)
+	if mt.memoryNetwork != nil {
+		ln, err = mt.memoryNetwork.Listen(addr)
+	} else {
+		ln, err = net.Listen("tcp", addr.DialString())
+	}
 	if err != nil {
 		return err
 	}
@@ -262,7 +330,6 @@ func (mt *MultiplexTransport) Listen(addr NetAddress) error {
 		ln = netutil.LimitListener(ln, mt.maxIncomingConnections)
 	}
 
-	mt.netAddr = addr
 	mt.listener = ln
 
 	go mt.acceptPeers()
@@ -306,49 +373,51 @@ func (mt *MultiplexTransport) acceptPeers() {
 		// Reference:  https://github.com/tendermint/tendermint/issues/2047
 		//
 		// [0] https://en.wikipedia.org/wiki/Head-of-line_blocking
-		go func(c net.Conn) {
-			defer func() {
-				if r := recover(); r != nil {
-					err := ErrRejected{
-						conn:          c,
-						err:           fmt.Errorf("recovered from panic: %v", r),
-						isAuthFailure: true,
-					}
-					select {
-					case mt.acceptc <- accept{err: err}:
-					case <-mt.closec:
-						// Give up if the transport was closed.
-						_ = c.Close()
-						return
-					}
-				}
-			}()
-
-			var (
-				nodeInfo   NodeInfo
-				secretConn *conn.SecretConnection
-				netAddr    *NetAddress
-			)
+		go mt.acceptConn(c)
+	}
+}
 
-			err := mt.filterConn(c)
-			if err == nil {
-				secretConn, nodeInfo, err = mt.upgrade(c, nil)
-				if err == nil {
-					addr := c.RemoteAddr()
-					id := PubKeyToID(secretConn.RemotePubKey())
-					netAddr = NewNetAddress(id, addr)
-				}
+// acceptConn filters and upgrades an incoming connection, and makes the
+// upgraded peer available to Accept.
+func (mt *MultiplexTransport) acceptConn(c net.Conn) {
+	defer func() {
+		if r := recover(); r != nil {
+			err := ErrRejected{
+				conn:          c,
+				err:           fmt.Errorf("recovered from panic: %v", r),
+				isAuthFailure: true,
 			}
-
 			select {
-			case mt.acceptc <- accept{netAddr, secretConn, nodeInfo, err}:
-				// Make the upgraded peer available.
+			case mt.acceptc <- accept{err: err}:
 			case <-mt.closec:
 				// Give up if the transport was closed.
 				_ = c.Close()
 				return
 			}
-		}(c)
+		}
+	}()
+
+	var (
+		nodeInfo     NodeInfo
+		upgradedConn net.Conn
+		netAddr      *NetAddress
+	)
+
+	err := mt.filterConn(c)
+	if err == nil {
+		upgradedConn, nodeInfo, err = mt.upgrade(c, nil)
+		if err == nil {
+			netAddr = NewNetAddress(nodeInfo.ID(), c.RemoteAddr())
+		}
+	}
+
+	select {
+	case mt.acceptc <- accept{netAddr, upgradedConn, nodeInfo, err}:
+		// Make the upgraded peer available.
+	case <-mt.closec:
+		// Give up if the transport was closed.
+		_ = c.Close()
+		return
 	}
 }
 
@@ -383,6 +452,12 @@ func (mt *MultiplexTransport) filterConn(c net.Conn) (err error) {
 		return err
 	}
 
+	if mt.admission != nil {
+		if err := mt.admission.filterConn(mt.conns, c, ips); err != nil {
+			return ErrRejected{conn: c, err: err, isFiltered: true}
+		}
+	}
+
 	errc := make(chan error, len(mt.connFilters))
 
 	for _, f := range mt.connFilters {
@@ -407,27 +482,45 @@ func (mt *MultiplexTransport) filterConn(c net.Conn) (err error) {
 	return nil
 }
 
+// upgrade authenticates the peer of the connection and exchanges the NodeInfo
+// with it. TCP connections are upgraded to a SecretConnection, or a
+// NoiseConnection if both nodes offer it, while the peers
+// of QUIC connections are authenticated by their TLS handshake.
 func (mt *MultiplexTransport) upgrade(
 	c net.Conn,
 	dialedAddr *NetAddress,
-) (secretConn *conn.SecretConnection, nodeInfo NodeInfo, err error) {
+) (upgradedConn net.Conn, nodeInfo NodeInfo, err error) {
 	defer func() {
 		if err != nil {
 			_ = mt.cleanup(c)
 		}
 	}()
 
-	secretConn, err = upgradeSecretConn(c, mt.handshakeTimeout, mt.nodeKey.PrivKey)
-	if err != nil {
-		return nil, nil, ErrRejected{
-			conn:          c,
-			err:           fmt.Errorf("secret conn failed: %w", err),
-			isAuthFailure: true,
+	var connID ID
+	if qc, ok := c.(*quicConn); ok {
+		connID, err = quicPeerID(qc.conn)
+		if err != nil {
+			return nil, nil, ErrRejected{
+				conn:          c,
+				err:           fmt.Errorf("QUIC peer authentication failed: %w", err),
+				isAuthFailure: true,
+			}
+		}
+		upgradedConn = c
+	} else {
+		secretConn, err := upgradeSecretConn(c, mt.handshakeTimeout, mt.nodeKey.PrivKey, mt.noiseHandshake)
+		if err != nil {
+			return nil, nil, ErrRejected{
+				conn:          c,
+				err:           fmt.Errorf("secret conn failed: %w", err),
+				isAuthFailure: true,
+			}
 		}
+		connID = PubKeyToID(secretConn.RemotePubKey())
+		upgradedConn = secretConn
 	}
 
 	// For outgoing conns, ensure connection key matches dialed key.
-	connID := PubKeyToID(secretConn.RemotePubKey())
 	if dialedAddr != nil {
 		if dialedID := dialedAddr.ID; connID != dialedID {
 			return nil, nil, ErrRejected{
@@ -443,7 +536,7 @@ func (mt *MultiplexTransport) upgrade(
 		}
 	}
 
-	nodeInfo, err = handshake(secretConn, mt.handshakeTimeout, mt.nodeInfo)
+	nodeInfo, err = handshake(upgradedConn, mt.handshakeTimeout, mt.nodeInfo)
 	if err != nil {
 		return nil, nil, ErrRejected{
 			conn:          c,
@@ -493,7 +586,7 @@ func (mt *MultiplexTransport) upgrade(
 		}
 	}
 
-	return secretConn, nodeInfo, nil
+	return upgradedConn, nodeInfo, nil
 }
 
 func (mt *MultiplexTransport) wrapPeer(
@@ -521,13 +614,32 @@ func (mt *MultiplexTransport) wrapPeer(
 		socketAddr,
 	)
 
+	chDescs := cfg.chDescs
+	if ourNI, ok := mt.nodeInfo.(DefaultNodeInfo); ok {
+		chDescs = withCompression(chDescs, ourNI.NegotiateCompression(ni))
+	}
+
+	if qc, ok := c.(*quicConn); ok {
+		return newQUICPeer(
+			peerConn,
+			qc.conn,
+			mt.mConfig,
+			ni,
+			cfg.reactorsByCh,
+			cfg.msgTypeByChID,
+			chDescs,
+			cfg.onPeerError,
+			PeerMetrics(cfg.metrics),
+		)
+	}
+
 	p := newPeer(
 		peerConn,
 		mt.mConfig,
 		ni,
 		cfg.reactorsByCh,
 		cfg.msgTypeByChID,
-		cfg.chDescs,
+		chDescs,
 		cfg.onPeerError,
 		PeerMetrics(cfg.metrics),
 	)
@@ -535,6 +647,31 @@ func (mt *MultiplexTransport) wrapPeer(
 	return p
 }
 
+// withCompression returns a copy of the channel descriptors with the
+// compression negotiated with a peer, or the descriptors themselves if no
+// channel is compressed. The message capacity of a compressed channel is raised
+// by the size of the compression header, so that messages of the maximum size
+// are still accepted when they are sent uncompressed.
+func withCompression(
+	chDescs []*conn.ChannelDescriptor,
+	compression map[byte]conn.Compression,
+) []*conn.ChannelDescriptor {
+	if len(compression) == 0 {
+		return chDescs
+	}
+	descs := make([]*conn.ChannelDescriptor, len(chDescs))
+	for i, chDesc := range chDescs {
+		desc := *chDesc
+		desc.Compression = compression[desc.ID]
+		if desc.Compression != conn.CompressionNone {
+			desc = desc.FillDefaults()
+			desc.RecvMessageCapacity += conn.CompressionHeaderSize
+		}
+		descs[i] = &desc
+	}
+	return descs
+}
+
 func handshake(
 	c net.Conn,
 	timeout time.Duration,
@@ -581,12 +718,13 @@ func upgradeSecretConn(
 	c net.Conn,
 	timeout time.Duration,
 	privKey crypto.PrivKey,
-) (*conn.SecretConnection, error) {
+	noise bool,
+) (conn.AuthenticatedConnection, error) {
 	if err := c.SetDeadline(time.Now().Add(timeout)); err != nil {
 		return nil, err
 	}
 
-	sc, err := conn.MakeSecretConnection(c, privKey)
+	sc, err := conn.MakeAuthenticatedConnection(c, privKey, noise)
 	if err != nil {
 		return nil, err
 	}
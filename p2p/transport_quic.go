@@ -0,0 +1,219 @@
+package p2p
+
+import (
+	"context"
+	stded25519 "crypto/ed25519"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"time"
+
+	"github.com/quic-go/quic-go"
+
+	"github.com/cometbft/cometbft/crypto"
+	"github.com/cometbft/cometbft/crypto/ed25519"
+)
+
+const (
+	// quicALPN is the application protocol negotiated by the TLS handshake of
+	// QUIC connections between peers.
+	quicALPN = "cometbft-p2p"
+
+	// maxQUICStreams is the maximum number of channel streams a peer can
+	// open: one per channel.
+	maxQUICStreams = 256
+)
+
+// ErrQUICKeyType is returned when listening or dialing over QUIC with a node
+// key which cannot sign a TLS certificate.
+var ErrQUICKeyType = errors.New("QUIC transport requires an ed25519 node key")
+
+// quicConn is the net.Conn of a peer connected over QUIC. Its reads and writes
+// go to the stream opened by the dialer to exchange the NodeInfo, and closing
+// it closes the QUIC connection. The channels of the peer are multiplexed
+// over other streams, see cmtconn.QUICConnection.
+type quicConn struct {
+	*quic.Stream
+	conn *quic.Conn
+}
+
+var _ net.Conn = (*quicConn)(nil)
+
+func (c *quicConn) LocalAddr() net.Addr {
+	return c.conn.LocalAddr()
+}
+
+func (c *quicConn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+func (c *quicConn) Close() error {
+	return c.conn.CloseWithError(0, "")
+}
+
+// quicTLSConfig returns the TLS configuration of the QUIC connections of the
+// transport, created on first use.
+func (mt *MultiplexTransport) quicTLSConfig() (*tls.Config, error) {
+	mt.quicTLSOnce.Do(func() {
+		mt.quicTLS, mt.quicTLSErr = newQUICTLSConfig(mt.nodeKey.PrivKey)
+	})
+	return mt.quicTLS, mt.quicTLSErr
+}
+
+// newQUICTLSConfig returns the TLS configuration of QUIC connections. The node
+// presents a self-signed certificate of its node key, and the peers are
+// authenticated by the key of their own certificate, from which their ID is
+// derived: as with SecretConnection, there is no certificate authority.
+func newQUICTLSConfig(privKey crypto.PrivKey) (*tls.Config, error) {
+	edKey, ok := privKey.(ed25519.PrivKey)
+	if !ok {
+		return nil, ErrQUICKeyType
+	}
+	key := stded25519.PrivateKey(edKey)
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.AddDate(100, 0, 0),
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		MinVersion:   tls.VersionTLS13,
+		Certificates: []tls.Certificate{{Certificate: [][]byte{cert}, PrivateKey: key}},
+		ClientAuth:   tls.RequireAnyClientCert,
+		// The certificate is self-signed; it is verified by
+		// VerifyPeerCertificate instead.
+		InsecureSkipVerify: true, //nolint:gosec
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			_, err := quicPeerPubKey(rawCerts)
+			return err
+		},
+		NextProtos: []string{quicALPN},
+	}, nil
+}
+
+// quicPeerPubKey returns the key of the self-signed certificate of a peer.
+func quicPeerPubKey(rawCerts [][]byte) (crypto.PubKey, error) {
+	if len(rawCerts) != 1 {
+		return nil, fmt.Errorf("expected one peer certificate, got %d", len(rawCerts))
+	}
+	cert, err := x509.ParseCertificate(rawCerts[0])
+	if err != nil {
+		return nil, err
+	}
+	if err := cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature); err != nil {
+		return nil, fmt.Errorf("peer certificate is not self-signed: %w", err)
+	}
+	pubKey, ok := cert.PublicKey.(stded25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("peer certificate key is a %T, not an ed25519 key", cert.PublicKey)
+	}
+	return ed25519.PubKey(pubKey), nil
+}
+
+// quicPeerID returns the ID of the peer authenticated by the TLS handshake of
+// a QUIC connection.
+func quicPeerID(qc *quic.Conn) (ID, error) {
+	state := qc.ConnectionState().TLS
+	rawCerts := make([][]byte, len(state.PeerCertificates))
+	for i, cert := range state.PeerCertificates {
+		rawCerts[i] = cert.Raw
+	}
+	pubKey, err := quicPeerPubKey(rawCerts)
+	if err != nil {
+		return "", err
+	}
+	return PubKeyToID(pubKey), nil
+}
+
+// quicConfig returns the configuration of QUIC connections. QUIC keep-alives
+// and idle timeout replace the pings and pongs of MConnection.
+func (mt *MultiplexTransport) quicConfig() *quic.Config {
+	return &quic.Config{
+		HandshakeIdleTimeout:  mt.handshakeTimeout,
+		MaxIdleTimeout:        mt.mConfig.PingInterval + mt.mConfig.PongTimeout,
+		KeepAlivePeriod:       mt.mConfig.PingInterval / 2,
+		MaxIncomingStreams:    1,
+		MaxIncomingUniStreams: maxQUICStreams,
+	}
+}
+
+// listenQUIC listens for QUIC connections on the UDP address.
+func (mt *MultiplexTransport) listenQUIC(addr NetAddress) error {
+	tlsConfig, err := mt.quicTLSConfig()
+	if err != nil {
+		return err
+	}
+	ln, err := quic.ListenAddr(addr.DialString(), tlsConfig, mt.quicConfig())
+	if err != nil {
+		return err
+	}
+	mt.quicListener = ln
+
+	go mt.acceptQUICPeers()
+
+	return nil
+}
+
+func (mt *MultiplexTransport) acceptQUICPeers() {
+	for {
+		qc, err := mt.quicListener.Accept(context.Background())
+		if err != nil {
+			// If Close() has been called, silently exit.
+			select {
+			case _, ok := <-mt.closec:
+				if !ok {
+					return
+				}
+			default:
+				// Transport is not closed
+			}
+
+			mt.acceptc <- accept{err: err}
+			return
+		}
+
+		// The dialer opens a stream to exchange the NodeInfo.
+		go func(qc *quic.Conn) {
+			ctx, cancel := context.WithTimeout(context.Background(), mt.handshakeTimeout)
+			defer cancel()
+			stream, err := qc.AcceptStream(ctx)
+			if err != nil {
+				_ = qc.CloseWithError(0, "")
+				return
+			}
+			mt.acceptConn(&quicConn{Stream: stream, conn: qc})
+		}(qc)
+	}
+}
+
+// dialQUIC connects to the peer over QUIC and opens the stream exchanging the
+// NodeInfo.
+func (mt *MultiplexTransport) dialQUIC(addr NetAddress) (net.Conn, error) {
+	tlsConfig, err := mt.quicTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), mt.dialTimeout)
+	defer cancel()
+	qc, err := quic.DialAddr(ctx, addr.DialString(), tlsConfig, mt.quicConfig())
+	if err != nil {
+		return nil, err
+	}
+	stream, err := qc.OpenStreamSync(ctx)
+	if err != nil {
+		_ = qc.CloseWithError(0, "")
+		return nil, err
+	}
+	return &quicConn{Stream: stream, conn: qc}, nil
+}
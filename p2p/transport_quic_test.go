@@ -0,0 +1,118 @@
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmp2p "github.com/cometbft/cometbft/api/cometbft/p2p/v1"
+	"github.com/cometbft/cometbft/crypto/ed25519"
+	"github.com/cometbft/cometbft/crypto/secp256k1"
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/p2p/conn"
+)
+
+func testQUICPeerConfig(reactor *TestReactor) peerConfig {
+	return peerConfig{
+		chDescs:       reactor.GetChannels(),
+		onPeerError:   func(Peer, any) {},
+		reactorsByCh:  map[byte]Reactor{testCh: reactor},
+		msgTypeByChID: map[byte]proto.Message{testCh: &tmp2p.Message{}},
+		metrics:       NopMetrics(),
+	}
+}
+
+func TestTransportMultiplexQUIC(t *testing.T) {
+	chDescs := []*conn.ChannelDescriptor{{ID: testCh, Priority: 1, SendQueueCapacity: 10}}
+
+	// The listener runs QUIC alongside TCP.
+	listener := testSetupMultiplexTransport(t)
+	t.Cleanup(func() { _ = listener.Close() })
+	addr, err := NewNetAddressString("quic://" + IDAddressString(listener.nodeKey.ID(), "127.0.0.1:0"))
+	require.NoError(t, err)
+	require.Equal(t, ProtocolQUIC, addr.Protocol)
+	require.NoError(t, listener.Listen(*addr))
+	// The first listen address remains the address of the transport.
+	assert.Empty(t, listener.NetAddress().Protocol)
+
+	quicAddr := NewNetAddress(listener.nodeKey.ID(), listener.quicListener.Addr())
+	require.Equal(t, ProtocolQUIC, quicAddr.Protocol)
+
+	dialerKey := ed25519.GenPrivKey()
+	dialer := newMultiplexTransport(
+		testNodeInfo(PubKeyToID(dialerKey.PubKey()), "dialer"),
+		NodeKey{PrivKey: dialerKey},
+	)
+
+	accepted := make(chan Peer)
+	acceptorReactor := NewTestReactor(chDescs, true)
+	go func() {
+		p, err := listener.Accept(testQUICPeerConfig(acceptorReactor))
+		if err != nil {
+			t.Error(err)
+		}
+		accepted <- p
+	}()
+
+	dialerReactor := NewTestReactor(chDescs, true)
+	out, err := dialer.Dial(*quicAddr, testQUICPeerConfig(dialerReactor))
+	require.NoError(t, err)
+	in := <-accepted
+	require.NotNil(t, in)
+
+	assert.Equal(t, listener.nodeKey.ID(), out.ID())
+	assert.Equal(t, dialer.nodeKey.ID(), in.ID())
+	assert.True(t, out.IsOutbound())
+	assert.Equal(t, ProtocolQUIC, in.SocketAddr().Protocol)
+
+	for _, p := range []Peer{in, out} {
+		p.SetLogger(log.TestingLogger())
+		require.NoError(t, p.Start())
+		t.Cleanup(func() { _ = p.Stop() })
+	}
+
+	// Messages go both ways over the channel stream.
+	require.True(t, out.Send(Envelope{ChannelID: testCh, Message: &tmp2p.PexRequest{}}))
+	require.True(t, in.Send(Envelope{ChannelID: testCh, Message: &tmp2p.PexRequest{}}))
+	require.True(t, out.TrySend(Envelope{ChannelID: testCh, Message: &tmp2p.PexRequest{}}))
+	require.Eventually(t, func() bool {
+		return len(acceptorReactor.getMsgs(testCh)) == 2 && len(dialerReactor.getMsgs(testCh)) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, &tmp2p.PexRequest{}, acceptorReactor.getMsgs(testCh)[0].Contents)
+	assert.Positive(t, out.Status().Channels[0].RecentlySent)
+
+	// Stopping a peer closes the connection of the other one.
+	require.NoError(t, out.Stop())
+	require.Eventually(t, func() bool { return !in.IsRunning() || in.Stop() == nil }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestTransportMultiplexQUICRejectWrongID(t *testing.T) {
+	listener := testSetupMultiplexTransport(t)
+	t.Cleanup(func() { _ = listener.Close() })
+	addr, err := NewNetAddressString("quic://" + IDAddressString(listener.nodeKey.ID(), "127.0.0.1:0"))
+	require.NoError(t, err)
+	require.NoError(t, listener.Listen(*addr))
+
+	dialerKey := ed25519.GenPrivKey()
+	dialer := newMultiplexTransport(
+		testNodeInfo(PubKeyToID(dialerKey.PubKey()), "dialer"),
+		NodeKey{PrivKey: dialerKey},
+	)
+
+	wrongAddr := NewNetAddress(PubKeyToID(ed25519.GenPrivKey().PubKey()), listener.quicListener.Addr())
+	_, err = dialer.Dial(*wrongAddr, peerConfig{})
+	var rejected ErrRejected
+	require.ErrorAs(t, err, &rejected)
+	assert.True(t, rejected.IsAuthFailure())
+}
+
+func TestTransportMultiplexQUICKeyType(t *testing.T) {
+	pv := secp256k1.GenPrivKey()
+	mt := newMultiplexTransport(testNodeInfo(PubKeyToID(pv.PubKey()), "transport"), NodeKey{PrivKey: pv})
+	addr, err := NewNetAddressString("quic://" + IDAddressString(mt.nodeKey.ID(), "127.0.0.1:0"))
+	require.NoError(t, err)
+	require.ErrorIs(t, mt.Listen(*addr), ErrQUICKeyType)
+}
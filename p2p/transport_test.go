@@ -299,7 +299,7 @@ func TestTransportMultiplexAcceptNonBlocking(t *testing.T) {
 			errc <- errors.New("fast peer timed out")
 		}
 
-		sc, err := upgradeSecretConn(c, 200*time.Millisecond, ed25519.GenPrivKey())
+		sc, err := upgradeSecretConn(c, 200*time.Millisecond, ed25519.GenPrivKey(), false)
 		if err != nil {
 			errc <- err
 			return
@@ -617,6 +617,122 @@ func TestTransportHandshake(t *testing.T) {
 	}
 }
 
+func TestTransportMultiplexCompression(t *testing.T) {
+	withCompression := func(ni NodeInfo, compression ...string) NodeInfo {
+		dni := ni.(DefaultNodeInfo)
+		dni.Other.Compression = compression
+		dni.Other.CompressedChannels = []byte{0x01, 0x02}
+		return dni
+	}
+	chDescs := []*conn.ChannelDescriptor{
+		{ID: 0x01, Priority: 1},
+		{ID: 0x02, Priority: 1},
+		{ID: 0x03, Priority: 1},
+	}
+
+	mt := testSetupMultiplexTransport(t)
+	mt.nodeInfo = withCompression(mt.nodeInfo, "snappy")
+	laddr := NewNetAddress(mt.nodeKey.ID(), mt.listener.Addr())
+
+	pv := ed25519.GenPrivKey()
+	dialer := newMultiplexTransport(
+		withCompression(testNodeInfo(PubKeyToID(pv.PubKey()), defaultNodeName), "zstd", "snappy"),
+		NodeKey{PrivKey: pv},
+	)
+
+	peerc := make(chan Peer, 1)
+	go func() {
+		p, err := mt.Accept(peerConfig{chDescs: chDescs})
+		if err != nil {
+			t.Error(err)
+		}
+		peerc <- p
+	}()
+
+	p, err := dialer.Dial(*laddr, peerConfig{chDescs: chDescs})
+	if err != nil {
+		t.Fatal(err)
+	}
+	accepted := <-peerc
+	if accepted == nil {
+		t.FailNow()
+	}
+
+	// Both peers compress channels 0x01 and 0x02 with snappy, the only
+	// algorithm supported by both.
+	want := []conn.Compression{conn.CompressionSnappy, conn.CompressionSnappy, conn.CompressionNone}
+	for _, p := range []Peer{p, accepted} {
+		for i, status := range p.Status().Channels {
+			if have := status.Compression; have != want[i] {
+				t.Errorf("channel %X: have %q, want %q", status.ID, have, want[i])
+			}
+		}
+	}
+	// The descriptors shared by the peers are left untouched.
+	if have := chDescs[0].Compression; have != conn.CompressionNone {
+		t.Errorf("have %q, want no compression", have)
+	}
+
+	if err := mt.Close(); err != nil {
+		t.Errorf("close errored: %v", err)
+	}
+}
+
+func TestTransportMultiplexNoiseHandshake(t *testing.T) {
+	for _, tc := range []struct {
+		listenerNoise, dialerNoise bool
+		noise                      bool
+	}{
+		{true, true, true},
+		{true, false, false},
+		{false, true, false},
+	} {
+		mt := testSetupMultiplexTransport(t)
+		mt.noiseHandshake = tc.listenerNoise
+		laddr := NewNetAddress(mt.nodeKey.ID(), mt.listener.Addr())
+
+		pv := ed25519.GenPrivKey()
+		dialer := newMultiplexTransport(
+			testNodeInfo(PubKeyToID(pv.PubKey()), defaultNodeName),
+			NodeKey{PrivKey: pv},
+		)
+		dialer.noiseHandshake = tc.dialerNoise
+
+		peerc := make(chan Peer, 1)
+		go func() {
+			p, err := mt.Accept(peerConfig{})
+			if err != nil {
+				t.Error(err)
+			}
+			peerc <- p
+		}()
+
+		p, err := dialer.Dial(*laddr, peerConfig{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		accepted := <-peerc
+		if accepted == nil {
+			t.FailNow()
+		}
+
+		if have, want := accepted.ID(), dialer.nodeKey.ID(); have != want {
+			t.Errorf("have %v, want %v", have, want)
+		}
+		for _, p := range []Peer{p, accepted} {
+			_, isNoise := p.(*peer).conn.(*conn.NoiseConnection)
+			if isNoise != tc.noise {
+				t.Errorf("listener noise %v, dialer noise %v: have noise %v, want %v",
+					tc.listenerNoise, tc.dialerNoise, isNoise, tc.noise)
+			}
+		}
+
+		if err := mt.Close(); err != nil {
+			t.Errorf("close errored: %v", err)
+		}
+	}
+}
+
 func TestTransportAddChannel(t *testing.T) {
 	mt := newMultiplexTransport(
 		emptyNodeInfo(),
@@ -0,0 +1,420 @@
+package privval
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	cmtproto "github.com/cometbft/cometbft/api/cometbft/types/v1"
+	"github.com/cometbft/cometbft/crypto"
+	"github.com/cometbft/cometbft/crypto/bls12381"
+	"github.com/cometbft/cometbft/internal/tempfile"
+	cmtjson "github.com/cometbft/cometbft/libs/json"
+	"github.com/cometbft/cometbft/libs/service"
+	"github.com/cometbft/cometbft/types"
+)
+
+// ErrThresholdNotMet is returned when fewer than the required number of key
+// share holders produced matching partial signatures.
+var ErrThresholdNotMet = errors.New("not enough matching partial signatures")
+
+// ThresholdShare describes a single key share of a ThresholdKey.
+type ThresholdShare struct {
+	// Index is the (1-based) evaluation point of the share.
+	Index  uint32        `json:"index"`
+	PubKey crypto.PubKey `json:"pub_key"`
+}
+
+// ThresholdKey stores the public part of a validator key which has been split
+// across several operators. Any Threshold of the Shares can jointly produce a
+// signature verifiable with PubKey.
+type ThresholdKey struct {
+	Threshold int              `json:"threshold"`
+	PubKey    crypto.PubKey    `json:"pub_key"`
+	Shares    []ThresholdShare `json:"shares"`
+}
+
+// GenThresholdKey generates a new BLS12-381 validator key split into total
+// shares, threshold of which are required to sign. It returns the public
+// ThresholdKey along with the private key shares, in the same order as
+// ThresholdKey.Shares.
+func GenThresholdKey(threshold, total int) (*ThresholdKey, []crypto.PrivKey, error) {
+	pubKey, privKeys, err := bls12381.GenThresholdKeyShares(threshold, total)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key := &ThresholdKey{
+		Threshold: threshold,
+		PubKey:    pubKey,
+		Shares:    make([]ThresholdShare, len(privKeys)),
+	}
+	for i, privKey := range privKeys {
+		key.Shares[i] = ThresholdShare{Index: uint32(i + 1), PubKey: privKey.PubKey()}
+	}
+
+	return key, privKeys, nil
+}
+
+// LoadThresholdKey reads a ThresholdKey from the given JSON file.
+func LoadThresholdKey(filePath string) (*ThresholdKey, error) {
+	bz, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	key := new(ThresholdKey)
+	if err := cmtjson.Unmarshal(bz, key); err != nil {
+		return nil, fmt.Errorf("error reading threshold key from %v: %w", filePath, err)
+	}
+	if err := key.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("invalid threshold key in %v: %w", filePath, err)
+	}
+	return key, nil
+}
+
+// Save persists the ThresholdKey to the given file.
+func (key *ThresholdKey) Save(filePath string) error {
+	bz, err := cmtjson.MarshalIndent(key, "", "  ")
+	if err != nil {
+		return err
+	}
+	return tempfile.WriteFileAtomic(filePath, bz, 0o600)
+}
+
+// ValidateBasic performs basic validation.
+func (key *ThresholdKey) ValidateBasic() error {
+	if key.PubKey == nil {
+		return errors.New("missing pub_key")
+	}
+	if key.Threshold < 1 {
+		return fmt.Errorf("threshold must be positive, got %d", key.Threshold)
+	}
+	if len(key.Shares) < key.Threshold {
+		return fmt.Errorf("threshold (%d) exceeds the number of shares (%d)", key.Threshold, len(key.Shares))
+	}
+	seen := make(map[uint32]struct{}, len(key.Shares))
+	for _, share := range key.Shares {
+		if share.Index == 0 {
+			return errors.New("share index must be positive")
+		}
+		if share.PubKey == nil {
+			return fmt.Errorf("missing pub_key for share %d", share.Index)
+		}
+		if _, ok := seen[share.Index]; ok {
+			return fmt.Errorf("duplicate share index %d", share.Index)
+		}
+		seen[share.Index] = struct{}{}
+	}
+	return nil
+}
+
+// shareByIndex returns the share with the given index.
+func (key *ThresholdKey) shareByIndex(index uint32) (ThresholdShare, bool) {
+	for _, share := range key.Shares {
+		if share.Index == index {
+			return share, true
+		}
+	}
+	return ThresholdShare{}, false
+}
+
+// -------------------------------------------------------------------------------
+
+// defaultThresholdSignTimeout is how long a signing request waits for enough
+// share holders to respond.
+const defaultThresholdSignTimeout = time.Second
+
+// ThresholdSignerClientOption sets an optional parameter on the
+// ThresholdSignerClient.
+type ThresholdSignerClientOption func(*ThresholdSignerClient)
+
+// ThresholdSignerClientTimeout sets how long a signing request waits for
+// Threshold share holders to return matching partial signatures.
+//
+// Default: 1s.
+func ThresholdSignerClientTimeout(timeout time.Duration) ThresholdSignerClientOption {
+	return func(tsc *ThresholdSignerClient) { tsc.timeout = timeout }
+}
+
+// ThresholdSignerClient implements PrivValidator.
+// It acts as a coordinator: every signing request is forwarded to all key
+// share holders (usually SignerClients talking to remote signers), and the
+// partial signatures of the first Threshold of them to agree on what to sign
+// are combined into a signature for the group public key. Share holders which
+// are slow or offline don't delay the request, as long as Threshold others
+// respond in time.
+//
+// Share holders keep their own double-signing protection.
+//
+// Stopping the client stops the share holders it manages.
+type ThresholdSignerClient struct {
+	service.BaseService
+
+	key     *ThresholdKey
+	signers []thresholdSigner
+	timeout time.Duration
+}
+
+type thresholdSigner struct {
+	share ThresholdShare
+	pv    types.PrivValidator
+}
+
+var _ types.PrivValidator = (*ThresholdSignerClient)(nil)
+
+// NewThresholdSignerClient returns a coordinator for the given key. signers
+// maps share indices to the PrivValidators holding the respective shares.
+// Share holders are not contacted before the first signing request, so they
+// don't need to be reachable at this point; a share holder using the wrong
+// key share is detected when its partial signatures fail to verify.
+func NewThresholdSignerClient(
+	key *ThresholdKey,
+	signers map[uint32]types.PrivValidator,
+	options ...ThresholdSignerClientOption,
+) (*ThresholdSignerClient, error) {
+	if err := key.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	if len(signers) < key.Threshold {
+		return nil, fmt.Errorf("got %d signers, need at least %d", len(signers), key.Threshold)
+	}
+
+	tsc := &ThresholdSignerClient{key: key, timeout: defaultThresholdSignTimeout}
+	for index, pv := range signers {
+		share, ok := key.shareByIndex(index)
+		if !ok {
+			return nil, fmt.Errorf("threshold key has no share %d", index)
+		}
+		if pv == nil {
+			return nil, fmt.Errorf("nil signer for share %d", index)
+		}
+		tsc.signers = append(tsc.signers, thresholdSigner{share: share, pv: pv})
+	}
+	sort.Slice(tsc.signers, func(i, j int) bool {
+		return tsc.signers[i].share.Index < tsc.signers[j].share.Index
+	})
+	for _, option := range options {
+		option(tsc)
+	}
+	tsc.BaseService = *service.NewBaseService(nil, "ThresholdSignerClient", tsc)
+
+	return tsc, nil
+}
+
+// OnStop implements service.Service by stopping the share holders.
+func (tsc *ThresholdSignerClient) OnStop() {
+	for _, s := range tsc.signers {
+		var err error
+		switch pv := s.pv.(type) {
+		case *SignerClient:
+			err = pv.endpoint.Stop()
+		case service.Service:
+			err = pv.Stop()
+		}
+		if err != nil {
+			tsc.Logger.Error("Stopping key share holder", "share", s.share.Index, "err", err)
+		}
+	}
+}
+
+// GetPubKey returns the group public key.
+func (tsc *ThresholdSignerClient) GetPubKey() (crypto.PubKey, error) {
+	return tsc.key.PubKey, nil
+}
+
+// SignVote collects partial signatures for the vote (and its extension, if
+// requested) and combines them.
+func (tsc *ThresholdSignerClient) SignVote(chainID string, vote *cmtproto.Vote, signExtension bool) error {
+	partials, err := tsc.collectPartials(func(s thresholdSigner) (partialSignature, error) {
+		v := *vote
+		if err := s.pv.SignVote(chainID, &v, signExtension); err != nil {
+			return partialSignature{}, err
+		}
+		// Share holders may return a previously signed vote with a different
+		// timestamp, so partial signatures are matched by what they sign over.
+		signBytes := types.VoteSignBytes(chainID, &v)
+		if !s.share.PubKey.VerifySignature(signBytes, v.Signature) {
+			return partialSignature{}, errors.New("invalid partial vote signature")
+		}
+		if len(v.ExtensionSignature) > 0 &&
+			!s.share.PubKey.VerifySignature(types.VoteExtensionSignBytes(chainID, &v), v.ExtensionSignature) {
+			return partialSignature{}, errors.New("invalid partial vote extension signature")
+		}
+		return partialSignature{signBytes: signBytes, sig: v.Signature, extSig: v.ExtensionSignature, signed: &v}, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	indices, sigs, extSigs := splitPartials(partials)
+	signed := *partials[0].signed.(*cmtproto.Vote)
+	if signed.Signature, err = bls12381.CombineThresholdSignatures(indices, sigs); err != nil {
+		return err
+	}
+	if len(extSigs) > 0 {
+		if len(extSigs) != len(indices) {
+			return errors.New("share holders disagree on signing the vote extension")
+		}
+		if signed.ExtensionSignature, err = bls12381.CombineThresholdSignatures(indices, extSigs); err != nil {
+			return err
+		}
+	}
+	if !tsc.key.PubKey.VerifySignature(types.VoteSignBytes(chainID, &signed), signed.Signature) {
+		return errors.New("combined vote signature is invalid")
+	}
+
+	*vote = signed
+	return nil
+}
+
+// SignProposal collects partial signatures for the proposal and combines them.
+func (tsc *ThresholdSignerClient) SignProposal(chainID string, proposal *cmtproto.Proposal) error {
+	partials, err := tsc.collectPartials(func(s thresholdSigner) (partialSignature, error) {
+		p := *proposal
+		if err := s.pv.SignProposal(chainID, &p); err != nil {
+			return partialSignature{}, err
+		}
+		signBytes := types.ProposalSignBytes(chainID, &p)
+		if !s.share.PubKey.VerifySignature(signBytes, p.Signature) {
+			return partialSignature{}, errors.New("invalid partial proposal signature")
+		}
+		return partialSignature{signBytes: signBytes, sig: p.Signature, signed: &p}, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	indices, sigs, _ := splitPartials(partials)
+	signed := *partials[0].signed.(*cmtproto.Proposal)
+	if signed.Signature, err = bls12381.CombineThresholdSignatures(indices, sigs); err != nil {
+		return err
+	}
+	if !tsc.key.PubKey.VerifySignature(types.ProposalSignBytes(chainID, &signed), signed.Signature) {
+		return errors.New("combined proposal signature is invalid")
+	}
+
+	*proposal = signed
+	return nil
+}
+
+// SignBytes collects partial signatures for bytes and combines them.
+func (tsc *ThresholdSignerClient) SignBytes(bytes []byte) ([]byte, error) {
+	partials, err := tsc.collectPartials(func(s thresholdSigner) (partialSignature, error) {
+		sig, err := s.pv.SignBytes(bytes)
+		if err != nil {
+			return partialSignature{}, err
+		}
+		if !s.share.PubKey.VerifySignature(bytes, sig) {
+			return partialSignature{}, errors.New("invalid partial signature")
+		}
+		return partialSignature{signBytes: bytes, sig: sig}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	indices, sigs, _ := splitPartials(partials)
+	sig, err := bls12381.CombineThresholdSignatures(indices, sigs)
+	if err != nil {
+		return nil, err
+	}
+	if !tsc.key.PubKey.VerifySignature(bytes, sig) {
+		return nil, errors.New("combined signature is invalid")
+	}
+	return sig, nil
+}
+
+// partialSignature is the signature of a single share holder.
+type partialSignature struct {
+	index     uint32
+	signBytes []byte
+	sig       []byte
+	extSig    []byte
+	signed    any // the signed vote or proposal
+}
+
+// collectPartials sends a signing request to all share holders concurrently
+// and returns the partial signatures of the first Threshold of them to sign
+// over the same bytes. It returns ErrThresholdNotMet if that doesn't happen
+// within the timeout, or if too many share holders failed.
+//
+// Responses arriving after collectPartials returned are dropped.
+func (tsc *ThresholdSignerClient) collectPartials(
+	sign func(s thresholdSigner) (partialSignature, error),
+) ([]partialSignature, error) {
+	type result struct {
+		partial partialSignature
+		err     error
+	}
+	// Buffered, so that late share holders don't block.
+	resultCh := make(chan result, len(tsc.signers))
+	for _, s := range tsc.signers {
+		go func(s thresholdSigner) {
+			partial, err := sign(s)
+			if err != nil {
+				err = fmt.Errorf("share %d: %w", s.share.Index, err)
+			}
+			partial.index = s.share.Index
+			resultCh <- result{partial: partial, err: err}
+		}(s)
+	}
+
+	timer := time.NewTimer(tsc.timeout)
+	defer timer.Stop()
+
+	var (
+		groups [][]partialSignature
+		errs   []error
+		best   int
+	)
+	for pending := len(tsc.signers); pending > 0; pending-- {
+		select {
+		case res := <-resultCh:
+			if res.err != nil {
+				errs = append(errs, res.err)
+				continue
+			}
+			g := 0
+			for g < len(groups) && !bytes.Equal(groups[g][0].signBytes, res.partial.signBytes) {
+				g++
+			}
+			if g == len(groups) {
+				groups = append(groups, nil)
+			}
+			groups[g] = append(groups[g], res.partial)
+			if len(groups[g]) == tsc.key.Threshold {
+				return groups[g], nil
+			}
+			if len(groups[g]) > best {
+				best = len(groups[g])
+			}
+		case <-timer.C:
+			errs = append(errs, fmt.Errorf("%d share holders did not respond within %v", pending, tsc.timeout))
+			return nil, tsc.thresholdNotMet(best, errs)
+		}
+	}
+	return nil, tsc.thresholdNotMet(best, errs)
+}
+
+func (tsc *ThresholdSignerClient) thresholdNotMet(got int, errs []error) error {
+	return fmt.Errorf("%w: got %d, need %d (errors: %v)",
+		ErrThresholdNotMet, got, tsc.key.Threshold, errors.Join(errs...))
+}
+
+// splitPartials returns the share indices, signatures and, if present,
+// extension signatures of the given partial signatures.
+func splitPartials(partials []partialSignature) (indices []uint32, sigs, extSigs [][]byte) {
+	indices = make([]uint32, 0, len(partials))
+	sigs = make([][]byte, 0, len(partials))
+	for _, p := range partials {
+		indices = append(indices, p.index)
+		sigs = append(sigs, p.sig)
+		if len(p.extSig) > 0 {
+			extSigs = append(extSigs, p.extSig)
+		}
+	}
+	return indices, sigs, extSigs
+}
@@ -0,0 +1,238 @@
+//go:build bls12381
+
+package privval
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cmtproto "github.com/cometbft/cometbft/api/cometbft/types/v1"
+	"github.com/cometbft/cometbft/crypto"
+	"github.com/cometbft/cometbft/crypto/tmhash"
+	cmtrand "github.com/cometbft/cometbft/internal/rand"
+	"github.com/cometbft/cometbft/types"
+)
+
+func newTestThresholdSigners(t *testing.T, threshold, total int) (*ThresholdKey, []types.PrivValidator) {
+	t.Helper()
+	key, shares, err := GenThresholdKey(threshold, total)
+	require.NoError(t, err)
+
+	signers := make([]types.PrivValidator, len(shares))
+	for i, share := range shares {
+		share := share
+		signers[i], _, _ = newTestFilePV(t, func() (crypto.PrivKey, error) { return share, nil })
+	}
+	return key, signers
+}
+
+// signerMap maps the share indices to the given signers, which are expected
+// to be in the order of the shares of key.
+func signerMap(key *ThresholdKey, signers []types.PrivValidator, positions ...int) map[uint32]types.PrivValidator {
+	m := make(map[uint32]types.PrivValidator, len(positions))
+	for _, i := range positions {
+		m[key.Shares[i].Index] = signers[i]
+	}
+	return m
+}
+
+// hangingSigner is a share holder that never responds.
+type hangingSigner struct {
+	types.PrivValidator
+	unblock chan struct{}
+}
+
+func (hs hangingSigner) SignVote(string, *cmtproto.Vote, bool) error {
+	<-hs.unblock
+	return errors.New("unblocked")
+}
+
+// failingSigner is a share holder that always returns an error.
+type failingSigner struct {
+	types.PrivValidator
+}
+
+func (failingSigner) SignVote(string, *cmtproto.Vote, bool) error {
+	return errors.New("signer failure")
+}
+
+func testVote(pubKey crypto.PubKey) *types.Vote {
+	hash := cmtrand.Bytes(tmhash.Size)
+	return &types.Vote{
+		Type:             types.PrecommitType,
+		Height:           10,
+		Round:            1,
+		BlockID:          types.BlockID{Hash: hash, PartSetHeader: types.PartSetHeader{Total: 5, Hash: hash}},
+		Timestamp:        time.Now().UTC(),
+		ValidatorAddress: pubKey.Address(),
+		Extension:        []byte("extension"),
+	}
+}
+
+func TestThresholdSignerClientSignVote(t *testing.T) {
+	const chainID = "threshold-chain"
+	key, signers := newTestThresholdSigners(t, 3, 5)
+
+	// Only 3 out of 5 share holders are configured.
+	tsc, err := NewThresholdSignerClient(key, signerMap(key, signers, 1, 2, 3))
+	require.NoError(t, err)
+
+	pubKey, err := tsc.GetPubKey()
+	require.NoError(t, err)
+	assert.Equal(t, key.PubKey, pubKey)
+
+	vote := testVote(pubKey)
+	v := vote.ToProto()
+	require.NoError(t, tsc.SignVote(chainID, v, true))
+
+	signed, err := types.VoteFromProto(v)
+	require.NoError(t, err)
+	require.NoError(t, signed.VerifyVoteAndExtension(chainID, pubKey))
+
+	// Signing the same vote again returns the same signature.
+	v2 := vote.ToProto()
+	require.NoError(t, tsc.SignVote(chainID, v2, true))
+	assert.Equal(t, v.Signature, v2.Signature)
+}
+
+func TestThresholdSignerClientSignProposal(t *testing.T) {
+	const chainID = "threshold-chain"
+	key, signers := newTestThresholdSigners(t, 2, 3)
+	tsc, err := NewThresholdSignerClient(key, signerMap(key, signers, 0, 1, 2))
+	require.NoError(t, err)
+
+	hash := cmtrand.Bytes(tmhash.Size)
+	proposal := &types.Proposal{
+		Type:      types.ProposalType,
+		Height:    10,
+		Round:     1,
+		POLRound:  -1,
+		BlockID:   types.BlockID{Hash: hash, PartSetHeader: types.PartSetHeader{Total: 5, Hash: hash}},
+		Timestamp: time.Now().UTC(),
+	}
+	p := proposal.ToProto()
+	require.NoError(t, tsc.SignProposal(chainID, p))
+	assert.True(t, key.PubKey.VerifySignature(types.ProposalSignBytes(chainID, p), p.Signature))
+
+	sig, err := tsc.SignBytes([]byte("some bytes"))
+	require.NoError(t, err)
+	assert.True(t, key.PubKey.VerifySignature([]byte("some bytes"), sig))
+}
+
+func TestThresholdSignerClientThresholdNotMet(t *testing.T) {
+	const chainID = "threshold-chain"
+	key, signers := newTestThresholdSigners(t, 2, 3)
+	tsc, err := NewThresholdSignerClient(key, signerMap(key, signers, 0, 1))
+	require.NoError(t, err)
+
+	hash := cmtrand.Bytes(tmhash.Size)
+	vote := &types.Vote{
+		Type:      types.PrevoteType,
+		Height:    10,
+		Round:     1,
+		BlockID:   types.BlockID{Hash: hash, PartSetHeader: types.PartSetHeader{Total: 5, Hash: hash}},
+		Timestamp: time.Now().UTC(),
+	}
+
+	// Make one of the share holders refuse to sign due to a height regression.
+	require.NoError(t, signers[1].SignVote(chainID, &cmtproto.Vote{
+		Type: types.PrevoteType, Height: 11, BlockID: vote.BlockID.ToProto(), Timestamp: vote.Timestamp,
+	}, false))
+
+	err = tsc.SignVote(chainID, vote.ToProto(), false)
+	require.ErrorIs(t, err, ErrThresholdNotMet)
+}
+
+func TestThresholdSignerClientUnresponsiveSigners(t *testing.T) {
+	const chainID = "threshold-chain"
+	key, signers := newTestThresholdSigners(t, 3, 5)
+
+	unblock := make(chan struct{})
+	defer close(unblock)
+	signers[0] = hangingSigner{PrivValidator: signers[0], unblock: unblock}
+	signers[3] = failingSigner{PrivValidator: signers[3]}
+
+	tsc, err := NewThresholdSignerClient(key, signerMap(key, signers, 0, 1, 2, 3, 4),
+		ThresholdSignerClientTimeout(time.Minute))
+	require.NoError(t, err)
+
+	// The remaining 3 share holders sign without waiting for the hanging one.
+	v := testVote(key.PubKey).ToProto()
+	start := time.Now()
+	require.NoError(t, tsc.SignVote(chainID, v, true))
+	assert.Less(t, time.Since(start), 10*time.Second)
+
+	signed, err := types.VoteFromProto(v)
+	require.NoError(t, err)
+	require.NoError(t, signed.VerifyVoteAndExtension(chainID, key.PubKey))
+}
+
+func TestThresholdSignerClientTimeout(t *testing.T) {
+	const chainID = "threshold-chain"
+	key, signers := newTestThresholdSigners(t, 2, 3)
+
+	unblock := make(chan struct{})
+	defer close(unblock)
+	signers[1] = hangingSigner{PrivValidator: signers[1], unblock: unblock}
+
+	tsc, err := NewThresholdSignerClient(key, signerMap(key, signers, 0, 1),
+		ThresholdSignerClientTimeout(50*time.Millisecond))
+	require.NoError(t, err)
+
+	start := time.Now()
+	err = tsc.SignVote(chainID, testVote(key.PubKey).ToProto(), false)
+	require.ErrorIs(t, err, ErrThresholdNotMet)
+	assert.Less(t, time.Since(start), 10*time.Second)
+}
+
+func TestNewThresholdSignerClientErrors(t *testing.T) {
+	key, signers := newTestThresholdSigners(t, 2, 3)
+	_, err := NewThresholdSignerClient(key, signerMap(key, signers, 0))
+	require.Error(t, err, "not enough signers")
+
+	_, err = NewThresholdSignerClient(key, map[uint32]types.PrivValidator{1: signers[0], 4: signers[1]})
+	require.Error(t, err, "unknown share")
+
+	_, err = NewThresholdSignerClient(key, map[uint32]types.PrivValidator{1: signers[0], 2: nil})
+	require.Error(t, err, "nil signer")
+}
+
+func TestThresholdSignerClientWrongShare(t *testing.T) {
+	const chainID = "threshold-chain"
+	key, signers := newTestThresholdSigners(t, 2, 3)
+	_, otherSigners := newTestThresholdSigners(t, 2, 3)
+
+	// A share holder with a share of another key only produces invalid
+	// partial signatures.
+	tsc, err := NewThresholdSignerClient(key, map[uint32]types.PrivValidator{
+		key.Shares[0].Index: signers[0],
+		key.Shares[1].Index: otherSigners[1],
+	})
+	require.NoError(t, err)
+
+	err = tsc.SignVote(chainID, testVote(key.PubKey).ToProto(), false)
+	require.ErrorIs(t, err, ErrThresholdNotMet)
+}
+
+func TestThresholdKeySaveLoad(t *testing.T) {
+	key, _, err := GenThresholdKey(2, 3)
+	require.NoError(t, err)
+
+	file := filepath.Join(t.TempDir(), "threshold_key.json")
+	require.NoError(t, key.Save(file))
+
+	loaded, err := LoadThresholdKey(file)
+	require.NoError(t, err)
+	assert.Equal(t, key.Threshold, loaded.Threshold)
+	assert.Equal(t, key.PubKey.Bytes(), loaded.PubKey.Bytes())
+	require.Len(t, loaded.Shares, 3)
+	for i := range key.Shares {
+		assert.Equal(t, key.Shares[i].Index, loaded.Shares[i].Index)
+		assert.Equal(t, key.Shares[i].PubKey.Bytes(), loaded.Shares[i].PubKey.Bytes())
+	}
+}
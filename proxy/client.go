@@ -1,6 +1,8 @@
 package proxy
 
 import (
+	"context"
+
 	abcicli "github.com/cometbft/cometbft/abci/client"
 	"github.com/cometbft/cometbft/abci/example/kvstore"
 	"github.com/cometbft/cometbft/abci/types"
@@ -302,3 +304,46 @@ func DefaultClientCreator(addr, transport, dbDir string) ClientCreator {
 		return NewRemoteClientCreator(addr, transport, mustConnect)
 	}
 }
+
+// StateExporter returns the application behind creator if it runs in the same
+// process as CometBFT and implements [types.StateExporter]. With
+// [NewLocalClientCreator], calls are serialized with the ABCI calls; with the
+// other local client creators, synchronization is left up to the app.
+func StateExporter(creator ClientCreator) (types.StateExporter, bool) {
+	var app types.Application
+	switch c := creator.(type) {
+	case *localClientCreator:
+		if exporter, ok := c.app.(types.StateExporter); ok {
+			return &syncStateExporter{mtx: c.mtx, exporter: exporter}, true
+		}
+		return nil, false
+	case *connSyncLocalClientCreator:
+		app = c.app
+	case *consensusSyncLocalClientCreator:
+		app = c.app
+	case *unsyncLocalClientCreator:
+		app = c.app
+	default:
+		return nil, false
+	}
+	exporter, ok := app.(types.StateExporter)
+	return exporter, ok
+}
+
+// syncStateExporter holds the mutex of a local client while calling the app.
+type syncStateExporter struct {
+	mtx      *cmtsync.Mutex
+	exporter types.StateExporter
+}
+
+func (e *syncStateExporter) ExportState(ctx context.Context) ([]byte, error) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	return e.exporter.ExportState(ctx)
+}
+
+func (e *syncStateExporter) ImportState(ctx context.Context, height int64, state []byte) error {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	return e.exporter.ImportState(ctx, height, state)
+}
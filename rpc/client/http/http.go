@@ -376,6 +376,22 @@ func (c *baseRPCClient) ConsensusParams(
 	return result, nil
 }
 
+func (c *baseRPCClient) ProposerSchedule(
+	ctx context.Context,
+	count *int,
+) (*ctypes.ResultProposerSchedule, error) {
+	result := new(ctypes.ResultProposerSchedule)
+	params := make(map[string]any)
+	if count != nil {
+		params["count"] = count
+	}
+	_, err := c.caller.Call(ctx, "proposer_schedule", params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (c *baseRPCClient) Health(ctx context.Context) (*ctypes.ResultHealth, error) {
 	result := new(ctypes.ResultHealth)
 	_, err := c.caller.Call(ctx, "health", map[string]any{}, result)
@@ -142,6 +142,10 @@ func (c *Local) ConsensusParams(_ context.Context, height *int64) (*ctypes.Resul
 	return c.env.ConsensusParams(c.ctx, height)
 }
 
+func (c *Local) ProposerSchedule(_ context.Context, count *int) (*ctypes.ResultProposerSchedule, error) {
+	return c.env.ProposerSchedule(c.ctx, count)
+}
+
 func (c *Local) Health(context.Context) (*ctypes.ResultHealth, error) {
 	return c.env.Health(c.ctx)
 }
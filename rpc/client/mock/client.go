@@ -136,6 +136,10 @@ func (c Client) ConsensusParams(_ context.Context, height *int64) (*ctypes.Resul
 	return c.env.ConsensusParams(&rpctypes.Context{}, height)
 }
 
+func (c Client) ProposerSchedule(_ context.Context, count *int) (*ctypes.ResultProposerSchedule, error) {
+	return c.env.ProposerSchedule(&rpctypes.Context{}, count)
+}
+
 func (c Client) Health(_ context.Context) (*ctypes.ResultHealth, error) {
 	return c.env.Health(&rpctypes.Context{})
 }
@@ -621,6 +621,29 @@ func (_m *Client) OnStop() {
 	_m.Called()
 }
 
+// ProposerSchedule provides a mock function with given fields: ctx, count
+func (_m *Client) ProposerSchedule(ctx context.Context, count *int) (*coretypes.ResultProposerSchedule, error) {
+	ret := _m.Called(ctx, count)
+
+	var r0 *coretypes.ResultProposerSchedule
+	if rf, ok := ret.Get(0).(func(context.Context, *int) *coretypes.ResultProposerSchedule); ok {
+		r0 = rf(ctx, count)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*coretypes.ResultProposerSchedule)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *int) error); ok {
+		r1 = rf(ctx, count)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Quit provides a mock function with given fields:
 func (_m *Client) Quit() <-chan struct{} {
 	ret := _m.Called()
@@ -184,6 +184,31 @@ func TestGenesisAndValidators(t *testing.T) {
 	}
 }
 
+func TestProposerSchedule(t *testing.T) {
+	for i, c := range GetClients() {
+		gen, err := c.Genesis(context.Background())
+		require.NoError(t, err, "%d: %+v", i, err)
+		require.Len(t, gen.Genesis.Validators, 1)
+		gval := gen.Genesis.Validators[0]
+
+		count := 5
+		res, err := c.ProposerSchedule(context.Background(), &count)
+		require.NoError(t, err, "%d: %+v", i, err)
+		require.Len(t, res.Proposers, count)
+		assert.Equal(t, res.LastHeight+2, res.FinalUntil)
+		assert.NotEmpty(t, res.Note)
+		for j, p := range res.Proposers {
+			assert.Equal(t, res.LastHeight+1+int64(j), p.Height)
+			assert.Equal(t, gval.Address, p.Address)
+			assert.Equal(t, gval.PubKey, p.PubKey)
+		}
+
+		count = 0
+		_, err = c.ProposerSchedule(context.Background(), &count)
+		require.Error(t, err, "%d", i)
+	}
+}
+
 func TestGenesisChunked(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
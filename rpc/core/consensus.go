@@ -8,6 +8,7 @@ import (
 	"github.com/cometbft/cometbft/p2p"
 	ctypes "github.com/cometbft/cometbft/rpc/core/types"
 	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+	sm "github.com/cometbft/cometbft/state"
 	"github.com/cometbft/cometbft/types"
 )
 
@@ -53,6 +54,46 @@ func (env *Environment) Validators(
 	}, nil
 }
 
+// ProposerSchedule gets the expected proposers of round 0 of the count heights
+// following the latest block (default: 10, max: 100).
+//
+// Only the proposers up to final_until are final: the later ones assume that
+// the application does not update the validator set.
+//
+// More: https://docs.cometbft.com/main/rpc/#/Info/proposer_schedule
+func (env *Environment) ProposerSchedule(_ *rpctypes.Context, countPtr *int) (*ctypes.ResultProposerSchedule, error) {
+	count := defaultProposerScheduleCount
+	if countPtr != nil {
+		count = cmtmath.MinInt(*countPtr, maxProposerScheduleCount)
+		if count < 1 {
+			return nil, fmt.Errorf("count must be positive, got %d", *countPtr)
+		}
+	}
+
+	state, err := env.StateStore.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	validators, finalUntil := state.ProposerSchedule(count)
+	proposers := make([]ctypes.ScheduledProposer, 0, len(validators))
+	for i, val := range validators {
+		proposers = append(proposers, ctypes.ScheduledProposer{
+			Height:      state.LastBlockHeight + 1 + int64(i),
+			Address:     val.Address,
+			PubKey:      val.PubKey,
+			VotingPower: val.VotingPower,
+		})
+	}
+
+	return &ctypes.ResultProposerSchedule{
+		LastHeight: state.LastBlockHeight,
+		FinalUntil: finalUntil,
+		Proposers:  proposers,
+		Note:       sm.ProposerScheduleNote,
+	}, nil
+}
+
 // DumpConsensusState dumps consensus state.
 // UNSTABLE
 // More: https://docs.cometbft.com/main/rpc/#/Info/dump_consensus_state
@@ -24,6 +24,10 @@ const (
 	defaultPerPage = 30
 	maxPerPage     = 100
 
+	// number of heights returned by the proposer_schedule endpoint.
+	defaultProposerScheduleCount = 10
+	maxProposerScheduleCount     = 100
+
 	// SubscribeTimeout is the maximum time we wait to subscribe for an event.
 	// must be less than the server's write timeout (see rpcserver.DefaultConfig).
 	SubscribeTimeout = 5 * time.Second
@@ -55,6 +59,7 @@ type peers interface {
 	AddPrivatePeerIDs(peerIDs []string) error
 	DialPeersAsync(peers []string) error
 	Peers() p2p.IPeerSet
+	PeerScore(id p2p.ID) float64
 }
 
 // A reactor that transitions from block sync or state sync to consensus mode.
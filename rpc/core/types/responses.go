@@ -135,6 +135,7 @@ type Peer struct {
 	IsOutbound       bool                 `json:"is_outbound"`
 	ConnectionStatus p2p.ConnectionStatus `json:"connection_status"`
 	RemoteIP         string               `json:"remote_ip"`
+	Score            float64              `json:"score"`
 }
 
 // Validators for a height.
@@ -147,6 +148,24 @@ type ResultValidators struct {
 	Total int `json:"total"`
 }
 
+// ScheduledProposer is the expected proposer of round 0 of a height.
+type ScheduledProposer struct {
+	Height      int64         `json:"height"`
+	Address     types.Address `json:"address"`
+	PubKey      crypto.PubKey `json:"pub_key"`
+	VotingPower int64         `json:"voting_power"`
+}
+
+// Expected proposers of round 0 of the heights following the last block.
+type ResultProposerSchedule struct {
+	LastHeight int64 `json:"last_height"`
+	// Proposers up to this height are final. The later ones assume that the
+	// validator set is not updated.
+	FinalUntil int64               `json:"final_until"`
+	Proposers  []ScheduledProposer `json:"proposers"`
+	Note       string              `json:"note"`
+}
+
 // ConsensusParams for given height.
 type ResultConsensusParams struct {
 	BlockHeight     int64                 `json:"block_height"`
@@ -22,6 +22,8 @@ type Client interface {
 	VersionServiceClient
 	BlockServiceClient
 	BlockResultsServiceClient
+	ProposerServiceClient
+	LightBlockServiceClient
 
 	// Close the connection to the server. Any subsequent requests will fail.
 	Close() error
@@ -34,6 +36,8 @@ type clientBuilder struct {
 	versionServiceEnabled      bool
 	blockServiceEnabled        bool
 	blockResultsServiceEnabled bool
+	proposerServiceEnabled     bool
+	lightBlockServiceEnabled   bool
 }
 
 func newClientBuilder() *clientBuilder {
@@ -43,6 +47,8 @@ func newClientBuilder() *clientBuilder {
 		versionServiceEnabled:      true,
 		blockServiceEnabled:        true,
 		blockResultsServiceEnabled: true,
+		proposerServiceEnabled:     true,
+		lightBlockServiceEnabled:   true,
 	}
 }
 
@@ -56,6 +62,8 @@ type client struct {
 	VersionServiceClient
 	BlockServiceClient
 	BlockResultsServiceClient
+	ProposerServiceClient
+	LightBlockServiceClient
 }
 
 // Close implements Client.
@@ -105,6 +113,28 @@ func WithBlockResultsServiceEnabled(enabled bool) Option {
 	}
 }
 
+// WithProposerServiceEnabled allows control of whether or not to create a
+// client for interacting with the proposer service of a CometBFT node.
+//
+// If disabled and the client attempts to access the proposer service API, the
+// client will panic.
+func WithProposerServiceEnabled(enabled bool) Option {
+	return func(b *clientBuilder) {
+		b.proposerServiceEnabled = enabled
+	}
+}
+
+// WithLightBlockServiceEnabled allows control of whether or not to create a
+// client for interacting with the light block service of a CometBFT node.
+//
+// If disabled and the client attempts to access the light block service API,
+// the client will panic.
+func WithLightBlockServiceEnabled(enabled bool) Option {
+	return func(b *clientBuilder) {
+		b.lightBlockServiceEnabled = enabled
+	}
+}
+
 // WithGRPCDialOption allows passing lower-level gRPC dial options through to
 // the gRPC dialer when creating the client.
 func WithGRPCDialOption(opt ggrpc.DialOption) Option {
@@ -144,10 +174,20 @@ func New(_ context.Context, addr string, opts ...Option) (Client, error) {
 	if builder.blockResultsServiceEnabled {
 		blockResultServiceClient = newBlockResultsServiceClient(conn)
 	}
+	proposerServiceClient := newDisabledProposerServiceClient()
+	if builder.proposerServiceEnabled {
+		proposerServiceClient = newProposerServiceClient(conn)
+	}
+	lightBlockServiceClient := newDisabledLightBlockServiceClient()
+	if builder.lightBlockServiceEnabled {
+		lightBlockServiceClient = newLightBlockServiceClient(conn)
+	}
 	return &client{
 		conn:                      conn,
 		VersionServiceClient:      versionServiceClient,
 		BlockServiceClient:        blockServiceClient,
 		BlockResultsServiceClient: blockResultServiceClient,
+		ProposerServiceClient:     proposerServiceClient,
+		LightBlockServiceClient:   lightBlockServiceClient,
 	}, nil
 }
@@ -0,0 +1,145 @@
+package client
+
+import (
+	"context"
+
+	"github.com/cosmos/gogoproto/grpc"
+
+	pbsvc "github.com/cometbft/cometbft/api/cometbft/services/light_block/v1"
+	"github.com/cometbft/cometbft/types"
+)
+
+// LatestLightBlockResult type used in GetLatestLightBlock and send to the
+// client via a channel.
+type LatestLightBlockResult struct {
+	LightBlock *types.LightBlock
+	Error      error
+}
+
+type getLatestLightBlockConfig struct {
+	chSize uint
+}
+
+type GetLatestLightBlockOption func(*getLatestLightBlockConfig)
+
+// GetLatestLightBlockChannelSize allows control over the channel size. If not
+// used or the channel size is set to 0, an unbuffered channel will be created.
+func GetLatestLightBlockChannelSize(sz uint) GetLatestLightBlockOption {
+	return func(opts *getLatestLightBlockConfig) {
+		opts.chSize = sz
+	}
+}
+
+// LightBlockServiceClient provides the light blocks used by light clients.
+type LightBlockServiceClient interface {
+	// GetLightBlock attempts to retrieve the light block at the given height,
+	// or the latest one if height is 0.
+	GetLightBlock(ctx context.Context, height int64) (*types.LightBlock, error)
+
+	// GetLatestLightBlock sends the light block of the latest committed block
+	// to the resulting output channel as blocks are committed.
+	GetLatestLightBlock(ctx context.Context, opts ...GetLatestLightBlockOption) (<-chan LatestLightBlockResult, error)
+
+	// ReportEvidence adds the evidence to the evidence pool of the node and
+	// returns its hash.
+	ReportEvidence(ctx context.Context, ev types.Evidence) ([]byte, error)
+}
+
+type lightBlockServiceClient struct {
+	client pbsvc.LightBlockServiceClient
+}
+
+func newLightBlockServiceClient(conn grpc.ClientConn) LightBlockServiceClient {
+	return &lightBlockServiceClient{
+		client: pbsvc.NewLightBlockServiceClient(conn),
+	}
+}
+
+// GetLightBlock implements LightBlockServiceClient GetLightBlock.
+func (c *lightBlockServiceClient) GetLightBlock(ctx context.Context, height int64) (*types.LightBlock, error) {
+	res, err := c.client.GetByHeight(ctx, &pbsvc.GetByHeightRequest{
+		Height: height,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return types.LightBlockFromProto(res.LightBlock)
+}
+
+// GetLatestLightBlock implements LightBlockServiceClient GetLatestLightBlock.
+func (c *lightBlockServiceClient) GetLatestLightBlock(ctx context.Context, opts ...GetLatestLightBlockOption) (<-chan LatestLightBlockResult, error) {
+	latestClient, err := c.client.GetLatest(ctx, &pbsvc.GetLatestRequest{})
+	if err != nil {
+		return nil, ErrStreamSetup{Source: err}
+	}
+
+	cfg := &getLatestLightBlockConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	resultCh := make(chan LatestLightBlockResult, cfg.chSize)
+
+	go func(client pbsvc.LightBlockService_GetLatestClient) {
+		defer close(resultCh)
+		for {
+			response, err := client.Recv()
+			var lb *types.LightBlock
+			if err == nil {
+				lb, err = types.LightBlockFromProto(response.LightBlock)
+			}
+			if err != nil {
+				res := LatestLightBlockResult{Error: ErrStreamReceive{Source: err}}
+				select {
+				case <-ctx.Done():
+				case resultCh <- res:
+				}
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case resultCh <- LatestLightBlockResult{LightBlock: lb}:
+			default:
+				// Skip sending this result because the channel is full - the
+				// client will get the next one once the channel opens up again
+			}
+		}
+	}(latestClient)
+
+	return resultCh, nil
+}
+
+// ReportEvidence implements LightBlockServiceClient ReportEvidence.
+func (c *lightBlockServiceClient) ReportEvidence(ctx context.Context, ev types.Evidence) ([]byte, error) {
+	evp, err := types.EvidenceToProto(ev)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.client.ReportEvidence(ctx, &pbsvc.ReportEvidenceRequest{Evidence: evp})
+	if err != nil {
+		return nil, err
+	}
+	return res.Hash, nil
+}
+
+type disabledLightBlockServiceClient struct{}
+
+func newDisabledLightBlockServiceClient() LightBlockServiceClient {
+	return &disabledLightBlockServiceClient{}
+}
+
+// GetLightBlock implements LightBlockServiceClient GetLightBlock - disabled client.
+func (*disabledLightBlockServiceClient) GetLightBlock(context.Context, int64) (*types.LightBlock, error) {
+	panic("light block service client is disabled")
+}
+
+// GetLatestLightBlock implements LightBlockServiceClient GetLatestLightBlock - disabled client.
+func (*disabledLightBlockServiceClient) GetLatestLightBlock(context.Context, ...GetLatestLightBlockOption) (<-chan LatestLightBlockResult, error) {
+	panic("light block service client is disabled")
+}
+
+// ReportEvidence implements LightBlockServiceClient ReportEvidence - disabled client.
+func (*disabledLightBlockServiceClient) ReportEvidence(context.Context, types.Evidence) ([]byte, error) {
+	panic("light block service client is disabled")
+}
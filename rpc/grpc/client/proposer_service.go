@@ -0,0 +1,88 @@
+package client
+
+import (
+	"context"
+
+	"github.com/cosmos/gogoproto/grpc"
+
+	pbsvc "github.com/cometbft/cometbft/api/cometbft/services/proposer/v1"
+	"github.com/cometbft/cometbft/crypto"
+	"github.com/cometbft/cometbft/crypto/encoding"
+)
+
+// ScheduledProposer is the expected proposer of round 0 of a height.
+type ScheduledProposer struct {
+	Height      int64
+	Address     []byte
+	PubKey      crypto.PubKey
+	VotingPower int64
+}
+
+// ProposerSchedule contains the expected proposers of round 0 of the heights
+// following LastHeight. Only the proposers up to FinalUntil are final: the
+// later ones assume that the application does not update the validator set.
+type ProposerSchedule struct {
+	LastHeight int64
+	FinalUntil int64
+	Proposers  []ScheduledProposer
+	// Note explains the limits of the schedule.
+	Note string
+}
+
+// ProposerServiceClient provides the expected proposers of the upcoming
+// heights.
+type ProposerServiceClient interface {
+	// GetProposerSchedule returns the expected proposers of the count heights
+	// following the latest block. If count is 0, the server default is used.
+	GetProposerSchedule(ctx context.Context, count int64) (*ProposerSchedule, error)
+}
+
+type proposerServiceClient struct {
+	client pbsvc.ProposerServiceClient
+}
+
+func newProposerServiceClient(conn grpc.ClientConn) ProposerServiceClient {
+	return &proposerServiceClient{
+		client: pbsvc.NewProposerServiceClient(conn),
+	}
+}
+
+// GetProposerSchedule implements ProposerServiceClient.
+func (c *proposerServiceClient) GetProposerSchedule(ctx context.Context, count int64) (*ProposerSchedule, error) {
+	res, err := c.client.GetProposerSchedule(ctx, &pbsvc.GetProposerScheduleRequest{Count: count})
+	if err != nil {
+		return nil, err
+	}
+
+	proposers := make([]ScheduledProposer, 0, len(res.Proposers))
+	for _, p := range res.Proposers {
+		pubKey, err := encoding.PubKeyFromTypeAndBytes(p.PubKeyType, p.PubKeyBytes)
+		if err != nil {
+			return nil, err
+		}
+		proposers = append(proposers, ScheduledProposer{
+			Height:      p.Height,
+			Address:     p.Address,
+			PubKey:      pubKey,
+			VotingPower: p.VotingPower,
+		})
+	}
+
+	return &ProposerSchedule{
+		LastHeight: res.LastHeight,
+		FinalUntil: res.FinalUntil,
+		Proposers:  proposers,
+		Note:       res.Note,
+	}, nil
+}
+
+type disabledProposerServiceClient struct{}
+
+func newDisabledProposerServiceClient() ProposerServiceClient {
+	return &disabledProposerServiceClient{}
+}
+
+// GetProposerSchedule implements ProposerServiceClient.
+func (*disabledProposerServiceClient) GetProposerSchedule(context.Context, int64) (*ProposerSchedule, error) {
+	panic("proposer service client is disabled")
+}
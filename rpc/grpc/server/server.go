@@ -9,11 +9,15 @@ import (
 
 	pbblocksvc "github.com/cometbft/cometbft/api/cometbft/services/block/v1"
 	brs "github.com/cometbft/cometbft/api/cometbft/services/block_results/v1"
+	pblightblocksvc "github.com/cometbft/cometbft/api/cometbft/services/light_block/v1"
+	pbproposersvc "github.com/cometbft/cometbft/api/cometbft/services/proposer/v1"
 	pbversionsvc "github.com/cometbft/cometbft/api/cometbft/services/version/v1"
 	"github.com/cometbft/cometbft/libs/log"
 	grpcerr "github.com/cometbft/cometbft/rpc/grpc/errors"
 	"github.com/cometbft/cometbft/rpc/grpc/server/services/blockresultservice"
 	"github.com/cometbft/cometbft/rpc/grpc/server/services/blockservice"
+	"github.com/cometbft/cometbft/rpc/grpc/server/services/lightblockservice"
+	"github.com/cometbft/cometbft/rpc/grpc/server/services/proposerservice"
 	"github.com/cometbft/cometbft/rpc/grpc/server/services/versionservice"
 	sm "github.com/cometbft/cometbft/state"
 	"github.com/cometbft/cometbft/store"
@@ -29,6 +33,8 @@ type serverBuilder struct {
 	versionService      pbversionsvc.VersionServiceServer
 	blockService        pbblocksvc.BlockServiceServer
 	blockResultsService brs.BlockResultsServiceServer
+	proposerService     pbproposersvc.ProposerServiceServer
+	lightBlockService   pblightblocksvc.LightBlockServiceServer
 	logger              log.Logger
 	grpcOpts            []grpc.ServerOption
 }
@@ -74,6 +80,27 @@ func WithBlockResultsService(bs *store.BlockStore, ss sm.Store, logger log.Logge
 	}
 }
 
+// WithProposerService enables the proposer service on the CometBFT server.
+func WithProposerService(ss sm.Store, logger log.Logger) Option {
+	return func(b *serverBuilder) {
+		b.proposerService = proposerservice.New(ss, logger)
+	}
+}
+
+// WithLightBlockService enables the light block service on the CometBFT
+// server.
+func WithLightBlockService(
+	bs *store.BlockStore,
+	ss sm.Store,
+	evpool sm.EvidencePool,
+	eventBus *types.EventBus,
+	logger log.Logger,
+) Option {
+	return func(b *serverBuilder) {
+		b.lightBlockService = lightblockservice.New(bs, ss, evpool, eventBus, logger)
+	}
+}
+
 // WithLogger enables logging using the given logger. If not specified, the
 // gRPC server does not log anything.
 func WithLogger(logger log.Logger) Option {
@@ -113,6 +140,14 @@ func Serve(listener net.Listener, opts ...Option) error {
 		brs.RegisterBlockResultsServiceServer(server, b.blockResultsService)
 		b.logger.Debug("Registered block results service")
 	}
+	if b.proposerService != nil {
+		pbproposersvc.RegisterProposerServiceServer(server, b.proposerService)
+		b.logger.Debug("Registered proposer service")
+	}
+	if b.lightBlockService != nil {
+		pblightblocksvc.RegisterLightBlockServiceServer(server, b.lightBlockService)
+		b.logger.Debug("Registered light block service")
+	}
 	b.logger.Info("serve", "msg", fmt.Sprintf("Starting gRPC server on %s", listener.Addr()))
 	return server.Serve(b.listener)
 }
@@ -0,0 +1,188 @@
+package lightblockservice
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pbsvc "github.com/cometbft/cometbft/api/cometbft/services/light_block/v1"
+	ptypes "github.com/cometbft/cometbft/api/cometbft/types/v1"
+	"github.com/cometbft/cometbft/internal/rpctrace"
+	"github.com/cometbft/cometbft/libs/log"
+	cmtpubsub "github.com/cometbft/cometbft/libs/pubsub"
+	sm "github.com/cometbft/cometbft/state"
+	"github.com/cometbft/cometbft/store"
+	"github.com/cometbft/cometbft/types"
+)
+
+type lightBlockService struct {
+	blockStore   *store.BlockStore
+	stateStore   sm.Store
+	evidencePool sm.EvidencePool
+	eventBus     *types.EventBus
+	logger       log.Logger
+}
+
+// New creates a new CometBFT light block service server.
+func New(
+	bs *store.BlockStore,
+	ss sm.Store,
+	evpool sm.EvidencePool,
+	eventBus *types.EventBus,
+	logger log.Logger,
+) pbsvc.LightBlockServiceServer {
+	return &lightBlockService{
+		blockStore:   bs,
+		stateStore:   ss,
+		evidencePool: evpool,
+		eventBus:     eventBus,
+		logger:       logger.With("service", "LightBlockService"),
+	}
+}
+
+// GetByHeight implements v1.LightBlockServiceServer GetByHeight method.
+func (s *lightBlockService) GetByHeight(_ context.Context, req *pbsvc.GetByHeightRequest) (*pbsvc.GetByHeightResponse, error) {
+	logger := s.logger.With("endpoint", "GetByHeight")
+
+	height, latestHeight := req.Height, s.blockStore.Height()
+	switch {
+	case height < 0:
+		return nil, status.Error(codes.InvalidArgument, "Height cannot be negative")
+	case height == 0:
+		height = latestHeight
+	}
+	switch baseHeight := s.blockStore.Base(); {
+	case height > latestHeight || latestHeight == 0:
+		// OutOfRange tells providers that the node has not caught up yet.
+		return nil, status.Errorf(codes.OutOfRange, "Requested height %d is higher than latest height %d", height, latestHeight)
+	case height < baseHeight:
+		return nil, status.Errorf(codes.NotFound, "Requested height %d is below base height %d", height, baseHeight)
+	}
+
+	lb, err := s.lightBlock(height, logger)
+	if err != nil {
+		return nil, err
+	}
+	return &pbsvc.GetByHeightResponse{LightBlock: lb}, nil
+}
+
+// lightBlock loads the light block at the given height, which must be
+// between the base and latest heights of the block store.
+func (s *lightBlockService) lightBlock(height int64, logger log.Logger) (*ptypes.LightBlock, error) {
+	blockMeta := s.blockStore.LoadBlockMeta(height)
+	if blockMeta == nil {
+		return nil, status.Errorf(codes.NotFound, "Block not found for height %d", height)
+	}
+
+	// If the next block has not been committed yet, use a non-canonical
+	// commit.
+	var commit *types.Commit
+	if height == s.blockStore.Height() {
+		commit = s.blockStore.LoadSeenCommit(height)
+	} else {
+		commit = s.blockStore.LoadBlockCommit(height)
+	}
+	if commit == nil {
+		return nil, status.Errorf(codes.NotFound, "Commit not found for height %d", height)
+	}
+
+	vals, err := s.stateStore.LoadValidators(height)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "Validators not found for height %d", height)
+	}
+
+	lb := &types.LightBlock{
+		SignedHeader: &types.SignedHeader{Header: &blockMeta.Header, Commit: commit},
+		ValidatorSet: vals,
+	}
+	lbp, err := lb.ToProto()
+	if err != nil {
+		traceID, _ := rpctrace.New()
+		logger.Error("Error attempting to convert light block to its Protobuf representation", "err", err, "height", height, "traceID", traceID)
+		return nil, status.Errorf(codes.Internal, "Failed to load light block (see logs for trace ID: %s)", traceID)
+	}
+	return lbp, nil
+}
+
+// GetLatest implements v1.LightBlockServiceServer GetLatest method.
+func (s *lightBlockService) GetLatest(_ *pbsvc.GetLatestRequest, stream pbsvc.LightBlockService_GetLatestServer) error {
+	logger := s.logger.With("endpoint", "GetLatest")
+
+	traceID, err := rpctrace.New()
+	if err != nil {
+		logger.Error("Error generating RPC trace ID", "err", err)
+		return status.Error(codes.Internal, "Internal server error")
+	}
+
+	// The trace ID is reused as a unique subscriber ID
+	sub, err := s.eventBus.Subscribe(context.Background(), traceID, types.QueryForEvent(types.EventNewBlock), 1)
+	if err != nil {
+		logger.Error("Cannot subscribe to new block events", "err", err, "traceID", traceID)
+		return status.Errorf(codes.Internal, "Cannot subscribe to new block events (see logs for trace ID: %s)", traceID)
+	}
+	defer func() {
+		if err := s.eventBus.Unsubscribe(context.Background(), traceID, types.QueryForEvent(types.EventNewBlock)); err != nil {
+			logger.Debug("Failed to unsubscribe from new block events", "err", err, "traceID", traceID)
+		}
+	}()
+
+	for {
+		select {
+		case msg := <-sub.Out():
+			height, err := getHeightFromMsg(msg)
+			if err != nil {
+				logger.Error("Failed to extract height from subscription message", "err", err, "traceID", traceID)
+				return status.Errorf(codes.Internal, "Internal server error (see logs for trace ID: %s)", traceID)
+			}
+			lb, err := s.lightBlock(height, logger)
+			if err != nil {
+				return err
+			}
+			if err := stream.Send(&pbsvc.GetLatestResponse{LightBlock: lb}); err != nil {
+				logger.Error("Failed to stream new light block", "err", err, "height", height, "traceID", traceID)
+				return status.Errorf(codes.Unavailable, "Cannot send stream response (see logs for trace ID: %s)", traceID)
+			}
+		case <-sub.Canceled():
+			switch sub.Err() {
+			case cmtpubsub.ErrUnsubscribed:
+				return status.Error(codes.Canceled, "Subscription terminated")
+			case nil:
+				return status.Error(codes.Canceled, "Subscription canceled without errors")
+			default:
+				logger.Info("Subscription canceled with errors", "err", sub.Err(), "traceID", traceID)
+				return status.Errorf(codes.Canceled, "Subscription canceled with errors (see logs for trace ID: %s)", traceID)
+			}
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		}
+	}
+}
+
+// ReportEvidence implements v1.LightBlockServiceServer ReportEvidence method.
+func (s *lightBlockService) ReportEvidence(_ context.Context, req *pbsvc.ReportEvidenceRequest) (*pbsvc.ReportEvidenceResponse, error) {
+	if req.Evidence == nil {
+		return nil, status.Error(codes.InvalidArgument, "Evidence is required")
+	}
+	ev, err := types.EvidenceFromProto(req.Evidence)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid evidence: %v", err)
+	}
+	if err := ev.ValidateBasic(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid evidence: %v", err)
+	}
+	if err := s.evidencePool.AddEvidence(ev); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Failed to add evidence: %v", err)
+	}
+	return &pbsvc.ReportEvidenceResponse{Hash: ev.Hash()}, nil
+}
+
+func getHeightFromMsg(msg cmtpubsub.Message) (int64, error) {
+	switch eventType := msg.Data().(type) {
+	case types.EventDataNewBlock:
+		return eventType.Block.Height, nil
+	default:
+		return -1, fmt.Errorf("unexpected event type: %v", eventType)
+	}
+}
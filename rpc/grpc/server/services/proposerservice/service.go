@@ -0,0 +1,73 @@
+package proposerservice
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pbsvc "github.com/cometbft/cometbft/api/cometbft/services/proposer/v1"
+	"github.com/cometbft/cometbft/libs/log"
+	sm "github.com/cometbft/cometbft/state"
+)
+
+const (
+	defaultCount = 10
+	maxCount     = 100
+)
+
+type proposerService struct {
+	stateStore sm.Store
+	logger     log.Logger
+}
+
+// New creates a new CometBFT proposer service server.
+func New(ss sm.Store, logger log.Logger) pbsvc.ProposerServiceServer {
+	return &proposerService{
+		stateStore: ss,
+		logger:     logger.With("service", "ProposerService"),
+	}
+}
+
+// GetProposerSchedule implements v1.ProposerServiceServer.
+func (s *proposerService) GetProposerSchedule(
+	_ context.Context,
+	req *pbsvc.GetProposerScheduleRequest,
+) (*pbsvc.GetProposerScheduleResponse, error) {
+	logger := s.logger.With("endpoint", "GetProposerSchedule")
+
+	count := req.Count
+	switch {
+	case count < 0:
+		return nil, status.Errorf(codes.InvalidArgument, "Count must not be negative (got %d)", count)
+	case count == 0:
+		count = defaultCount
+	case count > maxCount:
+		count = maxCount
+	}
+
+	state, err := s.stateStore.Load()
+	if err != nil {
+		logger.Error("Error loading state", "err", err)
+		return nil, status.Error(codes.Internal, "Internal server error")
+	}
+
+	validators, finalUntil := state.ProposerSchedule(int(count))
+	proposers := make([]*pbsvc.ScheduledProposer, 0, len(validators))
+	for i, val := range validators {
+		proposers = append(proposers, &pbsvc.ScheduledProposer{
+			Height:      state.LastBlockHeight + 1 + int64(i),
+			Address:     val.Address,
+			PubKeyBytes: val.PubKey.Bytes(),
+			PubKeyType:  val.PubKey.Type(),
+			VotingPower: val.VotingPower,
+		})
+	}
+
+	return &pbsvc.GetProposerScheduleResponse{
+		LastHeight: state.LastBlockHeight,
+		FinalUntil: finalUntil,
+		Proposers:  proposers,
+		Note:       sm.ProposerScheduleNote,
+	}, nil
+}
@@ -32,6 +32,9 @@ type BlockExecutor struct {
 
 	pruner *Pruner
 
+	// takes snapshots of the committed state, if set
+	snapshotter Snapshotter
+
 	// execute the app against this
 	proxyApp proxy.AppConnConsensus
 
@@ -59,6 +62,19 @@ func BlockExecutorWithPruner(pruner *Pruner) BlockExecutorOption {
 	}
 }
 
+// Snapshotter takes snapshots of the state of the node and the application.
+type Snapshotter interface {
+	// Snapshot is called once the block at state.LastBlockHeight is committed
+	// and the state saved, before the next block is executed.
+	Snapshot(state State) error
+}
+
+func BlockExecutorWithSnapshotter(snapshotter Snapshotter) BlockExecutorOption {
+	return func(blockExec *BlockExecutor) {
+		blockExec.snapshotter = snapshotter
+	}
+}
+
 func BlockExecutorWithMetrics(metrics *Metrics) BlockExecutorOption {
 	return func(blockExec *BlockExecutor) {
 		blockExec.metrics = metrics
@@ -321,6 +337,13 @@ func (blockExec *BlockExecutor) applyBlock(state State, blockID types.BlockID, b
 
 	fail.Fail() // XXX
 
+	// Snapshot the committed state before the app moves on to the next block.
+	if blockExec.snapshotter != nil {
+		if err := blockExec.snapshotter.Snapshot(state); err != nil {
+			blockExec.logger.Error("Failed to take snapshot", "height", state.LastBlockHeight, "err", err)
+		}
+	}
+
 	// Prune old heights, if requested by ABCI app.
 	if retainHeight > 0 && blockExec.pruner != nil {
 		err := blockExec.pruner.SetApplicationBlockRetainHeight(retainHeight)
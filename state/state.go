@@ -135,6 +135,25 @@ func (state State) IsEmpty() bool {
 	return state.Validators == nil // XXX can't compare to Empty
 }
 
+// ProposerScheduleNote explains to API users what ProposerSchedule predicts.
+const ProposerScheduleNote = "Proposers of heights above final_until assume that the application " +
+	"does not update the validator set: validator set updates change them. " +
+	"Every proposer is the one of round 0; later rounds have different proposers."
+
+// ProposerSchedule returns the expected proposers of round 0 of the n heights
+// following LastBlockHeight. The proposers up to the returned finalUntil
+// height are final, since the validator sets of those heights are already
+// known. The later ones assume that the application does not update the
+// validator set.
+func (state State) ProposerSchedule(n int) (proposers []*types.Validator, finalUntil int64) {
+	finalUntil = state.LastBlockHeight + 2
+	if state.IsEmpty() || n <= 0 {
+		return nil, finalUntil
+	}
+	proposers = []*types.Validator{state.Validators.GetProposer()}
+	return append(proposers, state.NextValidators.ProposerSchedule(n-1)...), finalUntil
+}
+
 // ToProto takes the local state type and returns the equivalent proto type.
 func (state *State) ToProto() (*cmtstate.State, error) {
 	if state == nil {
@@ -451,6 +451,39 @@ func testProposerFreq(t *testing.T, caseNum int, valSet *types.ValidatorSet) {
 
 // TestProposerPriorityDoesNotGetResetToZero assert that we preserve accum when calling updateState
 // see https://github.com/tendermint/tendermint/issues/2718
+func TestStateProposerSchedule(t *testing.T) {
+	tearDown, _, state := setupTestCase(t)
+	defer tearDown(t)
+
+	vals := make([]*types.Validator, 0, 3)
+	for _, power := range []int64{10, 3, 5} {
+		pubKey := ed25519.GenPrivKey().PubKey()
+		vals = append(vals, types.NewValidator(pubKey, power))
+	}
+	state.Validators = types.NewValidatorSet(vals)
+	state.NextValidators = state.Validators.CopyIncrementProposerPriority(1)
+
+	schedule, finalUntil := state.ProposerSchedule(10)
+	require.Len(t, schedule, 10)
+	assert.Equal(t, state.LastBlockHeight+2, finalUntil)
+
+	// Without validator set updates, the schedule matches the proposers of
+	// the following heights.
+	for i, proposer := range schedule {
+		assert.Equal(t, state.Validators.GetProposer().Address, proposer.Address, "height %d", state.LastBlockHeight+1)
+
+		block := makeBlock(state, state.LastBlockHeight+1, new(types.Commit))
+		bps, err := block.MakePartSet(testPartSize)
+		require.NoError(t, err)
+		blockID := types.BlockID{Hash: block.Hash(), PartSetHeader: bps.Header()}
+		state, err = sm.UpdateState(state, blockID, &block.Header, &abci.FinalizeBlockResponse{}, nil)
+		require.NoError(t, err, "height offset %d", i)
+	}
+
+	schedule, _ = state.ProposerSchedule(0)
+	assert.Nil(t, schedule)
+}
+
 func TestProposerPriorityDoesNotGetResetToZero(t *testing.T) {
 	tearDown, _, state := setupTestCase(t)
 	defer tearDown(t)
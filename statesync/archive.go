@@ -0,0 +1,219 @@
+package statesync
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/config"
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/proxy"
+	sm "github.com/cometbft/cometbft/state"
+	"github.com/cometbft/cometbft/types"
+)
+
+// maxArchiveRetries is the number of times the restoration of a snapshot from
+// an archive is retried when the application asks for it.
+const maxArchiveRetries = 5
+
+// chunkLoader loads the chunks of a snapshot from local storage. It returns
+// nil if the chunk does not exist.
+type chunkLoader interface {
+	LoadChunk(height uint64, index uint32) ([]byte, error)
+}
+
+// snapshotArchive is a snapshot stored in a directory holding a metadata.json
+// file, describing the snapshot, and one file per chunk named after the chunk
+// index. This is the layout of the snapshots taken by the Snapshotter. The
+// directory can also be packed in a tar file, optionally gzipped.
+type snapshotArchive struct {
+	dir       string
+	extracted bool
+	snapshot  *snapshot
+}
+
+var _ chunkLoader = (*snapshotArchive)(nil)
+
+// openSnapshotArchive opens the archive at path, extracting it into a
+// directory created in tempDir if it is a tar file. Callers must call Close()
+// when done.
+func openSnapshotArchive(path, tempDir string) (*snapshotArchive, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	archive := &snapshotArchive{dir: path}
+	if !fi.IsDir() {
+		archive.dir, err = os.MkdirTemp(tempDir, "cmt-statesync-archive")
+		if err != nil {
+			return nil, fmt.Errorf("unable to create temp dir for snapshot archive: %w", err)
+		}
+		archive.extracted = true
+		if err := extractSnapshotArchive(path, archive.dir); err != nil {
+			archive.Close()
+			return nil, fmt.Errorf("failed to extract %s: %w", path, err)
+		}
+	}
+
+	bz, err := os.ReadFile(filepath.Join(archive.dir, managedMetadataFile))
+	if err == nil {
+		archive.snapshot = &snapshot{}
+		err = json.Unmarshal(bz, archive.snapshot)
+	}
+	if err != nil {
+		archive.Close()
+		return nil, fmt.Errorf("invalid snapshot metadata: %w", err)
+	}
+	if archive.snapshot.Height == 0 || archive.snapshot.Chunks == 0 {
+		archive.Close()
+		return nil, errors.New("invalid snapshot metadata: height and chunks must be positive")
+	}
+	return archive, nil
+}
+
+// extractSnapshotArchive extracts the metadata and chunk files of the tar file
+// at path into dir, ignoring the directories of the archive.
+func extractSnapshotArchive(path, dir string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var rd io.Reader = bufio.NewReader(f)
+	if magic, err := rd.(*bufio.Reader).Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(rd)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		rd = gz
+	}
+
+	tr := tar.NewReader(rd)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := filepath.Base(hdr.Name)
+		if _, err := strconv.ParseUint(name, 10, 32); err != nil && name != managedMetadataFile {
+			continue
+		}
+		out, err := os.OpenFile(filepath.Join(dir, name), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err != nil {
+			return err
+		}
+		_, err = io.CopyN(out, tr, hdr.Size)
+		if cerr := out.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// LoadChunk implements chunkLoader.
+func (a *snapshotArchive) LoadChunk(height uint64, index uint32) ([]byte, error) {
+	if height != a.snapshot.Height {
+		return nil, nil
+	}
+	bz, err := os.ReadFile(filepath.Join(a.dir, strconv.FormatUint(uint64(index), 10)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return bz, err
+}
+
+// Close removes the extracted files, if any.
+func (a *snapshotArchive) Close() error {
+	if !a.extracted {
+		return nil
+	}
+	return os.RemoveAll(a.dir)
+}
+
+// RestoreFromArchive restores the snapshot stored in the archive at path, a
+// directory or a tar file as described in snapshotArchive, instead of
+// fetching it from peers. Snapshots in the ManagedSnapshotFormat format are
+// imported through stateExporter, which may be nil for other formats. The
+// snapshot is verified with stateProvider. It returns the state and last
+// commit at the snapshot height, which the caller must store in the state
+// store and block store. The restoration is aborted when ctx is canceled, or
+// after it was retried maxArchiveRetries times.
+func RestoreFromArchive(
+	ctx context.Context,
+	cfg config.StateSyncConfig,
+	logger log.Logger,
+	conn proxy.AppConnSnapshot,
+	connQuery proxy.AppConnQuery,
+	stateProvider StateProvider,
+	stateExporter abci.StateExporter,
+	path string,
+) (sm.State, *types.Commit, error) {
+	archive, err := openSnapshotArchive(path, cfg.TempDir)
+	if err != nil {
+		return sm.State{}, nil, err
+	}
+	defer archive.Close()
+
+	s := archive.snapshot
+	logger.Info("Restoring snapshot from archive", "path", path, "height", s.Height, "format", s.Format,
+		"chunks", s.Chunks, "hash", log.NewLazySprintf("%X", s.Hash))
+
+	syncer := newSyncer(cfg, logger, conn, connQuery, stateProvider, cfg.TempDir)
+	syncer.stateExporter = stateExporter
+	syncer.chunkLoader = archive
+
+	chunks, err := newChunkQueue(s, cfg.TempDir)
+	if err != nil {
+		return sm.State{}, nil, fmt.Errorf("failed to create chunk queue: %w", err)
+	}
+	defer chunks.Close()
+
+	// Closing the queue stops the restoration in progress.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			chunks.Close()
+		case <-done:
+		}
+	}()
+
+	for retries := 0; ; retries++ {
+		state, commit, err := syncer.Sync(s, chunks)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return sm.State{}, nil, ctxErr
+		}
+		if errors.Is(err, errRetrySnapshot) {
+			if retries >= maxArchiveRetries {
+				return sm.State{}, nil, fmt.Errorf("failed to restore snapshot after %d retries: %w", retries, err)
+			}
+			chunks.RetryAll()
+			logger.Info("Retrying snapshot", "height", s.Height, "format", s.Format)
+			continue
+		}
+		if err != nil {
+			return sm.State{}, nil, fmt.Errorf("failed to restore snapshot: %w", err)
+		}
+		return state, commit, nil
+	}
+}
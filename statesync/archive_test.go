@@ -0,0 +1,188 @@
+package statesync
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/config"
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/proxy"
+	proxymocks "github.com/cometbft/cometbft/proxy/mocks"
+	sm "github.com/cometbft/cometbft/state"
+	"github.com/cometbft/cometbft/statesync/mocks"
+	"github.com/cometbft/cometbft/types"
+)
+
+func mockArchiveProviders(state sm.State, commit *types.Commit) (*mocks.StateProvider, *proxymocks.AppConnQuery) {
+	height := uint64(state.LastBlockHeight)
+	stateProvider := &mocks.StateProvider{}
+	stateProvider.On("AppHash", mock.Anything, height).Return(state.AppHash, nil)
+	stateProvider.On("State", mock.Anything, height).Return(state, nil)
+	stateProvider.On("Commit", mock.Anything, height).Return(commit, nil)
+	connQuery := &proxymocks.AppConnQuery{}
+	connQuery.On("Info", mock.Anything, proxy.InfoRequest).Return(&abci.InfoResponse{
+		AppVersion:       state.Version.Consensus.App,
+		LastBlockHeight:  state.LastBlockHeight,
+		LastBlockAppHash: state.AppHash,
+	}, nil)
+	return stateProvider, connQuery
+}
+
+func TestRestoreFromArchive_Directory(t *testing.T) {
+	state := makeManagedSnapshotState(4)
+	commit := &types.Commit{BlockID: types.BlockID{Hash: []byte("blockhash")}}
+	exporter := &testStateExporter{state: []byte("app_state")}
+
+	// A snapshot taken by the snapshotter can be restored from its directory.
+	dir := t.TempDir()
+	snapshotter, err := NewSnapshotter(dir, exporter, 4, 1)
+	require.NoError(t, err)
+	require.NoError(t, snapshotter.Snapshot(state))
+
+	stateProvider, connQuery := mockArchiveProviders(state, commit)
+	importer := &testStateExporter{}
+	cfg := config.DefaultStateSyncConfig()
+	cfg.TempDir = t.TempDir()
+
+	newState, newCommit, err := RestoreFromArchive(context.Background(), *cfg, log.NewNopLogger(),
+		&proxymocks.AppConnSnapshot{}, connQuery, stateProvider, importer, filepath.Join(dir, "4"))
+	require.NoError(t, err)
+	assert.Equal(t, state, newState)
+	assert.Equal(t, commit, newCommit)
+	assert.Equal(t, exporter.state, importer.importedState)
+}
+
+func TestRestoreFromArchive_TarGz(t *testing.T) {
+	state := makeManagedSnapshotState(4)
+	commit := &types.Commit{BlockID: types.BlockID{Hash: []byte("blockhash")}}
+	s := &snapshot{Height: 4, Format: 1, Chunks: 2, Hash: []byte{1, 2}, Metadata: []byte{3}}
+	chunks := [][]byte{{4, 0}, {4, 1}}
+
+	// Pack the snapshot in a directory of a gzipped tar file.
+	path := filepath.Join(t.TempDir(), "snapshot.tar.gz")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	writeFile := func(name string, bz []byte) {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(bz))}))
+		_, err := tw.Write(bz)
+		require.NoError(t, err)
+	}
+	bz, err := json.Marshal(s)
+	require.NoError(t, err)
+	writeFile("snapshot/metadata.json", bz)
+	writeFile("snapshot/0", chunks[0])
+	writeFile("snapshot/1", chunks[1])
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	require.NoError(t, f.Close())
+
+	stateProvider, connQuery := mockArchiveProviders(state, commit)
+	conn := &proxymocks.AppConnSnapshot{}
+	conn.On("OfferSnapshot", mock.Anything, &abci.OfferSnapshotRequest{
+		Snapshot: &abci.Snapshot{
+			Height:   s.Height,
+			Format:   s.Format,
+			Chunks:   s.Chunks,
+			Hash:     s.Hash,
+			Metadata: s.Metadata,
+		},
+		AppHash: state.AppHash,
+	}).Return(&abci.OfferSnapshotResponse{Result: abci.OFFER_SNAPSHOT_RESULT_ACCEPT}, nil)
+	for i, chunk := range chunks {
+		conn.On("ApplySnapshotChunk", mock.Anything, &abci.ApplySnapshotChunkRequest{
+			Index: uint32(i),
+			Chunk: chunk,
+		}).Once().Return(&abci.ApplySnapshotChunkResponse{Result: abci.APPLY_SNAPSHOT_CHUNK_RESULT_ACCEPT}, nil)
+	}
+	cfg := config.DefaultStateSyncConfig()
+	cfg.TempDir = t.TempDir()
+
+	newState, newCommit, err := RestoreFromArchive(context.Background(), *cfg, log.NewNopLogger(),
+		conn, connQuery, stateProvider, nil, path)
+	require.NoError(t, err)
+	assert.Equal(t, state, newState)
+	assert.Equal(t, commit, newCommit)
+	conn.AssertExpectations(t)
+
+	// The extracted files are removed.
+	entries, err := os.ReadDir(cfg.TempDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+// writeSnapshotDir writes the snapshot s and its chunks to a new directory.
+func writeSnapshotDir(t *testing.T, s *snapshot, chunks [][]byte) string {
+	t.Helper()
+	dir := t.TempDir()
+	bz, err := json.Marshal(s)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, managedMetadataFile), bz, 0o600))
+	for i, chunk := range chunks {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, strconv.Itoa(i)), chunk, 0o600))
+	}
+	return dir
+}
+
+func TestRestoreFromArchive_RetryLimit(t *testing.T) {
+	state := makeManagedSnapshotState(4)
+	commit := &types.Commit{BlockID: types.BlockID{Hash: []byte("blockhash")}}
+	s := &snapshot{Height: 4, Format: 1, Chunks: 1, Hash: []byte{1, 2}}
+	dir := writeSnapshotDir(t, s, [][]byte{{4, 0}})
+
+	// The app keeps asking to retry the snapshot.
+	stateProvider, connQuery := mockArchiveProviders(state, commit)
+	conn := &proxymocks.AppConnSnapshot{}
+	conn.On("OfferSnapshot", mock.Anything, mock.Anything).
+		Return(&abci.OfferSnapshotResponse{Result: abci.OFFER_SNAPSHOT_RESULT_ACCEPT}, nil)
+	conn.On("ApplySnapshotChunk", mock.Anything, mock.Anything).
+		Return(&abci.ApplySnapshotChunkResponse{Result: abci.APPLY_SNAPSHOT_CHUNK_RESULT_RETRY_SNAPSHOT}, nil)
+	cfg := config.DefaultStateSyncConfig()
+	cfg.TempDir = t.TempDir()
+
+	_, _, err := RestoreFromArchive(context.Background(), *cfg, log.NewNopLogger(),
+		conn, connQuery, stateProvider, nil, dir)
+	require.ErrorIs(t, err, errRetrySnapshot)
+	conn.AssertNumberOfCalls(t, "OfferSnapshot", maxArchiveRetries+1)
+}
+
+func TestRestoreFromArchive_Canceled(t *testing.T) {
+	state := makeManagedSnapshotState(4)
+	commit := &types.Commit{BlockID: types.BlockID{Hash: []byte("blockhash")}}
+	s := &snapshot{Height: 4, Format: 1, Chunks: 2, Hash: []byte{1, 2}}
+	dir := writeSnapshotDir(t, s, [][]byte{{4, 0}, {4, 1}})
+
+	// The restoration is canceled while the app applies the first chunk.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stateProvider, connQuery := mockArchiveProviders(state, commit)
+	conn := &proxymocks.AppConnSnapshot{}
+	conn.On("OfferSnapshot", mock.Anything, mock.Anything).
+		Return(&abci.OfferSnapshotResponse{Result: abci.OFFER_SNAPSHOT_RESULT_ACCEPT}, nil)
+	conn.On("ApplySnapshotChunk", mock.Anything, mock.Anything).
+		Run(func(mock.Arguments) {
+			cancel()
+			time.Sleep(10 * time.Millisecond)
+		}).
+		Return(&abci.ApplySnapshotChunkResponse{Result: abci.APPLY_SNAPSHOT_CHUNK_RESULT_RETRY_SNAPSHOT}, nil)
+	cfg := config.DefaultStateSyncConfig()
+	cfg.TempDir = t.TempDir()
+
+	_, _, err := RestoreFromArchive(ctx, *cfg, log.NewNopLogger(),
+		conn, connQuery, stateProvider, nil, dir)
+	require.ErrorIs(t, err, context.Canceled)
+	conn.AssertNumberOfCalls(t, "OfferSnapshot", 1)
+}
@@ -0,0 +1,50 @@
+package statesync
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/cometbft/cometbft/internal/tempfile"
+)
+
+// progressFile is the name of the file holding the progress of a restoration,
+// in the directory of its chunks.
+const progressFile = "progress.json"
+
+// syncProgress is the progress of the restoration of a snapshot, persisted so
+// that the restoration can resume with the downloaded chunks after a restart.
+// The chunks applied by the app are not recorded: the app restarts the
+// restoration when offered the snapshot again, so all chunks are applied again.
+type syncProgress struct {
+	Snapshot *snapshot `json:"snapshot"`
+}
+
+// loadSyncProgress loads the progress saved in dir, or returns nil if there is
+// none.
+func loadSyncProgress(dir string) (*syncProgress, error) {
+	bz, err := os.ReadFile(filepath.Join(dir, progressFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	progress := &syncProgress{}
+	if err := json.Unmarshal(bz, progress); err != nil {
+		return nil, err
+	}
+	if progress.Snapshot == nil {
+		return nil, errors.New("missing snapshot")
+	}
+	return progress, nil
+}
+
+func (p *syncProgress) save(dir string) error {
+	bz, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return tempfile.WriteFileAtomic(filepath.Join(dir, progressFile), bz, 0o600)
+}
@@ -38,18 +38,50 @@ type Reactor struct {
 	tempDir   string
 	metrics   *Metrics
 
+	// snapshotter, if set, provides the snapshots taken by CometBFT itself.
+	snapshotter *Snapshotter
+	// stateExporter, if set, is used to restore the snapshots taken by
+	// CometBFT itself.
+	stateExporter abci.StateExporter
+	// progressDir, if set, holds the progress of the current restoration.
+	progressDir string
+
 	// This will only be set when a state sync is in progress. It is used to feed received
 	// snapshots and chunks into the sync.
 	mtx    cmtsync.RWMutex
 	syncer *syncer
 }
 
+// ReactorOption sets an optional parameter on the Reactor.
+type ReactorOption func(*Reactor)
+
+// WithSnapshotter makes the reactor serve the snapshots taken by the given
+// snapshotter, next to the ones of the application.
+func WithSnapshotter(snapshotter *Snapshotter) ReactorOption {
+	return func(r *Reactor) { r.snapshotter = snapshotter }
+}
+
+// WithStateExporter allows the reactor to restore snapshots of the
+// ManagedSnapshotFormat format, by importing their application state
+// through the given exporter.
+func WithStateExporter(exporter abci.StateExporter) ReactorOption {
+	return func(r *Reactor) { r.stateExporter = exporter }
+}
+
+// WithProgressDir makes the reactor keep the downloaded chunks and the
+// progress of a restoration in dir, so that a restoration interrupted by a
+// restart resumes with the same snapshot if peers still offer it.
+func WithProgressDir(dir string) ReactorOption {
+	return func(r *Reactor) { r.progressDir = dir }
+}
+
 // NewReactor creates a new state sync reactor.
 func NewReactor(
 	cfg config.StateSyncConfig,
 	conn proxy.AppConnSnapshot,
 	connQuery proxy.AppConnQuery,
 	metrics *Metrics,
+	options ...ReactorOption,
 ) *Reactor {
 	r := &Reactor{
 		cfg:       cfg,
@@ -59,6 +91,10 @@ func NewReactor(
 	}
 	r.BaseReactor = *p2p.NewBaseReactor("StateSync", r)
 
+	for _, option := range options {
+		option(r)
+	}
+
 	return r
 }
 
@@ -173,11 +209,7 @@ func (r *Reactor) Receive(e p2p.Envelope) {
 		case *ssproto.ChunkRequest:
 			r.Logger.Debug("Received chunk request", "height", msg.Height, "format", msg.Format,
 				"chunk", msg.Index, "peer", e.Src.ID())
-			resp, err := r.conn.LoadSnapshotChunk(context.TODO(), &abci.LoadSnapshotChunkRequest{
-				Height: msg.Height,
-				Format: msg.Format,
-				Chunk:  msg.Index,
-			})
+			resp, err := r.loadChunk(msg)
 			if err != nil {
 				r.Logger.Error("Failed to load chunk", "height", msg.Height, "format", msg.Format,
 					"chunk", msg.Index, "err", err)
@@ -191,8 +223,8 @@ func (r *Reactor) Receive(e p2p.Envelope) {
 					Height:  msg.Height,
 					Format:  msg.Format,
 					Index:   msg.Index,
-					Chunk:   resp.Chunk,
-					Missing: resp.Chunk == nil,
+					Chunk:   resp,
+					Missing: resp == nil,
 				},
 			})
 
@@ -227,15 +259,50 @@ func (r *Reactor) Receive(e p2p.Envelope) {
 	}
 }
 
-// recentSnapshots fetches the n most recent snapshots from the app.
+// loadChunk loads a chunk from the snapshotter for the managed snapshots, or
+// from the app otherwise. It returns nil if the chunk does not exist.
+func (r *Reactor) loadChunk(msg *ssproto.ChunkRequest) ([]byte, error) {
+	if msg.Format == ManagedSnapshotFormat && r.snapshotter != nil {
+		return r.snapshotter.store.LoadChunk(msg.Height, msg.Index)
+	}
+	resp, err := r.conn.LoadSnapshotChunk(context.TODO(), &abci.LoadSnapshotChunkRequest{
+		Height: msg.Height,
+		Format: msg.Format,
+		Chunk:  msg.Index,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Chunk, nil
+}
+
+// recentSnapshots fetches the n most recent snapshots from the app and the
+// snapshotter.
 func (r *Reactor) recentSnapshots(n uint32) ([]*snapshot, error) {
 	resp, err := r.conn.ListSnapshots(context.TODO(), &abci.ListSnapshotsRequest{})
 	if err != nil {
 		return nil, err
 	}
-	sort.Slice(resp.Snapshots, func(i, j int) bool {
-		a := resp.Snapshots[i]
-		b := resp.Snapshots[j]
+	snapshots := make([]*snapshot, 0, len(resp.Snapshots))
+	for _, s := range resp.Snapshots {
+		snapshots = append(snapshots, &snapshot{
+			Height:   s.Height,
+			Format:   s.Format,
+			Chunks:   s.Chunks,
+			Hash:     s.Hash,
+			Metadata: s.Metadata,
+		})
+	}
+	if r.snapshotter != nil {
+		managed, err := r.snapshotter.store.List()
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, managed...)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		a := snapshots[i]
+		b := snapshots[j]
 		switch {
 		case a.Height > b.Height:
 			return true
@@ -245,18 +312,8 @@ func (r *Reactor) recentSnapshots(n uint32) ([]*snapshot, error) {
 			return false
 		}
 	})
-	snapshots := make([]*snapshot, 0, n)
-	for i, s := range resp.Snapshots {
-		if i >= recentSnapshots {
-			break
-		}
-		snapshots = append(snapshots, &snapshot{
-			Height:   s.Height,
-			Format:   s.Format,
-			Chunks:   s.Chunks,
-			Hash:     s.Hash,
-			Metadata: s.Metadata,
-		})
+	if uint32(len(snapshots)) > n {
+		snapshots = snapshots[:n]
 	}
 	return snapshots, nil
 }
@@ -271,6 +328,9 @@ func (r *Reactor) Sync(stateProvider StateProvider, discoveryTime time.Duration)
 	}
 	r.metrics.Syncing.Set(1)
 	r.syncer = newSyncer(r.cfg, r.Logger, r.conn, r.connQuery, stateProvider, r.tempDir)
+	r.syncer.stateExporter = r.stateExporter
+	r.syncer.progressDir = r.progressDir
+	r.syncer.reportBehavior = r.Switch.ReportBehavior
 	r.mtx.Unlock()
 
 	hook := func() {
@@ -0,0 +1,284 @@
+package statesync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/cosmos/gogoproto/proto"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmtstate "github.com/cometbft/cometbft/api/cometbft/state/v1"
+	"github.com/cometbft/cometbft/libs/log"
+	cmtsync "github.com/cometbft/cometbft/libs/sync"
+	sm "github.com/cometbft/cometbft/state"
+)
+
+const (
+	// ManagedSnapshotFormat is the format of the snapshots taken by CometBFT
+	// itself, as opposed to the ones taken by the application. Its highest
+	// bit is set to avoid clashing with the formats used by applications.
+	ManagedSnapshotFormat uint32 = 1 << 31
+
+	// managedChunkSize is the size of the chunks of a managed snapshot, well
+	// below chunkMsgSize.
+	managedChunkSize = 4 << 20
+
+	// managedMetadataFile is the name of the file describing a snapshot, in
+	// the directory of the snapshot.
+	managedMetadataFile = "metadata.json"
+)
+
+// managedSnapshotContent is the content of a managed snapshot, before it is
+// split into chunks.
+type managedSnapshotContent struct {
+	// State is the protobuf encoding of the state.State of the node.
+	State []byte `json:"state"`
+	// AppState is the application state, as returned by ExportState.
+	AppState []byte `json:"app_state"`
+}
+
+func encodeManagedSnapshot(state sm.State, appState []byte) ([]byte, error) {
+	pbState, err := state.ToProto()
+	if err != nil {
+		return nil, err
+	}
+	bz, err := proto.Marshal(pbState)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(managedSnapshotContent{State: bz, AppState: appState})
+}
+
+func decodeManagedSnapshot(bz []byte) (*sm.State, []byte, error) {
+	var content managedSnapshotContent
+	if err := json.Unmarshal(bz, &content); err != nil {
+		return nil, nil, err
+	}
+	pbState := new(cmtstate.State)
+	if err := proto.Unmarshal(content.State, pbState); err != nil {
+		return nil, nil, err
+	}
+	state, err := sm.FromProto(pbState)
+	if err != nil {
+		return nil, nil, err
+	}
+	return state, content.AppState, nil
+}
+
+// snapshotStore keeps the managed snapshots on disk, one directory per
+// height holding the chunks and a metadata file.
+type snapshotStore struct {
+	mtx cmtsync.RWMutex
+	dir string
+}
+
+func newSnapshotStore(dir string) (*snapshotStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	return &snapshotStore{dir: dir}, nil
+}
+
+func (s *snapshotStore) snapshotDir(height uint64) string {
+	return filepath.Join(s.dir, strconv.FormatUint(height, 10))
+}
+
+// Save splits the snapshot content into chunks and stores them. The snapshot
+// only becomes visible once all its files are written. Saving a height
+// that is already stored does nothing.
+func (s *snapshotStore) Save(height uint64, content []byte) (*snapshot, error) {
+	hash := sha256.Sum256(content)
+	snapshot := &snapshot{
+		Height: height,
+		Format: ManagedSnapshotFormat,
+		Chunks: uint32((len(content) + managedChunkSize - 1) / managedChunkSize),
+		Hash:   hash[:],
+	}
+	if snapshot.Chunks == 0 {
+		snapshot.Chunks = 1
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	dir := s.snapshotDir(height)
+	if _, err := os.Stat(dir); err == nil {
+		return s.loadMetadata(height)
+	}
+
+	tmpDir, err := os.MkdirTemp(s.dir, "tmp-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	for i := uint32(0); i < snapshot.Chunks; i++ {
+		start := int(i) * managedChunkSize
+		end := min(start+managedChunkSize, len(content))
+		path := filepath.Join(tmpDir, strconv.FormatUint(uint64(i), 10))
+		if err := os.WriteFile(path, content[start:end], 0o600); err != nil {
+			return nil, err
+		}
+	}
+	bz, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, managedMetadataFile), bz, 0o600); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmpDir, dir); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// CONTRACT: s.mtx must be locked.
+func (s *snapshotStore) loadMetadata(height uint64) (*snapshot, error) {
+	bz, err := os.ReadFile(filepath.Join(s.snapshotDir(height), managedMetadataFile))
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &snapshot{}
+	if err := json.Unmarshal(bz, snapshot); err != nil {
+		return nil, fmt.Errorf("invalid metadata of snapshot %d: %w", height, err)
+	}
+	return snapshot, nil
+}
+
+// List returns the stored snapshots, highest first.
+func (s *snapshotStore) List() ([]*snapshot, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	return s.list()
+}
+
+// CONTRACT: s.mtx must be locked.
+func (s *snapshotStore) list() ([]*snapshot, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	snapshots := make([]*snapshot, 0, len(entries))
+	for _, entry := range entries {
+		height, err := strconv.ParseUint(entry.Name(), 10, 64)
+		if err != nil || !entry.IsDir() {
+			continue // a temporary directory or an unrelated file
+		}
+		snapshot, err := s.loadMetadata(height)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Height > snapshots[j].Height
+	})
+	return snapshots, nil
+}
+
+// LoadChunk returns a chunk of a stored snapshot, or nil if the snapshot or
+// the chunk does not exist.
+func (s *snapshotStore) LoadChunk(height uint64, index uint32) ([]byte, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	bz, err := os.ReadFile(filepath.Join(s.snapshotDir(height), strconv.FormatUint(uint64(index), 10)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return bz, err
+}
+
+// Prune removes all but the keepRecent most recent snapshots.
+func (s *snapshotStore) Prune(keepRecent uint32) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	snapshots, err := s.list()
+	if err != nil {
+		return err
+	}
+	for i := int(keepRecent); i < len(snapshots); i++ {
+		if err := os.RemoveAll(s.snapshotDir(snapshots[i].Height)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Snapshotter takes snapshots of the node state and of the state exported by
+// the application at regular heights, without involving the snapshot ABCI
+// methods. The snapshots are served by the Reactor it is passed to, using
+// the ManagedSnapshotFormat format. It implements state.Snapshotter.
+type Snapshotter struct {
+	logger     log.Logger
+	store      *snapshotStore
+	exporter   abci.StateExporter
+	interval   uint64
+	keepRecent uint32
+}
+
+var _ sm.Snapshotter = (*Snapshotter)(nil)
+
+// NewSnapshotter creates a Snapshotter storing its snapshots in dir, taking
+// one every interval heights and keeping the keepRecent most recent ones.
+func NewSnapshotter(
+	dir string,
+	exporter abci.StateExporter,
+	interval uint64,
+	keepRecent uint32,
+) (*Snapshotter, error) {
+	if interval == 0 {
+		return nil, errors.New("snapshot interval must be positive")
+	}
+	store, err := newSnapshotStore(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Snapshotter{
+		logger:     log.NewNopLogger(),
+		store:      store,
+		exporter:   exporter,
+		interval:   interval,
+		keepRecent: keepRecent,
+	}, nil
+}
+
+// SetLogger sets the logger of the snapshotter.
+func (s *Snapshotter) SetLogger(logger log.Logger) {
+	s.logger = logger
+}
+
+// Snapshot implements state.Snapshotter. It takes a snapshot if the last
+// block height of the state is a multiple of the interval, then prunes the
+// oldest snapshots.
+func (s *Snapshotter) Snapshot(state sm.State) error {
+	if state.LastBlockHeight <= 0 || uint64(state.LastBlockHeight)%s.interval != 0 {
+		return nil
+	}
+
+	appState, err := s.exporter.ExportState(context.TODO())
+	if err != nil {
+		return fmt.Errorf("failed to export app state: %w", err)
+	}
+	content, err := encodeManagedSnapshot(state, appState)
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	snapshot, err := s.store.Save(uint64(state.LastBlockHeight), content)
+	if err != nil {
+		return fmt.Errorf("failed to save snapshot: %w", err)
+	}
+	s.logger.Info("Took snapshot", "height", snapshot.Height, "chunks", snapshot.Chunks,
+		"hash", log.NewLazySprintf("%X", snapshot.Hash))
+
+	return s.store.Prune(s.keepRecent)
+}
@@ -0,0 +1,208 @@
+package statesync
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	ssproto "github.com/cometbft/cometbft/api/cometbft/statesync/v1"
+	"github.com/cometbft/cometbft/config"
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/proxy"
+	proxymocks "github.com/cometbft/cometbft/proxy/mocks"
+	sm "github.com/cometbft/cometbft/state"
+	"github.com/cometbft/cometbft/statesync/mocks"
+	"github.com/cometbft/cometbft/types"
+)
+
+type testStateExporter struct {
+	state          []byte
+	importedHeight int64
+	importedState  []byte
+}
+
+func (e *testStateExporter) ExportState(context.Context) ([]byte, error) {
+	return e.state, nil
+}
+
+func (e *testStateExporter) ImportState(_ context.Context, height int64, state []byte) error {
+	e.importedHeight = height
+	e.importedState = state
+	return nil
+}
+
+func makeManagedSnapshotState(height int64) sm.State {
+	vals, _ := types.RandValidatorSet(2, 10)
+	return sm.State{
+		ChainID:         "chain",
+		LastBlockHeight: height,
+		AppHash:         []byte("app_hash"),
+		LastValidators:  vals,
+		Validators:      vals,
+		NextValidators:  vals,
+		ConsensusParams: *types.DefaultConsensusParams(),
+	}
+}
+
+func TestSnapshotter(t *testing.T) {
+	exporter := &testStateExporter{state: []byte("app_state")}
+	snapshotter, err := NewSnapshotter(t.TempDir(), exporter, 2, 2)
+	require.NoError(t, err)
+
+	for height := int64(1); height <= 7; height++ {
+		require.NoError(t, snapshotter.Snapshot(makeManagedSnapshotState(height)))
+	}
+
+	// Only the 2 most recent snapshots, taken every 2 heights, are kept.
+	snapshots, err := snapshotter.store.List()
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+	assert.EqualValues(t, 6, snapshots[0].Height)
+	assert.EqualValues(t, 4, snapshots[1].Height)
+
+	s := snapshots[0]
+	assert.Equal(t, ManagedSnapshotFormat, s.Format)
+	require.EqualValues(t, 1, s.Chunks)
+	bz, err := snapshotter.store.LoadChunk(s.Height, 0)
+	require.NoError(t, err)
+	state, appState, err := decodeManagedSnapshot(bz)
+	require.NoError(t, err)
+	assert.EqualValues(t, 6, state.LastBlockHeight)
+	assert.Equal(t, exporter.state, appState)
+
+	// Pruned snapshots and out of range chunks are missing.
+	bz, err = snapshotter.store.LoadChunk(2, 0)
+	require.NoError(t, err)
+	assert.Nil(t, bz)
+	bz, err = snapshotter.store.LoadChunk(s.Height, 1)
+	require.NoError(t, err)
+	assert.Nil(t, bz)
+}
+
+func TestSnapshotStoreChunks(t *testing.T) {
+	store, err := newSnapshotStore(t.TempDir())
+	require.NoError(t, err)
+
+	content := bytes.Repeat([]byte{7}, managedChunkSize+1)
+	s, err := store.Save(1, content)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, s.Chunks)
+
+	var loaded []byte
+	for i := uint32(0); i < s.Chunks; i++ {
+		bz, err := store.LoadChunk(1, i)
+		require.NoError(t, err)
+		loaded = append(loaded, bz...)
+	}
+	assert.Equal(t, content, loaded)
+
+	// Saving the same height again keeps the existing snapshot.
+	again, err := store.Save(1, []byte("other"))
+	require.NoError(t, err)
+	assert.Equal(t, s, again)
+}
+
+func TestSyncer_SyncManagedSnapshot(t *testing.T) {
+	state := makeManagedSnapshotState(4)
+	commit := &types.Commit{BlockID: types.BlockID{Hash: []byte("blockhash")}}
+	exporter := &testStateExporter{state: []byte("app_state")}
+
+	snapshotter, err := NewSnapshotter(t.TempDir(), exporter, 4, 1)
+	require.NoError(t, err)
+	require.NoError(t, snapshotter.Snapshot(state))
+	snapshots, err := snapshotter.store.List()
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+
+	newSyncerForSnapshot := func(t *testing.T, s *snapshot, stateExporter abci.StateExporter) (*syncer, *chunkQueue) {
+		t.Helper()
+		stateProvider := &mocks.StateProvider{}
+		stateProvider.On("AppHash", mock.Anything, uint64(4)).Return(state.AppHash, nil)
+		stateProvider.On("State", mock.Anything, uint64(4)).Return(state, nil)
+		stateProvider.On("Commit", mock.Anything, uint64(4)).Return(commit, nil)
+		connQuery := &proxymocks.AppConnQuery{}
+		connQuery.On("Info", mock.Anything, proxy.InfoRequest).Return(&abci.InfoResponse{
+			AppVersion:       state.Version.Consensus.App,
+			LastBlockHeight:  state.LastBlockHeight,
+			LastBlockAppHash: state.AppHash,
+		}, nil)
+
+		syncer := newSyncer(*config.DefaultStateSyncConfig(), log.NewNopLogger(),
+			&proxymocks.AppConnSnapshot{}, connQuery, stateProvider, "")
+		syncer.stateExporter = stateExporter
+
+		chunks, err := newChunkQueue(s, t.TempDir())
+		require.NoError(t, err)
+		t.Cleanup(func() { chunks.Close() })
+		for i := uint32(0); i < s.Chunks; i++ {
+			bz, err := snapshotter.store.LoadChunk(s.Height, i)
+			require.NoError(t, err)
+			_, err = chunks.Add(&chunk{Height: s.Height, Format: s.Format, Index: i, Chunk: bz})
+			require.NoError(t, err)
+		}
+		return syncer, chunks
+	}
+
+	t.Run("restores", func(t *testing.T) {
+		importer := &testStateExporter{}
+		s := *snapshots[0]
+		syncer, chunks := newSyncerForSnapshot(t, &s, importer)
+
+		newState, newCommit, err := syncer.Sync(&s, chunks)
+		require.NoError(t, err)
+		assert.Equal(t, state, newState)
+		assert.Equal(t, commit, newCommit)
+		assert.EqualValues(t, 4, importer.importedHeight)
+		assert.Equal(t, exporter.state, importer.importedState)
+	})
+
+	t.Run("rejects format without exporter", func(t *testing.T) {
+		s := *snapshots[0]
+		syncer, chunks := newSyncerForSnapshot(t, &s, nil)
+
+		_, _, err := syncer.Sync(&s, chunks)
+		require.ErrorIs(t, err, errRejectFormat)
+	})
+
+	t.Run("rejects content not matching the hash", func(t *testing.T) {
+		importer := &testStateExporter{}
+		s := *snapshots[0]
+		s.Hash = []byte("wrong")
+		syncer, chunks := newSyncerForSnapshot(t, &s, importer)
+
+		_, _, err := syncer.Sync(&s, chunks)
+		require.ErrorIs(t, err, errRejectSnapshot)
+		assert.Nil(t, importer.importedState)
+	})
+}
+
+func TestReactor_ServesManagedSnapshots(t *testing.T) {
+	exporter := &testStateExporter{state: []byte("app_state")}
+	snapshotter, err := NewSnapshotter(t.TempDir(), exporter, 4, 1)
+	require.NoError(t, err)
+	require.NoError(t, snapshotter.Snapshot(makeManagedSnapshotState(4)))
+
+	conn := &proxymocks.AppConnSnapshot{}
+	conn.On("ListSnapshots", mock.Anything, &abci.ListSnapshotsRequest{}).Return(&abci.ListSnapshotsResponse{
+		Snapshots: []*abci.Snapshot{{Height: 2, Format: 1, Chunks: 1, Hash: []byte{1}}},
+	}, nil)
+	r := NewReactor(*config.DefaultStateSyncConfig(), conn, nil, NopMetrics(), WithSnapshotter(snapshotter))
+
+	snapshots, err := r.recentSnapshots(recentSnapshots)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+	assert.EqualValues(t, 4, snapshots[0].Height)
+	assert.Equal(t, ManagedSnapshotFormat, snapshots[0].Format)
+	assert.EqualValues(t, 2, snapshots[1].Height)
+
+	bz, err := r.loadChunk(&ssproto.ChunkRequest{Height: 4, Format: ManagedSnapshotFormat, Index: 0})
+	require.NoError(t, err)
+	_, appState, err := decodeManagedSnapshot(bz)
+	require.NoError(t, err)
+	assert.Equal(t, exporter.state, appState)
+}
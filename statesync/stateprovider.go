@@ -1,13 +1,18 @@
 package statesync
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"sort"
 	"strings"
+	"time"
 
 	dbm "github.com/cometbft/cometbft-db"
 	cmtstate "github.com/cometbft/cometbft/api/cometbft/state/v1"
+	cmtjson "github.com/cometbft/cometbft/libs/json"
 	"github.com/cometbft/cometbft/libs/log"
 	cmtsync "github.com/cometbft/cometbft/libs/sync"
 	"github.com/cometbft/cometbft/light"
@@ -140,23 +145,6 @@ func (s *lightClientStateProvider) State(ctx context.Context, height uint64) (sm
 	s.Lock()
 	defer s.Unlock()
 
-	state := sm.State{
-		ChainID:       s.lc.ChainID(),
-		Version:       s.version,
-		InitialHeight: s.initialHeight,
-	}
-	if state.InitialHeight == 0 {
-		state.InitialHeight = 1
-	}
-
-	// The snapshot height maps onto the state heights as follows:
-	//
-	// height: last block, i.e. the snapshotted height
-	// height+1: current block, i.e. the first block we'll process after the snapshot
-	// height+2: next block, i.e. the second block after the snapshot
-	//
-	// We need to fetch the NextValidators from height+2 because if the application changed
-	// the validator set at the snapshot height then this only takes effect at height+2.
 	lastLightBlock, err := s.lc.VerifyLightBlockAtHeight(ctx, int64(height), cmttime.Now())
 	if err != nil {
 		return sm.State{}, err
@@ -170,19 +158,7 @@ func (s *lightClientStateProvider) State(ctx context.Context, height uint64) (sm
 		return sm.State{}, err
 	}
 
-	state.Version = cmtstate.Version{
-		Consensus: currentLightBlock.Version,
-		Software:  version.CMTSemVer,
-	}
-	state.LastBlockHeight = lastLightBlock.Height
-	state.LastBlockTime = lastLightBlock.Time
-	state.LastBlockID = lastLightBlock.Commit.BlockID
-	state.AppHash = currentLightBlock.AppHash
-	state.LastResultsHash = currentLightBlock.LastResultsHash
-	state.LastValidators = lastLightBlock.ValidatorSet
-	state.Validators = currentLightBlock.ValidatorSet
-	state.NextValidators = nextLightBlock.ValidatorSet
-	state.LastHeightValidatorsChanged = nextLightBlock.Height
+	state := stateFromLightBlocks(s.lc.ChainID(), s.initialHeight, lastLightBlock, currentLightBlock, nextLightBlock)
 
 	// We'll also need to fetch consensus params via RPC, using light client verification.
 	primaryURL, ok := s.providers[s.lc.Primary()]
@@ -205,6 +181,202 @@ func (s *lightClientStateProvider) State(ctx context.Context, height uint64) (sm
 	return state, nil
 }
 
+// stateFromLightBlocks builds the state after the block at the snapshot
+// height, without the consensus parameters, from the light blocks at the
+// snapshot height and the two next heights. The snapshot height maps onto the
+// state heights as follows:
+//
+// height: last block, i.e. the snapshotted height
+// height+1: current block, i.e. the first block we'll process after the snapshot
+// height+2: next block, i.e. the second block after the snapshot
+//
+// We need the NextValidators from height+2 because if the application changed
+// the validator set at the snapshot height then this only takes effect at height+2.
+func stateFromLightBlocks(chainID string, initialHeight int64, last, current, next *types.LightBlock) sm.State {
+	if initialHeight == 0 {
+		initialHeight = 1
+	}
+	return sm.State{
+		ChainID:       chainID,
+		InitialHeight: initialHeight,
+		Version: cmtstate.Version{
+			Consensus: current.Version,
+			Software:  version.CMTSemVer,
+		},
+		LastBlockHeight:             last.Height,
+		LastBlockTime:               last.Time,
+		LastBlockID:                 last.Commit.BlockID,
+		AppHash:                     current.AppHash,
+		LastResultsHash:             current.LastResultsHash,
+		LastValidators:              last.ValidatorSet,
+		Validators:                  current.ValidatorSet,
+		NextValidators:              next.ValidatorSet,
+		LastHeightValidatorsChanged: next.Height,
+	}
+}
+
+// LightBlocksFile is the content of the file read by the offline state
+// provider. It holds the light blocks verifying a snapshot, and the consensus
+// parameters at the height after the snapshot. The file needs not come from a
+// trusted source, as its content is verified against the trusted header.
+type LightBlocksFile struct {
+	LightBlocks     []*types.LightBlock   `json:"light_blocks"`
+	ConsensusParams types.ConsensusParams `json:"consensus_params"`
+}
+
+// offlineMaxClockDrift is the maximum drift of the verified headers' time
+// into the future.
+const offlineMaxClockDrift = 10 * time.Second
+
+// offlineStateProvider is a state provider verifying the light blocks of a
+// LightBlocksFile against a trusted header, without any RPC request.
+type offlineStateProvider struct {
+	chainID         string
+	initialHeight   int64
+	lightBlocks     map[int64]*types.LightBlock
+	consensusParams types.ConsensusParams
+}
+
+// NewOfflineStateProvider creates a StateProvider reading the LightBlocksFile
+// at path. The file must hold the light block at the trusted height, whose
+// hash must be the trusted hash, and the light blocks at the snapshot height
+// and the two next heights. The light blocks above the trusted height are
+// verified like a light client does, in ascending order of height, so the
+// trusting period must not be expired. The light blocks below the trusted
+// height must be contiguous, and are verified backwards from it.
+func NewOfflineStateProvider(
+	chainID string,
+	initialHeight int64,
+	trustOptions light.TrustOptions,
+	path string,
+) (StateProvider, error) {
+	if err := trustOptions.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file LightBlocksFile
+	if err := cmtjson.Unmarshal(bz, &file); err != nil {
+		return nil, fmt.Errorf("invalid light blocks file: %w", err)
+	}
+
+	lightBlocks := make(map[int64]*types.LightBlock, len(file.LightBlocks))
+	heights := make([]int64, 0, len(file.LightBlocks))
+	for _, lb := range file.LightBlocks {
+		if lb == nil {
+			return nil, errors.New("invalid light blocks file: missing light block")
+		}
+		if err := lb.ValidateBasic(chainID); err != nil {
+			return nil, fmt.Errorf("invalid light block at height %d: %w", lb.Height, err)
+		}
+		if _, ok := lightBlocks[lb.Height]; ok {
+			return nil, fmt.Errorf("duplicate light block at height %d", lb.Height)
+		}
+		lightBlocks[lb.Height] = lb
+		heights = append(heights, lb.Height)
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+
+	trusted, ok := lightBlocks[trustOptions.Height]
+	if !ok {
+		return nil, fmt.Errorf("missing light block at trusted height %d", trustOptions.Height)
+	}
+	if !bytes.Equal(trusted.Hash(), trustOptions.Hash) {
+		return nil, fmt.Errorf("expected header's hash %X, but got %X", trustOptions.Hash, trusted.Hash())
+	}
+
+	now := cmttime.Now()
+	verified := trusted
+	for _, height := range heights {
+		if height <= trustOptions.Height {
+			continue
+		}
+		lb := lightBlocks[height]
+		err := light.Verify(verified.SignedHeader, verified.ValidatorSet, lb.SignedHeader, lb.ValidatorSet,
+			trustOptions.Period, now, offlineMaxClockDrift, light.DefaultTrustLevel)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify light block at height %d: %w", height, err)
+		}
+		verified = lb
+	}
+	verified = trusted
+	for i := len(heights) - 1; i >= 0; i-- {
+		height := heights[i]
+		if height >= trustOptions.Height {
+			continue
+		}
+		if height != verified.Height-1 {
+			return nil, fmt.Errorf("missing light block at height %d", verified.Height-1)
+		}
+		lb := lightBlocks[height]
+		if err := light.VerifyBackwards(lb.Header, verified.Header); err != nil {
+			return nil, fmt.Errorf("failed to verify light block at height %d: %w", height, err)
+		}
+		verified = lb
+	}
+
+	return &offlineStateProvider{
+		chainID:         chainID,
+		initialHeight:   initialHeight,
+		lightBlocks:     lightBlocks,
+		consensusParams: file.ConsensusParams,
+	}, nil
+}
+
+func (s *offlineStateProvider) lightBlock(height uint64) (*types.LightBlock, error) {
+	lb, ok := s.lightBlocks[int64(height)]
+	if !ok {
+		return nil, fmt.Errorf("missing light block at height %d", height)
+	}
+	return lb, nil
+}
+
+// AppHash implements StateProvider.
+func (s *offlineStateProvider) AppHash(_ context.Context, height uint64) ([]byte, error) {
+	// The block at height+2 is needed to build the state.
+	if _, err := s.lightBlock(height + 2); err != nil {
+		return nil, err
+	}
+	lb, err := s.lightBlock(height + 1)
+	if err != nil {
+		return nil, err
+	}
+	return lb.AppHash, nil
+}
+
+// Commit implements StateProvider.
+func (s *offlineStateProvider) Commit(_ context.Context, height uint64) (*types.Commit, error) {
+	lb, err := s.lightBlock(height)
+	if err != nil {
+		return nil, err
+	}
+	return lb.Commit, nil
+}
+
+// State implements StateProvider.
+func (s *offlineStateProvider) State(_ context.Context, height uint64) (sm.State, error) {
+	var lightBlocks [3]*types.LightBlock
+	for i := range lightBlocks {
+		lb, err := s.lightBlock(height + uint64(i))
+		if err != nil {
+			return sm.State{}, err
+		}
+		lightBlocks[i] = lb
+	}
+	state := stateFromLightBlocks(s.chainID, s.initialHeight, lightBlocks[0], lightBlocks[1], lightBlocks[2])
+
+	current := lightBlocks[1]
+	if hash := s.consensusParams.Hash(); !bytes.Equal(hash, current.ConsensusHash) {
+		return sm.State{}, fmt.Errorf("consensus parameters hash %X does not match the header's %X at height %d",
+			hash, current.ConsensusHash, current.Height)
+	}
+	state.ConsensusParams = s.consensusParams
+	state.LastHeightConsensusParamsChanged = current.Height
+	return state, nil
+}
+
 // rpcClient sets up a new RPC client.
 func rpcClient(server string) (*rpchttp.HTTP, error) {
 	if !strings.Contains(server, "://") {
@@ -0,0 +1,140 @@
+package statesync
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cometbft/cometbft/internal/test"
+	cmtjson "github.com/cometbft/cometbft/libs/json"
+	"github.com/cometbft/cometbft/light"
+	"github.com/cometbft/cometbft/types"
+	cmttime "github.com/cometbft/cometbft/types/time"
+)
+
+// makeLightBlocks returns a chain of n light blocks, from height 1, signed by
+// a single validator.
+func makeLightBlocks(t *testing.T, n int, params types.ConsensusParams) []*types.LightBlock {
+	t.Helper()
+	valSet, privVals := test.ValidatorSet(context.Background(), t, 1, 10)
+	start := cmttime.Now().Add(-time.Duration(n) * time.Minute)
+
+	lightBlocks := make([]*types.LightBlock, 0, n)
+	lastBlockID := test.MakeBlockID()
+	for height := int64(1); height <= int64(n); height++ {
+		blockTime := start.Add(time.Duration(height) * time.Minute)
+		header := test.MakeHeader(t, &types.Header{
+			ChainID:            test.DefaultTestChainID,
+			Height:             height,
+			Time:               blockTime,
+			LastBlockID:        lastBlockID,
+			ValidatorsHash:     valSet.Hash(),
+			NextValidatorsHash: valSet.Hash(),
+			ConsensusHash:      params.Hash(),
+			ProposerAddress:    valSet.Proposer.Address,
+		})
+		blockID := test.MakeBlockIDWithHash(header.Hash())
+		commit, err := test.MakeCommit(blockID, height, 0, valSet, privVals, test.DefaultTestChainID, blockTime)
+		require.NoError(t, err)
+		lightBlocks = append(lightBlocks, &types.LightBlock{
+			SignedHeader: &types.SignedHeader{Header: header, Commit: commit},
+			ValidatorSet: valSet,
+		})
+		lastBlockID = blockID
+	}
+	return lightBlocks
+}
+
+func writeLightBlocksFile(t *testing.T, file LightBlocksFile) string {
+	t.Helper()
+	bz, err := cmtjson.Marshal(file)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "light_blocks.json")
+	require.NoError(t, os.WriteFile(path, bz, 0o600))
+	return path
+}
+
+func TestOfflineStateProvider(t *testing.T) {
+	params := *types.DefaultConsensusParams()
+	lightBlocks := makeLightBlocks(t, 6, params)
+	path := writeLightBlocksFile(t, LightBlocksFile{LightBlocks: lightBlocks[1:], ConsensusParams: params})
+	ctx := context.Background()
+
+	// The light blocks are verified forwards and backwards from the trusted
+	// height.
+	for _, trustHeight := range []int64{2, 4} {
+		stateProvider, err := NewOfflineStateProvider(test.DefaultTestChainID, 1, light.TrustOptions{
+			Period: time.Hour,
+			Height: trustHeight,
+			Hash:   lightBlocks[trustHeight-1].Hash(),
+		}, path)
+		require.NoError(t, err)
+
+		appHash, err := stateProvider.AppHash(ctx, 3)
+		require.NoError(t, err)
+		assert.EqualValues(t, lightBlocks[3].AppHash, appHash)
+
+		commit, err := stateProvider.Commit(ctx, 3)
+		require.NoError(t, err)
+		assert.Equal(t, lightBlocks[2].Commit, commit)
+
+		state, err := stateProvider.State(ctx, 3)
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, state.LastBlockHeight)
+		assert.Equal(t, lightBlocks[2].Commit.BlockID, state.LastBlockID)
+		assert.EqualValues(t, lightBlocks[3].AppHash, state.AppHash)
+		assert.Equal(t, params, state.ConsensusParams)
+		assert.EqualValues(t, 4, state.LastHeightConsensusParamsChanged)
+
+		// The light blocks after the snapshot must be in the file.
+		_, err = stateProvider.AppHash(ctx, 5)
+		require.Error(t, err)
+		_, err = stateProvider.State(ctx, 5)
+		require.Error(t, err)
+	}
+}
+
+func TestOfflineStateProvider_Invalid(t *testing.T) {
+	params := *types.DefaultConsensusParams()
+	lightBlocks := makeLightBlocks(t, 4, params)
+	trustOptions := light.TrustOptions{Period: time.Hour, Height: 1, Hash: lightBlocks[0].Hash()}
+
+	// The trusted hash doesn't match.
+	path := writeLightBlocksFile(t, LightBlocksFile{LightBlocks: lightBlocks, ConsensusParams: params})
+	_, err := NewOfflineStateProvider(test.DefaultTestChainID, 1, light.TrustOptions{
+		Period: time.Hour, Height: 1, Hash: lightBlocks[1].Hash(),
+	}, path)
+	require.Error(t, err)
+
+	// The trusting period is expired.
+	_, err = NewOfflineStateProvider(test.DefaultTestChainID, 1, light.TrustOptions{
+		Period: time.Minute, Height: 1, Hash: lightBlocks[0].Hash(),
+	}, path)
+	require.Error(t, err)
+
+	// A header was tampered with.
+	tampered := *lightBlocks[2].Header
+	tampered.AppHash = test.RandomHash()
+	forged := append([]*types.LightBlock{}, lightBlocks...)
+	forged[2] = &types.LightBlock{
+		SignedHeader: &types.SignedHeader{Header: &tampered, Commit: lightBlocks[2].Commit},
+		ValidatorSet: lightBlocks[2].ValidatorSet,
+	}
+	path = writeLightBlocksFile(t, LightBlocksFile{LightBlocks: forged, ConsensusParams: params})
+	_, err = NewOfflineStateProvider(test.DefaultTestChainID, 1, trustOptions, path)
+	require.Error(t, err)
+
+	// The consensus parameters don't match the headers.
+	otherParams := params
+	otherParams.Block.MaxBytes++
+	path = writeLightBlocksFile(t, LightBlocksFile{LightBlocks: lightBlocks, ConsensusParams: otherParams})
+	stateProvider, err := NewOfflineStateProvider(test.DefaultTestChainID, 1, trustOptions, path)
+	require.NoError(t, err)
+	_, err = stateProvider.State(context.Background(), 1)
+	require.Error(t, err)
+}
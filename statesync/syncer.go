@@ -3,8 +3,10 @@ package statesync
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"errors"
 	"fmt"
+	"os"
 	"time"
 
 	abci "github.com/cometbft/cometbft/abci/types"
@@ -59,6 +61,17 @@ type syncer struct {
 	tempDir       string
 	chunkFetchers int32
 	retryTimeout  time.Duration
+	// stateExporter imports the app state of ManagedSnapshotFormat snapshots.
+	// If nil, these snapshots are rejected.
+	stateExporter abci.StateExporter
+	// chunkLoader, if set, provides the chunks instead of the peers.
+	chunkLoader chunkLoader
+	// progressDir, if set, holds the chunks and progress of the current
+	// restoration, so that it can resume after a restart.
+	progressDir string
+	// reportBehavior, if set, reports the behaviors of the peers, e.g. slow
+	// responses to chunk requests.
+	reportBehavior func(p2p.Peer, p2p.PeerBehavior)
 
 	mtx    cmtsync.RWMutex
 	chunks *chunkQueue
@@ -156,13 +169,33 @@ func (s *syncer) SyncAny(discoveryTime time.Duration, retryHook func()) (sm.Stat
 	var (
 		snapshot *snapshot
 		chunks   *chunkQueue
+		resume   *syncProgress
 		err      error
 	)
+
+	// A restoration interrupted by a restart is resumed if its snapshot is still offered.
+	if s.progressDir != "" {
+		resume, err = loadSyncProgress(s.progressDir)
+		if err != nil {
+			s.logger.Error("Failed to load state sync progress, starting over", "err", err)
+			resume = nil
+		}
+	}
+
 	for {
 		// If not nil, we're going to retry restoration of the same snapshot.
 		if snapshot == nil {
 			snapshot = s.snapshots.Best()
 			chunks = nil
+			if resume != nil && snapshot != nil {
+				snapshot = s.resumableSnapshot(resume.Snapshot)
+				if snapshot == nil {
+					s.logger.Info("Interrupted snapshot is no longer offered, starting over",
+						"height", resume.Snapshot.Height, "format", resume.Snapshot.Format)
+					snapshot = s.snapshots.Best()
+					resume = nil
+				}
+			}
 		}
 		if snapshot == nil {
 			if discoveryTime == 0 {
@@ -174,7 +207,8 @@ func (s *syncer) SyncAny(discoveryTime time.Duration, retryHook func()) (sm.Stat
 			continue
 		}
 		if chunks == nil {
-			chunks, err = newChunkQueue(snapshot, s.tempDir)
+			chunks, err = s.newChunkQueue(snapshot, resume)
+			resume = nil
 			if err != nil {
 				return sm.State{}, nil, fmt.Errorf("failed to create chunk queue: %w", err)
 			}
@@ -235,6 +269,46 @@ func (s *syncer) SyncAny(discoveryTime time.Duration, retryHook func()) (sm.Stat
 	}
 }
 
+// resumableSnapshot returns the snapshot of the pool matching the given
+// snapshot, or nil if no peer offers it.
+func (s *syncer) resumableSnapshot(resume *snapshot) *snapshot {
+	for _, snapshot := range s.snapshots.Ranked() {
+		if snapshot.Key() == resume.Key() {
+			return snapshot
+		}
+	}
+	return nil
+}
+
+// newChunkQueue creates the chunk queue of a snapshot. If progressDir is set,
+// the chunks are stored there along with the progress of the restoration,
+// reusing the chunks of the resumed restoration if any.
+func (s *syncer) newChunkQueue(snapshot *snapshot, resume *syncProgress) (*chunkQueue, error) {
+	if s.progressDir == "" {
+		return newChunkQueue(snapshot, s.tempDir)
+	}
+	if resume == nil || resume.Snapshot.Key() != snapshot.Key() {
+		// Drop the chunks of any other snapshot.
+		if err := os.RemoveAll(s.progressDir); err != nil {
+			return nil, err
+		}
+	}
+	chunks, err := openChunkQueue(snapshot, s.progressDir)
+	if err != nil {
+		return nil, err
+	}
+	if resume != nil {
+		s.logger.Info("Resuming interrupted snapshot restoration", "height", snapshot.Height,
+			"format", snapshot.Format, "downloaded", len(chunks.chunkFiles), "total", snapshot.Chunks)
+	}
+	progress := &syncProgress{Snapshot: snapshot}
+	if err := progress.save(s.progressDir); err != nil {
+		chunks.Close()
+		return nil, fmt.Errorf("failed to save state sync progress: %w", err)
+	}
+	return chunks, nil
+}
+
 // Sync executes a sync for a specific snapshot, returning the latest state and block commit which
 // the caller must use to bootstrap the node.
 func (s *syncer) Sync(snapshot *snapshot, chunks *chunkQueue) (sm.State, *types.Commit, error) {
@@ -264,10 +338,20 @@ func (s *syncer) Sync(snapshot *snapshot, chunks *chunkQueue) (sm.State, *types.
 	}
 	snapshot.trustedAppHash = appHash
 
-	// Offer snapshot to ABCI app.
-	err = s.offerSnapshot(snapshot)
-	if err != nil {
-		return sm.State{}, nil, err
+	// Offer snapshot to ABCI app, unless it is managed by CometBFT.
+	managed := snapshot.Format == ManagedSnapshotFormat
+	if managed {
+		if s.stateExporter == nil {
+			return sm.State{}, nil, errRejectFormat
+		}
+	} else {
+		// The app restarts the restoration when offered a snapshot, so the
+		// chunks of a resumed restoration are applied again, without being
+		// downloaded again.
+		err = s.offerSnapshot(snapshot)
+		if err != nil {
+			return sm.State{}, nil, err
+		}
 	}
 
 	// Spawn chunk fetchers. They will terminate when the chunk queue is closed or context canceled.
@@ -299,7 +383,11 @@ func (s *syncer) Sync(snapshot *snapshot, chunks *chunkQueue) (sm.State, *types.
 	}
 
 	// Restore snapshot
-	err = s.applyChunks(chunks)
+	if managed {
+		err = s.importManagedSnapshot(snapshot, chunks, state)
+	} else {
+		err = s.applyChunks(chunks)
+	}
 	if err != nil {
 		return sm.State{}, nil, err
 	}
@@ -409,6 +497,48 @@ func (s *syncer) applyChunks(chunks *chunkQueue) error {
 	}
 }
 
+// importManagedSnapshot checks a snapshot of the ManagedSnapshotFormat format
+// against its hash and the state verified by the light client, then imports
+// its app state.
+func (s *syncer) importManagedSnapshot(snapshot *snapshot, chunks *chunkQueue, state sm.State) error {
+	var content []byte
+	for {
+		chunk, err := chunks.Next()
+		if err == errDone {
+			break
+		} else if err != nil {
+			return fmt.Errorf("failed to fetch chunk: %w", err)
+		}
+		content = append(content, chunk.Chunk...)
+	}
+
+	hash := sha256.Sum256(content)
+	if !bytes.Equal(hash[:], snapshot.Hash) {
+		s.logger.Error("Snapshot content does not match its hash", "height", snapshot.Height,
+			"expected", log.NewLazySprintf("%X", snapshot.Hash), "actual", log.NewLazySprintf("%X", hash))
+		return errRejectSnapshot
+	}
+	snapshotState, appState, err := decodeManagedSnapshot(content)
+	if err != nil {
+		s.logger.Error("Failed to decode snapshot", "height", snapshot.Height, "err", err)
+		return errRejectSender
+	}
+	if snapshotState.LastBlockHeight != int64(snapshot.Height) ||
+		!bytes.Equal(snapshotState.AppHash, snapshot.trustedAppHash) ||
+		!bytes.Equal(snapshotState.Validators.Hash(), state.Validators.Hash()) ||
+		!bytes.Equal(snapshotState.NextValidators.Hash(), state.NextValidators.Hash()) {
+		s.logger.Error("Snapshot state does not match the state verified by the light client",
+			"height", snapshot.Height)
+		return errRejectSender
+	}
+
+	s.logger.Info("Importing snapshot app state", "height", snapshot.Height, "bytes", len(appState))
+	if err := s.stateExporter.ImportState(context.TODO(), snapshotState.LastBlockHeight, appState); err != nil {
+		return fmt.Errorf("failed to import app state: %w", err)
+	}
+	return nil
+}
+
 // fetchChunks requests chunks from peers, receiving allocations from the chunk queue. Chunks
 // will be received from the reactor via syncer.AddChunks() to chunkQueue.Add().
 func (s *syncer) fetchChunks(ctx context.Context, snapshot *snapshot, chunks *chunkQueue) {
@@ -440,7 +570,7 @@ func (s *syncer) fetchChunks(ctx context.Context, snapshot *snapshot, chunks *ch
 		s.logger.Info("Fetching snapshot chunk", "height", snapshot.Height,
 			"format", snapshot.Format, "chunk", index, "total", chunks.Size())
 
-		s.requestChunk(snapshot, index)
+		peer := s.requestChunk(snapshot, index)
 
 		select {
 		case <-chunks.WaitFor(index):
@@ -448,6 +578,9 @@ func (s *syncer) fetchChunks(ctx context.Context, snapshot *snapshot, chunks *ch
 
 		case <-time.After(s.retryTimeout):
 			next = false
+			if peer != nil && s.reportBehavior != nil {
+				s.reportBehavior(peer, p2p.PeerBehaviorSlowResponse)
+			}
 
 		case <-ctx.Done():
 			return
@@ -455,13 +588,18 @@ func (s *syncer) fetchChunks(ctx context.Context, snapshot *snapshot, chunks *ch
 	}
 }
 
-// requestChunk requests a chunk from a peer.
-func (s *syncer) requestChunk(snapshot *snapshot, chunk uint32) {
+// requestChunk requests a chunk from a peer, or loads it from the chunk
+// loader if any. It returns the peer the chunk was requested from, if any.
+func (s *syncer) requestChunk(snapshot *snapshot, chunk uint32) p2p.Peer {
+	if s.chunkLoader != nil {
+		s.loadChunk(snapshot, chunk)
+		return nil
+	}
 	peer := s.snapshots.GetPeer(snapshot)
 	if peer == nil {
 		s.logger.Error("No valid peers found for snapshot", "height", snapshot.Height,
 			"format", snapshot.Format, "hash", log.NewLazySprintf("%X", snapshot.Hash))
-		return
+		return nil
 	}
 	s.logger.Debug("Requesting snapshot chunk", "height", snapshot.Height,
 		"format", snapshot.Format, "chunk", chunk, "peer", peer.ID())
@@ -473,6 +611,27 @@ func (s *syncer) requestChunk(snapshot *snapshot, chunk uint32) {
 			Index:  chunk,
 		},
 	})
+	return peer
+}
+
+// loadChunk loads a chunk from the chunk loader and adds it to the queue.
+func (s *syncer) loadChunk(snapshot *snapshot, index uint32) {
+	bz, err := s.chunkLoader.LoadChunk(snapshot.Height, index)
+	if err != nil || bz == nil {
+		s.logger.Error("Failed to load snapshot chunk", "height", snapshot.Height,
+			"format", snapshot.Format, "chunk", index, "err", err)
+		return
+	}
+	_, err = s.AddChunk(&chunk{
+		Height: snapshot.Height,
+		Format: snapshot.Format,
+		Index:  index,
+		Chunk:  bz,
+	})
+	if err != nil {
+		s.logger.Error("Failed to add snapshot chunk", "height", snapshot.Height,
+			"format", snapshot.Format, "chunk", index, "err", err)
+	}
 }
 
 // verifyApp verifies the sync, checking the app hash, last block height and app version.
@@ -2,6 +2,8 @@ package statesync
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -691,3 +693,103 @@ func toABCI(s *snapshot) *abci.Snapshot {
 		Metadata: s.Metadata,
 	}
 }
+
+func TestSyncer_SyncAny_Resume(t *testing.T) {
+	state := sm.State{
+		Version: cmtstate.Version{Consensus: cmtversion.Consensus{App: testAppVersion}},
+		AppHash: []byte("app_hash"),
+	}
+	commit := &types.Commit{BlockID: types.BlockID{Hash: []byte("blockhash")}}
+	s := &snapshot{Height: 1, Format: 1, Chunks: 3, Hash: []byte{1}}
+	newer := &snapshot{Height: 2, Format: 1, Chunks: 3, Hash: []byte{2}}
+
+	// A previous restoration of s was interrupted after downloading 2 chunks.
+	progressDir := filepath.Join(t.TempDir(), "statesync")
+	chunks, err := openChunkQueue(s, progressDir)
+	require.NoError(t, err)
+	for i := uint32(0); i < 2; i++ {
+		_, err := chunks.Add(&chunk{Height: 1, Format: 1, Index: i, Chunk: []byte{1, byte(i)}, Sender: "a"})
+		require.NoError(t, err)
+	}
+	require.NoError(t, (&syncProgress{Snapshot: s}).save(progressDir))
+
+	stateProvider := &mocks.StateProvider{}
+	stateProvider.On("AppHash", mock.Anything, uint64(1)).Return(state.AppHash, nil)
+	stateProvider.On("State", mock.Anything, uint64(1)).Return(state, nil)
+	stateProvider.On("Commit", mock.Anything, uint64(1)).Return(commit, nil)
+	connSnapshot := &proxymocks.AppConnSnapshot{}
+	connSnapshot.On("OfferSnapshot", mock.Anything, mock.Anything).Once().Return(
+		&abci.OfferSnapshotResponse{Result: abci.OFFER_SNAPSHOT_RESULT_ACCEPT}, nil)
+	// The app restarts the restoration when offered the snapshot, so all the
+	// chunks are applied, including those downloaded before the restart.
+	connSnapshot.On("ApplySnapshotChunk", mock.Anything, mock.Anything).Times(3).Return(
+		&abci.ApplySnapshotChunkResponse{Result: abci.APPLY_SNAPSHOT_CHUNK_RESULT_ACCEPT}, nil)
+	connQuery := &proxymocks.AppConnQuery{}
+	connQuery.On("Info", mock.Anything, proxy.InfoRequest).Return(&abci.InfoResponse{
+		AppVersion:       testAppVersion,
+		LastBlockHeight:  1,
+		LastBlockAppHash: state.AppHash,
+	}, nil)
+
+	syncer := newSyncer(*config.DefaultStateSyncConfig(), log.NewNopLogger(), connSnapshot, connQuery, stateProvider, "")
+	syncer.progressDir = progressDir
+
+	// The peer offers both snapshots, and only needs to send the missing chunk
+	// of the resumed one.
+	var requested []uint32
+	peer := simplePeer("a")
+	peer.On("Send", mock.MatchedBy(func(i any) bool {
+		e, ok := i.(p2p.Envelope)
+		return ok && e.ChannelID == ChunkChannel
+	})).Run(func(args mock.Arguments) {
+		req := args[0].(p2p.Envelope).Message.(*ssproto.ChunkRequest)
+		requested = append(requested, req.Index)
+		_, err := syncer.AddChunk(&chunk{
+			Height: req.Height, Format: req.Format, Index: req.Index, Chunk: []byte{1, byte(req.Index)}, Sender: "a",
+		})
+		require.NoError(t, err)
+	}).Return(true)
+	_, err = syncer.AddSnapshot(peer, s)
+	require.NoError(t, err)
+	_, err = syncer.AddSnapshot(peer, newer)
+	require.NoError(t, err)
+
+	newState, _, err := syncer.SyncAny(0, func() {})
+	require.NoError(t, err)
+	assert.Equal(t, state, newState)
+	assert.Equal(t, []uint32{2}, requested)
+	connSnapshot.AssertExpectations(t)
+
+	// The chunks and the progress are removed once done.
+	_, err = os.Stat(progressDir)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestSyncer_NewChunkQueue_DropsOtherSnapshot(t *testing.T) {
+	s := &snapshot{Height: 1, Format: 1, Chunks: 3, Hash: []byte{1}}
+	other := &snapshot{Height: 2, Format: 1, Chunks: 3, Hash: []byte{2}}
+
+	progressDir := filepath.Join(t.TempDir(), "statesync")
+	chunks, err := openChunkQueue(other, progressDir)
+	require.NoError(t, err)
+	_, err = chunks.Add(&chunk{Height: 2, Format: 1, Index: 0, Chunk: []byte{1}})
+	require.NoError(t, err)
+	resume := &syncProgress{Snapshot: other}
+
+	syncer, _ := setupOfferSyncer()
+	syncer.progressDir = progressDir
+	peer := simplePeer("a")
+	_, err = syncer.AddSnapshot(peer, s)
+	require.NoError(t, err)
+
+	// The interrupted snapshot is not offered anymore, so its chunks are dropped.
+	require.Nil(t, syncer.resumableSnapshot(resume.Snapshot))
+	chunks, err = syncer.newChunkQueue(s, nil)
+	require.NoError(t, err)
+	defer chunks.Close()
+	assert.False(t, chunks.Has(0))
+
+	progress, err := loadSyncProgress(progressDir)
+	require.NoError(t, err)
+	assert.Equal(t, s.Key(), progress.Snapshot.Key())
+}
@@ -190,6 +190,12 @@ type ManifestNode struct {
 	// this relates to the providers the light client is connected to.
 	PersistentPeersList []string `toml:"persistent_peers"`
 
+	// SentriesList is the list of node names of the sentries shielding this
+	// validator, in which case the validator only connects to its sentries
+	// and is not a default persistent peer of the other nodes. Defaults to
+	// none.
+	SentriesList []string `toml:"sentries"`
+
 	// Database specifies the database backend: "goleveldb", "rocksdb",
 	// "pebbledb" or "badgerdb". Defaults to "goleveldb".
 	Database string `toml:"database"`
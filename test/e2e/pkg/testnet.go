@@ -117,6 +117,8 @@ type Node struct {
 	PersistInterval         uint64
 	Seeds                   []*Node
 	PersistentPeers         []*Node
+	Sentries                []*Node
+	SentryOf                []*Node
 	Perturbations           []Perturbation
 	Prometheus              bool
 	PrometheusProxyPort     uint32
@@ -307,12 +309,27 @@ func NewTestnetFromManifest(manifest Manifest, file string, ifd InfrastructureDa
 			}
 			node.PersistentPeers = append(node.PersistentPeers, peer)
 		}
+		for _, sentryName := range nodeManifest.SentriesList {
+			sentry := testnet.LookupNode(sentryName)
+			if sentry == nil {
+				return nil, fmt.Errorf("unknown sentry %q for node %q", sentryName, node.Name)
+			}
+			node.Sentries = append(node.Sentries, sentry)
+			sentry.SentryOf = append(sentry.SentryOf, node)
+		}
+	}
 
-		// If there are no seeds or persistent peers specified, default to persistent
-		// connections to all other nodes.
+	// A validator with sentries only connects to them. Otherwise, if there are
+	// no seeds or persistent peers specified, default to persistent
+	// connections to all other nodes but the validators shielded by sentries.
+	for _, node := range testnet.Nodes {
+		if len(node.Sentries) > 0 {
+			node.PersistentPeers = node.Sentries
+			continue
+		}
 		if len(node.PersistentPeers) == 0 && len(node.Seeds) == 0 {
 			for _, peer := range testnet.Nodes {
-				if peer.Name == node.Name {
+				if peer.Name == node.Name || len(peer.Sentries) > 0 {
 					continue
 				}
 				node.PersistentPeers = append(node.PersistentPeers, peer)
@@ -574,6 +591,24 @@ func (n Node) Validate(testnet Testnet) error {
 	if n.SnapshotInterval > 0 && n.RetainBlocks > 0 && n.RetainBlocks < n.SnapshotInterval {
 		return errors.New("snapshot_interval must be less than er equal to retain_blocks")
 	}
+	if len(n.Sentries) > 0 && n.Mode != ModeValidator {
+		return errors.New("only validators can have sentries")
+	}
+	for _, sentry := range n.Sentries {
+		if sentry.Mode != ModeFull && sentry.Mode != ModeValidator {
+			return fmt.Errorf("sentry %q must be a full node or a validator", sentry.Name)
+		}
+		if len(sentry.Sentries) > 0 {
+			return fmt.Errorf("sentry %q can't have sentries", sentry.Name)
+		}
+	}
+	if n.Mode != ModeLight && len(n.Sentries) == 0 {
+		for _, peer := range slices.Concat(n.Seeds, n.PersistentPeers) {
+			if len(peer.Sentries) > 0 && !slices.Contains(n.SentryOf, peer) {
+				return fmt.Errorf("peer %q only connects to its sentries", peer.Name)
+			}
+		}
+	}
 
 	var upgradeFound bool
 	for _, perturbation := range n.Perturbations {
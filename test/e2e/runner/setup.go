@@ -219,6 +219,8 @@ func MakeConfig(node *e2e.Node) (*config.Config, error) {
 	cfg.GRPC.VersionService.Enabled = true
 	cfg.GRPC.BlockService.Enabled = true
 	cfg.GRPC.BlockResultsService.Enabled = true
+	cfg.GRPC.ProposerService.Enabled = true
+	cfg.GRPC.LightBlockService.Enabled = true
 
 	cfg.P2P.ExternalAddress = fmt.Sprintf("tcp://%v", node.AddressP2P(false))
 	cfg.P2P.AddrBookStrict = false
@@ -320,6 +322,25 @@ func MakeConfig(node *e2e.Node) (*config.Config, error) {
 		cfg.P2P.PexReactor = false
 	}
 
+	// A validator with sentries only connects to them, and the sentries never
+	// gossip the validators they shield.
+	cfg.P2P.TopologyPeers = ""
+	topologyPeers := node.Sentries
+	switch {
+	case len(node.Sentries) > 0:
+		cfg.P2P.Topology = config.TopologyValidator
+		cfg.P2P.PexReactor = false
+	case len(node.SentryOf) > 0:
+		cfg.P2P.Topology = config.TopologySentry
+		topologyPeers = node.SentryOf
+	}
+	for _, peer := range topologyPeers {
+		if len(cfg.P2P.TopologyPeers) > 0 {
+			cfg.P2P.TopologyPeers += ","
+		}
+		cfg.P2P.TopologyPeers += peer.AddressP2P(true)
+	}
+
 	if node.Testnet.LogLevel != "" {
 		cfg.LogLevel = node.Testnet.LogLevel
 	}
@@ -1,6 +1,7 @@
 package e2e_test
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"testing"
@@ -94,6 +95,74 @@ func TestGRPC_GetBlockResults(t *testing.T) {
 	})
 }
 
+// Test the GRPC Proposer service. Invoke the GetProposerSchedule method and
+// check the heights of the schedule.
+func TestGRPC_GetProposerSchedule(t *testing.T) {
+	t.Helper()
+	testFullNodesOrValidators(t, 0, func(t *testing.T, node e2e.Node) {
+		t.Helper()
+
+		ctx, ctxCancel := context.WithTimeout(context.Background(), time.Minute)
+		defer ctxCancel()
+
+		gRPCClient, err := node.GRPCClient(ctx)
+		require.NoError(t, err)
+		defer gRPCClient.Close()
+
+		schedule, err := gRPCClient.GetProposerSchedule(ctx, 5)
+		require.NoError(t, err)
+		require.Len(t, schedule.Proposers, 5)
+		require.Equal(t, schedule.LastHeight+2, schedule.FinalUntil)
+
+		for i, p := range schedule.Proposers {
+			require.Equal(t, schedule.LastHeight+1+int64(i), p.Height)
+		}
+
+		// The proposer of the next height is final: it is a validator of that height.
+		client, err := node.Client()
+		require.NoError(t, err)
+		next := schedule.Proposers[0]
+		vals, err := client.Validators(ctx, &next.Height, nil, nil)
+		require.NoError(t, err)
+		found := false
+		for _, val := range vals.Validators {
+			found = found || bytes.Equal(val.Address, next.Address)
+		}
+		require.True(t, found, "proposer of height %d is not a validator", next.Height)
+	})
+}
+
+// Test the GRPC Light Block service. Invoke the GetLightBlock method and check
+// that the light block matches the commit and validators returned by the RPC.
+func TestGRPC_GetLightBlock(t *testing.T) {
+	testFullNodesOrValidators(t, 0, func(t *testing.T, node e2e.Node) {
+		t.Helper()
+
+		ctx, ctxCancel := context.WithTimeout(context.Background(), time.Minute)
+		defer ctxCancel()
+
+		gRPCClient, err := node.GRPCClient(ctx)
+		require.NoError(t, err)
+		defer gRPCClient.Close()
+
+		latest, err := gRPCClient.GetLightBlock(ctx, 0)
+		require.NoError(t, err)
+		require.NoError(t, latest.ValidateBasic(node.Testnet.Name))
+
+		client, err := node.Client()
+		require.NoError(t, err)
+		height := latest.Height - 1
+		lb, err := gRPCClient.GetLightBlock(ctx, height)
+		require.NoError(t, err)
+		commit, err := client.Commit(ctx, &height)
+		require.NoError(t, err)
+		require.Equal(t, commit.Header.Hash(), lb.Hash())
+		vals, err := client.Validators(ctx, &height, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, lb.ValidatorSet.Validators, vals.Total)
+	})
+}
+
 // Test the GRPC Privileged Pruning Service methods to set and get the block retain height.
 func TestGRPC_BlockRetainHeight(t *testing.T) {
 	t.Helper()
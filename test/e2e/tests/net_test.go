@@ -45,3 +45,25 @@ func TestNet_Peers(t *testing.T) {
 		}
 	})
 }
+
+// Tests that validators with sentries are only peered with their sentries.
+func TestNet_Sentries(t *testing.T) {
+	testNode(t, func(t *testing.T, node e2e.Node) {
+		t.Helper()
+		if len(node.Sentries) == 0 {
+			return
+		}
+
+		client, err := node.Client()
+		require.NoError(t, err)
+		netInfo, err := client.NetInfo(ctx)
+		require.NoError(t, err)
+
+		for _, peerInfo := range netInfo.Peers {
+			peer := node.Testnet.LookupNode(peerInfo.NodeInfo.Moniker)
+			require.NotNil(t, peer, "unknown node %v", peerInfo.NodeInfo.Moniker)
+			require.Contains(t, node.Sentries, peer,
+				"validator %v peered with %v, which is not one of its sentries", node.Name, peer.Name)
+		}
+	})
+}
@@ -53,6 +53,13 @@ test:
 	@go test -p 1 $(PACKAGES) -tags bls12381
 .PHONY: test
 
+# Runs the tests of the code requiring the bls12381 build tag, which need a
+# cgo toolchain able to build blst.
+test_bls12381:
+	@echo "--> Running go test with bls12381 support"
+	@CGO_ENABLED=1 go test -tags bls12381 ./crypto/bls12381/... ./privval/... ./node/...
+.PHONY: test_bls12381
+
 test_race:
 	@echo "--> Running go test --race"
 	@go test -p 1 -race $(PACKAGES) -tags bls12381
@@ -75,7 +82,7 @@ NUM_SPLIT ?= 4
 # Note we need to check for both in-package tests (.TestGoFiles) and
 # out-of-package tests (.XTestGoFiles).
 $(BUILDDIR)/packages.txt:$(GO_TEST_FILES) $(BUILDDIR)
-	go list -f "{{ if (or .TestGoFiles .XTestGoFiles) }}{{ .ImportPath }}{{ end }}" ./... | sort > $@
+	go list -tags bls12381 -f "{{ if (or .TestGoFiles .XTestGoFiles) }}{{ .ImportPath }}{{ end }}" ./... | sort > $@
 
 split-test-packages:$(BUILDDIR)/packages.txt
 	split -d -n l/$(NUM_SPLIT) $< $<.
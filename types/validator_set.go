@@ -152,6 +152,25 @@ func (vals *ValidatorSet) IncrementProposerPriority(times int32) {
 	vals.Proposer = proposer
 }
 
+// ProposerSchedule returns the proposers of round 0 of the next n heights,
+// starting with the current proposer, assuming the validator set does not
+// change in the meantime. It simulates IncrementProposerPriority, one height
+// at a time, on a copy of the set. Returns nil if the set is empty.
+func (vals *ValidatorSet) ProposerSchedule(n int) []*Validator {
+	if vals.IsNilOrEmpty() || n <= 0 {
+		return nil
+	}
+
+	cp := vals.Copy()
+	schedule := make([]*Validator, 0, n)
+	schedule = append(schedule, cp.GetProposer())
+	for len(schedule) < n {
+		cp.IncrementProposerPriority(1)
+		schedule = append(schedule, cp.GetProposer())
+	}
+	return schedule
+}
+
 // RescalePriorities rescales the priorities such that the distance between the
 // maximum and minimum is smaller than `diffMax`. Panics if validator set is
 // empty.
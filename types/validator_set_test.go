@@ -240,6 +240,27 @@ func TestProposerSelection1(t *testing.T) {
 	}
 }
 
+func TestProposerSchedule(t *testing.T) {
+	vset := NewValidatorSet([]*Validator{
+		newValidator([]byte("foo"), 1000),
+		newValidator([]byte("bar"), 300),
+		newValidator([]byte("baz"), 330),
+	})
+	before := vset.Copy()
+
+	schedule := vset.ProposerSchedule(20)
+	require.Len(t, schedule, 20)
+	assert.Equal(t, before, vset, "ProposerSchedule must not mutate the set")
+
+	for i, proposer := range schedule {
+		assert.Equal(t, vset.GetProposer().Address, proposer.Address, "height offset %d", i)
+		vset.IncrementProposerPriority(1)
+	}
+
+	assert.Nil(t, vset.ProposerSchedule(0))
+	assert.Nil(t, NewValidatorSet(nil).ProposerSchedule(5))
+}
+
 func TestProposerSelection2(t *testing.T) {
 	addr0 := []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
 	addr1 := []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}